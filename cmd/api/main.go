@@ -17,18 +17,29 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
 	_ "github.com/Enas-Ijaabo/drone-delivery-management/docs"
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/geocode"
 	iface "github.com/Enas-Ijaabo/drone-delivery-management/internal/interface"
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
 	"github.com/Enas-Ijaabo/drone-delivery-management/internal/repo"
 	"github.com/Enas-Ijaabo/drone-delivery-management/internal/usecase"
 )
 
+// shutdownTimeout bounds draining HTTP requests and resigning leadership on
+// SIGTERM, within the 10s docker waits before killing the container.
+const shutdownTimeout = 8 * time.Second
+
 func main() {
 	fmt.Println("Starting Drone Delivery Management System...")
 
@@ -46,14 +57,28 @@ func main() {
 	}
 	defer db.Close()
 
-	if err := repo.MigrateIfEmpty(db, "/migrations"); err != nil {
-		log.Fatalf("migration failed: %v", err)
+	// extra replicas leave migrations to the first instance
+	if getenv("DB_MIGRATE", "true") != "false" {
+		if err := repo.MigrateIfEmpty(db, "/migrations"); err != nil {
+			log.Fatalf("migration failed: %v", err)
+		}
 	}
 
 	// Initialize repositories
 	usersRepo := repo.NewUsersRepo(db)
 	orderRepo := repo.NewOrderRepo(db)
 	droneRepo := repo.NewDroneRepo(db)
+	tripRepo := repo.NewTripRepo(db)
+	addressRepo := repo.NewAddressRepo(db)
+	serviceAreaRepo := repo.NewServiceAreaRepo(db)
+	noFlyZoneRepo := repo.NewNoFlyZoneRepo(db)
+	landingSiteRepo := repo.NewLandingSiteRepo(db)
+	depotRepo := repo.NewDepotRepo(db)
+	breachRepo := repo.NewGeofenceBreachRepo(db)
+	telemetryRepo := repo.NewTelemetryRepo(db)
+	commandRepo := repo.NewDroneCommandRepo(db)
+	rebalanceRepo := repo.NewRebalanceRepo(db)
+	maintenanceRepo := repo.NewMaintenanceRepo(db)
 
 	// Auth config from env
 	jwtSecret := []byte(getenv("JWT_SECRET", "dev-secret"))
@@ -66,30 +91,315 @@ func main() {
 	jwtIssuer := getenv("JWT_ISSUER", "drone-delivery")
 	jwtAudience := getenv("JWT_AUDIENCE", "drone-delivery")
 
+	// Proof of delivery config from env
+	deliveryRadiusStr := getenv("DELIVERY_RADIUS_METERS", "100")
+	deliveryRadius, err := strconv.ParseFloat(deliveryRadiusStr, 64)
+	if err != nil || deliveryRadius <= 0 {
+		log.Printf("invalid DELIVERY_RADIUS_METERS %q, defaulting to 100: %v", deliveryRadiusStr, err)
+		deliveryRadius = 100
+	}
+	evidenceMaxAgeStr := getenv("DELIVERY_EVIDENCE_MAX_AGE", "10m")
+	evidenceMaxAge, err := time.ParseDuration(evidenceMaxAgeStr)
+	if err != nil {
+		log.Printf("invalid DELIVERY_EVIDENCE_MAX_AGE %q, defaulting to 10m: %v", evidenceMaxAgeStr, err)
+		evidenceMaxAge = 10 * time.Minute
+	}
+	maxPINAttemptsStr := getenv("DELIVERY_PIN_MAX_ATTEMPTS", "5")
+	maxPINAttempts, err := strconv.Atoi(maxPINAttemptsStr)
+	if err != nil || maxPINAttempts <= 0 || maxPINAttempts > 255 {
+		log.Printf("invalid DELIVERY_PIN_MAX_ATTEMPTS %q, defaulting to 5: %v", maxPINAttemptsStr, err)
+		maxPINAttempts = 5
+	}
+	deliveryPolicy := model.DeliveryPolicy{
+		RadiusMeters:   deliveryRadius,
+		EvidenceKey:    []byte(getenv("DELIVERY_EVIDENCE_SECRET", "dev-evidence-secret")),
+		EvidenceMaxAge: evidenceMaxAge,
+		MaxPINAttempts: maxPINAttempts,
+	}
+
+	// Geocoding config from env
+	reverseRadiusStr := getenv("GEOCODER_REVERSE_RADIUS_METERS", "500")
+	reverseRadius, err := strconv.ParseFloat(reverseRadiusStr, 64)
+	if err != nil || reverseRadius < 0 {
+		log.Printf("invalid GEOCODER_REVERSE_RADIUS_METERS %q, defaulting to 500: %v", reverseRadiusStr, err)
+		reverseRadius = 500
+	}
+	geocodeCacheTTLStr := getenv("GEOCODER_CACHE_TTL", "1h")
+	geocodeCacheTTL, err := time.ParseDuration(geocodeCacheTTLStr)
+	if err != nil {
+		log.Printf("invalid GEOCODER_CACHE_TTL %q, defaulting to 1h: %v", geocodeCacheTTLStr, err)
+		geocodeCacheTTL = time.Hour
+	}
+	geocodeCacheSizeStr := getenv("GEOCODER_CACHE_SIZE", "10000")
+	geocodeCacheSize, err := strconv.Atoi(geocodeCacheSizeStr)
+	if err != nil {
+		log.Printf("invalid GEOCODER_CACHE_SIZE %q, defaulting to 10000: %v", geocodeCacheSizeStr, err)
+		geocodeCacheSize = 10000
+	}
+	geocoder, err := geocode.New(geocode.Config{
+		Provider:            getenv("GEOCODER_PROVIDER", geocode.ProviderGazetteer),
+		GazetteerPath:       getenv("GEOCODER_GAZETTEER_PATH", "/app/data/gazetteer.csv"),
+		ReverseRadiusMeters: reverseRadius,
+		CacheTTL:            geocodeCacheTTL,
+		CacheSize:           geocodeCacheSize,
+	})
+	if err != nil {
+		log.Fatalf("geocoder setup failed: %v", err)
+	}
+
+	// Geofence monitoring config from env
+	breachActionStr := getenv("GEOFENCE_BREACH_ACTION", string(model.BreachActionHold))
+	breachAction, err := model.ParseBreachAction(breachActionStr)
+	if err != nil {
+		log.Printf("invalid GEOFENCE_BREACH_ACTION %q, defaulting to hold: %v", breachActionStr, err)
+		breachAction = model.BreachActionHold
+	}
+
+	// Dispatch config from env: greedy offers each order as it arrives, batch
+	// matches waiting orders to drones every interval
+	dispatchModeStr := getenv("DISPATCH_STRATEGY", string(model.DispatchGreedy))
+	dispatchMode, err := model.ParseDispatchMode(dispatchModeStr)
+	if err != nil {
+		log.Printf("invalid DISPATCH_STRATEGY %q, defaulting to greedy: %v", dispatchModeStr, err)
+		dispatchMode = model.DispatchGreedy
+	}
+	dispatchCostStr := getenv("DISPATCH_COST", string(model.DispatchCostHaversine))
+	dispatchCost, err := model.ParseDispatchCost(dispatchCostStr)
+	if err != nil {
+		log.Printf("invalid DISPATCH_COST %q, defaulting to haversine: %v", dispatchCostStr, err)
+		dispatchCost = model.DispatchCostHaversine
+	}
+	dispatchBatchEvery := getenvDuration("DISPATCH_BATCH_INTERVAL", 2*time.Second)
+	dispatchOfferTimeout := getenvDuration("DISPATCH_OFFER_TIMEOUT", 30*time.Second)
+
+	// Handoff config from env: parcels of a drone that broke down wait where
+	// it broke down, or at the nearest landing site within the detour cap
+	handoffRendezvousStr := getenv("HANDOFF_RENDEZVOUS", string(model.HandoffAtBreakdown))
+	handoffRendezvous, err := model.ParseHandoffRendezvous(handoffRendezvousStr)
+	if err != nil {
+		log.Printf("invalid HANDOFF_RENDEZVOUS %q, defaulting to breakdown: %v", handoffRendezvousStr, err)
+		handoffRendezvous = model.HandoffAtBreakdown
+	}
+	handoffDetourStr := getenv("HANDOFF_MAX_DETOUR_KM", "2")
+	handoffDetour, err := strconv.ParseFloat(handoffDetourStr, 64)
+	if err != nil || handoffDetour < 0 {
+		log.Printf("invalid HANDOFF_MAX_DETOUR_KM %q, defaulting to 2: %v", handoffDetourStr, err)
+		handoffDetour = 2
+	}
+	handoffPolicy := model.HandoffPolicy{Rendezvous: handoffRendezvous, MaxDetourKm: handoffDetour}
+
+	// Return-to-base config from env: idle drones below the battery threshold
+	// go to charge, and with RTB_PARK_WHEN_IDLE drones with nothing waiting
+	// park at a depot after a trip
+	basePolicy := model.BasePolicy{ChargeBelowPct: 25, ChargedPct: 90, ParkWhenIdle: true}
+	rtbBatteryStr := getenv("RTB_BATTERY_PCT", "25")
+	rtbChargedStr := getenv("RTB_CHARGED_PCT", "90")
+	rtbBattery, errBattery := strconv.ParseFloat(rtbBatteryStr, 64)
+	rtbCharged, errCharged := strconv.ParseFloat(rtbChargedStr, 64)
+	if errBattery != nil || errCharged != nil || rtbBattery < 0 || rtbBattery >= rtbCharged || rtbCharged > 100 {
+		log.Printf("invalid RTB_BATTERY_PCT %q / RTB_CHARGED_PCT %q (need 0 <= battery < charged <= 100), defaulting to 25 / 90", rtbBatteryStr, rtbChargedStr)
+	} else {
+		basePolicy.ChargeBelowPct, basePolicy.ChargedPct = rtbBattery, rtbCharged
+	}
+	rtbParkStr := getenv("RTB_PARK_WHEN_IDLE", "true")
+	if basePolicy.ParkWhenIdle, err = strconv.ParseBool(rtbParkStr); err != nil {
+		log.Printf("invalid RTB_PARK_WHEN_IDLE %q, defaulting to true: %v", rtbParkStr, err)
+		basePolicy.ParkWhenIdle = true
+	}
+
+	// Pre-positioning config from env: every interval idle drones are spread
+	// by the pickups seen in the same hour over the lookback; aggressiveness
+	// 0 leaves it to admin-triggered runs
+	rebalanceAggressivenessStr := getenv("REBALANCE_AGGRESSIVENESS", "0")
+	rebalanceAggressiveness, err := strconv.ParseFloat(rebalanceAggressivenessStr, 64)
+	if err == nil {
+		err = model.ValidateAggressiveness(rebalanceAggressiveness)
+	}
+	if err != nil {
+		log.Printf("invalid REBALANCE_AGGRESSIVENESS %q, defaulting to 0: %v", rebalanceAggressivenessStr, err)
+		rebalanceAggressiveness = 0
+	}
+	rebalanceCellKmStr := getenv("REBALANCE_CELL_KM", "1")
+	rebalanceCellKm, err := strconv.ParseFloat(rebalanceCellKmStr, 64)
+	if err != nil || rebalanceCellKm < 0.2 {
+		log.Printf("invalid REBALANCE_CELL_KM %q (at least 0.2), defaulting to 1: %v", rebalanceCellKmStr, err)
+		rebalanceCellKm = 1
+	}
+	rebalancePolicy := model.RebalancePolicy{
+		Aggressiveness: rebalanceAggressiveness,
+		CellKm:         rebalanceCellKm,
+		Lookback:       getenvDuration("REBALANCE_LOOKBACK", 672*time.Hour),
+		Interval:       getenvDuration("REBALANCE_INTERVAL", 15*time.Minute),
+	}
+
+	// Maintenance config from env: drones are due for service after this many
+	// flight hours or trips since the last one; 0 leaves it to the per-drone
+	// schedules
+	maintenancePolicy := model.MaintenancePolicy{
+		CheckInterval: getenvDuration("MAINTENANCE_CHECK_INTERVAL", 10*time.Minute),
+	}
+	maintenanceHoursStr := getenv("MAINTENANCE_FLIGHT_HOURS", "0")
+	maintenanceCyclesStr := getenv("MAINTENANCE_CYCLES", "0")
+	maintenanceHours, errHours := strconv.ParseFloat(maintenanceHoursStr, 64)
+	maintenanceCycles, errCycles := strconv.Atoi(maintenanceCyclesStr)
+	if err := (model.MaintenanceSchedule{FlightHours: &maintenanceHours, Cycles: &maintenanceCycles}).Validate(); errHours != nil || errCycles != nil || err != nil {
+		log.Printf("invalid MAINTENANCE_FLIGHT_HOURS %q / MAINTENANCE_CYCLES %q (0 to 10000 / 0 to 100000), defaulting to 0 / 0", maintenanceHoursStr, maintenanceCyclesStr)
+	} else {
+		maintenancePolicy.FlightHours, maintenancePolicy.Cycles = maintenanceHours, maintenanceCycles
+	}
+
+	// Telemetry history config from env
+	telemetryPolicy := model.TelemetryPolicy{
+		Retention:          getenvDuration("TELEMETRY_RETENTION", 720*time.Hour),
+		DownsampleAfter:    getenvDuration("TELEMETRY_DOWNSAMPLE_AFTER", 24*time.Hour),
+		DownsampleInterval: getenvDuration("TELEMETRY_DOWNSAMPLE_INTERVAL", time.Minute),
+	}
+	telemetryMaintenanceEvery := getenvDuration("TELEMETRY_MAINTENANCE_INTERVAL", 10*time.Minute)
+
+	// Websocket keepalive and delivery config from env
+	wsCfg := iface.WSConfig{
+		PingInterval: getenvDuration("WS_PING_INTERVAL", 25*time.Second),
+		PongWait:     getenvDuration("WS_PONG_WAIT", 60*time.Second),
+		WriteWait:    getenvDuration("WS_WRITE_TIMEOUT", 10*time.Second),
+		SendBuffer:   getenvPositiveInt("WS_SEND_BUFFER", 64),
+		ResumeWindow: getenvDuration("WS_RESUME_WINDOW", 2*time.Minute),
+		ResumeBuffer: getenvPositiveInt("WS_RESUME_BUFFER", 256),
+	}
+	if wsCfg.PongWait <= wsCfg.PingInterval {
+		log.Printf("WS_PONG_WAIT %s must exceed WS_PING_INTERVAL %s, using %s", wsCfg.PongWait, wsCfg.PingInterval, 2*wsCfg.PingInterval)
+		wsCfg.PongWait = 2 * wsCfg.PingInterval
+	}
+	slowClientStr := getenv("WS_SLOW_CLIENT_POLICY", string(iface.SlowClientClose))
+	wsCfg.SlowClient, err = iface.ParseSlowClientPolicy(slowClientStr)
+	if err != nil {
+		log.Printf("invalid WS_SLOW_CLIENT_POLICY %q, defaulting to close: %v", slowClientStr, err)
+		wsCfg.SlowClient = iface.SlowClientClose
+	}
+	wsMetrics := iface.NewWSMetrics()
+
+	// Cluster config from env: where drone websockets live and how nodes relay
+	clusterBus := getenv("CLUSTER_BUS", "mysql")
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "api"
+	}
+	clusterCfg := iface.ClusterConfig{
+		NodeID:      getenv("CLUSTER_NODE_ID", hostname),
+		PresenceTTL: getenvDuration("CLUSTER_PRESENCE_TTL", 30*time.Second),
+	}
+	clusterPollInterval := getenvDuration("CLUSTER_POLL_INTERVAL", 500*time.Millisecond)
+
+	// Leader election for singleton background jobs
+	leaderLeaseTTL := getenvDuration("LEADER_LEASE_TTL", 10*time.Second)
+	leaderRenewEvery := getenvDuration("LEADER_RENEW_INTERVAL", 3*time.Second)
+	if leaderRenewEvery >= leaderLeaseTTL {
+		log.Printf("LEADER_RENEW_INTERVAL %s must be shorter than LEADER_LEASE_TTL %s, using %s", leaderRenewEvery, leaderLeaseTTL, leaderLeaseTTL/3)
+		leaderRenewEvery = leaderLeaseTTL / 3
+	}
+
 	// Initialize usecases
 	authUC := usecase.NewAuthUsecase(usersRepo, jwtSecret, jwtTTL, jwtIssuer, jwtAudience)
-	droneUC := usecase.NewDroneUsecase(droneRepo)
-	registry := iface.NewConnectionRegistry()
-	droneWSHandler := iface.NewDroneWSHandler(droneUC, registry)
-	orderUC := usecase.NewOrderUsecase(orderRepo, droneRepo, droneWSHandler)
-	droneOpsUC := usecase.NewDroneOpsUsecase(droneRepo, orderRepo, orderUC)
+	registry := iface.NewConnectionRegistry(wsCfg, wsMetrics)
+	switch clusterBus {
+	case "mysql":
+		bus := repo.NewMySQLMessageBus(db, clusterPollInterval)
+		if err := registry.JoinCluster(context.Background(), clusterCfg, repo.NewPresenceRepo(db), bus); err != nil {
+			log.Fatalf("joining websocket cluster failed: %v", err)
+		}
+		log.Printf("websocket cluster node %s (mysql relay every %s)", clusterCfg.NodeID, clusterPollInterval)
+	case "none":
+	default:
+		log.Fatalf("unknown CLUSTER_BUS %q (expected mysql or none)", clusterBus)
+	}
+	adminWSHandler := iface.NewAdminWSHandler(wsCfg, wsMetrics)
+	breachAlerter := iface.NewBreachAlerter(registry, adminWSHandler)
+	commandUC := usecase.NewDroneCommandUsecase(commandRepo, droneRepo, iface.NewCommandDispatcher(registry))
+	depotUC := usecase.NewDepotUsecase(depotRepo, droneRepo, orderRepo, commandUC, basePolicy, dispatchOfferTimeout)
+	maintenanceUC := usecase.NewMaintenanceUsecase(maintenanceRepo, droneRepo, maintenancePolicy)
+	geofenceCache := usecase.NewGeofenceCache(noFlyZoneRepo, serviceAreaRepo)
+	droneUC := usecase.NewDroneUsecase(droneRepo, telemetryRepo, geofenceCache, breachRepo, breachAlerter, breachAction, depotUC)
+	droneWSHandler := iface.NewDroneWSHandler(droneUC, commandUC, registry)
+	orderUC := usecase.NewOrderUsecase(orderRepo, droneRepo, tripRepo, addressRepo, serviceAreaRepo, noFlyZoneRepo, geocoder, droneWSHandler, deliveryPolicy, depotUC, maintenanceUC)
+	droneOpsUC := usecase.NewDroneOpsUsecase(droneRepo, orderRepo, tripRepo, landingSiteRepo, noFlyZoneRepo, orderUC, handoffPolicy, maintenanceRepo)
+	addressUC := usecase.NewAddressUsecase(addressRepo)
+	geocodeUC := usecase.NewGeocodeUsecase(geocoder)
+	serviceAreaUC := usecase.NewServiceAreaUsecase(serviceAreaRepo, geofenceCache)
+	noFlyZoneUC := usecase.NewNoFlyZoneUsecase(noFlyZoneRepo, geofenceCache)
+	landingSiteUC := usecase.NewLandingSiteUsecase(landingSiteRepo)
+	telemetryUC := usecase.NewTelemetryUsecase(telemetryRepo, droneRepo, orderRepo, telemetryPolicy)
+	rebalancer := usecase.NewRebalancer(rebalanceRepo, droneRepo, commandUC, rebalancePolicy, dispatchOfferTimeout)
+
+	// Singleton background jobs run only on the elected leader
+	leaderElector := usecase.NewLeaderElector(repo.NewLeaseRepo(db), clusterCfg.NodeID, leaderLeaseTTL, leaderRenewEvery)
+	leaderElector.Register("telemetry-maintenance", func(ctx context.Context) {
+		telemetryUC.Maintain(ctx, telemetryMaintenanceEvery)
+	})
+	leaderElector.Register("scheduled-maintenance", maintenanceUC.Run)
+	if dispatchMode == model.DispatchBatch {
+		batchDispatch := usecase.NewBatchDispatch(orderUC, orderRepo, droneRepo, dispatchCost, dispatchOfferTimeout)
+		orderUC.UseDispatch(batchDispatch)
+		leaderElector.Register("batch-dispatch", func(ctx context.Context) {
+			batchDispatch.Run(ctx, dispatchBatchEvery)
+		})
+	}
+	log.Printf("dispatch strategy %s", dispatchMode)
+	if rebalancePolicy.Aggressiveness > 0 {
+		leaderElector.Register("rebalance", rebalancer.Run)
+		log.Printf("rebalancing idle drones every %s (aggressiveness %.2f)", rebalancePolicy.Interval, rebalancePolicy.Aggressiveness)
+	}
+	// SIGINT/SIGTERM stop the server and hand the leader lease over at once
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	leaderResigned := leaderElector.Start(ctx)
 
 	// Initialize interfaces/handlers
 	authHandler := iface.NewAuthHandler(authUC)
 	orderHandler := iface.NewOrderHandler(orderUC)
+	addressHandler := iface.NewAddressHandler(addressUC)
+	geocodeHandler := iface.NewGeocodeHandler(geocodeUC)
+	serviceAreaHandler := iface.NewServiceAreaHandler(serviceAreaUC)
+	noFlyZoneHandler := iface.NewNoFlyZoneHandler(noFlyZoneUC)
+	landingSiteHandler := iface.NewLandingSiteHandler(landingSiteUC)
+	depotHandler := iface.NewDepotHandler(depotUC)
 	droneHandler := iface.NewDroneHandler(droneOpsUC)
+	breachHandler := iface.NewGeofenceBreachHandler(droneUC)
+	trackHandler := iface.NewTrackHandler(telemetryUC)
+	commandHandler := iface.NewDroneCommandHandler(commandUC)
+	leaderHandler := iface.NewLeaderHandler(leaderElector)
+	dispatchHandler := iface.NewDispatchHandler(usecase.NewDispatchPlanner(orderRepo, droneRepo, noFlyZoneRepo, dispatchMode, dispatchCost, dispatchOfferTimeout))
+	rebalanceHandler := iface.NewRebalanceHandler(rebalancer)
+	maintenanceHandler := iface.NewMaintenanceHandler(maintenanceUC)
 	// Auth middleware instance
 	authMW := iface.AuthMiddleware(jwtSecret, jwtIssuer, jwtAudience)
 
 	// Gin router
-	r := iface.NewRouter(authHandler, orderHandler, droneHandler, droneWSHandler, authMW)
+	r := iface.NewRouter(authHandler, orderHandler, addressHandler, geocodeHandler, serviceAreaHandler, noFlyZoneHandler, landingSiteHandler, depotHandler, droneHandler, droneWSHandler, breachHandler, trackHandler, commandHandler, adminWSHandler, wsMetrics, leaderHandler, dispatchHandler, rebalanceHandler, maintenanceHandler, authMW)
 
 	srv := &http.Server{
 		Addr:    ":8080",
 		Handler: r,
 	}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
 	log.Println("Ready. Health: http://0.0.0.0:8080/health")
-	log.Fatal(srv.ListenAndServe())
+
+	<-ctx.Done()
+	stop()
+	log.Println("Shutting down...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("http server shutdown: %v", err)
+	}
+	select {
+	case <-leaderResigned:
+	case <-shutdownCtx.Done():
+		log.Printf("leader did not resign within %s", shutdownTimeout)
+	}
 }
 
 func getenv(key, def string) string {
@@ -98,3 +408,41 @@ func getenv(key, def string) string {
 	}
 	return def
 }
+
+// getenvDuration parses key as a time.Duration, logging and falling back to
+// def when it is unset or malformed (non-positive values included).
+func getenvDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("invalid %s %q, defaulting to %s: %v", key, v, def, err)
+		return def
+	}
+	if d <= 0 {
+		log.Printf("invalid %s %q, defaulting to %s: must be positive", key, v, def)
+		return def
+	}
+	return d
+}
+
+// getenvPositiveInt parses key as a positive int, logging and falling back
+// to def when it is unset or malformed.
+func getenvPositiveInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("invalid %s %q, defaulting to %d: %v", key, v, def, err)
+		return def
+	}
+	if n <= 0 {
+		log.Printf("invalid %s %q, defaulting to %d: must be positive", key, v, def)
+		return def
+	}
+	return n
+}
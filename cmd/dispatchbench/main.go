@@ -0,0 +1,128 @@
+// Command dispatchbench compares greedy and batch-optimal dispatch on random
+// scenarios, reporting the total distance the fleet flies to the pickups.
+//
+//	go run ./cmd/dispatchbench -orders 100 -drones 60 -hotspots 3 -runs 50
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const kmPerDegreeLat = 111.32
+
+type scenario struct {
+	orders []model.Order
+	drones []model.Drone
+}
+
+type result struct {
+	paired  int
+	totalKm float64
+	elapsed time.Duration
+}
+
+func main() {
+	orders := flag.Int("orders", 100, "orders waiting per scenario")
+	drones := flag.Int("drones", 60, "available drones per scenario")
+	capacity := flag.Int("capacity", 1, "orders each drone can take")
+	radiusKm := flag.Float64("radius-km", 10, "radius of the service area")
+	hotspots := flag.Int("hotspots", 3, "demand hotspots orders cluster around (0 = uniform)")
+	runs := flag.Int("runs", 50, "scenarios to average over")
+	seed := flag.Int64("seed", 1, "random seed")
+	lat := flag.Float64("lat", 31.9539, "service area center latitude")
+	lng := flag.Float64("lng", 35.9106, "service area center longitude")
+	flag.Parse()
+
+	if *orders < 1 || *drones < 1 || *capacity < 1 || *capacity > model.MaxDroneCapacity || *runs < 1 || *radiusKm <= 0 || *hotspots < 0 {
+		log.Fatalf("orders, drones, runs and radius-km must be positive, capacity between 1 and %d", model.MaxDroneCapacity)
+	}
+
+	rng := rand.New(rand.NewSource(*seed))
+	center := model.GeoPoint{Lat: *lat, Lng: *lng}
+	cost := func(order model.Order, drone model.Drone) float64 {
+		return model.DispatchCostHaversine.Km(order, drone, model.Airspace{})
+	}
+
+	var greedy, optimal result
+	for range *runs {
+		s := newScenario(rng, center, *radiusKm, *hotspots, *orders, *drones, *capacity)
+		greedy.add(measure(model.MatchGreedy, s, cost))
+		optimal.add(measure(model.MatchOptimal, s, cost))
+	}
+
+	fmt.Printf("%d runs: %d orders, %d drones (capacity %d), %.1f km radius, %d hotspots, seed %d\n\n",
+		*runs, *orders, *drones, *capacity, *radiusKm, *hotspots, *seed)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "strategy\tpaired/run\ttotal km/run\tkm/order\tmatch time/run\t")
+	greedy.print(w, "greedy", *runs)
+	optimal.print(w, "batch", *runs)
+	w.Flush()
+
+	if greedy.totalKm > 0 {
+		fmt.Printf("\nbatch flies %.1f%% less than greedy to reach the pickups\n", 100*(greedy.totalKm-optimal.totalKm)/greedy.totalKm)
+	}
+}
+
+func measure(match func([]model.Order, []model.Drone, func(model.Order, model.Drone) float64) []model.DispatchPair, s scenario, cost func(model.Order, model.Drone) float64) result {
+	start := time.Now()
+	pairs := match(s.orders, s.drones, cost)
+	return result{paired: len(pairs), totalKm: model.TotalCostKm(pairs), elapsed: time.Since(start)}
+}
+
+func (r *result) add(o result) {
+	r.paired += o.paired
+	r.totalKm += o.totalKm
+	r.elapsed += o.elapsed
+}
+
+func (r result) print(w *tabwriter.Writer, name string, runs int) {
+	perOrder := 0.0
+	if r.paired > 0 {
+		perOrder = r.totalKm / float64(r.paired)
+	}
+	fmt.Fprintf(w, "%s\t%.1f\t%.2f\t%.3f\t%s\t\n",
+		name, float64(r.paired)/float64(runs), r.totalKm/float64(runs), perOrder, (r.elapsed / time.Duration(runs)).Round(time.Microsecond))
+}
+
+// newScenario scatters idle drones uniformly over the service area and
+// orders either uniformly or around a few hotspots, the bursty case greedy
+// handles worst.
+func newScenario(rng *rand.Rand, center model.GeoPoint, radiusKm float64, hotspots, orders, drones, capacity int) scenario {
+	spots := make([]model.GeoPoint, hotspots)
+	for i := range spots {
+		spots[i] = randomPoint(rng, center, radiusKm*0.7)
+	}
+
+	var s scenario
+	for i := range orders {
+		p := randomPoint(rng, center, radiusKm)
+		if hotspots > 0 {
+			p = randomPoint(rng, spots[rng.Intn(hotspots)], radiusKm*0.15)
+		}
+		s.orders = append(s.orders, model.Order{ID: int64(i + 1), PickupLat: p.Lat, PickupLng: p.Lng, Status: model.OrderPending})
+	}
+	for i := range drones {
+		p := randomPoint(rng, center, radiusKm)
+		s.drones = append(s.drones, model.Drone{ID: int64(i + 1), Status: model.DroneIdle, Capacity: capacity, Lat: p.Lat, Lng: p.Lng})
+	}
+	return s
+}
+
+// randomPoint is uniform over the disc of radiusKm around center.
+func randomPoint(rng *rand.Rand, center model.GeoPoint, radiusKm float64) model.GeoPoint {
+	r := radiusKm * math.Sqrt(rng.Float64())
+	theta := 2 * math.Pi * rng.Float64()
+	dLat := r * math.Sin(theta) / kmPerDegreeLat
+	dLng := r * math.Cos(theta) / (kmPerDegreeLat * math.Cos(center.Lat*math.Pi/180))
+	return model.GeoPoint{Lat: center.Lat + dLat, Lng: center.Lng + dLng}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiError is a non-2xx answer from the API.
+type apiError struct {
+	Status  int
+	Code    string `json:"error"`
+	Message string `json:"message"`
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Status, e.Code, e.Message)
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	User        struct {
+		ID   int64  `json:"id"`
+		Type string `json:"type"`
+	} `json:"user"`
+}
+
+// apiClient calls the API as one user, logging in again when the token
+// expires, and records the latency of every call under its route.
+type apiClient struct {
+	baseURL  string
+	name     string
+	password string
+	http     *http.Client
+	stats    *stats
+
+	mu     sync.Mutex
+	token  string
+	userID int64
+}
+
+func newAPIClient(baseURL, name, password string, httpClient *http.Client, st *stats) *apiClient {
+	return &apiClient{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		name:     name,
+		password: password,
+		http:     httpClient,
+		stats:    st,
+	}
+}
+
+func (c *apiClient) login(ctx context.Context) error {
+	var resp tokenResponse
+	body := map[string]string{"name": c.name, "password": c.password}
+	if err := c.send(ctx, http.MethodPost, "/auth/token", "POST /auth/token", "", body, &resp); err != nil {
+		return fmt.Errorf("log in as %s: %w", c.name, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = resp.AccessToken
+	c.userID = resp.User.ID
+	return nil
+}
+
+func (c *apiClient) bearer() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return "Bearer " + c.token
+}
+
+func (c *apiClient) id() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.userID
+}
+
+// call sends an authenticated request; route names the endpoint in the
+// latency report, e.g. "POST /orders/{id}/pickup".
+func (c *apiClient) call(ctx context.Context, method, path, route string, body, out interface{}) error {
+	err := c.send(ctx, method, path, route, c.bearer(), body, out)
+	if apiErr, ok := err.(*apiError); ok && apiErr.Status == http.StatusUnauthorized {
+		if err := c.login(ctx); err != nil {
+			return err
+		}
+		return c.send(ctx, method, path, route, c.bearer(), body, out)
+	}
+	return err
+}
+
+func (c *apiClient) send(ctx context.Context, method, path, route, auth string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	if err != nil {
+		if ctx.Err() == nil {
+			c.stats.observe(route, 0, true)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	elapsed := time.Since(start)
+	if err != nil {
+		c.stats.observe(route, 0, true)
+		return err
+	}
+
+	if resp.StatusCode >= 300 {
+		c.stats.observe(route, 0, true)
+		apiErr := &apiError{Status: resp.StatusCode}
+		_ = json.Unmarshal(data, apiErr)
+		return apiErr
+	}
+
+	c.stats.observe(route, elapsed, false)
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
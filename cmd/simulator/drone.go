@@ -0,0 +1,714 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// flightStep is how often a flying drone moves along its leg.
+	flightStep = 100 * time.Millisecond
+	// arrivalTimeout bounds the wait for the heartbeat that puts the drone
+	// at the dropoff before it asks for the delivery.
+	arrivalTimeout = 5 * time.Second
+	reconnectDelay = time.Second
+)
+
+// assignmentReturn is the description of an assignment to carry a returning
+// parcel back to its origin.
+const assignmentReturn = "return_handoff"
+
+type leg int
+
+const (
+	legPickup leg = iota
+	legDropoff
+	legReturn
+)
+
+// job is an order the drone reserved. Jobs are flown one after the other in
+// the order the offers came in.
+type job struct {
+	orderID  int64
+	pickup   model.GeoPoint
+	dropoff  model.GeoPoint
+	returnTo *model.GeoPoint
+	canceled bool
+}
+
+// inboundMessage is the union of the server messages the simulator reads.
+type inboundMessage struct {
+	Type        string           `json:"type"`
+	Seq         *int64           `json:"seq"`
+	Error       string           `json:"error"`
+	LastSeq     int64            `json:"last_seq"`
+	OrderID     int64            `json:"order_id"`
+	Event       string           `json:"event"`
+	Description string           `json:"description"`
+	PickupLat   float64          `json:"pickup_lat"`
+	PickupLng   float64          `json:"pickup_lng"`
+	DropoffLat  float64          `json:"dropoff_lat"`
+	DropoffLng  float64          `json:"dropoff_lng"`
+	ReturnLat   *float64         `json:"return_lat"`
+	ReturnLng   *float64         `json:"return_lng"`
+	HandoffLat  *float64         `json:"handoff_lat"`
+	HandoffLng  *float64         `json:"handoff_lng"`
+	Waypoints   []model.GeoPoint `json:"waypoints"`
+	CommandID   int64            `json:"command_id"`
+}
+
+type heartbeatMessage struct {
+	Type       string    `json:"type"`
+	Lat        float64   `json:"lat"`
+	Lng        float64   `json:"lng"`
+	SpeedMPS   float64   `json:"speed_mps"`
+	GPSFix     string    `json:"gps_fix"`
+	DeviceTime time.Time `json:"device_time"`
+}
+
+// outboundMessage covers the acks and the resume the drone sends.
+type outboundMessage struct {
+	Type    string `json:"type"`
+	OrderID int64  `json:"order_id,omitempty"`
+	Event   string `json:"event,omitempty"`
+	Status  string `json:"status,omitempty"`
+	Note    string `json:"note,omitempty"`
+	Seq     *int64 `json:"seq,omitempty"`
+	LastSeq *int64 `json:"last_seq,omitempty"`
+}
+
+type commandAckMessage struct {
+	Type      string `json:"type"`
+	CommandID int64  `json:"command_id"`
+	Status    string `json:"status"`
+	Note      string `json:"note"`
+}
+
+type locationRequest struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+type deliverRequest struct {
+	PIN string `json:"pin"`
+}
+
+// pendingHeartbeat is a heartbeat waiting for its reply; the server answers
+// heartbeats in order.
+type pendingHeartbeat struct {
+	sentAt time.Time
+	done   chan struct{}
+}
+
+// droneAgent flies one drone: it keeps a websocket open, heartbeats its
+// position, takes every offer it can and works the orders through the REST
+// API, breaking down now and then.
+type droneAgent struct {
+	sim *simulation
+	api *apiClient
+	// rng is only used by the flight loop
+	rng  *rand.Rand
+	wake chan struct{}
+
+	writeMu sync.Mutex
+	conn    *websocket.Conn
+	// lastDeviceTime keeps device times strictly increasing at the
+	// millisecond precision the server stores them with
+	lastDeviceTime time.Time
+
+	mu      sync.Mutex
+	pos     model.GeoPoint
+	speed   float64
+	jobs    []*job
+	broken  bool
+	lastSeq int64
+	seen    map[int64]bool
+	pending []pendingHeartbeat
+}
+
+func newDroneAgent(sim *simulation, api *apiClient, rng *rand.Rand, start model.GeoPoint) *droneAgent {
+	return &droneAgent{
+		sim:  sim,
+		api:  api,
+		rng:  rng,
+		wake: make(chan struct{}, 1),
+		pos:  start,
+		seen: map[int64]bool{},
+	}
+}
+
+// run keeps the drone connected and flying until ctx is done.
+func (d *droneAgent) run(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		d.heartbeats(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		d.fly(ctx)
+	}()
+	defer wg.Wait()
+
+	for first := true; ctx.Err() == nil; first = false {
+		if !first {
+			d.sim.stats.count(countReconnects)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(reconnectDelay):
+			}
+		}
+
+		conn, err := d.connect(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("%s could not connect: %v", d.api.name, err)
+			}
+			continue
+		}
+		d.read(ctx, conn)
+	}
+}
+
+func (d *droneAgent) connect(ctx context.Context) (*websocket.Conn, error) {
+	url := "ws" + strings.TrimPrefix(d.api.baseURL, "http") + "/ws/heartbeat"
+	header := http.Header{"Authorization": []string{d.api.bearer()}}
+
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, url, header)
+	if err != nil && resp != nil && resp.StatusCode == http.StatusUnauthorized {
+		if err := d.api.login(ctx); err != nil {
+			return nil, err
+		}
+		header.Set("Authorization", d.api.bearer())
+		conn, _, err = websocket.DefaultDialer.DialContext(ctx, url, header)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.writeMu.Lock()
+	d.conn = conn
+	d.writeMu.Unlock()
+
+	d.mu.Lock()
+	lastSeq := d.lastSeq
+	d.mu.Unlock()
+	if lastSeq > 0 {
+		_ = d.send(outboundMessage{Type: "resume", LastSeq: &lastSeq})
+	}
+	d.heartbeat()
+	return conn, nil
+}
+
+// read handles server messages until the connection drops.
+func (d *droneAgent) read(ctx context.Context, conn *websocket.Conn) {
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+	defer func() {
+		d.writeMu.Lock()
+		d.conn = nil
+		d.writeMu.Unlock()
+		_ = conn.Close()
+		d.dropPendingHeartbeats()
+	}()
+
+	for {
+		var msg inboundMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			if ctx.Err() == nil && !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				log.Printf("%s websocket dropped: %v", d.api.name, err)
+			}
+			return
+		}
+
+		if msg.Seq != nil && !d.fresh(*msg.Seq) {
+			// replayed after a resume and already handled
+			_ = d.send(outboundMessage{Type: "ack", Seq: msg.Seq})
+			continue
+		}
+
+		switch msg.Type {
+		case "heartbeat":
+			d.heartbeatAnswered(msg)
+		case "resume":
+			d.mu.Lock()
+			// a lower last_seq means a fresh session: numbering starts over
+			if msg.LastSeq < d.lastSeq {
+				d.lastSeq = msg.LastSeq
+				d.seen = map[int64]bool{}
+			}
+			d.mu.Unlock()
+		case "assignment":
+			go d.takeOffer(ctx, msg)
+		case "order_update":
+			d.orderUpdated(msg)
+		case "command":
+			_ = d.send(commandAckMessage{
+				Type:      "command_ack",
+				CommandID: msg.CommandID,
+				Status:    "rejected",
+				Note:      "simulated drones do not execute commands",
+			})
+		}
+
+		if msg.Seq != nil {
+			_ = d.send(outboundMessage{Type: "ack", Seq: msg.Seq})
+		}
+	}
+}
+
+// fresh records seq as handled, reporting whether it was new. Replays can
+// arrive out of order, so every seq is remembered, not just the highest.
+func (d *droneAgent) fresh(seq int64) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.seen[seq] {
+		return false
+	}
+	d.seen[seq] = true
+	d.lastSeq = max(d.lastSeq, seq)
+	return true
+}
+
+func (d *droneAgent) send(v interface{}) error {
+	d.writeMu.Lock()
+	defer d.writeMu.Unlock()
+
+	if d.conn == nil {
+		return errors.New("not connected")
+	}
+	return d.conn.WriteJSON(v)
+}
+
+func (d *droneAgent) heartbeats(ctx context.Context) {
+	ticker := time.NewTicker(d.sim.cfg.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.heartbeat()
+		}
+	}
+}
+
+// heartbeat reports the drone's position; the returned channel is closed
+// once the server has answered, or the connection dropped.
+func (d *droneAgent) heartbeat() <-chan struct{} {
+	done := make(chan struct{})
+
+	d.mu.Lock()
+	pos, speed := d.pos, d.speed
+	d.mu.Unlock()
+
+	d.writeMu.Lock()
+	defer d.writeMu.Unlock()
+
+	if d.conn == nil {
+		close(done)
+		return done
+	}
+
+	sentAt := time.Now()
+	deviceTime := sentAt.UTC().Truncate(time.Millisecond)
+	if !deviceTime.After(d.lastDeviceTime) {
+		deviceTime = d.lastDeviceTime.Add(time.Millisecond)
+	}
+	d.lastDeviceTime = deviceTime
+
+	d.mu.Lock()
+	d.pending = append(d.pending, pendingHeartbeat{sentAt: sentAt, done: done})
+	d.mu.Unlock()
+
+	err := d.conn.WriteJSON(heartbeatMessage{
+		Type:       "heartbeat",
+		Lat:        pos.Lat,
+		Lng:        pos.Lng,
+		SpeedMPS:   speed,
+		GPSFix:     "3d",
+		DeviceTime: deviceTime,
+	})
+	if err != nil {
+		// the read loop sees the broken connection and drops the pending
+		// heartbeats, this one included
+		log.Printf("%s heartbeat not sent: %v", d.api.name, err)
+	}
+	return done
+}
+
+func (d *droneAgent) heartbeatAnswered(msg inboundMessage) {
+	d.mu.Lock()
+	if len(d.pending) == 0 {
+		d.mu.Unlock()
+		return
+	}
+	hb := d.pending[0]
+	d.pending = d.pending[1:]
+	d.mu.Unlock()
+
+	if msg.Error != "" {
+		log.Printf("%s heartbeat rejected: %s", d.api.name, msg.Error)
+	}
+	d.sim.stats.observe(seriesHeartbeatRTT, time.Since(hb.sentAt), msg.Error != "")
+	close(hb.done)
+}
+
+func (d *droneAgent) dropPendingHeartbeats() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, hb := range d.pending {
+		close(hb.done)
+	}
+	d.pending = nil
+}
+
+// takeOffer accepts the offer and reserves the order, unless the drone is
+// broken or already has it.
+func (d *droneAgent) takeOffer(ctx context.Context, msg inboundMessage) {
+	d.sim.stats.count(countOffers)
+	if wait, ok := d.sim.book.firstOffer(msg.OrderID, time.Now()); ok {
+		d.sim.stats.observe(seriesDispatch, wait, false)
+	}
+
+	d.mu.Lock()
+	broken, taken := d.broken, d.findJob(msg.OrderID) != nil
+	d.mu.Unlock()
+	if taken {
+		return
+	}
+	if broken {
+		d.sim.stats.count(countDeclined)
+		_ = d.send(outboundMessage{Type: "assignment_ack", OrderID: msg.OrderID, Status: "declined", Note: "drone is broken"})
+		return
+	}
+	_ = d.send(outboundMessage{Type: "assignment_ack", OrderID: msg.OrderID, Status: "accepted"})
+
+	path := fmt.Sprintf("/orders/%d/reserve", msg.OrderID)
+	if err := d.api.call(ctx, http.MethodPost, path, "POST /orders/{id}/reserve", nil, nil); err != nil {
+		if ctx.Err() == nil {
+			log.Printf("%s could not reserve order %d: %v", d.api.name, msg.OrderID, err)
+		}
+		return
+	}
+	d.sim.stats.count(countReserved)
+	if msg.Description != "" && msg.Description != "new_order" {
+		d.sim.stats.count(countHandoffs)
+	}
+
+	j := &job{
+		orderID: msg.OrderID,
+		pickup:  offeredPickup(msg),
+		dropoff: model.GeoPoint{Lat: msg.DropoffLat, Lng: msg.DropoffLng},
+	}
+	if msg.Description == assignmentReturn && msg.ReturnLat != nil && msg.ReturnLng != nil {
+		j.returnTo = &model.GeoPoint{Lat: *msg.ReturnLat, Lng: *msg.ReturnLng}
+	}
+
+	d.mu.Lock()
+	// a breakdown while reserving released the order on the server
+	if !d.broken {
+		d.jobs = append(d.jobs, j)
+	}
+	d.mu.Unlock()
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// offeredPickup is where to collect the parcel: the handoff point for
+// handoffs, the pickup otherwise.
+func offeredPickup(msg inboundMessage) model.GeoPoint {
+	if msg.HandoffLat != nil && msg.HandoffLng != nil {
+		return model.GeoPoint{Lat: *msg.HandoffLat, Lng: *msg.HandoffLng}
+	}
+	return model.GeoPoint{Lat: msg.PickupLat, Lng: msg.PickupLng}
+}
+
+func (d *droneAgent) orderUpdated(msg inboundMessage) {
+	d.mu.Lock()
+	if j := d.findJob(msg.OrderID); j != nil {
+		switch msg.Event {
+		case "order_canceled":
+			j.canceled = true
+		case "route_updated":
+			j.pickup = model.GeoPoint{Lat: msg.PickupLat, Lng: msg.PickupLng}
+			j.dropoff = model.GeoPoint{Lat: msg.DropoffLat, Lng: msg.DropoffLng}
+		}
+	}
+	d.mu.Unlock()
+
+	if msg.Event == "order_canceled" {
+		if _, ok := d.sim.book.finish(msg.OrderID, time.Now()); ok {
+			d.sim.stats.count(countCanceled)
+		}
+	}
+
+	_ = d.send(outboundMessage{Type: "order_update_ack", OrderID: msg.OrderID, Event: msg.Event, Status: "accepted"})
+}
+
+// findJob must be called with d.mu held.
+func (d *droneAgent) findJob(orderID int64) *job {
+	for _, j := range d.jobs {
+		if j.orderID == orderID {
+			return j
+		}
+	}
+	return nil
+}
+
+func (d *droneAgent) removeJob(j *job) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i, other := range d.jobs {
+		if other == j {
+			d.jobs = append(d.jobs[:i], d.jobs[i+1:]...)
+			return
+		}
+	}
+}
+
+// fly works through the jobs until ctx is done.
+func (d *droneAgent) fly(ctx context.Context) {
+	for {
+		d.mu.Lock()
+		var next *job
+		if len(d.jobs) > 0 {
+			next = d.jobs[0]
+		}
+		d.mu.Unlock()
+
+		if next == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-d.wake:
+			}
+			continue
+		}
+
+		d.work(ctx, next)
+		d.removeJob(next)
+	}
+}
+
+// work flies one order: to the pickup, then to the dropoff to deliver or,
+// now and then, fail the delivery and bring the parcel back. A breakdown
+// may strike on either leg.
+func (d *droneAgent) work(ctx context.Context, j *job) {
+	cfg := d.sim.cfg
+	breakOn, breakAt := leg(-1), 0.0
+	if d.rng.Float64() < cfg.breakdownRate {
+		breakOn, breakAt = leg(d.rng.Intn(2)), d.rng.Float64()
+	}
+
+	if !d.flyLeg(ctx, j, legPickup, breakOn == legPickup, breakAt) {
+		return
+	}
+	if !d.act(ctx, j.orderID, "pickup", nil, nil) {
+		return
+	}
+	d.sim.stats.count(countPickedUp)
+
+	if j.returnTo != nil {
+		d.bringBack(ctx, j)
+		return
+	}
+
+	if !d.flyLeg(ctx, j, legDropoff, breakOn == legDropoff, breakAt) {
+		return
+	}
+
+	pin, known := d.sim.book.pin(j.orderID)
+	if !known || d.rng.Float64() < cfg.failRate {
+		d.failDelivery(ctx, j)
+		return
+	}
+
+	// the server checks the drone's last reported position against the
+	// dropoff, so report the arrival first
+	select {
+	case <-d.heartbeat():
+	case <-time.After(arrivalTimeout):
+	case <-ctx.Done():
+		return
+	}
+	if !d.act(ctx, j.orderID, "deliver", deliverRequest{PIN: pin}, nil) {
+		return
+	}
+	d.sim.stats.count(countDelivered)
+	if age, ok := d.sim.book.finish(j.orderID, time.Now()); ok {
+		d.sim.stats.observe(seriesDelivery, age, false)
+	}
+}
+
+func (d *droneAgent) failDelivery(ctx context.Context, j *job) {
+	var resp orderResponse
+	if !d.act(ctx, j.orderID, "fail", nil, &resp) {
+		return
+	}
+	d.sim.stats.count(countFailed)
+
+	if resp.ReturnLat == nil || resp.ReturnLng == nil {
+		return
+	}
+	d.mu.Lock()
+	j.returnTo = &model.GeoPoint{Lat: *resp.ReturnLat, Lng: *resp.ReturnLng}
+	d.mu.Unlock()
+	d.bringBack(ctx, j)
+}
+
+func (d *droneAgent) bringBack(ctx context.Context, j *job) {
+	if !d.flyLeg(ctx, j, legReturn, false, 0) {
+		return
+	}
+	if !d.act(ctx, j.orderID, "return", nil, nil) {
+		return
+	}
+	d.sim.stats.count(countReturned)
+	d.sim.book.finish(j.orderID, time.Now())
+}
+
+// act calls POST /orders/{id}/{action} for the job's order.
+func (d *droneAgent) act(ctx context.Context, orderID int64, action string, body, out interface{}) bool {
+	path := fmt.Sprintf("/orders/%d/%s", orderID, action)
+	route := "POST /orders/{id}/" + action
+	if err := d.api.call(ctx, http.MethodPost, path, route, body, out); err != nil {
+		if ctx.Err() == nil {
+			log.Printf("%s could not %s order %d: %v", d.api.name, action, orderID, err)
+		}
+		return false
+	}
+	return true
+}
+
+// flyLeg moves the drone in a straight line to the leg's end at the
+// configured speed, reporting whether it got there. It gives up when the
+// order is canceled and, when breakDown is set, breaks down after flying
+// breakAt of the leg.
+func (d *droneAgent) flyLeg(ctx context.Context, j *job, l leg, breakDown bool, breakAt float64) bool {
+	ticker := time.NewTicker(flightStep)
+	defer ticker.Stop()
+	defer d.setSpeed(0)
+
+	speed := d.sim.cfg.speed
+	stepKm := speed * flightStep.Seconds() / 1000
+	flownKm := 0.0
+	legKm := -1.0
+
+	for {
+		d.mu.Lock()
+		target := j.target(l)
+		canceled := j.canceled
+		pos := d.pos
+		d.mu.Unlock()
+
+		if canceled {
+			return false
+		}
+
+		remainingKm := model.Airspace{}.DistanceKm(pos.Lat, pos.Lng, target.Lat, target.Lng)
+		if legKm < 0 {
+			legKm = remainingKm
+		}
+		if breakDown && flownKm >= breakAt*legKm {
+			d.breakDown(ctx)
+			return false
+		}
+		if remainingKm == 0 {
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+
+		next := target
+		if remainingKm > stepKm {
+			f := stepKm / remainingKm
+			next = model.GeoPoint{Lat: pos.Lat + f*(target.Lat-pos.Lat), Lng: pos.Lng + f*(target.Lng-pos.Lng)}
+		}
+		flownKm += min(stepKm, remainingKm)
+
+		d.mu.Lock()
+		d.pos = next
+		d.speed = speed
+		d.mu.Unlock()
+	}
+}
+
+func (j *job) target(l leg) model.GeoPoint {
+	switch l {
+	case legPickup:
+		return j.pickup
+	case legReturn:
+		return *j.returnTo
+	}
+	return j.dropoff
+}
+
+func (d *droneAgent) setSpeed(speed float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.speed = speed
+}
+
+// breakDown reports the drone broken where it is, which hands its orders
+// back to dispatch, waits for the repair and reports it fixed.
+func (d *droneAgent) breakDown(ctx context.Context) {
+	d.mu.Lock()
+	d.broken = true
+	d.jobs = nil
+	d.speed = 0
+	at := locationRequest{Lat: d.pos.Lat, Lng: d.pos.Lng}
+	d.mu.Unlock()
+
+	defer func() {
+		d.mu.Lock()
+		d.broken = false
+		d.mu.Unlock()
+	}()
+
+	id := d.api.id()
+	if err := d.api.call(ctx, http.MethodPost, fmt.Sprintf("/drones/%d/broken", id), "POST /drones/{id}/broken", at, nil); err != nil {
+		if ctx.Err() == nil {
+			log.Printf("%s could not report a breakdown: %v", d.api.name, err)
+		}
+		return
+	}
+	d.sim.stats.count(countBreakdowns)
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(d.sim.cfg.fixAfter):
+	}
+
+	if err := d.api.call(ctx, http.MethodPost, fmt.Sprintf("/drones/%d/fixed", id), "POST /drones/{id}/fixed", at, nil); err != nil {
+		if ctx.Err() == nil {
+			log.Printf("%s could not report the repair: %v", d.api.name, err)
+		}
+		return
+	}
+	d.sim.stats.count(countRepairs)
+}
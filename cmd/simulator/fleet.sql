@@ -0,0 +1,20 @@
+-- Simulator fleet: drone1..drone50 and enduser1..enduser20, all with the
+-- password "password". Not a migration; apply it by hand before large runs:
+--
+--   docker compose exec -T db sh -c 'mysql -u"$MYSQL_USER" -p"$MYSQL_PASSWORD" "$MYSQL_DATABASE"' < cmd/simulator/fleet.sql
+--
+-- Existing accounts are left alone, so it is safe to apply more than once.
+
+INSERT IGNORE INTO users (name, password_hash, type)
+WITH RECURSIVE seq (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM seq WHERE n < 50)
+SELECT CONCAT('drone', n), '$2a$10$pwF9DODNqZ.QVgGaMwU3keqWVIvlT02TNWjoUwt21xaJwyyVy66jy', 'drone' FROM seq;
+
+INSERT IGNORE INTO users (name, password_hash, type)
+WITH RECURSIVE seq (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM seq WHERE n < 20)
+SELECT CONCAT('enduser', n), '$2a$10$pwF9DODNqZ.QVgGaMwU3keqWVIvlT02TNWjoUwt21xaJwyyVy66jy', 'enduser' FROM seq;
+
+-- New drones start idle at 0,0 until their first heartbeat, like the seeded ones
+INSERT IGNORE INTO drone_status (drone_id, status, current_order_id, lat, lng, location)
+SELECT id, 'idle', NULL, 0.0, 0.0, ST_SRID(POINT(0.0, 0.0), 4326)
+FROM users
+WHERE type = 'drone';
@@ -0,0 +1,219 @@
+// Command simulator drives a simulated fleet against a running API: drones
+// log in, hold /ws/heartbeat connections, fly straight-line legs, take every
+// offer and work orders through reserve, pickup and deliver, while endusers
+// place orders at random. Breakdowns and failed deliveries are injected at
+// random. At the end it reports throughput and latency.
+//
+//	go run ./cmd/simulator -drones 2 -endusers 2 -order-rate 0.2 -duration 2m
+//
+// Only drone1, drone2, enduser1 and enduser2 are seeded; cmd/simulator/fleet.sql
+// adds drone1..drone50 and enduser1..enduser20 for larger runs.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+// maxReportedSpeedMPS is the highest ground speed a heartbeat may carry.
+const maxReportedSpeedMPS = 100
+
+type config struct {
+	baseURL       string
+	drones        int
+	endusers      int
+	dronePrefix   string
+	enduserPrefix string
+	password      string
+	duration      time.Duration
+	drain         time.Duration
+	orderRate     float64
+	speed         float64
+	heartbeat     time.Duration
+	radiusKm      float64
+	breakdownRate float64
+	fixAfter      time.Duration
+	failRate      float64
+	progress      time.Duration
+}
+
+// simulation is what every agent shares.
+type simulation struct {
+	cfg    config
+	center model.GeoPoint
+	stats  *stats
+	book   *orderBook
+}
+
+func main() {
+	var cfg config
+	flag.StringVar(&cfg.baseURL, "base-url", "http://localhost:8080", "API base URL")
+	flag.IntVar(&cfg.drones, "drones", 2, "drones to fly, logged in as <drone-prefix>1..N")
+	flag.IntVar(&cfg.endusers, "endusers", 2, "endusers placing orders, logged in as <enduser-prefix>1..M")
+	flag.StringVar(&cfg.dronePrefix, "drone-prefix", "drone", "drone account name prefix")
+	flag.StringVar(&cfg.enduserPrefix, "enduser-prefix", "enduser", "enduser account name prefix")
+	flag.StringVar(&cfg.password, "password", "password", "password of every simulated account")
+	flag.DurationVar(&cfg.duration, "duration", 2*time.Minute, "how long endusers keep placing orders")
+	flag.DurationVar(&cfg.drain, "drain", time.Minute, "how long to wait afterwards for open orders to finish")
+	flag.Float64Var(&cfg.orderRate, "order-rate", 0.2, "orders placed per second across all endusers")
+	flag.Float64Var(&cfg.speed, "speed", model.DroneCruiseSpeedMPS, "drone ground speed in m/s")
+	flag.DurationVar(&cfg.heartbeat, "heartbeat", time.Second, "heartbeat interval")
+	flag.Float64Var(&cfg.radiusKm, "radius-km", 2, "radius of the area orders and drones start in")
+	flag.Float64Var(&cfg.breakdownRate, "breakdown-rate", 0.05, "chance a drone breaks down while flying an order")
+	flag.DurationVar(&cfg.fixAfter, "fix-after", 30*time.Second, "how long a broken drone waits before it is fixed")
+	flag.Float64Var(&cfg.failRate, "fail-rate", 0.05, "chance a delivery fails at the dropoff and the parcel is returned")
+	flag.DurationVar(&cfg.progress, "progress", 10*time.Second, "progress log interval (0 = off)")
+	lat := flag.Float64("lat", 31.9539, "service area center latitude")
+	lng := flag.Float64("lng", 35.9106, "service area center longitude")
+	seed := flag.Int64("seed", 1, "random seed")
+	flag.Parse()
+
+	if err := cfg.validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	sim := &simulation{
+		cfg:    cfg,
+		center: model.GeoPoint{Lat: *lat, Lng: *lng},
+		stats:  newStats(),
+		book:   newOrderBook(),
+	}
+	httpClient := &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &http.Transport{MaxIdleConnsPerHost: cfg.drones + cfg.endusers},
+	}
+
+	drones := clients(cfg.baseURL, cfg.dronePrefix, cfg.drones, cfg.password, httpClient, sim.stats)
+	endusers := clients(cfg.baseURL, cfg.enduserPrefix, cfg.endusers, cfg.password, httpClient, sim.stats)
+	if err := loginAll(ctx, append(drones, endusers...)); err != nil {
+		log.Fatalf("%v (larger fleets need cmd/simulator/fleet.sql applied)", err)
+	}
+
+	rng := rand.New(rand.NewSource(*seed))
+	fleetCtx, stopFleet := context.WithCancel(ctx)
+	defer stopFleet()
+
+	var fleet sync.WaitGroup
+	for _, api := range drones {
+		agent := newDroneAgent(sim, api, rand.New(rand.NewSource(rng.Int63())), randomPoint(rng, sim.center, cfg.radiusKm))
+		fleet.Add(1)
+		go func() {
+			defer fleet.Done()
+			agent.run(fleetCtx)
+		}()
+	}
+
+	start := time.Now()
+	log.Printf("simulating %d drones and %d endusers against %s for %s", cfg.drones, cfg.endusers, cfg.baseURL, cfg.duration)
+	if cfg.progress > 0 {
+		go sim.logProgress(fleetCtx, start)
+	}
+
+	orderCtx, stopOrders := context.WithTimeout(ctx, cfg.duration)
+	sim.placeOrders(orderCtx, endusers, rng)
+	stopOrders()
+
+	sim.drain(ctx)
+	stopFleet()
+	fleet.Wait()
+
+	sim.stats.report(os.Stdout, time.Since(start))
+	fmt.Printf("\n%d orders still open\n", sim.book.open())
+}
+
+func (c config) validate() error {
+	switch {
+	case c.drones < 1 || c.endusers < 1:
+		return fmt.Errorf("drones and endusers must be positive")
+	case c.duration <= 0 || c.drain < 0 || c.heartbeat <= 0 || c.fixAfter < 0:
+		return fmt.Errorf("duration and heartbeat must be positive, drain and fix-after not negative")
+	case c.orderRate <= 0 || c.radiusKm <= 0:
+		return fmt.Errorf("order-rate and radius-km must be positive")
+	case c.speed <= 0 || c.speed > maxReportedSpeedMPS:
+		return fmt.Errorf("speed must be in (0, %d] m/s", maxReportedSpeedMPS)
+	case c.breakdownRate < 0 || c.breakdownRate > 1 || c.failRate < 0 || c.failRate > 1:
+		return fmt.Errorf("breakdown-rate and fail-rate must be between 0 and 1")
+	}
+	return nil
+}
+
+func clients(baseURL, prefix string, n int, password string, httpClient *http.Client, st *stats) []*apiClient {
+	out := make([]*apiClient, n)
+	for i := range out {
+		out[i] = newAPIClient(baseURL, fmt.Sprintf("%s%d", prefix, i+1), password, httpClient, st)
+	}
+	return out
+}
+
+func loginAll(ctx context.Context, all []*apiClient) error {
+	errs := make([]error, len(all))
+	var wg sync.WaitGroup
+	for i, c := range all {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = c.login(ctx)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drain lets the fleet finish the orders already placed, for up to the
+// drain period.
+func (s *simulation) drain(ctx context.Context) {
+	if s.cfg.drain == 0 || s.book.open() == 0 {
+		return
+	}
+	log.Printf("waiting up to %s for %d open orders", s.cfg.drain, s.book.open())
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	deadline := time.After(s.cfg.drain)
+
+	for s.book.open() > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *simulation) logProgress(ctx context.Context, start time.Time) {
+	ticker := time.NewTicker(s.cfg.progress)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		log.Printf("%s: %d orders placed, %d delivered, %d open, %d breakdowns",
+			time.Since(start).Round(time.Second), s.stats.counter(countOrdersCreated),
+			s.stats.counter(countDelivered), s.book.open(), s.stats.counter(countBreakdowns))
+	}
+}
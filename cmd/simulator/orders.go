@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const kmPerDegreeLat = 111.32
+
+type orderRecord struct {
+	pin       string
+	createdAt time.Time
+	offered   bool
+	finished  bool
+}
+
+// orderBook remembers the orders the simulated endusers placed, so drones
+// can prove delivery with the PIN the enduser would hand over and the run
+// can time each order end to end.
+type orderBook struct {
+	mu     sync.Mutex
+	orders map[int64]*orderRecord
+	// earlyOffers holds offers that beat the create response to the
+	// simulator, by order ID
+	earlyOffers map[int64]time.Time
+}
+
+func newOrderBook() *orderBook {
+	return &orderBook{orders: map[int64]*orderRecord{}, earlyOffers: map[int64]time.Time{}}
+}
+
+// add records a placed order. When its first offer already arrived, the
+// dispatch latency is reported here instead of by firstOffer.
+func (b *orderBook) add(orderID int64, pin string, createdAt time.Time) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	rec := &orderRecord{pin: pin, createdAt: createdAt}
+	b.orders[orderID] = rec
+
+	offeredAt, ok := b.earlyOffers[orderID]
+	if !ok {
+		return 0, false
+	}
+	delete(b.earlyOffers, orderID)
+	rec.offered = true
+	return offeredAt.Sub(createdAt), true
+}
+
+// firstOffer reports how long the order waited for its first offer; later
+// offers (handoffs, lapsed offers) are not dispatch latency.
+func (b *orderBook) firstOffer(orderID int64, at time.Time) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	rec, ok := b.orders[orderID]
+	if !ok {
+		if _, seen := b.earlyOffers[orderID]; !seen {
+			b.earlyOffers[orderID] = at
+		}
+		return 0, false
+	}
+	if rec.offered {
+		return 0, false
+	}
+	rec.offered = true
+	return at.Sub(rec.createdAt), true
+}
+
+func (b *orderBook) pin(orderID int64) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	rec, ok := b.orders[orderID]
+	if !ok {
+		return "", false
+	}
+	return rec.pin, true
+}
+
+// finish closes the order and reports its age.
+func (b *orderBook) finish(orderID int64, at time.Time) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	rec, ok := b.orders[orderID]
+	if !ok || rec.finished {
+		return 0, false
+	}
+	rec.finished = true
+	return at.Sub(rec.createdAt), true
+}
+
+// open counts placed orders not yet delivered, returned or canceled.
+func (b *orderBook) open() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n := 0
+	for _, rec := range b.orders {
+		if !rec.finished {
+			n++
+		}
+	}
+	return n
+}
+
+type createOrderRequest struct {
+	PickupLat  float64 `json:"pickup_lat"`
+	PickupLng  float64 `json:"pickup_lng"`
+	DropoffLat float64 `json:"dropoff_lat"`
+	DropoffLng float64 `json:"dropoff_lng"`
+}
+
+type orderResponse struct {
+	OrderID     int64    `json:"order_id"`
+	Status      string   `json:"status"`
+	ReturnLat   *float64 `json:"return_lat"`
+	ReturnLng   *float64 `json:"return_lng"`
+	DeliveryPIN *string  `json:"delivery_pin"`
+}
+
+// placeOrders has random endusers place orders between random points of the
+// service area, arriving as a Poisson process at rate per second, until ctx
+// is done.
+func (s *simulation) placeOrders(ctx context.Context, endusers []*apiClient, rng *rand.Rand) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		wait := time.Duration(rng.ExpFloat64() / s.cfg.orderRate * float64(time.Second))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		enduser := endusers[rng.Intn(len(endusers))]
+		pickup := randomPoint(rng, s.center, s.cfg.radiusKm)
+		dropoff := randomPoint(rng, s.center, s.cfg.radiusKm)
+
+		// an order already sent is seen through even when placing stops
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.placeOrder(context.WithoutCancel(ctx), enduser, pickup, dropoff)
+		}()
+	}
+}
+
+func (s *simulation) placeOrder(ctx context.Context, enduser *apiClient, pickup, dropoff model.GeoPoint) {
+	req := createOrderRequest{
+		PickupLat:  pickup.Lat,
+		PickupLng:  pickup.Lng,
+		DropoffLat: dropoff.Lat,
+		DropoffLng: dropoff.Lng,
+	}
+
+	// the order may be offered before the response arrives, so the clock
+	// starts when the request is sent
+	sentAt := time.Now()
+	var resp orderResponse
+	if err := enduser.call(ctx, http.MethodPost, "/orders", "POST /orders", req, &resp); err != nil {
+		if ctx.Err() == nil {
+			log.Printf("%s could not place an order: %v", enduser.name, err)
+		}
+		return
+	}
+	if resp.DeliveryPIN == nil {
+		log.Printf("order %d came back without a delivery PIN", resp.OrderID)
+		return
+	}
+
+	if wait, ok := s.book.add(resp.OrderID, *resp.DeliveryPIN, sentAt); ok {
+		s.stats.observe(seriesDispatch, wait, false)
+	}
+	s.stats.count(countOrdersCreated)
+}
+
+// randomPoint is uniform over the disc of radiusKm around center.
+func randomPoint(rng *rand.Rand, center model.GeoPoint, radiusKm float64) model.GeoPoint {
+	r := radiusKm * math.Sqrt(rng.Float64())
+	theta := 2 * math.Pi * rng.Float64()
+	dLat := r * math.Sin(theta) / kmPerDegreeLat
+	dLng := r * math.Cos(theta) / (kmPerDegreeLat * math.Cos(center.Lat*math.Pi/180))
+	return model.GeoPoint{Lat: center.Lat + dLat, Lng: center.Lng + dLng}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Counters reported at the end of a run, in this order.
+const (
+	countOrdersCreated = "orders created"
+	countOffers        = "offers received"
+	countDeclined      = "offers declined"
+	countReserved      = "orders reserved"
+	countPickedUp      = "parcels picked up"
+	countDelivered     = "orders delivered"
+	countFailed        = "deliveries failed"
+	countReturned      = "parcels returned"
+	countCanceled      = "orders canceled"
+	countBreakdowns    = "breakdowns"
+	countRepairs       = "repairs"
+	countHandoffs      = "handoffs taken over"
+	countReconnects    = "websocket reconnects"
+)
+
+var counterOrder = []string{
+	countOrdersCreated, countOffers, countDeclined, countReserved, countPickedUp,
+	countDelivered, countFailed, countReturned, countCanceled, countBreakdowns,
+	countRepairs, countHandoffs, countReconnects,
+}
+
+// Latency series that are not HTTP calls.
+const (
+	seriesHeartbeatRTT = "ws heartbeat round trip"
+	seriesDispatch     = "order created → first offer"
+	seriesDelivery     = "order created → delivered"
+)
+
+type series struct {
+	samples []time.Duration
+	errors  int
+}
+
+// stats collects everything the simulated fleet observes. It is shared by
+// every agent.
+type stats struct {
+	mu       sync.Mutex
+	series   map[string]*series
+	counters map[string]int
+}
+
+func newStats() *stats {
+	return &stats{series: map[string]*series{}, counters: map[string]int{}}
+}
+
+// observe records one sample of op; failed samples only count as errors.
+func (s *stats) observe(op string, d time.Duration, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ser, ok := s.series[op]
+	if !ok {
+		ser = &series{}
+		s.series[op] = ser
+	}
+	if failed {
+		ser.errors++
+		return
+	}
+	ser.samples = append(ser.samples, d)
+}
+
+func (s *stats) count(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counters[name]++
+}
+
+func (s *stats) counter(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.counters[name]
+}
+
+func (s *stats) report(w io.Writer, elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	minutes := elapsed.Minutes()
+	fmt.Fprintf(w, "\nran for %s\n\n", elapsed.Round(time.Second))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "event\tcount\tper minute\t")
+	for _, name := range counterOrder {
+		n := s.counters[name]
+		fmt.Fprintf(tw, "%s\t%d\t%.1f\t\n", name, n, float64(n)/minutes)
+	}
+	tw.Flush()
+
+	ops := make([]string, 0, len(s.series))
+	for op := range s.series {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "latency\tok\terrors\tp50\tp95\tp99\tmax\t")
+	for _, op := range ops {
+		ser := s.series[op]
+		sorted := slices.Clone(ser.samples)
+		slices.Sort(sorted)
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t\n", op, len(sorted), ser.errors,
+			percentile(sorted, 0.50), percentile(sorted, 0.95), percentile(sorted, 0.99), percentile(sorted, 1))
+	}
+	tw.Flush()
+}
+
+// percentile is the nearest-rank percentile of sorted samples.
+func percentile(sorted []time.Duration, p float64) string {
+	if len(sorted) == 0 {
+		return "-"
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	d := sorted[max(rank, 0)]
+	switch {
+	case d >= time.Minute:
+		return d.Round(time.Second).String()
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond).String()
+	}
+	return d.Round(100 * time.Microsecond).String()
+}
@@ -15,14 +15,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/admin/drones": {
+        "/addresses": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a paginated list of all drones with their status",
+                "description": "Get the caller's address book, ordered by label",
                 "consumes": [
                     "application/json"
                 ],
@@ -30,9 +30,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin"
+                    "addresses"
                 ],
-                "summary": "List all drones (Admin action)",
+                "summary": "List saved addresses",
                 "parameters": [
                     {
                         "type": "integer",
@@ -42,16 +42,16 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Page size (default: 10)",
+                        "description": "Page size (default: 20)",
                         "name": "page_size",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of drones",
+                        "description": "Saved addresses",
                         "schema": {
-                            "$ref": "#/definitions/iface.droneListResponse"
+                            "$ref": "#/definitions/iface.addressListResponse"
                         }
                     },
                     "400": {
@@ -73,7 +73,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - Admin only",
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -91,16 +91,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/admin/drones/{id}/fixed": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Admin marks a broken drone as fixed and ready for operation",
+                "description": "Add a labelled location with optional delivery notes to the caller's address book",
                 "consumes": [
                     "application/json"
                 ],
@@ -108,32 +106,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin"
+                    "addresses"
                 ],
-                "summary": "Mark drone as fixed (Admin action)",
+                "summary": "Save an address",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Drone ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Drone location",
-                        "name": "location",
+                        "description": "Address details",
+                        "name": "address",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/iface.droneLocationRequest"
+                            "$ref": "#/definitions/iface.createAddressRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Drone marked as fixed",
+                    "201": {
+                        "description": "Address saved",
                         "schema": {
-                            "$ref": "#/definitions/iface.droneStatusResponse"
+                            "$ref": "#/definitions/iface.addressResponse"
                         }
                     },
                     "400": {
@@ -155,16 +146,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden - Admin only",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "404": {
-                        "description": "Drone not found",
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -184,14 +166,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/orders": {
+        "/addresses/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a paginated list of all orders with optional filters",
                 "consumes": [
                     "application/json"
                 ],
@@ -199,46 +180,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "admin"
+                    "addresses"
                 ],
-                "summary": "List all orders (Admin action)",
+                "summary": "Get a saved address",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page number (default: 1)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page size (default: 10)",
-                        "name": "page_size",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by order status",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter by end user ID",
-                        "name": "enduser_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Filter by assigned drone ID",
-                        "name": "assigned_drone_id",
-                        "in": "query"
+                        "description": "Address ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of orders",
+                        "description": "Address",
                         "schema": {
-                            "$ref": "#/definitions/iface.orderListResponse"
+                            "$ref": "#/definitions/iface.addressResponse"
                         }
                     },
                     "400": {
@@ -260,7 +218,16 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden",
+                        "description": "Address belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Address not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -278,50 +245,30 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/admin/orders/{id}": {
-            "patch": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Admin updates the pickup or dropoff location of an order",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Orders placed from the address keep their copied coordinates and notes",
                 "tags": [
-                    "admin"
+                    "addresses"
                 ],
-                "summary": "Update order route (Admin action)",
+                "summary": "Delete a saved address",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
+                        "description": "Address ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Route update details",
-                        "name": "route",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/iface.updateRouteRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Route updated successfully",
-                        "schema": {
-                            "$ref": "#/definitions/iface.orderResponse"
-                        }
+                    "204": {
+                        "description": "Address deleted"
                     },
                     "400": {
                         "description": "Invalid request",
@@ -342,7 +289,7 @@ const docTemplate = `{
                         }
                     },
                     "403": {
-                        "description": "Forbidden",
+                        "description": "Address belongs to another user",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -351,7 +298,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Order not found",
+                        "description": "Address not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -369,11 +316,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/auth/token": {
-            "post": {
-                "description": "Authenticate a user and return an access token",
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change any of label, coordinates or notes; an empty notes string clears it. Existing orders keep their snapshot.",
                 "consumes": [
                     "application/json"
                 ],
@@ -381,25 +331,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "addresses"
                 ],
-                "summary": "User login",
+                "summary": "Update a saved address",
                 "parameters": [
                     {
-                        "description": "Login credentials",
-                        "name": "credentials",
+                        "type": "integer",
+                        "description": "Address ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "address",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/iface.loginRequest"
+                            "$ref": "#/definitions/iface.updateAddressRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Login successful",
+                        "description": "Address updated",
                         "schema": {
-                            "$ref": "#/definitions/iface.loginResponse"
+                            "$ref": "#/definitions/iface.addressResponse"
                         }
                     },
                     "400": {
@@ -412,7 +369,25 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Invalid credentials",
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Address belongs to another user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Address not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -432,14 +407,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/drones/{id}/broken": {
-            "post": {
+        "/admin/depots": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Report that a drone is broken (can be called by drone or admin)",
+                "description": "Get every depot with its occupancy: occupied counts drones parked, charging or on their way to a pad; charging is the part of them recharging",
                 "consumes": [
                     "application/json"
                 ],
@@ -447,41 +422,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drones"
-                ],
-                "summary": "Report drone as broken",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Drone ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Drone location",
-                        "name": "location",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/iface.droneLocationRequest"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "List depots (admin)",
                 "responses": {
                     "200": {
-                        "description": "Drone marked as broken",
-                        "schema": {
-                            "$ref": "#/definitions/iface.droneStatusResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request",
+                        "description": "Depots",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/iface.depotListResponse"
                         }
                     },
                     "401": {
@@ -493,8 +441,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Drone not found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -512,36 +460,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/health": {
-            "get": {
-                "description": "Check if the API is running",
-                "produces": [
-                    "text/plain"
-                ],
-                "tags": [
-                    "health"
-                ],
-                "summary": "Health check",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/orders": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new delivery order with pickup and dropoff locations",
+                "description": "Register a base with capacity charging pads. Idle drones below RTB_BATTERY_PCT go there to charge, and with RTB_PARK_WHEN_IDLE drones with no waiting order park there after a trip.",
                 "consumes": [
                     "application/json"
                 ],
@@ -549,29 +475,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "admin"
                 ],
-                "summary": "Create a new delivery order",
+                "summary": "Create a depot (admin)",
                 "parameters": [
                     {
-                        "description": "Order details",
-                        "name": "order",
+                        "description": "Depot",
+                        "name": "depot",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/iface.createOrderRequest"
+                            "$ref": "#/definitions/iface.createDepotRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Order created successfully",
+                        "description": "Depot created",
                         "schema": {
-                            "$ref": "#/definitions/iface.orderResponse"
+                            "$ref": "#/definitions/iface.depotResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Invalid request or depot",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -588,6 +514,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -600,39 +535,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/{id}": {
-            "get": {
+        "/admin/depots/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get detailed information about a specific order",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Drones based there lose their home depot and pad",
                 "tags": [
-                    "orders"
+                    "admin"
                 ],
-                "summary": "Get order details",
+                "summary": "Delete a depot (admin)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
+                        "description": "Depot ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Order details",
-                        "schema": {
-                            "$ref": "#/definitions/iface.orderResponse"
-                        }
+                    "204": {
+                        "description": "Depot deleted"
                     },
                     "400": {
                         "description": "Invalid request",
@@ -652,8 +578,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Order not found",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Depot not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -671,16 +606,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/orders/{id}/cancel": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Cancel a pending delivery order",
+                "description": "Rename, move, resize or activate/deactivate a depot. Drones already holding a pad keep it; lowering the capacity only stops new arrivals.",
                 "consumes": [
                     "application/json"
                 ],
@@ -688,27 +621,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "orders"
+                    "admin"
                 ],
-                "summary": "Cancel an order",
+                "summary": "Update a depot (admin)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
+                        "description": "Depot ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "depot",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.updateDepotRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order canceled successfully",
+                        "description": "Depot updated",
                         "schema": {
-                            "$ref": "#/definitions/iface.orderResponse"
+                            "$ref": "#/definitions/iface.depotResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Invalid request or depot",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -725,8 +667,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Order not found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -734,8 +676,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Order cannot be canceled",
+                    "404": {
+                        "description": "Depot not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -755,14 +697,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/{id}/deliver": {
+        "/admin/dispatch/dry-run": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Drone delivers an order to the dropoff location",
+                "description": "Runs the dispatcher without offering anything and returns the assignments it would propose, with per-order ETAs and total distance.\nWithout a scenario it uses the live state the next batch round would see: orders waiting for an offer and drones able to take one.\nA scenario supplies hypothetical orders and drones instead (at most 500 of each). strategy (greedy|batch) and cost (haversine|route) default to the configured ones.\ndistance_km is the dispatch cost to the pickup; delivery distance and ETAs follow the path planned around no-fly zones in effect now.",
                 "consumes": [
                     "application/json"
                 ],
@@ -770,27 +712,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drone-actions"
+                    "admin"
                 ],
-                "summary": "Deliver an order (Drone action)",
+                "summary": "Dry-run dispatch (admin)",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Order ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Strategy, cost and optional scenario",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/iface.dispatchDryRunRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order delivered successfully",
+                        "description": "Proposed assignments",
                         "schema": {
-                            "$ref": "#/definitions/iface.orderResponse"
+                            "$ref": "#/definitions/iface.dispatchPlanResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Invalid request or scenario",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -807,8 +750,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Order not found",
+                    "403": {
+                        "description": "Forbidden - Admin only",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -816,8 +759,77 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Order cannot be delivered",
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/drones": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a paginated list of all drones with their status and usage",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List all drones (Admin action)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default: 10)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of drones",
+                        "schema": {
+                            "$ref": "#/definitions/iface.droneListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -837,28 +849,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/{id}/fail": {
-            "post": {
+        "/admin/drones/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Drone reports that an order delivery has failed",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get a drone's status, latest readings and usage: deliveries, failures, breakdowns, distance flown between heartbeats and time spent delivering",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "drone-actions"
+                    "admin"
                 ],
-                "summary": "Fail an order (Drone action)",
+                "summary": "Get a drone (Admin action)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
+                        "description": "Drone ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -866,13 +875,13 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order marked as failed",
+                        "description": "Drone",
                         "schema": {
-                            "$ref": "#/definitions/iface.orderResponse"
+                            "$ref": "#/definitions/iface.droneStatusResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Invalid drone ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -889,8 +898,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Order not found",
+                    "403": {
+                        "description": "Forbidden - Admin only",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -898,8 +907,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Order cannot be failed",
+                    "404": {
+                        "description": "Drone not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -917,16 +926,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/orders/{id}/pickup": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Drone picks up an order from the pickup location",
+                "description": "Set how many orders a drone may carry on one multi-stop trip",
                 "consumes": [
                     "application/json"
                 ],
@@ -934,23 +941,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drone-actions"
+                    "admin"
                 ],
-                "summary": "Pickup an order (Drone action)",
+                "summary": "Set drone carrying capacity (Admin action)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
+                        "description": "Drone ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Capacity",
+                        "name": "capacity",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.droneCapacityRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order picked up successfully",
+                        "description": "Drone capacity updated",
                         "schema": {
-                            "$ref": "#/definitions/iface.orderResponse"
+                            "$ref": "#/definitions/iface.droneStatusResponse"
                         }
                     },
                     "400": {
@@ -971,8 +987,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Order not found",
+                    "403": {
+                        "description": "Forbidden - Admin only",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -980,8 +996,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Order cannot be picked up",
+                    "404": {
+                        "description": "Drone not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1001,14 +1017,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/{id}/reserve": {
-            "post": {
+        "/admin/drones/{id}/breaches": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Drone reserves a pending order for delivery",
+                "description": "Breaches detected from the drone's heartbeats, newest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -1016,23 +1032,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drone-actions"
+                    "admin"
                 ],
-                "summary": "Reserve an order (Drone action)",
+                "summary": "Geofence breach history for a drone (admin)",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
+                        "description": "Drone ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default: 20)",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order reserved successfully",
+                        "description": "Breaches",
                         "schema": {
-                            "$ref": "#/definitions/iface.orderResponse"
+                            "$ref": "#/definitions/iface.geofenceBreachListResponse"
                         }
                     },
                     "400": {
@@ -1053,8 +1081,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Order not found",
+                    "403": {
+                        "description": "Forbidden - Admin only",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1062,8 +1090,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Order cannot be reserved",
+                    "404": {
+                        "description": "Drone not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1083,14 +1111,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/ws/heartbeat": {
+        "/admin/drones/{id}/commands": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Establishes a WebSocket connection for drones to send heartbeat updates and receive assignments\nThe drone must authenticate with a Bearer token in the query parameter or header.\n\n**Message Types:**\n\n1. **Heartbeat** (Drone → Server):\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"type\": \"heartbeat\",\n\"lat\": 40.7128,\n\"lng\": -74.0060\n}\n` + "`" + `` + "`" + `` + "`" + `\n\n2. **Heartbeat Response** (Server → Drone):\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"type\": \"heartbeat\",\n\"message\": \"heartbeat received\",\n\"timestamp\": \"2025-11-10T12:00:00Z\"\n}\n` + "`" + `` + "`" + `` + "`" + `\n\n3. **Assignment** (Server → Drone):\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"type\": \"assignment\",\n\"drone_id\": 1,\n\"order_id\": 123,\n\"pickup_lat\": 40.7128,\n\"pickup_lng\": -74.0060,\n\"dropoff_lat\": 40.7580,\n\"dropoff_lng\": -73.9855,\n\"enduser_id\": 456,\n\"order_status\": \"reserved\",\n\"created_at\": \"2025-11-10T12:00:00Z\"\n}\n` + "`" + `` + "`" + `` + "`" + `\n\n4. **Assignment Acknowledgment** (Drone → Server):\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"type\": \"assignment_ack\",\n\"order_id\": 123,\n\"status\": \"accepted\"\n}\n` + "`" + `` + "`" + `` + "`" + `",
+                "description": "Commands sent to the drone with their delivery and ack status, newest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -1098,26 +1126,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drone-websocket"
+                    "admin"
                 ],
-                "summary": "WebSocket heartbeat endpoint for drones",
+                "summary": "Command history for a drone (admin)",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Bearer token can also be passed as query parameter",
-                        "name": "Authorization",
+                        "type": "integer",
+                        "description": "Drone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default: 20)",
+                        "name": "page_size",
                         "in": "query"
                     }
                 ],
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols - WebSocket connection established",
+                    "200": {
+                        "description": "Commands",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/iface.droneCommandListResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
+                        "description": "Invalid request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1134,6 +1175,24 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Drone not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -1144,18 +1203,5186 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "iface.createOrderRequest": {
-            "type": "object",
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Push return_to_home (optional lat/lng of the depot), hold_position, land_now, divert or reposition (need lat/lng) or cancel_assignment (needs order_id) over the drone's websocket.\nThe command is stored either way; status is ` + "`" + `sent` + "`" + ` when it reached the drone and ` + "`" + `undelivered` + "`" + ` when the drone was not connected.\nThe drone answers with ` + "`" + `command_ack` + "`" + ` (accepted|rejected) and later ` + "`" + `command_result` + "`" + ` (completed|failed).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Send a command to a drone (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Drone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Command",
+                        "name": "command",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.issueCommandRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Command issued",
+                        "schema": {
+                            "$ref": "#/definitions/iface.droneCommandResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or command",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Drone not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/drones/{id}/commands/{command_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Current delivery and ack status of one command",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get a drone command (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Drone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Command ID",
+                        "name": "command_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Command",
+                        "schema": {
+                            "$ref": "#/definitions/iface.droneCommandResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Command not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/drones/{id}/fixed": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin marks a broken drone as fixed and ready for operation; fixing an idle drone only updates its location, and a drone in maintenance or charging is rejected with 409\nCloses the drone's open maintenance record with the optional notes, parts and technician; without an open record, details are kept as a closed breakdown record",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Mark drone as fixed (Admin action)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Drone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Drone location and repair details",
+                        "name": "location",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.droneFixedRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Drone marked as fixed",
+                        "schema": {
+                            "$ref": "#/definitions/iface.droneStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Drone not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Drone is in maintenance or charging",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/drones/{id}/home-depot": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The drone returns to its home depot when a pad is free there, otherwise to the nearest active depot with a free pad",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set a drone's home depot (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Drone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Home depot",
+                        "name": "depot",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.homeDepotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Drone updated",
+                        "schema": {
+                            "$ref": "#/definitions/iface.droneStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Drone or depot not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The drone returns to the nearest active depot with a free pad",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Clear a drone's home depot (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Drone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Drone updated",
+                        "schema": {
+                            "$ref": "#/definitions/iface.droneStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Drone not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/drones/{id}/maintenance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Flight hours and cycles (trips that ended) since the last closed record, the service interval that applies (0 = never by that measure), whether the drone is due and its open record",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "A drone's maintenance standing (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Drone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Maintenance standing",
+                        "schema": {
+                            "$ref": "#/definitions/iface.droneMaintenanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Drone not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Opens a maintenance record and takes an idle or charging drone out of service (status maintenance, left out of dispatch, depot pad released). A drone on a trip cannot be taken (409); a broken one stays broken. A drone has at most one open record.\nissue is one of breakdown, scheduled, inspection, battery, propulsion, sensors, airframe, other.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Take a drone into maintenance (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Drone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Maintenance record",
+                        "name": "record",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.openMaintenanceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Record opened",
+                        "schema": {
+                            "$ref": "#/definitions/iface.maintenanceRecordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or record",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Drone not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Drone busy or a record already open",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/drones/{id}/maintenance-schedule": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Flight hours and trips between services for this drone. A field left out or null follows MAINTENANCE_FLIGHT_HOURS / MAINTENANCE_CYCLES, 0 never calls for service by that measure; {} puts the drone back on the fleet interval.\nA drone past its interval is taken into maintenance when it next finishes a trip or at the next check (MAINTENANCE_CHECK_INTERVAL).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set a drone's service interval (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Drone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Service interval",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.maintenanceScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Maintenance standing",
+                        "schema": {
+                            "$ref": "#/definitions/iface.droneMaintenanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or schedule",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Drone not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/drones/{id}/track": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Telemetry recorded from the drone's heartbeats as a GeoJSON LineString Feature.\nThe window defaults to the last 24 hours and may span at most 7 days; at most 10000 points are returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Replay a drone's flight track (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Drone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Window start (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Window end (RFC3339 or YYYY-MM-DD), defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Track",
+                        "schema": {
+                            "$ref": "#/definitions/iface.trackFeature"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Drone not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/landing-sites": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every landing site, including inactive ones",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List landing sites (admin)",
+                "responses": {
+                    "200": {
+                        "description": "Landing sites",
+                        "schema": {
+                            "$ref": "#/definitions/iface.landingSiteListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Register a safe place where a drone that breaks down can leave its parcels. With HANDOFF_RENDEZVOUS=nearest_site the nearest active site within HANDOFF_MAX_DETOUR_KM becomes the handoff point.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a landing site (admin)",
+                "parameters": [
+                    {
+                        "description": "Landing site",
+                        "name": "site",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.createLandingSiteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Landing site created",
+                        "schema": {
+                            "$ref": "#/definitions/iface.landingSiteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or landing site",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/landing-sites/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a landing site (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Landing site ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Landing site deleted"
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Landing site not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rename, move, or activate/deactivate a landing site. Parcels already waiting there keep their handoff point.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update a landing site (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Landing site ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "site",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.updateLandingSiteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Landing site updated",
+                        "schema": {
+                            "$ref": "#/definitions/iface.landingSiteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or landing site",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Landing site not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/leader": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The node holding the leader lease, which alone runs singleton background jobs (telemetry maintenance), and whether the answering node is it.\nleader is null while no node holds a live lease, for instance between a leader dying and its lease expiring.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Current background-job leader (admin)",
+                "responses": {
+                    "200": {
+                        "description": "Leader",
+                        "schema": {
+                            "$ref": "#/definitions/iface.leaderStatusResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/maintenance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Maintenance records across the fleet, newest first, optionally for one drone and only open or closed ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List maintenance records (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Drone ID",
+                        "name": "drone_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "true for open records, false for closed ones",
+                        "name": "open",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default: 20)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Maintenance records",
+                        "schema": {
+                            "$ref": "#/definitions/iface.maintenanceRecordListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/maintenance/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get a maintenance record (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Record ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Maintenance record",
+                        "schema": {
+                            "$ref": "#/definitions/iface.maintenanceRecordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Record not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the issue, notes, parts or technician of an open or closed record. An empty parts list clears the parts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update a maintenance record (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Record ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "record",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.maintenanceDetailsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Record updated",
+                        "schema": {
+                            "$ref": "#/definitions/iface.maintenanceRecordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or record",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Record not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/maintenance/{id}/close": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ends the work, with any last details, and puts the drone back in service (idle). The record of a broken drone is closed by marking the drone fixed instead (409 maintenance_drone_broken).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Close a maintenance record (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Record ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Last details",
+                        "name": "record",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/iface.maintenanceDetailsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Record closed",
+                        "schema": {
+                            "$ref": "#/definitions/iface.maintenanceRecordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or record",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Record not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Record already closed or drone broken",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/no-fly-zones": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every no-fly zone, including expired and upcoming ones; ` + "`" + `active` + "`" + ` tells whether it is in effect now",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List no-fly zones (admin)",
+                "responses": {
+                    "200": {
+                        "description": "No-fly zones",
+                        "schema": {
+                            "$ref": "#/definitions/iface.noFlyZoneListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restrict airspace drones must route around; orders with pickup or dropoff inside an active zone are rejected. Omit starts_at/ends_at (RFC3339) for a permanent zone.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a no-fly zone (admin)",
+                "parameters": [
+                    {
+                        "description": "No-fly zone",
+                        "name": "zone",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.createNoFlyZoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "No-fly zone created",
+                        "schema": {
+                            "$ref": "#/definitions/iface.noFlyZoneResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, polygon or time window",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/no-fly-zones/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a no-fly zone (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "No-fly zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No-fly zone deleted"
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No-fly zone not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rename, redraw or reschedule a zone; an empty starts_at/ends_at removes that bound. Existing orders are not re-validated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update a no-fly zone (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "No-fly zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "zone",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.updateNoFlyZoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "No-fly zone updated",
+                        "schema": {
+                            "$ref": "#/definitions/iface.noFlyZoneResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, polygon or time window",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No-fly zone not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a paginated list of all orders with optional filters",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List all orders (Admin action)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default: 10)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by order status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by end user ID",
+                        "name": "enduser_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by assigned drone ID",
+                        "name": "assigned_drone_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or before (RFC3339 or YYYY-MM-DD, inclusive of the whole day)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of orders",
+                        "schema": {
+                            "$ref": "#/definitions/iface.orderListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/{id}": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Admin updates the pickup or dropoff location of an order, as coordinates or a free-text address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update order route (Admin action)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Route update details",
+                        "name": "route",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.updateRouteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Route updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/iface.orderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/orders/{id}/track": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "GeoJSON FeatureCollection with one LineString Feature per trip that carried the order, in flight order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Replay an order's flight track (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tracks",
+                        "schema": {
+                            "$ref": "#/definitions/iface.trackFeatureCollection"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/rebalance/report": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets the wait each rebalance run predicted per cell against the wait orders placed there while the forecast stood actually saw, from creation to pickup. Predictions are weighted by the orders picked up under them.\nCovers the runs between from and to (default: the last 24 hours, at most 31 days).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Predicted vs actual wait (admin)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Window start (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Window end (RFC3339 or YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Wait report",
+                        "schema": {
+                            "$ref": "#/definitions/iface.rebalanceReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/rebalance/run": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Forecasts the coming hour's pickups per cell (REBALANCE_CELL_KM) from the same hour of the day over REBALANCE_LOOKBACK, spreads the idle drones over the cells in proportion and sends aggressiveness (0..1, default REBALANCE_AGGRESSIVENESS) of the drones out of place off with reposition commands.\npredicted_wait_seconds is the flight time of the nearest idle drone to the middle of the cell once the moves are made. A dry run only plans; otherwise the forecasts are kept for the wait report. A move without command_id was skipped because the drone took an order meanwhile.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Rebalance idle drones (admin)",
+                "parameters": [
+                    {
+                        "description": "Aggressiveness and dry run",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/iface.rebalanceRunRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rebalance plan",
+                        "schema": {
+                            "$ref": "#/definitions/iface.rebalancePlanResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/service-areas": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get every service area, including inactive ones",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List all service areas (admin)",
+                "responses": {
+                    "200": {
+                        "description": "Service areas",
+                        "schema": {
+                            "$ref": "#/definitions/iface.serviceAreaListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Draw a polygon the fleet operates in; vertices are given in order and the ring is closed automatically",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a service area (admin)",
+                "parameters": [
+                    {
+                        "description": "Service area",
+                        "name": "area",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.createServiceAreaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Service area created",
+                        "schema": {
+                            "$ref": "#/definitions/iface.serviceAreaResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or polygon",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/service-areas/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete a service area (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service area ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Service area deleted"
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Service area not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rename, redraw, or activate/deactivate a service area. Existing orders are not re-validated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update a service area (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service area ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "area",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.updateServiceAreaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Service area updated",
+                        "schema": {
+                            "$ref": "#/definitions/iface.serviceAreaResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or polygon",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Service area not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/ws/metrics": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Live connection counts per kind (drone, admin) and counters since start: connections opened, disconnects by reason\n(peer_closed, read_timeout, read_error, write_error, slow_consumer, replaced, server_closed), messages sent and dropped, pings and pongs.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Websocket connection health (admin)",
+                "responses": {
+                    "200": {
+                        "description": "Metrics",
+                        "schema": {
+                            "$ref": "#/definitions/iface.wsMetricsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden - Admin only",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/token": {
+            "post": {
+                "description": "Authenticate a user and return an access token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "User login",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.loginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful",
+                        "schema": {
+                            "$ref": "#/definitions/iface.loginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid credentials",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/drones/{id}/broken": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Report that a drone is broken (can be called by drone or admin).\nEvery order on the drone's trip is handed off; the first is echoed in handoff_order_id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drones"
+                ],
+                "summary": "Report drone as broken",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Drone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Drone location",
+                        "name": "location",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.droneLocationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Drone marked as broken",
+                        "schema": {
+                            "$ref": "#/definitions/iface.droneStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Drone not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/drones/{id}/trip": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Ordered pickup/dropoff stops for the drone's current multi-stop trip (drone self or admin)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drones"
+                ],
+                "summary": "Get a drone's active trip",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Drone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Active trip",
+                        "schema": {
+                            "$ref": "#/definitions/iface.tripResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid drone ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Drone not found or not on a trip",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/geocode": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Resolve a free-text address to coordinates, as order creation would",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "geocoding"
+                ],
+                "summary": "Resolve an address",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Free-text address",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resolved place",
+                        "schema": {
+                            "$ref": "#/definitions/iface.placeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Address could not be resolved",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/geocode/reverse": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Find the named place nearest to the coordinates",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "geocoding"
+                ],
+                "summary": "Name a location",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Latitude",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude",
+                        "name": "lng",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Nearest named place",
+                        "schema": {
+                            "$ref": "#/definitions/iface.placeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No named place nearby",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check if the API is running",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the caller's order history, newest first; active orders include drone location and ETA",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "List my orders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default: 10)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by order status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or after (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created at or before (RFC3339 or YYYY-MM-DD, inclusive of the whole day)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of orders",
+                        "schema": {
+                            "$ref": "#/definitions/iface.orderListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new delivery order; each of pickup and dropoff is given as coordinates, a saved address id (coordinates and notes are copied onto the order) or a free-text address resolved by the geocoder",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Create a new delivery order",
+                "parameters": [
+                    {
+                        "description": "Order details",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.createOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Order created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/iface.orderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get detailed information about a specific order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get order details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order details",
+                        "schema": {
+                            "$ref": "#/definitions/iface.orderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel a pending delivery order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Cancel an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order canceled successfully",
+                        "schema": {
+                            "$ref": "#/definitions/iface.orderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Order cannot be canceled",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/deliver": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Drone delivers an order to the dropoff location. The drone must submit the recipient PIN or a signed evidence payload,\nand its last heartbeat must be within the configured radius of the dropoff.\nWrong PINs are counted per order; the one that uses up the last attempt fails the order (a parcel on board is returned) and answers ` + "`" + `delivery_pin_locked` + "`" + `.\nEvidence signatures are hex HMAC-SHA256 over ` + "`" + `order_id|photo_hash|lat|lng|captured_at` + "`" + ` (coordinates with 6 decimals, RFC3339 UTC timestamp),\nkeyed with the drone's own key, HMAC-SHA256(DELIVERY_EVIDENCE_SECRET, drone_id).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drone-actions"
+                ],
+                "summary": "Deliver an order (Drone action)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Proof of delivery",
+                        "name": "proof",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/iface.deliverOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order delivered successfully",
+                        "schema": {
+                            "$ref": "#/definitions/iface.orderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or missing proof",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Proof rejected or pin proof locked",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Order cannot be delivered or drone is not at the dropoff",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/fail": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Drone reports that an order delivery has failed. Orders already picked up move to returning and are routed back to their pickup location.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drone-actions"
+                ],
+                "summary": "Fail an order (Drone action)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order marked as failed or returning",
+                        "schema": {
+                            "$ref": "#/definitions/iface.orderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Order cannot be failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/pickup": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Drone picks up an order from the pickup location",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drone-actions"
+                ],
+                "summary": "Pickup an order (Drone action)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order picked up successfully",
+                        "schema": {
+                            "$ref": "#/definitions/iface.orderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Order cannot be picked up",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/reserve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Drone reserves a pending order for delivery",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drone-actions"
+                ],
+                "summary": "Reserve an order (Drone action)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order reserved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/iface.orderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Order cannot be reserved",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/return": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Drone confirms that a returning parcel has been brought back to its origin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drone-actions"
+                ],
+                "summary": "Confirm a returned order (Drone action)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order marked as returned",
+                        "schema": {
+                            "$ref": "#/definitions/iface.orderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Order is not returning",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/service-areas": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the active service areas; pickup and dropoff must fall inside one of them. An empty list means coverage is unrestricted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "service-areas"
+                ],
+                "summary": "List service coverage",
+                "responses": {
+                    "200": {
+                        "description": "Active service areas",
+                        "schema": {
+                            "$ref": "#/definitions/iface.serviceAreaListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/ws/admin": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Pushes operational events to admin consoles as they happen.\n\n**Geofence breach** (Server → Admin):\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"type\": \"geofence_breach\",\n\"breach_id\": 7,\n\"drone_id\": 3,\n\"order_id\": 123,\n\"kind\": \"no_fly_zone | outside_service_area\",\n\"zone_id\": 2,\n\"zone_name\": \"Airport\",\n\"lat\": 31.72,\n\"lng\": 35.99,\n\"action\": \"none | hold | land\",\n\"detected_at\": \"2025-11-10T12:00:00Z\"\n}\n` + "`" + `` + "`" + `` + "`" + `",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "WebSocket event stream for admins",
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols - WebSocket connection established",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/ws/heartbeat": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Establishes a WebSocket connection for drones to send heartbeat updates and receive assignments\nThe drone must authenticate with a Bearer token in the query parameter or header.\n\n**Message Types:**\n\n1. **Heartbeat** (Drone → Server):\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"type\": \"heartbeat\",\n\"lat\": 40.7128,\n\"lng\": -74.0060,\n\"altitude_m\": 120.5,\n\"heading_deg\": 270,\n\"speed_mps\": 12.4,\n\"battery_pct\": 87.5,\n\"gps_fix\": \"3d\",\n\"gps_accuracy_m\": 2.5,\n\"device_time\": \"2025-11-10T12:00:00.250Z\"\n}\n` + "`" + `` + "`" + `` + "`" + `\nEverything but lat/lng is optional and stored in the telemetry history. speed_mps is ground speed (0-100) and drives ETAs while the drone is moving;\nheading_deg is in [0, 360), gps_fix is one of none, 2d, 3d, dgps, rtk (none is rejected). A heartbeat whose device_time is not newer than the last\napplied one is rejected as stale, as is one more than 5 minutes ahead of the server clock.\n\n2. **Heartbeat Response** (Server → Drone):\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"type\": \"heartbeat\",\n\"message\": \"heartbeat received\",\n\"timestamp\": \"2025-11-10T12:00:00Z\"\n}\n` + "`" + `` + "`" + `` + "`" + `\n\n3. **Assignment** (Server → Drone):\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"type\": \"assignment\",\n\"drone_id\": 1,\n\"order_id\": 123,\n\"pickup_lat\": 40.7128,\n\"pickup_lng\": -74.0060,\n\"dropoff_lat\": 40.7580,\n\"dropoff_lng\": -73.9855,\n\"enduser_id\": 456,\n\"order_status\": \"reserved\",\n\"created_at\": \"2025-11-10T12:00:00Z\",\n\"description\": \"new_order | handoff | return_handoff\",\n\"waypoints\": [{\"lat\": 40.7000, \"lng\": -74.0100}, {\"lat\": 40.7128, \"lng\": -74.0060}, {\"lat\": 40.7580, \"lng\": -73.9855}]\n}\n` + "`" + `` + "`" + `` + "`" + `\nFor ` + "`" + `handoff` + "`" + ` and ` + "`" + `return_handoff` + "`" + ` the parcel is collected at ` + "`" + `handoff_lat` + "`" + `/` + "`" + `handoff_lng` + "`" + ` (where the previous drone broke down, or the landing site it was sent to) instead of the pickup; the waypoints already lead there.\n\n4. **Assignment Acknowledgment** (Drone → Server):\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"type\": \"assignment_ack\",\n\"order_id\": 123,\n\"status\": \"accepted\"\n}\n` + "`" + `` + "`" + `` + "`" + `\n\n5. **Command** (Server → Drone), issued via ` + "`" + `POST /admin/drones/{id}/commands` + "`" + `:\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"type\": \"command\",\n\"command_id\": 42,\n\"command\": \"return_to_home | hold_position | land_now | divert | cancel_assignment | reposition\",\n\"order_id\": 123,\n\"lat\": 40.7000,\n\"lng\": -74.0100,\n\"issued_at\": \"2025-11-10T12:00:00Z\"\n}\n` + "`" + `` + "`" + `` + "`" + `\n\n6. **Command Ack / Result** (Drone → Server), answered with the same type plus ` + "`" + `command_status` + "`" + `:\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"type\": \"command_ack | command_result\",\n\"command_id\": 42,\n\"status\": \"accepted | rejected | completed | failed\",\n\"note\": \"optional\"\n}\n` + "`" + `` + "`" + `` + "`" + `\n\n7. **Order Update** (Server → Drone), sent to the drone an order is assigned to or was last offered to:\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"type\": \"order_update\",\n\"event\": \"order_canceled | route_updated | handoff_required\",\n\"drone_id\": 1,\n\"order_id\": 123,\n\"order_status\": \"canceled\",\n\"pickup_lat\": 40.7128,\n\"pickup_lng\": -74.0060,\n\"dropoff_lat\": 40.7580,\n\"dropoff_lng\": -73.9855,\n\"handoff_lat\": 40.7300,\n\"handoff_lng\": -74.0000,\n\"waypoints\": [{\"lat\": 40.7000, \"lng\": -74.0100}, {\"lat\": 40.7128, \"lng\": -74.0060}, {\"lat\": 40.7580, \"lng\": -73.9855}],\n\"created_at\": \"2025-11-10T12:00:00Z\"\n}\n` + "`" + `` + "`" + `` + "`" + `\nwaypoints come with route_updated, handoff_lat/handoff_lng with handoff_required for a parcel already on board.\n\n8. **Order Update Ack** (Drone → Server), answered with the same type and ` + "`" + `\"message\": \"acknowledged\"` + "`" + `:\n` + "`" + `` + "`" + `` + "`" + `json\n{\n\"type\": \"order_update_ack\",\n\"order_id\": 123,\n\"event\": \"order_canceled\",\n\"status\": \"accepted | rejected\",\n\"note\": \"optional\"\n}\n` + "`" + `` + "`" + `` + "`" + `\n\n**Reliable delivery:** every message the server pushes on its own (assignment, command, order_update, geofence_breach) carries a per-drone ` + "`" + `seq` + "`" + `.\nUnacknowledged messages are buffered for the resume window; messages sent while the drone is away are delivered when it reconnects.\nMessages may arrive out of order after a reconnect, so drones should order and de-duplicate by ` + "`" + `seq` + "`" + `.\nBehind several API nodes, messages are relayed to the node holding the connection, which numbers and buffers them; a drone that reconnects to another node gets a fresh session.\n\n9. **Ack** (Drone → Server), no reply; confirms everything up to and including ` + "`" + `seq` + "`" + `:\n` + "`" + `` + "`" + `` + "`" + `json\n{ \"type\": \"ack\", \"seq\": 17 }\n` + "`" + `` + "`" + `` + "`" + `\n\n10. **Resume** (Drone → Server) after reconnecting, with the highest ` + "`" + `seq` + "`" + ` received; everything after it is replayed, then:\n` + "`" + `` + "`" + `` + "`" + `json\n{ \"type\": \"resume\", \"message\": \"ok\", \"last_seq\": 19, \"replayed\": 2 }\n` + "`" + `` + "`" + `` + "`" + `\nAfter a server restart, or when the drone resumes on another API node, numbering continues from the drone's ` + "`" + `last_seq` + "`" + `; unacked messages held by the node it left follow with new seqs.\n\n**Keepalive:** the server sends a websocket ping every WS_PING_INTERVAL (25s) and closes the connection when nothing, pong included,\narrives for WS_PONG_WAIT (60s). Outgoing messages are queued (WS_SEND_BUFFER, 64); a drone that falls that far behind is disconnected and should resume.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drone-websocket"
+                ],
+                "summary": "WebSocket heartbeat endpoint for drones",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer token can also be passed as query parameter",
+                        "name": "Authorization",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols - WebSocket connection established",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "iface.addressListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.addressResponse"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/iface.paginationMeta"
+                }
+            }
+        },
+        "iface.addressResponse": {
+            "type": "object",
+            "properties": {
+                "access_instructions": {
+                    "type": "string"
+                },
+                "address_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivery_notes": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.createAddressRequest": {
+            "type": "object",
+            "required": [
+                "label",
+                "lat",
+                "lng"
+            ],
+            "properties": {
+                "access_instructions": {
+                    "type": "string"
+                },
+                "delivery_notes": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                }
+            }
+        },
+        "iface.createDepotRequest": {
+            "type": "object",
+            "required": [
+                "capacity",
+                "lat",
+                "lng",
+                "name"
+            ],
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.createLandingSiteRequest": {
+            "type": "object",
+            "required": [
+                "lat",
+                "lng",
+                "name"
+            ],
+            "properties": {
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.createNoFlyZoneRequest": {
+            "type": "object",
+            "required": [
+                "boundary",
+                "name"
+            ],
+            "properties": {
+                "boundary": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.geoPointRequest"
+                    }
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.createOrderRequest": {
+            "type": "object",
+            "properties": {
+                "dropoff_address": {
+                    "type": "string"
+                },
+                "dropoff_address_id": {
+                    "type": "integer"
+                },
+                "dropoff_lat": {
+                    "type": "number"
+                },
+                "dropoff_lng": {
+                    "type": "number"
+                },
+                "pickup_address": {
+                    "type": "string"
+                },
+                "pickup_address_id": {
+                    "type": "integer"
+                },
+                "pickup_lat": {
+                    "type": "number"
+                },
+                "pickup_lng": {
+                    "type": "number"
+                }
+            }
+        },
+        "iface.createServiceAreaRequest": {
+            "type": "object",
+            "required": [
+                "boundary",
+                "name"
+            ],
+            "properties": {
+                "boundary": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.geoPointRequest"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.deliverOrderRequest": {
+            "type": "object",
+            "properties": {
+                "evidence": {
+                    "$ref": "#/definitions/iface.deliveryEvidenceRequest"
+                },
+                "pin": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.deliveryEvidenceRequest": {
+            "type": "object",
+            "required": [
+                "captured_at",
+                "lat",
+                "lng",
+                "photo_hash",
+                "signature"
+            ],
+            "properties": {
+                "captured_at": {
+                    "type": "string"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "photo_hash": {
+                    "type": "string"
+                },
+                "signature": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.depotListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.depotResponse"
+                    }
+                }
+            }
+        },
+        "iface.depotResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "capacity": {
+                    "type": "integer"
+                },
+                "charging": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "depot_id": {
+                    "type": "integer"
+                },
+                "free_pads": {
+                    "type": "integer"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "occupied": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.dispatchDryRunRequest": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "type": "string",
+                    "example": "haversine"
+                },
+                "scenario": {
+                    "$ref": "#/definitions/iface.dispatchScenarioRequest"
+                },
+                "strategy": {
+                    "type": "string",
+                    "example": "batch"
+                }
+            }
+        },
+        "iface.dispatchPlanResponse": {
+            "type": "object",
+            "properties": {
+                "assignments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.dispatchProposalResponse"
+                    }
+                },
+                "computed_at": {
+                    "type": "string"
+                },
+                "cost": {
+                    "type": "string"
+                },
+                "drones_considered": {
+                    "type": "integer"
+                },
+                "orders_considered": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "strategy": {
+                    "type": "string"
+                },
+                "total_delivery_distance_km": {
+                    "type": "number"
+                },
+                "total_distance_km": {
+                    "type": "number"
+                },
+                "unassigned_order_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "iface.dispatchProposalResponse": {
+            "type": "object",
+            "properties": {
+                "delivery_distance_km": {
+                    "type": "number"
+                },
+                "delivery_eta_minutes": {
+                    "type": "integer"
+                },
+                "distance_km": {
+                    "type": "number"
+                },
+                "drone_id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "pickup_eta_minutes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "iface.dispatchScenarioRequest": {
+            "type": "object",
+            "properties": {
+                "drones": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.scenarioDroneRequest"
+                    }
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.scenarioOrderRequest"
+                    }
+                }
+            }
+        },
+        "iface.droneCapacityRequest": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "iface.droneCommandListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.droneCommandResponse"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/iface.paginationMeta"
+                }
+            }
+        },
+        "iface.droneCommandResponse": {
+            "type": "object",
+            "properties": {
+                "acked_at": {
+                    "type": "string"
+                },
+                "command_id": {
+                    "type": "integer"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "drone_id": {
+                    "type": "integer"
+                },
+                "issued_by": {
+                    "type": "integer"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.droneFixedRequest": {
+            "type": "object",
+            "properties": {
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "replaced the rear left motor"
+                },
+                "parts": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "technician": {
+                    "type": "string",
+                    "example": "Sami"
+                }
+            }
+        },
+        "iface.droneListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.droneStatusResponse"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/iface.paginationMeta"
+                }
+            }
+        },
+        "iface.droneLocationRequest": {
+            "type": "object",
+            "properties": {
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                }
+            }
+        },
+        "iface.droneMaintenanceResponse": {
+            "type": "object",
+            "properties": {
+                "custom_schedule": {
+                    "type": "boolean"
+                },
+                "cycles": {
+                    "type": "integer"
+                },
+                "drone_id": {
+                    "type": "integer"
+                },
+                "due": {
+                    "type": "boolean"
+                },
+                "flight_hours": {
+                    "type": "number"
+                },
+                "last_service_at": {
+                    "type": "string"
+                },
+                "open_record": {
+                    "$ref": "#/definitions/iface.maintenanceRecordResponse"
+                },
+                "service_cycles": {
+                    "type": "integer"
+                },
+                "service_flight_hours": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.droneStatusResponse": {
+            "type": "object",
+            "properties": {
+                "active_orders": {
+                    "type": "integer"
+                },
+                "altitude_m": {
+                    "type": "number"
+                },
+                "assignment_pending": {
+                    "type": "boolean"
+                },
+                "battery_pct": {
+                    "type": "number"
+                },
+                "capacity": {
+                    "type": "integer"
+                },
+                "current_order_id": {
+                    "type": "integer"
+                },
+                "current_trip_id": {
+                    "type": "integer"
+                },
+                "depot_id": {
+                    "type": "integer"
+                },
+                "device_time": {
+                    "type": "string"
+                },
+                "drone_id": {
+                    "type": "integer"
+                },
+                "gps_accuracy_m": {
+                    "type": "number"
+                },
+                "gps_fix": {
+                    "type": "string"
+                },
+                "handoff_order_id": {
+                    "type": "integer"
+                },
+                "handoff_order_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "heading_deg": {
+                    "type": "number"
+                },
+                "home_depot_id": {
+                    "type": "integer"
+                },
+                "last_heartbeat": {
+                    "type": "string"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "order_status": {
+                    "type": "string"
+                },
+                "speed_mps": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "usage": {
+                    "$ref": "#/definitions/iface.droneUsageResponse"
+                }
+            }
+        },
+        "iface.droneUsageResponse": {
+            "type": "object",
+            "properties": {
+                "airborne_seconds": {
+                    "type": "number"
+                },
+                "breakdowns": {
+                    "type": "integer"
+                },
+                "deliveries": {
+                    "type": "integer"
+                },
+                "distance_m": {
+                    "type": "number"
+                },
+                "failures": {
+                    "type": "integer"
+                }
+            }
+        },
+        "iface.geoPointRequest": {
+            "type": "object",
+            "required": [
+                "lat",
+                "lng"
+            ],
+            "properties": {
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                }
+            }
+        },
+        "iface.geoPointResponse": {
+            "type": "object",
+            "properties": {
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                }
+            }
+        },
+        "iface.geofenceBreachListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.geofenceBreachResponse"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/iface.paginationMeta"
+                }
+            }
+        },
+        "iface.geofenceBreachResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "breach_id": {
+                    "type": "integer"
+                },
+                "detected_at": {
+                    "type": "string"
+                },
+                "drone_id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "zone_id": {
+                    "type": "integer"
+                },
+                "zone_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.homeDepotRequest": {
+            "type": "object",
+            "required": [
+                "depot_id"
+            ],
+            "properties": {
+                "depot_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "iface.issueCommandRequest": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.landingSiteListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.landingSiteResponse"
+                    }
+                }
+            }
+        },
+        "iface.landingSiteResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "landing_site_id": {
+                    "type": "integer"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.leaderLeaseResponse": {
+            "type": "object",
+            "properties": {
+                "acquired_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "node_id": {
+                    "type": "string"
+                },
+                "renewed_at": {
+                    "type": "string"
+                },
+                "term": {
+                    "type": "integer"
+                }
+            }
+        },
+        "iface.leaderStatusResponse": {
+            "type": "object",
+            "properties": {
+                "is_leader": {
+                    "type": "boolean"
+                },
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "leader": {
+                    "description": "Leader is null while no node holds a live lease, e.g. mid-failover.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/iface.leaderLeaseResponse"
+                        }
+                    ]
+                },
+                "node_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.legETAResponse": {
+            "type": "object",
+            "properties": {
+                "eta_minutes": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "stops_before": {
+                    "type": "integer"
+                }
+            }
+        },
+        "iface.lineStringGeometry": {
+            "type": "object",
+            "properties": {
+                "coordinates": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "number"
+                        }
+                    }
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.locationResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                }
+            }
+        },
+        "iface.loginRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "password"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.loginResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "token_type": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/iface.userResponse"
+                }
+            }
+        },
+        "iface.maintenanceDetailsRequest": {
+            "type": "object",
+            "properties": {
+                "issue": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "parts": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "technician": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.maintenanceRecordListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.maintenanceRecordResponse"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/iface.paginationMeta"
+                }
+            }
+        },
+        "iface.maintenanceRecordResponse": {
+            "type": "object",
+            "properties": {
+                "closed_at": {
+                    "type": "string"
+                },
+                "drone_id": {
+                    "type": "integer"
+                },
+                "issue": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "open": {
+                    "type": "boolean"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "parts": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "record_id": {
+                    "type": "integer"
+                },
+                "technician": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.maintenanceScheduleRequest": {
+            "type": "object",
+            "properties": {
+                "cycles": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "flight_hours": {
+                    "type": "number",
+                    "example": 50
+                }
+            }
+        },
+        "iface.noFlyZoneListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.noFlyZoneResponse"
+                    }
+                }
+            }
+        },
+        "iface.noFlyZoneResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "boundary": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.geoPointResponse"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "no_fly_zone_id": {
+                    "type": "integer"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.openMaintenanceRequest": {
+            "type": "object",
             "required": [
-                "dropoff_lat",
-                "dropoff_lng",
-                "pickup_lat",
-                "pickup_lng"
+                "issue"
             ],
+            "properties": {
+                "issue": {
+                    "type": "string",
+                    "example": "propulsion"
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "rear left motor runs hot"
+                },
+                "parts": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "technician": {
+                    "type": "string",
+                    "example": "Sami"
+                }
+            }
+        },
+        "iface.orderListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.orderResponse"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/iface.paginationMeta"
+                }
+            }
+        },
+        "iface.orderResponse": {
+            "type": "object",
+            "properties": {
+                "access_instructions": {
+                    "type": "string"
+                },
+                "assigned_drone_id": {
+                    "type": "integer"
+                },
+                "canceled_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivery_notes": {
+                    "type": "string"
+                },
+                "delivery_pin": {
+                    "type": "string"
+                },
+                "drone_location": {
+                    "$ref": "#/definitions/iface.locationResponse"
+                },
+                "dropoff": {
+                    "$ref": "#/definitions/iface.locationResponse"
+                },
+                "dropoff_address_id": {
+                    "type": "integer"
+                },
+                "eta_minutes": {
+                    "type": "integer"
+                },
+                "handoff_lat": {
+                    "type": "number"
+                },
+                "handoff_lng": {
+                    "type": "number"
+                },
+                "legs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.legETAResponse"
+                    }
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "pickup": {
+                    "$ref": "#/definitions/iface.locationResponse"
+                },
+                "pickup_address_id": {
+                    "type": "integer"
+                },
+                "return_lat": {
+                    "type": "number"
+                },
+                "return_lng": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.paginationMeta": {
+            "type": "object",
+            "properties": {
+                "has_next": {
+                    "type": "boolean"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "iface.placeResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                }
+            }
+        },
+        "iface.rebalanceCellReportResponse": {
+            "type": "object",
+            "properties": {
+                "actual_wait_seconds": {
+                    "type": "integer"
+                },
+                "expected_orders": {
+                    "type": "number"
+                },
+                "forecasts": {
+                    "type": "integer"
+                },
+                "max_lat": {
+                    "type": "number"
+                },
+                "max_lng": {
+                    "type": "number"
+                },
+                "min_lat": {
+                    "type": "number"
+                },
+                "min_lng": {
+                    "type": "number"
+                },
+                "orders": {
+                    "type": "integer"
+                },
+                "picked_up": {
+                    "type": "integer"
+                },
+                "predicted_wait_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "iface.rebalanceCellResponse": {
+            "type": "object",
+            "properties": {
+                "center_lat": {
+                    "type": "number"
+                },
+                "center_lng": {
+                    "type": "number"
+                },
+                "idle_drones": {
+                    "type": "integer"
+                },
+                "max_lat": {
+                    "type": "number"
+                },
+                "max_lng": {
+                    "type": "number"
+                },
+                "min_lat": {
+                    "type": "number"
+                },
+                "min_lng": {
+                    "type": "number"
+                },
+                "orders_per_hour": {
+                    "type": "number"
+                },
+                "predicted_wait_seconds": {
+                    "type": "integer"
+                },
+                "target_drones": {
+                    "type": "integer"
+                }
+            }
+        },
+        "iface.rebalanceMoveResponse": {
+            "type": "object",
+            "properties": {
+                "command_id": {
+                    "type": "integer"
+                },
+                "distance_km": {
+                    "type": "number"
+                },
+                "drone_id": {
+                    "type": "integer"
+                },
+                "from_lat": {
+                    "type": "number"
+                },
+                "from_lng": {
+                    "type": "number"
+                },
+                "to_lat": {
+                    "type": "number"
+                },
+                "to_lng": {
+                    "type": "number"
+                }
+            }
+        },
+        "iface.rebalancePlanResponse": {
+            "type": "object",
+            "properties": {
+                "aggressiveness": {
+                    "type": "number"
+                },
+                "cells": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.rebalanceCellResponse"
+                    }
+                },
+                "computed_at": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "hour_of_day": {
+                    "type": "integer"
+                },
+                "idle_drones": {
+                    "type": "integer"
+                },
+                "moves": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.rebalanceMoveResponse"
+                    }
+                }
+            }
+        },
+        "iface.rebalanceReportResponse": {
+            "type": "object",
+            "properties": {
+                "cells": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.rebalanceCellReportResponse"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "overall": {
+                    "$ref": "#/definitions/iface.waitComparisonResponse"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.rebalanceRunRequest": {
+            "type": "object",
+            "properties": {
+                "aggressiveness": {
+                    "type": "number",
+                    "example": 0.5
+                },
+                "dry_run": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "iface.scenarioDroneRequest": {
+            "type": "object",
+            "properties": {
+                "active_orders": {
+                    "type": "integer"
+                },
+                "capacity": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "speed_mps": {
+                    "type": "number"
+                }
+            }
+        },
+        "iface.scenarioOrderRequest": {
+            "type": "object",
             "properties": {
                 "dropoff_lat": {
                     "type": "number"
@@ -1163,6 +6390,9 @@ const docTemplate = `{
                 "dropoff_lng": {
                     "type": "number"
                 },
+                "id": {
+                    "type": "integer"
+                },
                 "pickup_lat": {
                     "type": "number"
                 },
@@ -1171,184 +6401,268 @@ const docTemplate = `{
                 }
             }
         },
-        "iface.droneListResponse": {
+        "iface.serviceAreaListResponse": {
             "type": "object",
             "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/iface.droneStatusResponse"
+                        "$ref": "#/definitions/iface.serviceAreaResponse"
                     }
-                },
-                "meta": {
-                    "$ref": "#/definitions/iface.paginationMeta"
-                }
-            }
-        },
-        "iface.droneLocationRequest": {
-            "type": "object",
-            "properties": {
-                "lat": {
-                    "type": "number"
-                },
-                "lng": {
-                    "type": "number"
                 }
             }
         },
-        "iface.droneStatusResponse": {
+        "iface.serviceAreaResponse": {
             "type": "object",
             "properties": {
-                "assignment_pending": {
+                "active": {
                     "type": "boolean"
                 },
-                "current_order_id": {
-                    "type": "integer"
-                },
-                "drone_id": {
-                    "type": "integer"
-                },
-                "handoff_order_id": {
-                    "type": "integer"
+                "boundary": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.geoPointResponse"
+                    }
                 },
-                "last_heartbeat": {
+                "created_at": {
                     "type": "string"
                 },
-                "lat": {
-                    "type": "number"
-                },
-                "lng": {
-                    "type": "number"
-                },
-                "order_status": {
+                "name": {
                     "type": "string"
                 },
-                "status": {
+                "service_area_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "iface.locationResponse": {
+        "iface.trackFeature": {
             "type": "object",
             "properties": {
-                "lat": {
-                    "type": "number"
+                "geometry": {
+                    "$ref": "#/definitions/iface.lineStringGeometry"
                 },
-                "lng": {
-                    "type": "number"
+                "properties": {
+                    "$ref": "#/definitions/iface.trackProperties"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "iface.loginRequest": {
+        "iface.trackFeatureCollection": {
             "type": "object",
-            "required": [
-                "name",
-                "password"
-            ],
             "properties": {
-                "name": {
-                    "type": "string"
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.trackFeature"
+                    }
                 },
-                "password": {
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "iface.loginResponse": {
+        "iface.trackProperties": {
             "type": "object",
             "properties": {
-                "access_token": {
+                "altitude_m": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "battery_pct": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "drone_id": {
+                    "type": "integer"
+                },
+                "from": {
                     "type": "string"
                 },
-                "expires_in": {
+                "heading_deg": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "order_id": {
                     "type": "integer"
                 },
-                "token_type": {
+                "point_count": {
+                    "type": "integer"
+                },
+                "speed_mps": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "timestamps": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "to": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/iface.userResponse"
+                "trip_id": {
+                    "type": "integer"
+                },
+                "truncated": {
+                    "type": "boolean"
                 }
             }
         },
-        "iface.orderListResponse": {
+        "iface.tripResponse": {
             "type": "object",
             "properties": {
-                "data": {
+                "created_at": {
+                    "type": "string"
+                },
+                "drone_id": {
+                    "type": "integer"
+                },
+                "next_stop": {
+                    "$ref": "#/definitions/iface.tripStopResponse"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "stops": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/iface.orderResponse"
+                        "$ref": "#/definitions/iface.tripStopResponse"
                     }
                 },
-                "meta": {
-                    "$ref": "#/definitions/iface.paginationMeta"
+                "trip_id": {
+                    "type": "integer"
                 }
             }
         },
-        "iface.orderResponse": {
+        "iface.tripStopResponse": {
             "type": "object",
             "properties": {
-                "assigned_drone_id": {
-                    "type": "integer"
-                },
-                "canceled_at": {
+                "completed_at": {
                     "type": "string"
                 },
-                "created_at": {
+                "kind": {
                     "type": "string"
                 },
-                "drone_location": {
-                    "$ref": "#/definitions/iface.locationResponse"
+                "lat": {
+                    "type": "number"
                 },
-                "dropoff": {
-                    "$ref": "#/definitions/iface.locationResponse"
+                "lng": {
+                    "type": "number"
                 },
-                "eta_minutes": {
+                "order_id": {
                     "type": "integer"
                 },
-                "handoff_lat": {
+                "sequence": {
+                    "type": "integer"
+                }
+            }
+        },
+        "iface.updateAddressRequest": {
+            "type": "object",
+            "properties": {
+                "access_instructions": {
+                    "type": "string"
+                },
+                "delivery_notes": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "lat": {
                     "type": "number"
                 },
-                "handoff_lng": {
+                "lng": {
                     "type": "number"
+                }
+            }
+        },
+        "iface.updateDepotRequest": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
                 },
-                "order_id": {
+                "capacity": {
                     "type": "integer"
                 },
-                "pickup": {
-                    "$ref": "#/definitions/iface.locationResponse"
+                "lat": {
+                    "type": "number"
                 },
-                "status": {
-                    "type": "string"
+                "lng": {
+                    "type": "number"
                 },
-                "updated_at": {
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "iface.paginationMeta": {
+        "iface.updateLandingSiteRequest": {
             "type": "object",
             "properties": {
-                "has_next": {
+                "active": {
                     "type": "boolean"
                 },
-                "page": {
-                    "type": "integer"
+                "lat": {
+                    "type": "number"
                 },
-                "page_size": {
-                    "type": "integer"
+                "lng": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "iface.updateNoFlyZoneRequest": {
+            "type": "object",
+            "properties": {
+                "boundary": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.geoPointRequest"
+                    }
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
                 }
             }
         },
         "iface.updateRouteRequest": {
             "type": "object",
             "properties": {
+                "dropoff_address": {
+                    "type": "string"
+                },
                 "dropoff_lat": {
                     "type": "number"
                 },
                 "dropoff_lng": {
                     "type": "number"
                 },
+                "pickup_address": {
+                    "type": "string"
+                },
                 "pickup_lat": {
                     "type": "number"
                 },
@@ -1357,6 +6671,23 @@ const docTemplate = `{
                 }
             }
         },
+        "iface.updateServiceAreaRequest": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "boundary": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/iface.geoPointRequest"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "iface.userResponse": {
             "type": "object",
             "properties": {
@@ -1370,6 +6701,61 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "iface.waitComparisonResponse": {
+            "type": "object",
+            "properties": {
+                "actual_wait_seconds": {
+                    "type": "integer"
+                },
+                "expected_orders": {
+                    "type": "number"
+                },
+                "forecasts": {
+                    "type": "integer"
+                },
+                "orders": {
+                    "type": "integer"
+                },
+                "picked_up": {
+                    "type": "integer"
+                },
+                "predicted_wait_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "iface.wsMetricsResponse": {
+            "type": "object",
+            "properties": {
+                "active_connections": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "connections_opened": {
+                    "type": "integer"
+                },
+                "disconnects": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "messages_dropped": {
+                    "type": "integer"
+                },
+                "messages_sent": {
+                    "type": "integer"
+                },
+                "pings_sent": {
+                    "type": "integer"
+                },
+                "pongs_received": {
+                    "type": "integer"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,111 @@
+package geocode
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+type cacheEntry struct {
+	key     string
+	place   *model.Place
+	err     error
+	expires time.Time
+}
+
+// Cache is an LRU decorator with a TTL. Misses ("no such address", "nothing
+// nearby") are cached too; provider failures are not.
+type Cache struct {
+	inner      Provider
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+func NewCache(inner Provider, ttl time.Duration, maxEntries int) *Cache {
+	return &Cache{
+		inner:      inner,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+func (c *Cache) Geocode(ctx context.Context, query string) (*model.Place, error) {
+	key := "q:" + model.NormalizeAddressQuery(query)
+	return c.lookup(key, func() (*model.Place, error) {
+		return c.inner.Geocode(ctx, query)
+	})
+}
+
+func (c *Cache) ReverseGeocode(ctx context.Context, lat, lng float64) (*model.Place, error) {
+	// ~1m grid so nearby lookups for the same spot share an entry
+	key := fmt.Sprintf("r:%.5f,%.5f", lat, lng)
+	return c.lookup(key, func() (*model.Place, error) {
+		return c.inner.ReverseGeocode(ctx, lat, lng)
+	})
+}
+
+func (c *Cache) lookup(key string, resolve func() (*model.Place, error)) (*model.Place, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		if now.Before(entry.expires) {
+			c.order.MoveToFront(elem)
+			c.mu.Unlock()
+			return copyPlace(entry.place), entry.err
+		}
+		c.order.Remove(elem)
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+
+	place, err := resolve()
+	if err != nil && !isMiss(err) {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.order.Remove(elem)
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{
+		key:     key,
+		place:   copyPlace(place),
+		err:     err,
+		expires: now.Add(c.ttl),
+	})
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+
+	return place, err
+}
+
+func isMiss(err error) bool {
+	var domainErr *model.DomainError
+	return errors.As(err, &domainErr) && domainErr.Code == model.ErrCodeAddressNotGeocoded
+}
+
+func copyPlace(place *model.Place) *model.Place {
+	if place == nil {
+		return nil
+	}
+	cp := *place
+	return &cp
+}
@@ -0,0 +1,156 @@
+package geocode
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+type gazetteerEntry struct {
+	place model.Place
+	keys  [][]string
+}
+
+// Gazetteer is the built-in offline provider: a fixed list of named places
+// loaded from a CSV file with a name,lat,lng,aliases header, aliases separated
+// by "|".
+type Gazetteer struct {
+	entries             []gazetteerEntry
+	index               map[string]int
+	reverseRadiusMeters float64
+}
+
+func LoadGazetteer(path string, reverseRadiusMeters float64) (*Gazetteer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return NewGazetteer(f, reverseRadiusMeters)
+}
+
+func NewGazetteer(r io.Reader, reverseRadiusMeters float64) (*Gazetteer, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Gazetteer{
+		index:               make(map[string]int),
+		reverseRadiusMeters: reverseRadiusMeters,
+	}
+
+	for i, record := range records {
+		if i == 0 && len(record) > 0 && strings.EqualFold(record[0], "name") {
+			continue
+		}
+		if len(record) < 3 {
+			return nil, fmt.Errorf("gazetteer line %d: expected name,lat,lng", i+1)
+		}
+
+		lat, latErr := strconv.ParseFloat(record[1], 64)
+		lng, lngErr := strconv.ParseFloat(record[2], 64)
+		if err := errors.Join(latErr, lngErr); err != nil {
+			return nil, fmt.Errorf("gazetteer line %d: %w", i+1, err)
+		}
+
+		names := []string{record[0]}
+		if len(record) > 3 && record[3] != "" {
+			names = append(names, strings.Split(record[3], "|")...)
+		}
+
+		entry := gazetteerEntry{place: model.Place{Name: strings.TrimSpace(record[0]), Lat: lat, Lng: lng}}
+		for _, name := range names {
+			key := model.NormalizeAddressQuery(name)
+			if key == "" {
+				continue
+			}
+			entry.keys = append(entry.keys, strings.Fields(key))
+			if _, exists := g.index[key]; !exists {
+				g.index[key] = len(g.entries)
+			}
+		}
+		g.entries = append(g.entries, entry)
+	}
+
+	return g, nil
+}
+
+/*
+Geocode: exact match on a normalized name or alias first, otherwise the entry
+whose name shares all words with the query (in either direction) with the
+fewest words left over, e.g. "abdali" or "abdali boulevard, amman"
+*/
+func (g *Gazetteer) Geocode(_ context.Context, query string) (*model.Place, error) {
+	normalized := model.NormalizeAddressQuery(query)
+	if normalized == "" {
+		return nil, model.ErrAddressNotGeocoded(query)
+	}
+
+	if i, ok := g.index[normalized]; ok {
+		place := g.entries[i].place
+		return &place, nil
+	}
+
+	queryTokens := strings.Fields(normalized)
+	best, bestExtra := -1, 0
+	for i, entry := range g.entries {
+		for _, key := range entry.keys {
+			extra, ok := tokenOverlap(queryTokens, key)
+			if ok && (best < 0 || extra < bestExtra) {
+				best, bestExtra = i, extra
+			}
+		}
+	}
+
+	if best < 0 {
+		return nil, model.ErrAddressNotGeocoded(query)
+	}
+
+	place := g.entries[best].place
+	return &place, nil
+}
+
+func (g *Gazetteer) ReverseGeocode(_ context.Context, lat, lng float64) (*model.Place, error) {
+	var nearest *model.Place
+	nearestMeters := g.reverseRadiusMeters
+	for i := range g.entries {
+		place := g.entries[i].place
+		if d := place.DistanceMeters(lat, lng); d <= nearestMeters {
+			nearest, nearestMeters = &place, d
+		}
+	}
+	return nearest, nil
+}
+
+// tokenOverlap reports whether one token list contains the other, and how
+// many tokens the longer one has beyond the shorter.
+func tokenOverlap(a, b []string) (int, bool) {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+
+	set := make(map[string]struct{}, len(b))
+	for _, token := range b {
+		set[token] = struct{}{}
+	}
+	for _, token := range a {
+		if _, ok := set[token]; !ok {
+			return 0, false
+		}
+	}
+
+	return len(b) - len(a), true
+}
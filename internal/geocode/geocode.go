@@ -0,0 +1,52 @@
+package geocode
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const (
+	ProviderGazetteer = "gazetteer"
+)
+
+// Provider resolves free-text addresses to coordinates and back. Geocode
+// returns model.ErrAddressNotGeocoded when nothing matches; ReverseGeocode
+// returns nil, nil when no known place is close enough.
+type Provider interface {
+	Geocode(ctx context.Context, query string) (*model.Place, error)
+	ReverseGeocode(ctx context.Context, lat, lng float64) (*model.Place, error)
+}
+
+type Config struct {
+	Provider            string
+	GazetteerPath       string
+	ReverseRadiusMeters float64
+	CacheTTL            time.Duration
+	CacheSize           int
+}
+
+// New builds the configured provider, wrapped in a cache when CacheTTL and
+// CacheSize are both set. External providers plug in here.
+func New(cfg Config) (Provider, error) {
+	var provider Provider
+
+	switch cfg.Provider {
+	case "", ProviderGazetteer:
+		gazetteer, err := LoadGazetteer(cfg.GazetteerPath, cfg.ReverseRadiusMeters)
+		if err != nil {
+			return nil, err
+		}
+		provider = gazetteer
+	default:
+		return nil, fmt.Errorf("unknown geocoder provider %q", cfg.Provider)
+	}
+
+	if cfg.CacheTTL > 0 && cfg.CacheSize > 0 {
+		provider = NewCache(provider, cfg.CacheTTL, cfg.CacheSize)
+	}
+
+	return provider, nil
+}
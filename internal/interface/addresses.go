@@ -0,0 +1,277 @@
+package iface
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+const paramAddressID = "id"
+
+type AddressUsecase interface {
+	CreateAddress(ctx context.Context, req model.CreateAddressRequest) (*model.Address, error)
+	GetAddress(ctx context.Context, userID, addressID int64) (*model.Address, error)
+	ListAddresses(ctx context.Context, userID int64, page, pageSize int) ([]model.Address, model.Pagination, error)
+	UpdateAddress(ctx context.Context, userID, addressID int64, req model.UpdateAddressRequest) (*model.Address, error)
+	DeleteAddress(ctx context.Context, userID, addressID int64) error
+}
+
+type AddressHandler struct {
+	uc AddressUsecase
+}
+
+func NewAddressHandler(uc AddressUsecase) *AddressHandler {
+	return &AddressHandler{uc: uc}
+}
+
+type createAddressRequest struct {
+	Label              string   `json:"label" binding:"required"`
+	Lat                *float64 `json:"lat" binding:"required"`
+	Lng                *float64 `json:"lng" binding:"required"`
+	DeliveryNotes      *string  `json:"delivery_notes,omitempty"`
+	AccessInstructions *string  `json:"access_instructions,omitempty"`
+}
+
+type updateAddressRequest struct {
+	Label              *string  `json:"label,omitempty"`
+	Lat                *float64 `json:"lat,omitempty"`
+	Lng                *float64 `json:"lng,omitempty"`
+	DeliveryNotes      *string  `json:"delivery_notes,omitempty"`
+	AccessInstructions *string  `json:"access_instructions,omitempty"`
+}
+
+type addressResponse struct {
+	AddressID          int64     `json:"address_id"`
+	Label              string    `json:"label"`
+	Lat                float64   `json:"lat"`
+	Lng                float64   `json:"lng"`
+	DeliveryNotes      *string   `json:"delivery_notes,omitempty"`
+	AccessInstructions *string   `json:"access_instructions,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type addressListResponse struct {
+	Data []addressResponse `json:"data"`
+	Meta paginationMeta    `json:"meta"`
+}
+
+// CreateAddress godoc
+// @Summary Save an address
+// @Description Add a labelled location with optional delivery notes to the caller's address book
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param address body createAddressRequest true "Address details"
+// @Success 201 {object} addressResponse "Address saved"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /addresses [post]
+func (h *AddressHandler) CreateAddress(c *gin.Context) {
+	userID, err := extractSubjectID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized", "message": err.Error()})
+		return
+	}
+
+	var req createAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "label, lat and lng are required"})
+		return
+	}
+
+	address, err := h.uc.CreateAddress(c.Request.Context(), model.CreateAddressRequest{
+		UserID:             userID,
+		Label:              req.Label,
+		Lat:                *req.Lat,
+		Lng:                *req.Lng,
+		DeliveryNotes:      req.DeliveryNotes,
+		AccessInstructions: req.AccessInstructions,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toAddressResponse(*address))
+}
+
+// ListAddresses godoc
+// @Summary List saved addresses
+// @Description Get the caller's address book, ordered by label
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Page size (default: 20)"
+// @Success 200 {object} addressListResponse "Saved addresses"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /addresses [get]
+func (h *AddressHandler) ListAddresses(c *gin.Context) {
+	userID, err := extractSubjectID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized", "message": err.Error()})
+		return
+	}
+
+	page, pageSize, err := parsePaginationParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
+		return
+	}
+
+	addresses, pagination, err := h.uc.ListAddresses(c.Request.Context(), userID, page, pageSize)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	data := make([]addressResponse, len(addresses))
+	for i := range addresses {
+		data[i] = toAddressResponse(addresses[i])
+	}
+
+	c.JSON(http.StatusOK, addressListResponse{
+		Data: data,
+		Meta: toPaginationMeta(pagination, len(addresses)),
+	})
+}
+
+// GetAddress godoc
+// @Summary Get a saved address
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Address ID"
+// @Success 200 {object} addressResponse "Address"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Address belongs to another user"
+// @Failure 404 {object} map[string]string "Address not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /addresses/{id} [get]
+func (h *AddressHandler) GetAddress(c *gin.Context) {
+	addressID, userID, ok := parseAddressRequest(c)
+	if !ok {
+		return
+	}
+
+	address, err := h.uc.GetAddress(c.Request.Context(), userID, addressID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toAddressResponse(*address))
+}
+
+// UpdateAddress godoc
+// @Summary Update a saved address
+// @Description Change any of label, coordinates or notes; an empty notes string clears it. Existing orders keep their snapshot.
+// @Tags addresses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Address ID"
+// @Param address body updateAddressRequest true "Fields to change"
+// @Success 200 {object} addressResponse "Address updated"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Address belongs to another user"
+// @Failure 404 {object} map[string]string "Address not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /addresses/{id} [patch]
+func (h *AddressHandler) UpdateAddress(c *gin.Context) {
+	addressID, userID, ok := parseAddressRequest(c)
+	if !ok {
+		return
+	}
+
+	var req updateAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid json body"})
+		return
+	}
+
+	address, err := h.uc.UpdateAddress(c.Request.Context(), userID, addressID, model.UpdateAddressRequest{
+		Label:              req.Label,
+		Lat:                req.Lat,
+		Lng:                req.Lng,
+		DeliveryNotes:      req.DeliveryNotes,
+		AccessInstructions: req.AccessInstructions,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toAddressResponse(*address))
+}
+
+// DeleteAddress godoc
+// @Summary Delete a saved address
+// @Description Orders placed from the address keep their copied coordinates and notes
+// @Tags addresses
+// @Security BearerAuth
+// @Param id path int true "Address ID"
+// @Success 204 "Address deleted"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Address belongs to another user"
+// @Failure 404 {object} map[string]string "Address not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /addresses/{id} [delete]
+func (h *AddressHandler) DeleteAddress(c *gin.Context) {
+	addressID, userID, ok := parseAddressRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeleteAddress(c.Request.Context(), userID, addressID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func parseAddressRequest(c *gin.Context) (int64, int64, bool) {
+	addressID, err := strconv.ParseInt(c.Param(paramAddressID), 10, 64)
+	if err != nil || addressID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid address id"})
+		return 0, 0, false
+	}
+
+	userID, err := extractSubjectID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized", "message": err.Error()})
+		return 0, 0, false
+	}
+
+	return addressID, userID, true
+}
+
+func toAddressResponse(address model.Address) addressResponse {
+	return addressResponse{
+		AddressID:          address.ID,
+		Label:              address.Label,
+		Lat:                address.Lat,
+		Lng:                address.Lng,
+		DeliveryNotes:      address.DeliveryNotes,
+		AccessInstructions: address.AccessInstructions,
+		CreatedAt:          address.CreatedAt,
+		UpdatedAt:          address.UpdatedAt,
+	}
+}
@@ -0,0 +1,109 @@
+package iface
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// AdminWSHandler fans operational events out to every connected admin
+// console. Admins only listen; anything they send is ignored.
+type AdminWSHandler struct {
+	mu       sync.RWMutex
+	clients  map[*wsClient]struct{}
+	cfg      WSConfig
+	metrics  *WSMetrics
+	upgrader websocket.Upgrader
+}
+
+func NewAdminWSHandler(cfg WSConfig, metrics *WSMetrics) *AdminWSHandler {
+	return &AdminWSHandler{
+		clients: make(map[*wsClient]struct{}),
+		cfg:     cfg,
+		metrics: metrics,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// HandleEvents godoc
+// @Summary WebSocket event stream for admins
+// @Description Pushes operational events to admin consoles as they happen.
+// @Description
+// @Description **Geofence breach** (Server → Admin):
+// @Description ```json
+// @Description {
+// @Description   "type": "geofence_breach",
+// @Description   "breach_id": 7,
+// @Description   "drone_id": 3,
+// @Description   "order_id": 123,
+// @Description   "kind": "no_fly_zone | outside_service_area",
+// @Description   "zone_id": 2,
+// @Description   "zone_name": "Airport",
+// @Description   "lat": 31.72,
+// @Description   "lng": 35.99,
+// @Description   "action": "none | hold | land",
+// @Description   "detected_at": "2025-11-10T12:00:00Z"
+// @Description }
+// @Description ```
+// @Tags admin
+// @Security BearerAuth
+// @Success 101 {string} string "Switching Protocols - WebSocket connection established"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Router /ws/admin [get]
+func (h *AdminWSHandler) HandleEvents(c *gin.Context) {
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("admin websocket upgrade failed: %v", err)
+		return
+	}
+
+	client := newWSClient(conn, h.cfg, h.metrics, wsKindAdmin)
+	h.mu.Lock()
+	h.clients[client] = struct{}{}
+	h.mu.Unlock()
+	defer h.remove(client)
+
+	for {
+		if _, err := client.ReadMessage(); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				log.Printf("admin websocket read error: %v", err)
+			}
+			return
+		}
+	}
+}
+
+// Broadcast queues the payload for every admin. Consoles whose connection is
+// gone are removed; slow ones follow the slow client policy.
+func (h *AdminWSHandler) Broadcast(payload interface{}) {
+	h.mu.RLock()
+	clients := make([]*wsClient, 0, len(h.clients))
+	for client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	for _, client := range clients {
+		if err := client.Send(payload); err != nil {
+			log.Printf("admin websocket write error: %v", err)
+			if !errors.Is(err, errMessageDropped) {
+				h.remove(client)
+			}
+		}
+	}
+}
+
+func (h *AdminWSHandler) remove(client *wsClient) {
+	h.mu.Lock()
+	delete(h.clients, client)
+	h.mu.Unlock()
+
+	client.Close()
+}
@@ -0,0 +1,289 @@
+package iface
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+const paramDepotID = "id"
+
+type DepotUsecase interface {
+	CreateDepot(ctx context.Context, name string, lat, lng float64, capacity int) (*model.Depot, error)
+	ListDepots(ctx context.Context) ([]model.Depot, error)
+	UpdateDepot(ctx context.Context, id int64, req model.UpdateDepotRequest) (*model.Depot, error)
+	DeleteDepot(ctx context.Context, id int64) error
+	SetHomeDepot(ctx context.Context, droneID int64, depotID *int64) (*model.Drone, error)
+}
+
+type DepotHandler struct {
+	uc DepotUsecase
+}
+
+func NewDepotHandler(uc DepotUsecase) *DepotHandler {
+	return &DepotHandler{uc: uc}
+}
+
+type createDepotRequest struct {
+	Name     string   `json:"name" binding:"required"`
+	Lat      *float64 `json:"lat" binding:"required"`
+	Lng      *float64 `json:"lng" binding:"required"`
+	Capacity *int     `json:"capacity" binding:"required"`
+}
+
+type updateDepotRequest struct {
+	Name     *string  `json:"name,omitempty"`
+	Lat      *float64 `json:"lat,omitempty"`
+	Lng      *float64 `json:"lng,omitempty"`
+	Capacity *int     `json:"capacity,omitempty"`
+	Active   *bool    `json:"active,omitempty"`
+}
+
+type homeDepotRequest struct {
+	DepotID *int64 `json:"depot_id" binding:"required"`
+}
+
+type depotResponse struct {
+	DepotID   int64     `json:"depot_id"`
+	Name      string    `json:"name"`
+	Lat       float64   `json:"lat"`
+	Lng       float64   `json:"lng"`
+	Capacity  int       `json:"capacity"`
+	Occupied  int       `json:"occupied"`
+	Charging  int       `json:"charging"`
+	FreePads  int       `json:"free_pads"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type depotListResponse struct {
+	Data []depotResponse `json:"data"`
+}
+
+// AdminListDepots godoc
+// @Summary List depots (admin)
+// @Description Get every depot with its occupancy: occupied counts drones parked, charging or on their way to a pad; charging is the part of them recharging
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} depotListResponse "Depots"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/depots [get]
+func (h *DepotHandler) AdminListDepots(c *gin.Context) {
+	depots, err := h.uc.ListDepots(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDepotListResponse(depots))
+}
+
+// AdminCreateDepot godoc
+// @Summary Create a depot (admin)
+// @Description Register a base with capacity charging pads. Idle drones below RTB_BATTERY_PCT go there to charge, and with RTB_PARK_WHEN_IDLE drones with no waiting order park there after a trip.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param depot body createDepotRequest true "Depot"
+// @Success 201 {object} depotResponse "Depot created"
+// @Failure 400 {object} map[string]string "Invalid request or depot"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/depots [post]
+func (h *DepotHandler) AdminCreateDepot(c *gin.Context) {
+	var req createDepotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "name, lat, lng and capacity are required"})
+		return
+	}
+
+	depot, err := h.uc.CreateDepot(c.Request.Context(), req.Name, *req.Lat, *req.Lng, *req.Capacity)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toDepotResponse(*depot))
+}
+
+// AdminUpdateDepot godoc
+// @Summary Update a depot (admin)
+// @Description Rename, move, resize or activate/deactivate a depot. Drones already holding a pad keep it; lowering the capacity only stops new arrivals.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Depot ID"
+// @Param depot body updateDepotRequest true "Fields to change"
+// @Success 200 {object} depotResponse "Depot updated"
+// @Failure 400 {object} map[string]string "Invalid request or depot"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Depot not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/depots/{id} [patch]
+func (h *DepotHandler) AdminUpdateDepot(c *gin.Context) {
+	depotID, ok := parseDepotID(c)
+	if !ok {
+		return
+	}
+
+	var req updateDepotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid json body"})
+		return
+	}
+
+	depot, err := h.uc.UpdateDepot(c.Request.Context(), depotID, model.UpdateDepotRequest{
+		Name:     req.Name,
+		Lat:      req.Lat,
+		Lng:      req.Lng,
+		Capacity: req.Capacity,
+		Active:   req.Active,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDepotResponse(*depot))
+}
+
+// AdminDeleteDepot godoc
+// @Summary Delete a depot (admin)
+// @Description Drones based there lose their home depot and pad
+// @Tags admin
+// @Security BearerAuth
+// @Param id path int true "Depot ID"
+// @Success 204 "Depot deleted"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Depot not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/depots/{id} [delete]
+func (h *DepotHandler) AdminDeleteDepot(c *gin.Context) {
+	depotID, ok := parseDepotID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeleteDepot(c.Request.Context(), depotID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetHomeDepot godoc
+// @Summary Set a drone's home depot (admin)
+// @Description The drone returns to its home depot when a pad is free there, otherwise to the nearest active depot with a free pad
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drone ID"
+// @Param depot body homeDepotRequest true "Home depot"
+// @Success 200 {object} droneStatusResponse "Drone updated"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Drone or depot not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/drones/{id}/home-depot [put]
+func (h *DepotHandler) SetHomeDepot(c *gin.Context) {
+	droneID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || droneID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_drone_id", "message": "invalid drone id"})
+		return
+	}
+
+	var req homeDepotRequest
+	if err := c.ShouldBindJSON(&req); err != nil || *req.DepotID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "depot_id is required"})
+		return
+	}
+
+	drone, err := h.uc.SetHomeDepot(c.Request.Context(), droneID, req.DepotID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDroneStatusResponse(drone, nil))
+}
+
+// ClearHomeDepot godoc
+// @Summary Clear a drone's home depot (admin)
+// @Description The drone returns to the nearest active depot with a free pad
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drone ID"
+// @Success 200 {object} droneStatusResponse "Drone updated"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Drone not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/drones/{id}/home-depot [delete]
+func (h *DepotHandler) ClearHomeDepot(c *gin.Context) {
+	droneID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || droneID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_drone_id", "message": "invalid drone id"})
+		return
+	}
+
+	drone, err := h.uc.SetHomeDepot(c.Request.Context(), droneID, nil)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDroneStatusResponse(drone, nil))
+}
+
+func parseDepotID(c *gin.Context) (int64, bool) {
+	depotID, err := strconv.ParseInt(c.Param(paramDepotID), 10, 64)
+	if err != nil || depotID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid depot id"})
+		return 0, false
+	}
+	return depotID, true
+}
+
+func toDepotResponse(depot model.Depot) depotResponse {
+	return depotResponse{
+		DepotID:   depot.ID,
+		Name:      depot.Name,
+		Lat:       depot.Lat,
+		Lng:       depot.Lng,
+		Capacity:  depot.Capacity,
+		Occupied:  depot.Occupied,
+		Charging:  depot.Charging,
+		FreePads:  depot.FreePads(),
+		Active:    depot.Active,
+		CreatedAt: depot.CreatedAt,
+		UpdatedAt: depot.UpdatedAt,
+	}
+}
+
+func toDepotListResponse(depots []model.Depot) depotListResponse {
+	data := make([]depotResponse, len(depots))
+	for i := range depots {
+		data[i] = toDepotResponse(depots[i])
+	}
+	return depotListResponse{Data: data}
+}
@@ -0,0 +1,165 @@
+package iface
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+type DispatchPlanUsecase interface {
+	Plan(ctx context.Context, req model.DispatchPlanRequest) (*model.DispatchPlan, error)
+}
+
+type DispatchHandler struct {
+	uc DispatchPlanUsecase
+}
+
+func NewDispatchHandler(uc DispatchPlanUsecase) *DispatchHandler {
+	return &DispatchHandler{uc: uc}
+}
+
+type dispatchDryRunRequest struct {
+	Strategy string                   `json:"strategy,omitempty" example:"batch"`
+	Cost     string                   `json:"cost,omitempty" example:"haversine"`
+	Scenario *dispatchScenarioRequest `json:"scenario,omitempty"`
+}
+
+type dispatchScenarioRequest struct {
+	Orders []scenarioOrderRequest `json:"orders"`
+	Drones []scenarioDroneRequest `json:"drones"`
+}
+
+type scenarioOrderRequest struct {
+	ID         int64   `json:"id,omitempty"`
+	PickupLat  float64 `json:"pickup_lat"`
+	PickupLng  float64 `json:"pickup_lng"`
+	DropoffLat float64 `json:"dropoff_lat"`
+	DropoffLng float64 `json:"dropoff_lng"`
+}
+
+type scenarioDroneRequest struct {
+	ID           int64    `json:"id,omitempty"`
+	Lat          float64  `json:"lat"`
+	Lng          float64  `json:"lng"`
+	Capacity     int      `json:"capacity,omitempty"`
+	ActiveOrders int      `json:"active_orders,omitempty"`
+	SpeedMPS     *float64 `json:"speed_mps,omitempty"`
+}
+
+type dispatchProposalResponse struct {
+	OrderID            int64   `json:"order_id"`
+	DroneID            int64   `json:"drone_id"`
+	DistanceKm         float64 `json:"distance_km"`
+	DeliveryDistanceKm float64 `json:"delivery_distance_km"`
+	PickupETAMinutes   int     `json:"pickup_eta_minutes"`
+	DeliveryETAMinutes int     `json:"delivery_eta_minutes"`
+}
+
+type dispatchPlanResponse struct {
+	Strategy                string                     `json:"strategy"`
+	Cost                    string                     `json:"cost"`
+	Source                  string                     `json:"source"`
+	OrdersConsidered        int                        `json:"orders_considered"`
+	DronesConsidered        int                        `json:"drones_considered"`
+	Assignments             []dispatchProposalResponse `json:"assignments"`
+	UnassignedOrderIDs      []int64                    `json:"unassigned_order_ids"`
+	TotalDistanceKm         float64                    `json:"total_distance_km"`
+	TotalDeliveryDistanceKm float64                    `json:"total_delivery_distance_km"`
+	ComputedAt              time.Time                  `json:"computed_at"`
+}
+
+// DryRunDispatch godoc
+// @Summary Dry-run dispatch (admin)
+// @Description Runs the dispatcher without offering anything and returns the assignments it would propose, with per-order ETAs and total distance.
+// @Description Without a scenario it uses the live state the next batch round would see: orders waiting for an offer and drones able to take one.
+// @Description A scenario supplies hypothetical orders and drones instead (at most 500 of each). strategy (greedy|batch) and cost (haversine|route) default to the configured ones.
+// @Description distance_km is the dispatch cost to the pickup; delivery distance and ETAs follow the path planned around no-fly zones in effect now.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dispatchDryRunRequest false "Strategy, cost and optional scenario"
+// @Success 200 {object} dispatchPlanResponse "Proposed assignments"
+// @Failure 400 {object} map[string]string "Invalid request or scenario"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/dispatch/dry-run [post]
+func (h *DispatchHandler) DryRunDispatch(c *gin.Context) {
+	var req dispatchDryRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid request body"})
+		return
+	}
+
+	planReq := model.DispatchPlanRequest{Mode: req.Strategy, Cost: req.Cost}
+	if req.Scenario != nil {
+		planReq.Scenario = toDispatchScenario(*req.Scenario)
+	}
+
+	plan, err := h.uc.Plan(c.Request.Context(), planReq)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDispatchPlanResponse(*plan))
+}
+
+func toDispatchScenario(req dispatchScenarioRequest) *model.DispatchScenario {
+	scenario := &model.DispatchScenario{
+		Orders: make([]model.ScenarioOrder, len(req.Orders)),
+		Drones: make([]model.ScenarioDrone, len(req.Drones)),
+	}
+	for i, o := range req.Orders {
+		scenario.Orders[i] = model.ScenarioOrder{
+			ID:         o.ID,
+			PickupLat:  o.PickupLat,
+			PickupLng:  o.PickupLng,
+			DropoffLat: o.DropoffLat,
+			DropoffLng: o.DropoffLng,
+		}
+	}
+	for i, d := range req.Drones {
+		scenario.Drones[i] = model.ScenarioDrone{
+			ID:           d.ID,
+			Lat:          d.Lat,
+			Lng:          d.Lng,
+			Capacity:     d.Capacity,
+			ActiveOrders: d.ActiveOrders,
+			SpeedMPS:     d.SpeedMPS,
+		}
+	}
+	return scenario
+}
+
+func toDispatchPlanResponse(plan model.DispatchPlan) dispatchPlanResponse {
+	resp := dispatchPlanResponse{
+		Strategy:                string(plan.Mode),
+		Cost:                    string(plan.Cost),
+		Source:                  string(plan.Source),
+		OrdersConsidered:        plan.OrdersConsidered,
+		DronesConsidered:        plan.DronesConsidered,
+		Assignments:             make([]dispatchProposalResponse, len(plan.Proposals)),
+		UnassignedOrderIDs:      plan.UnassignedOrderIDs,
+		TotalDistanceKm:         plan.TotalDistanceKm,
+		TotalDeliveryDistanceKm: plan.TotalDeliveryDistanceKm,
+		ComputedAt:              plan.ComputedAt,
+	}
+	for i, p := range plan.Proposals {
+		resp.Assignments[i] = dispatchProposalResponse{
+			OrderID:            p.OrderID,
+			DroneID:            p.DroneID,
+			DistanceKm:         p.DistanceKm,
+			DeliveryDistanceKm: p.DeliveryDistanceKm,
+			PickupETAMinutes:   int(p.PickupETA),
+			DeliveryETAMinutes: int(p.DeliveryETA),
+		}
+	}
+	return resp
+}
@@ -0,0 +1,276 @@
+package iface
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	messageTypeCommand       = "command"
+	messageTypeCommandAck    = "command_ack"
+	messageTypeCommandResult = "command_result"
+)
+
+type DroneCommandUsecase interface {
+	IssueCommand(ctx context.Context, req model.CreateDroneCommandRequest) (*model.DroneCommand, error)
+	GetCommand(ctx context.Context, droneID, commandID int64) (*model.DroneCommand, error)
+	ListDroneCommands(ctx context.Context, droneID int64, page, pageSize int) ([]model.DroneCommand, model.Pagination, error)
+}
+
+// DroneCommandAckUsecase records what the drone reports back over its
+// websocket.
+type DroneCommandAckUsecase interface {
+	AcknowledgeCommand(ctx context.Context, droneID, commandID int64, accepted bool, note string) (*model.DroneCommand, error)
+	CompleteCommand(ctx context.Context, droneID, commandID int64, succeeded bool, note string) (*model.DroneCommand, error)
+}
+
+type DroneCommandHandler struct {
+	uc DroneCommandUsecase
+}
+
+func NewDroneCommandHandler(uc DroneCommandUsecase) *DroneCommandHandler {
+	return &DroneCommandHandler{uc: uc}
+}
+
+type issueCommandRequest struct {
+	Type    string   `json:"type" binding:"required"`
+	OrderID *int64   `json:"order_id,omitempty"`
+	Lat     *float64 `json:"lat,omitempty"`
+	Lng     *float64 `json:"lng,omitempty"`
+}
+
+type droneCommandResponse struct {
+	CommandID   int64      `json:"command_id"`
+	DroneID     int64      `json:"drone_id"`
+	Type        string     `json:"type"`
+	OrderID     *int64     `json:"order_id,omitempty"`
+	Lat         *float64   `json:"lat,omitempty"`
+	Lng         *float64   `json:"lng,omitempty"`
+	IssuedBy    *int64     `json:"issued_by,omitempty"`
+	Status      string     `json:"status"`
+	Note        *string    `json:"note,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	SentAt      *time.Time `json:"sent_at,omitempty"`
+	AckedAt     *time.Time `json:"acked_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+type droneCommandListResponse struct {
+	Data []droneCommandResponse `json:"data"`
+	Meta paginationMeta         `json:"meta"`
+}
+
+// commandMessage is what the drone receives over its websocket.
+type commandMessage struct {
+	Type      string    `json:"type"`
+	CommandID int64     `json:"command_id"`
+	Command   string    `json:"command"`
+	OrderID   *int64    `json:"order_id,omitempty"`
+	Lat       *float64  `json:"lat,omitempty"`
+	Lng       *float64  `json:"lng,omitempty"`
+	IssuedAt  time.Time `json:"issued_at"`
+}
+
+// commandReportRequest is a drone's command_ack (accepted|rejected) or
+// command_result (completed|failed).
+type commandReportRequest struct {
+	Type      string `json:"type"`
+	CommandID int64  `json:"command_id"`
+	Status    string `json:"status"`
+	Note      string `json:"note,omitempty"`
+}
+
+type commandReportResponse struct {
+	Type          string `json:"type"`
+	CommandID     int64  `json:"command_id"`
+	Status        string `json:"status"`
+	CommandStatus string `json:"command_status"`
+	Message       string `json:"message"`
+}
+
+// IssueCommand godoc
+// @Summary Send a command to a drone (admin)
+// @Description Push return_to_home (optional lat/lng of the depot), hold_position, land_now, divert or reposition (need lat/lng) or cancel_assignment (needs order_id) over the drone's websocket.
+// @Description The command is stored either way; status is `sent` when it reached the drone and `undelivered` when the drone was not connected.
+// @Description The drone answers with `command_ack` (accepted|rejected) and later `command_result` (completed|failed).
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drone ID"
+// @Param command body issueCommandRequest true "Command"
+// @Success 201 {object} droneCommandResponse "Command issued"
+// @Failure 400 {object} map[string]string "Invalid request or command"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 404 {object} map[string]string "Drone not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/drones/{id}/commands [post]
+func (h *DroneCommandHandler) IssueCommand(c *gin.Context) {
+	droneID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || droneID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_drone_id", "message": "invalid drone id"})
+		return
+	}
+
+	var req issueCommandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "type is required"})
+		return
+	}
+
+	adminID, err := extractSubjectID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized", "message": err.Error()})
+		return
+	}
+
+	cmd, err := h.uc.IssueCommand(c.Request.Context(), model.CreateDroneCommandRequest{
+		DroneID:  droneID,
+		Type:     model.CommandType(strings.ToLower(req.Type)),
+		OrderID:  req.OrderID,
+		Lat:      req.Lat,
+		Lng:      req.Lng,
+		IssuedBy: &adminID,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toDroneCommandResponse(*cmd))
+}
+
+// ListDroneCommands godoc
+// @Summary Command history for a drone (admin)
+// @Description Commands sent to the drone with their delivery and ack status, newest first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drone ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Page size (default: 20)"
+// @Success 200 {object} droneCommandListResponse "Commands"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 404 {object} map[string]string "Drone not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/drones/{id}/commands [get]
+func (h *DroneCommandHandler) ListDroneCommands(c *gin.Context) {
+	droneID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || droneID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_drone_id", "message": "invalid drone id"})
+		return
+	}
+
+	page, pageSize, err := parsePaginationParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
+		return
+	}
+
+	commands, pagination, err := h.uc.ListDroneCommands(c.Request.Context(), droneID, page, pageSize)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	data := make([]droneCommandResponse, len(commands))
+	for i := range commands {
+		data[i] = toDroneCommandResponse(commands[i])
+	}
+
+	c.JSON(http.StatusOK, droneCommandListResponse{
+		Data: data,
+		Meta: toPaginationMeta(pagination, len(commands)),
+	})
+}
+
+// GetDroneCommand godoc
+// @Summary Get a drone command (admin)
+// @Description Current delivery and ack status of one command
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drone ID"
+// @Param command_id path int true "Command ID"
+// @Success 200 {object} droneCommandResponse "Command"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 404 {object} map[string]string "Command not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/drones/{id}/commands/{command_id} [get]
+func (h *DroneCommandHandler) GetDroneCommand(c *gin.Context) {
+	droneID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || droneID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_drone_id", "message": "invalid drone id"})
+		return
+	}
+
+	commandID, err := strconv.ParseInt(c.Param("command_id"), 10, 64)
+	if err != nil || commandID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid command id"})
+		return
+	}
+
+	cmd, err := h.uc.GetCommand(c.Request.Context(), droneID, commandID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDroneCommandResponse(*cmd))
+}
+
+// CommandDispatcher delivers commands through the drone connection registry.
+type CommandDispatcher struct {
+	registry *ConnectionRegistry
+}
+
+func NewCommandDispatcher(registry *ConnectionRegistry) *CommandDispatcher {
+	return &CommandDispatcher{registry: registry}
+}
+
+func (d *CommandDispatcher) SendCommand(ctx context.Context, cmd model.DroneCommand) error {
+	return d.registry.Send(cmd.DroneID, toCommandMessage(cmd))
+}
+
+func toCommandMessage(cmd model.DroneCommand) commandMessage {
+	return commandMessage{
+		Type:      messageTypeCommand,
+		CommandID: cmd.ID,
+		Command:   string(cmd.Type),
+		OrderID:   cmd.OrderID,
+		Lat:       cmd.Lat,
+		Lng:       cmd.Lng,
+		IssuedAt:  cmd.CreatedAt,
+	}
+}
+
+func toDroneCommandResponse(cmd model.DroneCommand) droneCommandResponse {
+	return droneCommandResponse{
+		CommandID:   cmd.ID,
+		DroneID:     cmd.DroneID,
+		Type:        string(cmd.Type),
+		OrderID:     cmd.OrderID,
+		Lat:         cmd.Lat,
+		Lng:         cmd.Lng,
+		IssuedBy:    cmd.IssuedBy,
+		Status:      string(cmd.Status),
+		Note:        cmd.Note,
+		CreatedAt:   cmd.CreatedAt,
+		SentAt:      cmd.SentAt,
+		AckedAt:     cmd.AckedAt,
+		CompletedAt: cmd.CompletedAt,
+	}
+}
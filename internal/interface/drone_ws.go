@@ -19,6 +19,8 @@ import (
 const (
 	messageTypeHeartbeat     = "heartbeat"
 	messageTypeAssignmentAck = "assignment_ack"
+	messageTypeResume        = "resume"
+	messageTypeAck           = "ack"
 )
 
 type DroneHeartbeatUsecase interface {
@@ -27,13 +29,21 @@ type DroneHeartbeatUsecase interface {
 
 type DroneWSHandler struct {
 	uc       DroneHeartbeatUsecase
+	commands DroneCommandAckUsecase
 	registry *ConnectionRegistry
 	upgrader websocket.Upgrader
 }
 
 type heartbeatRequest struct {
-	Lat *float64 `json:"lat"`
-	Lng *float64 `json:"lng"`
+	Lat          *float64   `json:"lat"`
+	Lng          *float64   `json:"lng"`
+	AltitudeM    *float64   `json:"altitude_m"`
+	HeadingDeg   *float64   `json:"heading_deg"`
+	SpeedMPS     *float64   `json:"speed_mps"`
+	BatteryPct   *float64   `json:"battery_pct"`
+	GPSFix       string     `json:"gps_fix"`
+	GPSAccuracyM *float64   `json:"gps_accuracy_m"`
+	DeviceTime   *time.Time `json:"device_time"`
 }
 
 type heartbeatResponse struct {
@@ -57,6 +67,25 @@ type assignmentAckResponse struct {
 	Message string `json:"message"`
 }
 
+// resumeRequest is sent by a reconnecting drone with the highest seq it has
+// received; ackRequest confirms everything up to seq.
+type resumeRequest struct {
+	Type    string `json:"type"`
+	LastSeq *int64 `json:"last_seq"`
+}
+
+type ackRequest struct {
+	Type string `json:"type"`
+	Seq  *int64 `json:"seq"`
+}
+
+type resumeResponse struct {
+	Type     string `json:"type"`
+	Message  string `json:"message"`
+	LastSeq  int64  `json:"last_seq"`
+	Replayed int    `json:"replayed"`
+}
+
 type assignmentMessage struct {
 	Type        string    `json:"type"`
 	DroneID     int64     `json:"drone_id"`
@@ -65,15 +94,29 @@ type assignmentMessage struct {
 	PickupLng   float64   `json:"pickup_lng"`
 	DropoffLat  float64   `json:"dropoff_lat"`
 	DropoffLng  float64   `json:"dropoff_lng"`
+	ReturnLat   *float64  `json:"return_lat,omitempty"`
+	ReturnLng   *float64  `json:"return_lng,omitempty"`
+	HandoffLat  *float64  `json:"handoff_lat,omitempty"`
+	HandoffLng  *float64  `json:"handoff_lng,omitempty"`
+	TripID      *int64    `json:"trip_id,omitempty"`
 	EnduserID   int64     `json:"enduser_id"`
 	OrderStatus string    `json:"order_status"`
 	CreatedAt   time.Time `json:"created_at"`
 	Description string    `json:"description,omitempty"`
+	// Waypoints is the planned flight path around no-fly zones, from the
+	// drone's position via pickup to the destination.
+	Waypoints []waypointMessage `json:"waypoints"`
+}
+
+type waypointMessage struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
 }
 
-func NewDroneWSHandler(uc DroneHeartbeatUsecase, registry *ConnectionRegistry) *DroneWSHandler {
+func NewDroneWSHandler(uc DroneHeartbeatUsecase, commands DroneCommandAckUsecase, registry *ConnectionRegistry) *DroneWSHandler {
 	return &DroneWSHandler{
 		uc:       uc,
+		commands: commands,
 		registry: registry,
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool { return true },
@@ -93,9 +136,19 @@ func NewDroneWSHandler(uc DroneHeartbeatUsecase, registry *ConnectionRegistry) *
 // @Description {
 // @Description   "type": "heartbeat",
 // @Description   "lat": 40.7128,
-// @Description   "lng": -74.0060
+// @Description   "lng": -74.0060,
+// @Description   "altitude_m": 120.5,
+// @Description   "heading_deg": 270,
+// @Description   "speed_mps": 12.4,
+// @Description   "battery_pct": 87.5,
+// @Description   "gps_fix": "3d",
+// @Description   "gps_accuracy_m": 2.5,
+// @Description   "device_time": "2025-11-10T12:00:00.250Z"
 // @Description }
 // @Description ```
+// @Description Everything but lat/lng is optional and stored in the telemetry history. speed_mps is ground speed (0-100) and drives ETAs while the drone is moving;
+// @Description heading_deg is in [0, 360), gps_fix is one of none, 2d, 3d, dgps, rtk (none is rejected). A heartbeat whose device_time is not newer than the last
+// @Description applied one is rejected as stale, as is one more than 5 minutes ahead of the server clock.
 // @Description
 // @Description 2. **Heartbeat Response** (Server → Drone):
 // @Description ```json
@@ -118,9 +171,12 @@ func NewDroneWSHandler(uc DroneHeartbeatUsecase, registry *ConnectionRegistry) *
 // @Description   "dropoff_lng": -73.9855,
 // @Description   "enduser_id": 456,
 // @Description   "order_status": "reserved",
-// @Description   "created_at": "2025-11-10T12:00:00Z"
+// @Description   "created_at": "2025-11-10T12:00:00Z",
+// @Description   "description": "new_order | handoff | return_handoff",
+// @Description   "waypoints": [{"lat": 40.7000, "lng": -74.0100}, {"lat": 40.7128, "lng": -74.0060}, {"lat": 40.7580, "lng": -73.9855}]
 // @Description }
 // @Description ```
+// @Description For `handoff` and `return_handoff` the parcel is collected at `handoff_lat`/`handoff_lng` (where the previous drone broke down, or the landing site it was sent to) instead of the pickup; the waypoints already lead there.
 // @Description
 // @Description 4. **Assignment Acknowledgment** (Drone → Server):
 // @Description ```json
@@ -130,6 +186,79 @@ func NewDroneWSHandler(uc DroneHeartbeatUsecase, registry *ConnectionRegistry) *
 // @Description   "status": "accepted"
 // @Description }
 // @Description ```
+// @Description
+// @Description 5. **Command** (Server → Drone), issued via `POST /admin/drones/{id}/commands`:
+// @Description ```json
+// @Description {
+// @Description   "type": "command",
+// @Description   "command_id": 42,
+// @Description   "command": "return_to_home | hold_position | land_now | divert | cancel_assignment | reposition",
+// @Description   "order_id": 123,
+// @Description   "lat": 40.7000,
+// @Description   "lng": -74.0100,
+// @Description   "issued_at": "2025-11-10T12:00:00Z"
+// @Description }
+// @Description ```
+// @Description
+// @Description 6. **Command Ack / Result** (Drone → Server), answered with the same type plus `command_status`:
+// @Description ```json
+// @Description {
+// @Description   "type": "command_ack | command_result",
+// @Description   "command_id": 42,
+// @Description   "status": "accepted | rejected | completed | failed",
+// @Description   "note": "optional"
+// @Description }
+// @Description ```
+// @Description
+// @Description 7. **Order Update** (Server → Drone), sent to the drone an order is assigned to or was last offered to:
+// @Description ```json
+// @Description {
+// @Description   "type": "order_update",
+// @Description   "event": "order_canceled | route_updated | handoff_required",
+// @Description   "drone_id": 1,
+// @Description   "order_id": 123,
+// @Description   "order_status": "canceled",
+// @Description   "pickup_lat": 40.7128,
+// @Description   "pickup_lng": -74.0060,
+// @Description   "dropoff_lat": 40.7580,
+// @Description   "dropoff_lng": -73.9855,
+// @Description   "handoff_lat": 40.7300,
+// @Description   "handoff_lng": -74.0000,
+// @Description   "waypoints": [{"lat": 40.7000, "lng": -74.0100}, {"lat": 40.7128, "lng": -74.0060}, {"lat": 40.7580, "lng": -73.9855}],
+// @Description   "created_at": "2025-11-10T12:00:00Z"
+// @Description }
+// @Description ```
+// @Description waypoints come with route_updated, handoff_lat/handoff_lng with handoff_required for a parcel already on board.
+// @Description
+// @Description 8. **Order Update Ack** (Drone → Server), answered with the same type and `"message": "acknowledged"`:
+// @Description ```json
+// @Description {
+// @Description   "type": "order_update_ack",
+// @Description   "order_id": 123,
+// @Description   "event": "order_canceled",
+// @Description   "status": "accepted | rejected",
+// @Description   "note": "optional"
+// @Description }
+// @Description ```
+// @Description
+// @Description **Reliable delivery:** every message the server pushes on its own (assignment, command, order_update, geofence_breach) carries a per-drone `seq`.
+// @Description Unacknowledged messages are buffered for the resume window; messages sent while the drone is away are delivered when it reconnects.
+// @Description Messages may arrive out of order after a reconnect, so drones should order and de-duplicate by `seq`.
+// @Description Behind several API nodes, messages are relayed to the node holding the connection, which numbers and buffers them; a drone that reconnects to another node gets a fresh session.
+// @Description
+// @Description 9. **Ack** (Drone → Server), no reply; confirms everything up to and including `seq`:
+// @Description ```json
+// @Description { "type": "ack", "seq": 17 }
+// @Description ```
+// @Description
+// @Description 10. **Resume** (Drone → Server) after reconnecting, with the highest `seq` received; everything after it is replayed, then:
+// @Description ```json
+// @Description { "type": "resume", "message": "ok", "last_seq": 19, "replayed": 2 }
+// @Description ```
+// @Description After a server restart, or when the drone resumes on another API node, numbering continues from the drone's `last_seq`; unacked messages held by the node it left follow with new seqs.
+// @Description
+// @Description **Keepalive:** the server sends a websocket ping every WS_PING_INTERVAL (25s) and closes the connection when nothing, pong included,
+// @Description arrives for WS_PONG_WAIT (60s). Outgoing messages are queued (WS_SEND_BUFFER, 64); a drone that falls that far behind is disconnected and should resume.
 // @Tags drone-websocket
 // @Accept json
 // @Produce json
@@ -168,7 +297,7 @@ func (h *DroneWSHandler) HandleHeartbeat(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	for {
-		_, raw, err := conn.ReadMessage()
+		raw, err := client.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("heartbeat websocket read error: %v", err)
@@ -203,6 +332,34 @@ func (h *DroneWSHandler) HandleHeartbeat(c *gin.Context) {
 				continue
 			}
 			h.processAssignmentAck(client, droneID, ack)
+		case messageTypeCommandAck, messageTypeCommandResult:
+			var report commandReportRequest
+			if err := json.Unmarshal(raw, &report); err != nil {
+				h.writeError(client, fmt.Errorf("invalid %s payload: %w", msgType, err))
+				continue
+			}
+			h.processCommandReport(ctx, client, droneID, msgType, report)
+		case messageTypeOrderUpdateAck:
+			var ack orderUpdateAckRequest
+			if err := json.Unmarshal(raw, &ack); err != nil {
+				h.writeError(client, fmt.Errorf("invalid order update ack payload: %w", err))
+				continue
+			}
+			h.processOrderUpdateAck(client, droneID, ack)
+		case messageTypeResume:
+			var req resumeRequest
+			if err := json.Unmarshal(raw, &req); err != nil {
+				h.writeError(client, fmt.Errorf("invalid resume payload: %w", err))
+				continue
+			}
+			h.processResume(client, droneID, req)
+		case messageTypeAck:
+			var ack ackRequest
+			if err := json.Unmarshal(raw, &ack); err != nil || ack.Seq == nil || *ack.Seq < 0 {
+				h.writeError(client, errors.New("ack requires a non-negative seq"))
+				continue
+			}
+			h.registry.Ack(droneID, *ack.Seq)
 		default:
 			h.writeError(client, fmt.Errorf("unknown message type: %s", envelope.Type))
 		}
@@ -222,6 +379,29 @@ func (h *DroneWSHandler) NotifyAssignment(ctx context.Context, notice model.Assi
 	return nil
 }
 
+func (h *DroneWSHandler) processResume(client *wsClient, droneID int64, req resumeRequest) {
+	if req.LastSeq == nil || *req.LastSeq < 0 {
+		h.writeError(client, errors.New("resume requires a non-negative last_seq"))
+		return
+	}
+
+	replayed, lastSeq, err := h.registry.Resume(droneID, client, *req.LastSeq)
+	if err != nil {
+		log.Printf("resume for drone %d failed after %d messages: %v", droneID, replayed, err)
+		return
+	}
+
+	resp := resumeResponse{
+		Type:     messageTypeResume,
+		Message:  "ok",
+		LastSeq:  lastSeq,
+		Replayed: replayed,
+	}
+	if err := client.Send(resp); err != nil {
+		log.Printf("resume response error: %v", err)
+	}
+}
+
 func (h *DroneWSHandler) processHeartbeat(ctx context.Context, client *wsClient, droneID int64, payload heartbeatRequest) {
 	hb, err := toHeartbeatModel(payload)
 	if err != nil {
@@ -256,6 +436,44 @@ func (h *DroneWSHandler) processAssignmentAck(client *wsClient, droneID int64, a
 	}
 }
 
+func (h *DroneWSHandler) processCommandReport(ctx context.Context, client *wsClient, droneID int64, msgType string, report commandReportRequest) {
+	if report.CommandID == 0 {
+		h.writeError(client, fmt.Errorf("command_id is required for %s", msgType))
+		return
+	}
+
+	status := strings.ToLower(report.Status)
+	var (
+		cmd *model.DroneCommand
+		err error
+	)
+	switch {
+	case msgType == messageTypeCommandAck && (status == "accepted" || status == "rejected"):
+		cmd, err = h.commands.AcknowledgeCommand(ctx, droneID, report.CommandID, status == "accepted", report.Note)
+	case msgType == messageTypeCommandResult && (status == "completed" || status == "failed"):
+		cmd, err = h.commands.CompleteCommand(ctx, droneID, report.CommandID, status == "completed", report.Note)
+	case msgType == messageTypeCommandAck:
+		err = errors.New("status must be accepted or rejected")
+	default:
+		err = errors.New("status must be completed or failed")
+	}
+	if err != nil {
+		h.writeError(client, err)
+		return
+	}
+
+	resp := commandReportResponse{
+		Type:          msgType,
+		CommandID:     cmd.ID,
+		Status:        status,
+		CommandStatus: string(cmd.Status),
+		Message:       "recorded",
+	}
+	if err := client.Send(resp); err != nil {
+		log.Printf("%s response error: %v", msgType, err)
+	}
+}
+
 func (h *DroneWSHandler) writeOK(client *wsClient) {
 	resp := toHeartbeatResponse("ok", nil)
 	if err := client.Send(resp); err != nil {
@@ -293,6 +511,15 @@ func toHeartbeatModel(req heartbeatRequest) (model.DroneHeartbeat, error) {
 	return model.DroneHeartbeat{
 		Lat: *req.Lat,
 		Lng: *req.Lng,
+		FlightReadings: model.FlightReadings{
+			AltitudeM:      req.AltitudeM,
+			HeadingDeg:     req.HeadingDeg,
+			GroundSpeedMPS: req.SpeedMPS,
+			BatteryPct:     req.BatteryPct,
+			GPSFix:         model.GPSFix(strings.ToLower(req.GPSFix)),
+			GPSAccuracyM:   req.GPSAccuracyM,
+			DeviceTime:     req.DeviceTime,
+		},
 	}, nil
 }
 
@@ -309,6 +536,11 @@ func toHeartbeatResponse(msg string, err error) heartbeatResponse {
 }
 
 func toAssignmentMessage(notice model.AssignmentNotice) assignmentMessage {
+	waypoints := make([]waypointMessage, len(notice.Waypoints))
+	for i, p := range notice.Waypoints {
+		waypoints[i] = waypointMessage{Lat: p.Lat, Lng: p.Lng}
+	}
+
 	return assignmentMessage{
 		Type:        "assignment",
 		DroneID:     notice.DroneID,
@@ -317,10 +549,16 @@ func toAssignmentMessage(notice model.AssignmentNotice) assignmentMessage {
 		PickupLng:   notice.PickupLng,
 		DropoffLat:  notice.DropoffLat,
 		DropoffLng:  notice.DropoffLng,
+		ReturnLat:   notice.ReturnLat,
+		ReturnLng:   notice.ReturnLng,
+		HandoffLat:  notice.HandoffLat,
+		HandoffLng:  notice.HandoffLng,
+		TripID:      notice.TripID,
 		EnduserID:   notice.EnduserID,
 		OrderStatus: string(notice.OrderStatus),
 		CreatedAt:   time.Now().UTC(),
 		Description: string(notice.Description),
+		Waypoints:   waypoints,
 	}
 }
 
@@ -1,100 +1,340 @@
 package iface
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
 )
 
-var (
-	errClientClosed      = errors.New("websocket client closed")
-	ErrDroneNotConnected = errors.New("drone websocket not connected")
-)
-
-type wsClient struct {
-	conn     *websocket.Conn
-	writeMu  sync.Mutex
-	isClosed atomic.Bool
-}
-
-func newWSClient(conn *websocket.Conn) *wsClient {
-	return &wsClient{conn: conn}
-}
-
-func (c *wsClient) Send(v interface{}) error {
-	c.writeMu.Lock()
-	defer c.writeMu.Unlock()
+var ErrDroneNotConnected = errors.New("drone websocket not connected")
 
-	if c.isClosed.Load() {
-		return errClientClosed
-	}
-
-	_ = c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-	if err := c.conn.WriteJSON(v); err != nil {
-		return err
-	}
-	return nil
+// outboundMessage is a sequenced message kept until the drone acknowledges
+// its seq. writtenTo is the connection it was last written to, nil while it
+// has not reached any.
+type outboundMessage struct {
+	seq       int64
+	data      []byte
+	queuedAt  time.Time
+	writtenTo *wsClient
 }
 
-func (c *wsClient) Close() {
-	if c.isClosed.CompareAndSwap(false, true) {
-		c.writeMu.Lock()
-		_ = c.conn.Close()
-		c.writeMu.Unlock()
-	}
+// droneSession outlives individual connections so messages sent while the
+// drone is briefly away can be replayed when it reconnects. previousNode is
+// the other node the drone was connected to before this connection; its
+// buffer is taken over when the drone resumes.
+type droneSession struct {
+	mu             sync.Mutex
+	client         *wsClient
+	lastSeq        int64
+	pending        []outboundMessage
+	disconnectedAt time.Time
+	previousNode   string
 }
 
+// ConnectionRegistry tracks the live websocket of every drone and numbers
+// everything pushed to it with a per-drone seq. Messages stay buffered until
+// the drone acks them; a drone that reconnects within the resume window gets
+// whatever it missed. Once it has joined a cluster, messages for drones held
+// by other nodes are relayed to them, and a drone that resumes on another
+// node has its unacked messages handed over.
 type ConnectionRegistry struct {
-	mu      sync.RWMutex
-	clients map[int64]*wsClient
+	mu       sync.RWMutex
+	sessions map[int64]*droneSession
+	cfg      WSConfig
+	metrics  *WSMetrics
+	cluster  *clusterLink
 }
 
-func NewConnectionRegistry() *ConnectionRegistry {
+func NewConnectionRegistry(cfg WSConfig, metrics *WSMetrics) *ConnectionRegistry {
 	return &ConnectionRegistry{
-		clients: make(map[int64]*wsClient),
+		sessions: make(map[int64]*droneSession),
+		cfg:      cfg,
+		metrics:  metrics,
 	}
 }
 
+// Register attaches a new connection to the drone's session, replacing any
+// previous one, and flushes messages queued while it was away.
 func (r *ConnectionRegistry) Register(droneID int64, conn *websocket.Conn) *wsClient {
-	client := newWSClient(conn)
+	client := newWSClient(conn, r.cfg, r.metrics, wsKindDrone)
+	previous := r.previousNode(droneID)
 
 	r.mu.Lock()
-	if existing, ok := r.clients[droneID]; ok {
-		existing.Close()
+	session, ok := r.sessions[droneID]
+	if !ok {
+		session = &droneSession{}
+		r.sessions[droneID] = session
 	}
-	r.clients[droneID] = client
 	r.mu.Unlock()
 
+	session.mu.Lock()
+	if session.client != nil {
+		session.client.closeWith(disconnectReplaced)
+	}
+	session.client = client
+	session.previousNode = previous
+	r.prune(droneID, session, time.Now())
+
+	for i := range session.pending {
+		if session.pending[i].writtenTo != nil {
+			continue
+		}
+		if err := r.write(session, &session.pending[i]); err != nil {
+			break
+		}
+	}
+	session.mu.Unlock()
+
+	r.announce(droneID)
+
 	return client
 }
 
 func (r *ConnectionRegistry) Unregister(droneID int64, client *wsClient) {
-	r.mu.Lock()
-	if curr, ok := r.clients[droneID]; ok && curr == client {
-		delete(r.clients, droneID)
+	r.mu.RLock()
+	session, ok := r.sessions[droneID]
+	r.mu.RUnlock()
+
+	if ok {
+		session.mu.Lock()
+		if session.client == client {
+			session.detach()
+		}
+		// a failed write may have detached the client already; only a newer
+		// connection on this node keeps the presence
+		replaced := session.client != nil
+		session.mu.Unlock()
+
+		if !replaced {
+			r.withdraw(droneID)
+		}
 	}
-	r.mu.Unlock()
 
 	if client != nil {
 		client.Close()
 	}
 }
 
+// Send numbers the payload and writes it to the drone, or relays it to the
+// node holding the drone's connection. The message is kept for replay until
+// acked, so ErrDroneNotConnected for a drone that dropped within the resume
+// window does not mean the message is lost.
 func (r *ConnectionRegistry) Send(droneID int64, payload interface{}) error {
+	if !r.connectedHere(droneID) {
+		if nodeID, ok := r.remoteNode(droneID); ok {
+			return r.relay(nodeID, droneID, payload)
+		}
+	}
+	return r.sendLocal(droneID, payload)
+}
+
+func (r *ConnectionRegistry) connectedHere(droneID int64) bool {
+	r.mu.RLock()
+	session, ok := r.sessions[droneID]
+	r.mu.RUnlock()
+
+	if !ok {
+		return false
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.client != nil
+}
+
+func (r *ConnectionRegistry) sendLocal(droneID int64, payload interface{}) error {
 	r.mu.RLock()
-	client, ok := r.clients[droneID]
+	session, ok := r.sessions[droneID]
 	r.mu.RUnlock()
 
 	if !ok {
 		return ErrDroneNotConnected
 	}
 
-	if err := client.Send(payload); err != nil {
-		r.Unregister(droneID, client)
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	now := time.Now()
+	if session.client == nil && now.Sub(session.disconnectedAt) > r.cfg.ResumeWindow {
+		session.pending = nil
+		return ErrDroneNotConnected
+	}
+
+	data, err := withSeq(payload, session.lastSeq+1)
+	if err != nil {
+		return err
+	}
+	session.lastSeq++
+	session.pending = append(session.pending, outboundMessage{seq: session.lastSeq, data: data, queuedAt: now})
+	r.prune(droneID, session, now)
+
+	if session.client == nil {
+		return ErrDroneNotConnected
+	}
+	return r.write(session, &session.pending[len(session.pending)-1])
+}
+
+// Ack drops every buffered message up to and including seq.
+func (r *ConnectionRegistry) Ack(droneID, seq int64) {
+	r.mu.RLock()
+	session, ok := r.sessions[droneID]
+	r.mu.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	session.mu.Lock()
+	session.ack(seq)
+	session.mu.Unlock()
+}
+
+// Resume acks everything up to lastSeq and writes the rest of the buffer
+// again, skipping what already went out on this connection. It returns how
+// many messages were replayed and the latest seq handed out.
+//
+// A lastSeq that is not this node's numbering, because the server restarted
+// or the drone was connected to another node, would make the drone drop
+// what follows as duplicates; the buffer is numbered on from lastSeq
+// instead and written again. The other node is asked to hand over what it
+// still holds, which arrives as new messages.
+func (r *ConnectionRegistry) Resume(droneID int64, client *wsClient, lastSeq int64) (int, int64, error) {
+	r.mu.RLock()
+	session, ok := r.sessions[droneID]
+	r.mu.RUnlock()
+
+	if !ok {
+		return 0, 0, ErrDroneNotConnected
+	}
+
+	session.mu.Lock()
+	if session.client != client {
+		seq := session.lastSeq
+		session.mu.Unlock()
+		return 0, seq, ErrDroneNotConnected
+	}
+
+	previous := session.previousNode
+	session.previousNode = ""
+	if previous == "" && lastSeq <= session.lastSeq {
+		session.ack(lastSeq)
+	} else if err := session.renumber(lastSeq); err != nil {
+		seq := session.lastSeq
+		session.mu.Unlock()
+		return 0, seq, err
+	}
+
+	replayed := 0
+	var err error
+	for i := range session.pending {
+		if session.pending[i].writtenTo == client {
+			continue
+		}
+		if err = r.write(session, &session.pending[i]); err != nil {
+			break
+		}
+		replayed++
+	}
+	seq := session.lastSeq
+	session.mu.Unlock()
+
+	if previous != "" {
+		r.requestTakeover(previous, droneID, lastSeq)
+	}
+	return replayed, seq, err
+}
+
+// write queues one buffered message on the session's connection. A message
+// the client was too slow for stays unwritten for the next flush or resume;
+// any other failure drops the connection.
+func (r *ConnectionRegistry) write(session *droneSession, msg *outboundMessage) error {
+	client := session.client
+	if err := client.SendRaw(msg.data); err != nil {
+		if !errors.Is(err, errMessageDropped) {
+			session.detach()
+			client.Close()
+		}
 		return err
 	}
+	msg.writtenTo = client
 	return nil
 }
+
+// prune drops messages older than the resume window and, past the buffer
+// size, the oldest ones. Drones that never ack only keep a window's worth.
+func (r *ConnectionRegistry) prune(droneID int64, session *droneSession, now time.Time) {
+	drop := 0
+	for drop < len(session.pending) && now.Sub(session.pending[drop].queuedAt) > r.cfg.ResumeWindow {
+		drop++
+	}
+	if overflow := len(session.pending) - drop - r.cfg.ResumeBuffer; overflow > 0 {
+		log.Printf("drone %d outbound buffer full, dropping %d unacked messages", droneID, overflow)
+		drop += overflow
+	}
+	if drop > 0 {
+		session.pending = append(session.pending[:0], session.pending[drop:]...)
+	}
+}
+
+func (s *droneSession) detach() {
+	s.client = nil
+	s.disconnectedAt = time.Now()
+}
+
+// renumber moves the buffer past lastSeq, the highest seq the drone has
+// seen in some other numbering, so that every message is written again.
+func (s *droneSession) renumber(lastSeq int64) error {
+	next := max(lastSeq, s.lastSeq)
+	for i := range s.pending {
+		data, err := withSeq(json.RawMessage(s.pending[i].data), next+1)
+		if err != nil {
+			return err
+		}
+		next++
+		s.pending[i].seq = next
+		s.pending[i].data = data
+		s.pending[i].writtenTo = nil
+	}
+	s.lastSeq = next
+	return nil
+}
+
+func (s *droneSession) ack(seq int64) {
+	drop := 0
+	for drop < len(s.pending) && s.pending[drop].seq <= seq {
+		drop++
+	}
+	if drop > 0 {
+		s.pending = append(s.pending[:0], s.pending[drop:]...)
+	}
+}
+
+// withSeq adds the seq field to a JSON object payload.
+func withSeq(payload interface{}, seq int64) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("sequenced message must be a JSON object: %w", err)
+	}
+	fields["seq"] = json.RawMessage(fmt.Sprint(seq))
+	return json.Marshal(fields)
+}
+
+// withoutSeq strips the seq field from a sequenced message.
+func withoutSeq(data []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "seq")
+	return json.Marshal(fields)
+}
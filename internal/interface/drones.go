@@ -3,6 +3,7 @@ package iface
 import (
 	"context"
 	"errors"
+	"math"
 	"net/http"
 	"strconv"
 	"time"
@@ -12,9 +13,12 @@ import (
 )
 
 type DroneOpsUsecase interface {
-	ReportBroken(ctx context.Context, actorID, droneID int64, actorRole model.Role, location model.DroneHeartbeat) (*model.Drone, *model.Order, error)
-	ReportFixed(ctx context.Context, actorID, droneID int64, actorRole model.Role, location model.DroneHeartbeat) (*model.Drone, error)
+	ReportBroken(ctx context.Context, actorID, droneID int64, actorRole model.Role, location model.DroneHeartbeat) (*model.Drone, []model.Order, error)
+	ReportFixed(ctx context.Context, actorID, droneID int64, actorRole model.Role, location model.DroneHeartbeat, repair model.MaintenanceDetails) (*model.Drone, error)
 	ListDrones(ctx context.Context, page, pageSize int) ([]model.Drone, model.Pagination, error)
+	GetDrone(ctx context.Context, droneID int64) (*model.Drone, error)
+	UpdateCapacity(ctx context.Context, droneID int64, capacity int) (*model.Drone, error)
+	GetTrip(ctx context.Context, actorID, droneID int64, actorRole model.Role) (*model.Drone, *model.Trip, error)
 }
 
 type DroneHandler struct {
@@ -30,16 +34,72 @@ type droneLocationRequest struct {
 	Lng *float64 `json:"lng,omitempty"`
 }
 
+// droneFixedRequest carries the repair details for the drone's open
+// maintenance record along with its location.
+type droneFixedRequest struct {
+	Lat        *float64 `json:"lat,omitempty"`
+	Lng        *float64 `json:"lng,omitempty"`
+	Notes      *string  `json:"notes,omitempty" example:"replaced the rear left motor"`
+	Parts      []string `json:"parts,omitempty"`
+	Technician *string  `json:"technician,omitempty" example:"Sami"`
+}
+
+type droneCapacityRequest struct {
+	Capacity *int `json:"capacity"`
+}
+
 type droneStatusResponse struct {
-	DroneID           int64      `json:"drone_id"`
-	Status            string     `json:"status"`
-	Lat               float64    `json:"lat"`
-	Lng               float64    `json:"lng"`
-	CurrentOrderID    *int64     `json:"current_order_id,omitempty"`
-	HandoffOrderID    *int64     `json:"handoff_order_id,omitempty"`
-	OrderStatus       *string    `json:"order_status,omitempty"`
-	AssignmentPending bool       `json:"assignment_pending"`
-	LastHeartbeat     *time.Time `json:"last_heartbeat,omitempty"`
+	DroneID           int64              `json:"drone_id"`
+	Status            string             `json:"status"`
+	Lat               float64            `json:"lat"`
+	Lng               float64            `json:"lng"`
+	Capacity          int                `json:"capacity"`
+	ActiveOrders      int                `json:"active_orders"`
+	CurrentOrderID    *int64             `json:"current_order_id,omitempty"`
+	CurrentTripID     *int64             `json:"current_trip_id,omitempty"`
+	HomeDepotID       *int64             `json:"home_depot_id,omitempty"`
+	DepotID           *int64             `json:"depot_id,omitempty"`
+	HandoffOrderID    *int64             `json:"handoff_order_id,omitempty"`
+	HandoffOrderIDs   []int64            `json:"handoff_order_ids,omitempty"`
+	OrderStatus       *string            `json:"order_status,omitempty"`
+	AssignmentPending bool               `json:"assignment_pending"`
+	LastHeartbeat     *time.Time         `json:"last_heartbeat,omitempty"`
+	AltitudeM         *float64           `json:"altitude_m,omitempty"`
+	HeadingDeg        *float64           `json:"heading_deg,omitempty"`
+	SpeedMPS          *float64           `json:"speed_mps,omitempty"`
+	BatteryPct        *float64           `json:"battery_pct,omitempty"`
+	GPSFix            string             `json:"gps_fix,omitempty"`
+	GPSAccuracyM      *float64           `json:"gps_accuracy_m,omitempty"`
+	DeviceTime        *time.Time         `json:"device_time,omitempty"`
+	Usage             droneUsageResponse `json:"usage"`
+}
+
+// droneUsageResponse is what the drone has done over its life; airborne
+// time is the time spent delivering, including the current stretch.
+type droneUsageResponse struct {
+	Deliveries      int     `json:"deliveries"`
+	Failures        int     `json:"failures"`
+	Breakdowns      int     `json:"breakdowns"`
+	DistanceM       float64 `json:"distance_m"`
+	AirborneSeconds float64 `json:"airborne_seconds"`
+}
+
+type tripStopResponse struct {
+	Sequence    int        `json:"sequence"`
+	OrderID     int64      `json:"order_id"`
+	Kind        string     `json:"kind"`
+	Lat         float64    `json:"lat"`
+	Lng         float64    `json:"lng"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+type tripResponse struct {
+	TripID    int64              `json:"trip_id"`
+	DroneID   int64              `json:"drone_id"`
+	Status    string             `json:"status"`
+	NextStop  *tripStopResponse  `json:"next_stop,omitempty"`
+	Stops     []tripStopResponse `json:"stops"`
+	CreatedAt time.Time          `json:"created_at"`
 }
 
 type paginationMeta struct {
@@ -55,7 +115,8 @@ type droneListResponse struct {
 
 // MarkBroken godoc
 // @Summary Report drone as broken
-// @Description Report that a drone is broken (can be called by drone or admin)
+// @Description Report that a drone is broken (can be called by drone or admin).
+// @Description Every order on the drone's trip is handed off; the first is echoed in handoff_order_id.
 // @Tags drones
 // @Accept json
 // @Produce json
@@ -99,29 +160,31 @@ func (h *DroneHandler) MarkBroken(c *gin.Context) {
 		Lng: *req.Lng,
 	}
 
-	drone, order, err := h.ops.ReportBroken(c.Request.Context(), subjectID, droneID, model.Role(subjectRole), location)
+	drone, orders, err := h.ops.ReportBroken(c.Request.Context(), subjectID, droneID, model.Role(subjectRole), location)
 	if err != nil {
 		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, toDroneStatusResponse(drone, order))
+	c.JSON(http.StatusOK, toDroneStatusResponse(drone, orders))
 }
 
 // MarkFixed godoc
 // @Summary Mark drone as fixed (Admin action)
-// @Description Admin marks a broken drone as fixed and ready for operation
+// @Description Admin marks a broken drone as fixed and ready for operation; fixing an idle drone only updates its location, and a drone in maintenance or charging is rejected with 409
+// @Description Closes the drone's open maintenance record with the optional notes, parts and technician; without an open record, details are kept as a closed breakdown record
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Drone ID"
-// @Param location body droneLocationRequest true "Drone location"
+// @Param location body droneFixedRequest true "Drone location and repair details"
 // @Success 200 {object} droneStatusResponse "Drone marked as fixed"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 403 {object} map[string]string "Forbidden - Admin only"
 // @Failure 404 {object} map[string]string "Drone not found"
+// @Failure 409 {object} map[string]string "Drone is in maintenance or charging"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /admin/drones/{id}/fixed [post]
 func (h *DroneHandler) MarkFixed(c *gin.Context) {
@@ -144,15 +207,16 @@ func (h *DroneHandler) MarkFixed(c *gin.Context) {
 		return
 	}
 
-	var req droneLocationRequest
+	var req droneFixedRequest
 	if err := c.ShouldBindJSON(&req); err != nil || req.Lat == nil || req.Lng == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "lat and lng are required"})
 		return
 	}
 
 	location := model.DroneHeartbeat{Lat: *req.Lat, Lng: *req.Lng}
+	repair := model.MaintenanceDetails{Notes: req.Notes, Parts: req.Parts, Technician: req.Technician}
 
-	drone, err := h.ops.ReportFixed(c.Request.Context(), subjectID, droneID, model.Role(subjectRole), location)
+	drone, err := h.ops.ReportFixed(c.Request.Context(), subjectID, droneID, model.Role(subjectRole), location, repair)
 	if err != nil {
 		c.Error(err)
 		return
@@ -163,7 +227,7 @@ func (h *DroneHandler) MarkFixed(c *gin.Context) {
 
 // List godoc
 // @Summary List all drones (Admin action)
-// @Description Get a paginated list of all drones with their status
+// @Description Get a paginated list of all drones with their status and usage
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -193,22 +257,157 @@ func (h *DroneHandler) List(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// Get godoc
+// @Summary Get a drone (Admin action)
+// @Description Get a drone's status, latest readings and usage: deliveries, failures, breakdowns, distance flown between heartbeats and time spent delivering
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drone ID"
+// @Success 200 {object} droneStatusResponse "Drone"
+// @Failure 400 {object} map[string]string "Invalid drone ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 404 {object} map[string]string "Drone not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/drones/{id} [get]
+func (h *DroneHandler) Get(c *gin.Context) {
+	droneIDParam := c.Param("id")
+	droneID, err := strconv.ParseInt(droneIDParam, 10, 64)
+	if err != nil || droneID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_drone_id", "message": "invalid drone id"})
+		return
+	}
+
+	drone, err := h.ops.GetDrone(c.Request.Context(), droneID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDroneStatusResponse(drone, nil))
+}
+
+// UpdateCapacity godoc
+// @Summary Set drone carrying capacity (Admin action)
+// @Description Set how many orders a drone may carry on one multi-stop trip
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drone ID"
+// @Param capacity body droneCapacityRequest true "Capacity"
+// @Success 200 {object} droneStatusResponse "Drone capacity updated"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 404 {object} map[string]string "Drone not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/drones/{id} [patch]
+func (h *DroneHandler) UpdateCapacity(c *gin.Context) {
+	droneIDParam := c.Param("id")
+	droneID, err := strconv.ParseInt(droneIDParam, 10, 64)
+	if err != nil || droneID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_drone_id", "message": "invalid drone id"})
+		return
+	}
+
+	var req droneCapacityRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Capacity == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "capacity is required"})
+		return
+	}
+
+	drone, err := h.ops.UpdateCapacity(c.Request.Context(), droneID, *req.Capacity)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDroneStatusResponse(drone, nil))
+}
+
+// GetTrip godoc
+// @Summary Get a drone's active trip
+// @Description Ordered pickup/dropoff stops for the drone's current multi-stop trip (drone self or admin)
+// @Tags drones
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drone ID"
+// @Success 200 {object} tripResponse "Active trip"
+// @Failure 400 {object} map[string]string "Invalid drone ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Drone not found or not on a trip"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /drones/{id}/trip [get]
+func (h *DroneHandler) GetTrip(c *gin.Context) {
+	droneIDParam := c.Param("id")
+	droneID, err := strconv.ParseInt(droneIDParam, 10, 64)
+	if err != nil || droneID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_drone_id", "message": "invalid drone id"})
+		return
+	}
+
+	subjectID, err := extractSubjectID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized", "message": err.Error()})
+		return
+	}
+
+	subjectRole, err := extractSubjectRole(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized", "message": err.Error()})
+		return
+	}
+
+	_, trip, err := h.ops.GetTrip(c.Request.Context(), subjectID, droneID, model.Role(subjectRole))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toTripResponse(trip))
+}
+
 // Response converters
 
-func toDroneStatusResponse(drone *model.Drone, order *model.Order) droneStatusResponse {
+func toDroneStatusResponse(drone *model.Drone, handedOff []model.Order) droneStatusResponse {
 	resp := droneStatusResponse{
 		DroneID:        drone.ID,
 		Status:         string(drone.Status),
 		Lat:            drone.Lat,
 		Lng:            drone.Lng,
+		Capacity:       drone.Capacity,
+		ActiveOrders:   drone.ActiveOrders,
 		CurrentOrderID: drone.CurrentOrderID,
+		CurrentTripID:  drone.CurrentTripID,
+		HomeDepotID:    drone.HomeDepotID,
+		DepotID:        drone.DepotID,
 		LastHeartbeat:  drone.LastHeartbeat,
+		AltitudeM:      drone.Readings.AltitudeM,
+		HeadingDeg:     drone.Readings.HeadingDeg,
+		SpeedMPS:       drone.Readings.GroundSpeedMPS,
+		BatteryPct:     drone.Readings.BatteryPct,
+		GPSFix:         string(drone.Readings.GPSFix),
+		GPSAccuracyM:   drone.Readings.GPSAccuracyM,
+		DeviceTime:     drone.Readings.DeviceTime,
+		Usage: droneUsageResponse{
+			Deliveries:      drone.Usage.Deliveries,
+			Failures:        drone.Usage.Failures,
+			Breakdowns:      drone.Usage.Breakdowns,
+			DistanceM:       math.Round(drone.Usage.DistanceM*10) / 10,
+			AirborneSeconds: math.Round(drone.Usage.AirborneTime.Seconds()*10) / 10,
+		},
 	}
 
-	if order != nil {
-		resp.HandoffOrderID = &order.ID
-		status := string(order.Status)
-		resp.OrderStatus = &status
+	for i, order := range handedOff {
+		if i == 0 {
+			resp.HandoffOrderID = &handedOff[0].ID
+			status := string(order.Status)
+			resp.OrderStatus = &status
+		}
+		resp.HandoffOrderIDs = append(resp.HandoffOrderIDs, order.ID)
 		if order.Status == model.OrderPending || order.Status == model.OrderHandoffPending {
 			resp.AssignmentPending = true
 		}
@@ -217,6 +416,33 @@ func toDroneStatusResponse(drone *model.Drone, order *model.Order) droneStatusRe
 	return resp
 }
 
+func toTripResponse(trip *model.Trip) tripResponse {
+	resp := tripResponse{
+		TripID:    trip.ID,
+		DroneID:   trip.DroneID,
+		Status:    string(trip.Status),
+		Stops:     make([]tripStopResponse, len(trip.Stops)),
+		CreatedAt: trip.CreatedAt,
+	}
+
+	for i, stop := range trip.Stops {
+		resp.Stops[i] = tripStopResponse{
+			Sequence:    i + 1,
+			OrderID:     stop.OrderID,
+			Kind:        string(stop.Kind),
+			Lat:         stop.Lat,
+			Lng:         stop.Lng,
+			CompletedAt: stop.CompletedAt,
+		}
+		if resp.NextStop == nil && !stop.IsCompleted() {
+			next := resp.Stops[i]
+			resp.NextStop = &next
+		}
+	}
+
+	return resp
+}
+
 func toDroneListResponse(drones []model.Drone, pagination model.Pagination) droneListResponse {
 	data := make([]droneStatusResponse, len(drones))
 	for i := range drones {
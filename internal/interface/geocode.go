@@ -0,0 +1,108 @@
+package iface
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	queryParamGeocodeQuery = "q"
+	queryParamLat          = "lat"
+	queryParamLng          = "lng"
+)
+
+type GeocodeUsecase interface {
+	Search(ctx context.Context, query string) (*model.Place, error)
+	Reverse(ctx context.Context, lat, lng float64) (*model.Place, error)
+}
+
+type GeocodeHandler struct {
+	uc GeocodeUsecase
+}
+
+func NewGeocodeHandler(uc GeocodeUsecase) *GeocodeHandler {
+	return &GeocodeHandler{uc: uc}
+}
+
+type placeResponse struct {
+	Address string  `json:"address"`
+	Lat     float64 `json:"lat"`
+	Lng     float64 `json:"lng"`
+}
+
+// Geocode godoc
+// @Summary Resolve an address
+// @Description Resolve a free-text address to coordinates, as order creation would
+// @Tags geocoding
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Free-text address"
+// @Success 200 {object} placeResponse "Resolved place"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 422 {object} map[string]string "Address could not be resolved"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /geocode [get]
+func (h *GeocodeHandler) Geocode(c *gin.Context) {
+	query := c.Query(queryParamGeocodeQuery)
+	if strings.TrimSpace(query) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "q is required"})
+		return
+	}
+
+	place, err := h.uc.Search(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toPlaceResponse(*place))
+}
+
+// ReverseGeocode godoc
+// @Summary Name a location
+// @Description Find the named place nearest to the coordinates
+// @Tags geocoding
+// @Produce json
+// @Security BearerAuth
+// @Param lat query number true "Latitude"
+// @Param lng query number true "Longitude"
+// @Success 200 {object} placeResponse "Nearest named place"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "No named place nearby"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /geocode/reverse [get]
+func (h *GeocodeHandler) ReverseGeocode(c *gin.Context) {
+	lat, latErr := strconv.ParseFloat(c.Query(queryParamLat), 64)
+	lng, lngErr := strconv.ParseFloat(c.Query(queryParamLng), 64)
+	if latErr != nil || lngErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "lat and lng are required numbers"})
+		return
+	}
+
+	place, err := h.uc.Reverse(c.Request.Context(), lat, lng)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if place == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "place_not_found", "message": "no named place near these coordinates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toPlaceResponse(*place))
+}
+
+func toPlaceResponse(place model.Place) placeResponse {
+	return placeResponse{
+		Address: place.Name,
+		Lat:     place.Lat,
+		Lng:     place.Lng,
+	}
+}
@@ -0,0 +1,141 @@
+package iface
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+const messageTypeGeofenceBreach = "geofence_breach"
+
+type GeofenceBreachUsecase interface {
+	ListDroneBreaches(ctx context.Context, droneID int64, page, pageSize int) ([]model.GeofenceBreach, model.Pagination, error)
+}
+
+type GeofenceBreachHandler struct {
+	uc GeofenceBreachUsecase
+}
+
+func NewGeofenceBreachHandler(uc GeofenceBreachUsecase) *GeofenceBreachHandler {
+	return &GeofenceBreachHandler{uc: uc}
+}
+
+type geofenceBreachResponse struct {
+	BreachID   int64     `json:"breach_id"`
+	DroneID    int64     `json:"drone_id"`
+	OrderID    *int64    `json:"order_id,omitempty"`
+	Kind       string    `json:"kind"`
+	ZoneID     *int64    `json:"zone_id,omitempty"`
+	ZoneName   *string   `json:"zone_name,omitempty"`
+	Lat        float64   `json:"lat"`
+	Lng        float64   `json:"lng"`
+	Action     string    `json:"action"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+type geofenceBreachListResponse struct {
+	Data []geofenceBreachResponse `json:"data"`
+	Meta paginationMeta           `json:"meta"`
+}
+
+// geofenceBreachMessage is pushed to admins and, when the breach carries an
+// action, to the offending drone.
+type geofenceBreachMessage struct {
+	Type string `json:"type"`
+	geofenceBreachResponse
+}
+
+// ListDroneBreaches godoc
+// @Summary Geofence breach history for a drone (admin)
+// @Description Breaches detected from the drone's heartbeats, newest first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drone ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Page size (default: 20)"
+// @Success 200 {object} geofenceBreachListResponse "Breaches"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 404 {object} map[string]string "Drone not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/drones/{id}/breaches [get]
+func (h *GeofenceBreachHandler) ListDroneBreaches(c *gin.Context) {
+	droneID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || droneID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_drone_id", "message": "invalid drone id"})
+		return
+	}
+
+	page, pageSize, err := parsePaginationParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
+		return
+	}
+
+	breaches, pagination, err := h.uc.ListDroneBreaches(c.Request.Context(), droneID, page, pageSize)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	data := make([]geofenceBreachResponse, len(breaches))
+	for i := range breaches {
+		data[i] = toGeofenceBreachResponse(breaches[i])
+	}
+
+	c.JSON(http.StatusOK, geofenceBreachListResponse{
+		Data: data,
+		Meta: toPaginationMeta(pagination, len(breaches)),
+	})
+}
+
+// BreachAlerter pushes breaches to admin consoles and relays the configured
+// action to the drone when it is connected.
+type BreachAlerter struct {
+	drones *ConnectionRegistry
+	admins *AdminWSHandler
+}
+
+func NewBreachAlerter(drones *ConnectionRegistry, admins *AdminWSHandler) *BreachAlerter {
+	return &BreachAlerter{drones: drones, admins: admins}
+}
+
+func (a *BreachAlerter) NotifyBreach(ctx context.Context, breach model.GeofenceBreach) error {
+	msg := geofenceBreachMessage{
+		Type:                   messageTypeGeofenceBreach,
+		geofenceBreachResponse: toGeofenceBreachResponse(breach),
+	}
+
+	a.admins.Broadcast(msg)
+
+	if breach.Action == model.BreachActionNone {
+		return nil
+	}
+	if err := a.drones.Send(breach.DroneID, msg); err != nil && !errors.Is(err, ErrDroneNotConnected) {
+		return err
+	}
+	return nil
+}
+
+func toGeofenceBreachResponse(breach model.GeofenceBreach) geofenceBreachResponse {
+	return geofenceBreachResponse{
+		BreachID:   breach.ID,
+		DroneID:    breach.DroneID,
+		OrderID:    breach.OrderID,
+		Kind:       string(breach.Kind),
+		ZoneID:     breach.ZoneID,
+		ZoneName:   breach.ZoneName,
+		Lat:        breach.Lat,
+		Lng:        breach.Lng,
+		Action:     string(breach.Action),
+		DetectedAt: breach.DetectedAt,
+	}
+}
@@ -0,0 +1,204 @@
+package iface
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+const paramLandingSiteID = "id"
+
+type LandingSiteUsecase interface {
+	CreateLandingSite(ctx context.Context, name string, lat, lng float64) (*model.LandingSite, error)
+	ListLandingSites(ctx context.Context) ([]model.LandingSite, error)
+	UpdateLandingSite(ctx context.Context, id int64, req model.UpdateLandingSiteRequest) (*model.LandingSite, error)
+	DeleteLandingSite(ctx context.Context, id int64) error
+}
+
+type LandingSiteHandler struct {
+	uc LandingSiteUsecase
+}
+
+func NewLandingSiteHandler(uc LandingSiteUsecase) *LandingSiteHandler {
+	return &LandingSiteHandler{uc: uc}
+}
+
+type createLandingSiteRequest struct {
+	Name string   `json:"name" binding:"required"`
+	Lat  *float64 `json:"lat" binding:"required"`
+	Lng  *float64 `json:"lng" binding:"required"`
+}
+
+type updateLandingSiteRequest struct {
+	Name   *string  `json:"name,omitempty"`
+	Lat    *float64 `json:"lat,omitempty"`
+	Lng    *float64 `json:"lng,omitempty"`
+	Active *bool    `json:"active,omitempty"`
+}
+
+type landingSiteResponse struct {
+	LandingSiteID int64     `json:"landing_site_id"`
+	Name          string    `json:"name"`
+	Lat           float64   `json:"lat"`
+	Lng           float64   `json:"lng"`
+	Active        bool      `json:"active"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type landingSiteListResponse struct {
+	Data []landingSiteResponse `json:"data"`
+}
+
+// AdminListLandingSites godoc
+// @Summary List landing sites (admin)
+// @Description Get every landing site, including inactive ones
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} landingSiteListResponse "Landing sites"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/landing-sites [get]
+func (h *LandingSiteHandler) AdminListLandingSites(c *gin.Context) {
+	sites, err := h.uc.ListLandingSites(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toLandingSiteListResponse(sites))
+}
+
+// AdminCreateLandingSite godoc
+// @Summary Create a landing site (admin)
+// @Description Register a safe place where a drone that breaks down can leave its parcels. With HANDOFF_RENDEZVOUS=nearest_site the nearest active site within HANDOFF_MAX_DETOUR_KM becomes the handoff point.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param site body createLandingSiteRequest true "Landing site"
+// @Success 201 {object} landingSiteResponse "Landing site created"
+// @Failure 400 {object} map[string]string "Invalid request or landing site"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/landing-sites [post]
+func (h *LandingSiteHandler) AdminCreateLandingSite(c *gin.Context) {
+	var req createLandingSiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "name, lat and lng are required"})
+		return
+	}
+
+	site, err := h.uc.CreateLandingSite(c.Request.Context(), req.Name, *req.Lat, *req.Lng)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toLandingSiteResponse(*site))
+}
+
+// AdminUpdateLandingSite godoc
+// @Summary Update a landing site (admin)
+// @Description Rename, move, or activate/deactivate a landing site. Parcels already waiting there keep their handoff point.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Landing site ID"
+// @Param site body updateLandingSiteRequest true "Fields to change"
+// @Success 200 {object} landingSiteResponse "Landing site updated"
+// @Failure 400 {object} map[string]string "Invalid request or landing site"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Landing site not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/landing-sites/{id} [patch]
+func (h *LandingSiteHandler) AdminUpdateLandingSite(c *gin.Context) {
+	siteID, ok := parseLandingSiteID(c)
+	if !ok {
+		return
+	}
+
+	var req updateLandingSiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid json body"})
+		return
+	}
+
+	site, err := h.uc.UpdateLandingSite(c.Request.Context(), siteID, model.UpdateLandingSiteRequest{
+		Name:   req.Name,
+		Lat:    req.Lat,
+		Lng:    req.Lng,
+		Active: req.Active,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toLandingSiteResponse(*site))
+}
+
+// AdminDeleteLandingSite godoc
+// @Summary Delete a landing site (admin)
+// @Tags admin
+// @Security BearerAuth
+// @Param id path int true "Landing site ID"
+// @Success 204 "Landing site deleted"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Landing site not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/landing-sites/{id} [delete]
+func (h *LandingSiteHandler) AdminDeleteLandingSite(c *gin.Context) {
+	siteID, ok := parseLandingSiteID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeleteLandingSite(c.Request.Context(), siteID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func parseLandingSiteID(c *gin.Context) (int64, bool) {
+	siteID, err := strconv.ParseInt(c.Param(paramLandingSiteID), 10, 64)
+	if err != nil || siteID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid landing site id"})
+		return 0, false
+	}
+	return siteID, true
+}
+
+func toLandingSiteResponse(site model.LandingSite) landingSiteResponse {
+	return landingSiteResponse{
+		LandingSiteID: site.ID,
+		Name:          site.Name,
+		Lat:           site.Lat,
+		Lng:           site.Lng,
+		Active:        site.Active,
+		CreatedAt:     site.CreatedAt,
+		UpdatedAt:     site.UpdatedAt,
+	}
+}
+
+func toLandingSiteListResponse(sites []model.LandingSite) landingSiteListResponse {
+	data := make([]landingSiteResponse, len(sites))
+	for i := range sites {
+		data[i] = toLandingSiteResponse(sites[i])
+	}
+	return landingSiteListResponse{Data: data}
+}
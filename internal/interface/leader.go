@@ -0,0 +1,75 @@
+package iface
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+type LeaderUsecase interface {
+	Status(ctx context.Context) (*model.LeaderStatus, error)
+}
+
+type LeaderHandler struct {
+	uc LeaderUsecase
+}
+
+func NewLeaderHandler(uc LeaderUsecase) *LeaderHandler {
+	return &LeaderHandler{uc: uc}
+}
+
+type leaderLeaseResponse struct {
+	NodeID     string    `json:"node_id"`
+	Term       int64     `json:"term"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	RenewedAt  time.Time `json:"renewed_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type leaderStatusResponse struct {
+	NodeID   string `json:"node_id"`
+	IsLeader bool   `json:"is_leader"`
+	// Leader is null while no node holds a live lease, e.g. mid-failover.
+	Leader *leaderLeaseResponse `json:"leader"`
+	Jobs   []string             `json:"jobs"`
+}
+
+// GetLeader godoc
+// @Summary Current background-job leader (admin)
+// @Description The node holding the leader lease, which alone runs singleton background jobs (telemetry maintenance), and whether the answering node is it.
+// @Description leader is null while no node holds a live lease, for instance between a leader dying and its lease expiring.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} leaderStatusResponse "Leader"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/leader [get]
+func (h *LeaderHandler) GetLeader(c *gin.Context) {
+	status, err := h.uc.Status(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	resp := leaderStatusResponse{
+		NodeID:   status.NodeID,
+		IsLeader: status.IsLeader,
+		Jobs:     status.Jobs,
+	}
+	if lease := status.Lease; lease != nil && !lease.Expired {
+		resp.Leader = &leaderLeaseResponse{
+			NodeID:     lease.Holder,
+			Term:       lease.Term,
+			AcquiredAt: lease.AcquiredAt,
+			RenewedAt:  lease.RenewedAt,
+			ExpiresAt:  lease.ExpiresAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
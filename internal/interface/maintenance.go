@@ -0,0 +1,445 @@
+package iface
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	paramMaintenanceID    = "id"
+	queryParamDroneID     = "drone_id"
+	queryParamOpenRecords = "open"
+)
+
+type MaintenanceUsecase interface {
+	OpenRecord(ctx context.Context, droneID int64, issue model.MaintenanceIssue, details model.MaintenanceDetails) (*model.MaintenanceRecord, error)
+	GetRecord(ctx context.Context, id int64) (*model.MaintenanceRecord, error)
+	ListRecords(ctx context.Context, filter model.MaintenanceFilter, page, pageSize int) ([]model.MaintenanceRecord, model.Pagination, error)
+	UpdateRecord(ctx context.Context, id int64, details model.MaintenanceDetails) (*model.MaintenanceRecord, error)
+	CloseRecord(ctx context.Context, id int64, details model.MaintenanceDetails) (*model.MaintenanceRecord, error)
+	Health(ctx context.Context, droneID int64) (*model.MaintenanceHealth, error)
+	SetSchedule(ctx context.Context, droneID int64, schedule model.MaintenanceSchedule) (*model.MaintenanceHealth, error)
+}
+
+type MaintenanceHandler struct {
+	uc MaintenanceUsecase
+}
+
+func NewMaintenanceHandler(uc MaintenanceUsecase) *MaintenanceHandler {
+	return &MaintenanceHandler{uc: uc}
+}
+
+type openMaintenanceRequest struct {
+	Issue      string   `json:"issue" binding:"required" example:"propulsion"`
+	Notes      *string  `json:"notes,omitempty" example:"rear left motor runs hot"`
+	Parts      []string `json:"parts,omitempty"`
+	Technician *string  `json:"technician,omitempty" example:"Sami"`
+}
+
+type maintenanceDetailsRequest struct {
+	Issue      *string  `json:"issue,omitempty"`
+	Notes      *string  `json:"notes,omitempty"`
+	Parts      []string `json:"parts,omitempty"`
+	Technician *string  `json:"technician,omitempty"`
+}
+
+type maintenanceScheduleRequest struct {
+	FlightHours *float64 `json:"flight_hours" example:"50"`
+	Cycles      *int     `json:"cycles" example:"200"`
+}
+
+type maintenanceRecordResponse struct {
+	RecordID   int64      `json:"record_id"`
+	DroneID    int64      `json:"drone_id"`
+	Issue      string     `json:"issue"`
+	Notes      string     `json:"notes,omitempty"`
+	Parts      []string   `json:"parts"`
+	Technician string     `json:"technician,omitempty"`
+	Open       bool       `json:"open"`
+	OpenedAt   time.Time  `json:"opened_at"`
+	ClosedAt   *time.Time `json:"closed_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type maintenanceRecordListResponse struct {
+	Data []maintenanceRecordResponse `json:"data"`
+	Meta paginationMeta              `json:"meta"`
+}
+
+type droneMaintenanceResponse struct {
+	DroneID            int64                      `json:"drone_id"`
+	Status             string                     `json:"status"`
+	LastServiceAt      *time.Time                 `json:"last_service_at,omitempty"`
+	FlightHours        float64                    `json:"flight_hours"`
+	Cycles             int                        `json:"cycles"`
+	ServiceFlightHours float64                    `json:"service_flight_hours"`
+	ServiceCycles      int                        `json:"service_cycles"`
+	CustomSchedule     bool                       `json:"custom_schedule"`
+	Due                bool                       `json:"due"`
+	OpenRecord         *maintenanceRecordResponse `json:"open_record,omitempty"`
+}
+
+// ListMaintenanceRecords godoc
+// @Summary List maintenance records (admin)
+// @Description Maintenance records across the fleet, newest first, optionally for one drone and only open or closed ones
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param drone_id query int false "Drone ID"
+// @Param open query bool false "true for open records, false for closed ones"
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Page size (default: 20)"
+// @Success 200 {object} maintenanceRecordListResponse "Maintenance records"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/maintenance [get]
+func (h *MaintenanceHandler) ListMaintenanceRecords(c *gin.Context) {
+	filter, err := parseMaintenanceFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
+		return
+	}
+
+	page, pageSize, err := parsePaginationParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
+		return
+	}
+
+	records, pagination, err := h.uc.ListRecords(c.Request.Context(), filter, page, pageSize)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	data := make([]maintenanceRecordResponse, len(records))
+	for i := range records {
+		data[i] = toMaintenanceRecordResponse(records[i])
+	}
+
+	c.JSON(http.StatusOK, maintenanceRecordListResponse{
+		Data: data,
+		Meta: toPaginationMeta(pagination, len(records)),
+	})
+}
+
+// GetMaintenanceRecord godoc
+// @Summary Get a maintenance record (admin)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Record ID"
+// @Success 200 {object} maintenanceRecordResponse "Maintenance record"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 404 {object} map[string]string "Record not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/maintenance/{id} [get]
+func (h *MaintenanceHandler) GetMaintenanceRecord(c *gin.Context) {
+	recordID, ok := parseMaintenanceID(c)
+	if !ok {
+		return
+	}
+
+	record, err := h.uc.GetRecord(c.Request.Context(), recordID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toMaintenanceRecordResponse(*record))
+}
+
+// UpdateMaintenanceRecord godoc
+// @Summary Update a maintenance record (admin)
+// @Description Change the issue, notes, parts or technician of an open or closed record. An empty parts list clears the parts.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Record ID"
+// @Param record body maintenanceDetailsRequest true "Fields to change"
+// @Success 200 {object} maintenanceRecordResponse "Record updated"
+// @Failure 400 {object} map[string]string "Invalid request or record"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 404 {object} map[string]string "Record not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/maintenance/{id} [patch]
+func (h *MaintenanceHandler) UpdateMaintenanceRecord(c *gin.Context) {
+	recordID, ok := parseMaintenanceID(c)
+	if !ok {
+		return
+	}
+
+	var req maintenanceDetailsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid json body"})
+		return
+	}
+
+	record, err := h.uc.UpdateRecord(c.Request.Context(), recordID, req.toModel())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toMaintenanceRecordResponse(*record))
+}
+
+// CloseMaintenanceRecord godoc
+// @Summary Close a maintenance record (admin)
+// @Description Ends the work, with any last details, and puts the drone back in service (idle). The record of a broken drone is closed by marking the drone fixed instead (409 maintenance_drone_broken).
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Record ID"
+// @Param record body maintenanceDetailsRequest false "Last details"
+// @Success 200 {object} maintenanceRecordResponse "Record closed"
+// @Failure 400 {object} map[string]string "Invalid request or record"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 404 {object} map[string]string "Record not found"
+// @Failure 409 {object} map[string]string "Record already closed or drone broken"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/maintenance/{id}/close [post]
+func (h *MaintenanceHandler) CloseMaintenanceRecord(c *gin.Context) {
+	recordID, ok := parseMaintenanceID(c)
+	if !ok {
+		return
+	}
+
+	details, ok := bindOptionalMaintenanceDetails(c)
+	if !ok {
+		return
+	}
+
+	record, err := h.uc.CloseRecord(c.Request.Context(), recordID, details)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toMaintenanceRecordResponse(*record))
+}
+
+// OpenDroneMaintenance godoc
+// @Summary Take a drone into maintenance (admin)
+// @Description Opens a maintenance record and takes an idle or charging drone out of service (status maintenance, left out of dispatch, depot pad released). A drone on a trip cannot be taken (409); a broken one stays broken. A drone has at most one open record.
+// @Description issue is one of breakdown, scheduled, inspection, battery, propulsion, sensors, airframe, other.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drone ID"
+// @Param record body openMaintenanceRequest true "Maintenance record"
+// @Success 201 {object} maintenanceRecordResponse "Record opened"
+// @Failure 400 {object} map[string]string "Invalid request or record"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 404 {object} map[string]string "Drone not found"
+// @Failure 409 {object} map[string]string "Drone busy or a record already open"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/drones/{id}/maintenance [post]
+func (h *MaintenanceHandler) OpenDroneMaintenance(c *gin.Context) {
+	droneID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || droneID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_drone_id", "message": "invalid drone id"})
+		return
+	}
+
+	var req openMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "issue is required"})
+		return
+	}
+
+	record, err := h.uc.OpenRecord(c.Request.Context(), droneID, model.MaintenanceIssue(req.Issue), model.MaintenanceDetails{
+		Notes:      req.Notes,
+		Parts:      req.Parts,
+		Technician: req.Technician,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toMaintenanceRecordResponse(*record))
+}
+
+// GetDroneMaintenance godoc
+// @Summary A drone's maintenance standing (admin)
+// @Description Flight hours and cycles (trips that ended) since the last closed record, the service interval that applies (0 = never by that measure), whether the drone is due and its open record
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drone ID"
+// @Success 200 {object} droneMaintenanceResponse "Maintenance standing"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 404 {object} map[string]string "Drone not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/drones/{id}/maintenance [get]
+func (h *MaintenanceHandler) GetDroneMaintenance(c *gin.Context) {
+	droneID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || droneID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_drone_id", "message": "invalid drone id"})
+		return
+	}
+
+	health, err := h.uc.Health(c.Request.Context(), droneID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDroneMaintenanceResponse(*health))
+}
+
+// SetMaintenanceSchedule godoc
+// @Summary Set a drone's service interval (admin)
+// @Description Flight hours and trips between services for this drone. A field left out or null follows MAINTENANCE_FLIGHT_HOURS / MAINTENANCE_CYCLES, 0 never calls for service by that measure; {} puts the drone back on the fleet interval.
+// @Description A drone past its interval is taken into maintenance when it next finishes a trip or at the next check (MAINTENANCE_CHECK_INTERVAL).
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drone ID"
+// @Param schedule body maintenanceScheduleRequest true "Service interval"
+// @Success 200 {object} droneMaintenanceResponse "Maintenance standing"
+// @Failure 400 {object} map[string]string "Invalid request or schedule"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 404 {object} map[string]string "Drone not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/drones/{id}/maintenance-schedule [put]
+func (h *MaintenanceHandler) SetMaintenanceSchedule(c *gin.Context) {
+	droneID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || droneID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_drone_id", "message": "invalid drone id"})
+		return
+	}
+
+	var req maintenanceScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid json body"})
+		return
+	}
+
+	health, err := h.uc.SetSchedule(c.Request.Context(), droneID, model.MaintenanceSchedule{
+		FlightHours: req.FlightHours,
+		Cycles:      req.Cycles,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toDroneMaintenanceResponse(*health))
+}
+
+func (req maintenanceDetailsRequest) toModel() model.MaintenanceDetails {
+	details := model.MaintenanceDetails{
+		Notes:      req.Notes,
+		Parts:      req.Parts,
+		Technician: req.Technician,
+	}
+	if req.Issue != nil {
+		issue := model.MaintenanceIssue(*req.Issue)
+		details.Issue = &issue
+	}
+	return details
+}
+
+// bindOptionalMaintenanceDetails reads the repair details of a request whose
+// body may be left out.
+func bindOptionalMaintenanceDetails(c *gin.Context) (model.MaintenanceDetails, bool) {
+	var req maintenanceDetailsRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid json body"})
+		return model.MaintenanceDetails{}, false
+	}
+	return req.toModel(), true
+}
+
+func parseMaintenanceID(c *gin.Context) (int64, bool) {
+	recordID, err := strconv.ParseInt(c.Param(paramMaintenanceID), 10, 64)
+	if err != nil || recordID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid maintenance record id"})
+		return 0, false
+	}
+	return recordID, true
+}
+
+func parseMaintenanceFilter(c *gin.Context) (model.MaintenanceFilter, error) {
+	var filter model.MaintenanceFilter
+
+	if droneStr := c.Query(queryParamDroneID); droneStr != "" {
+		id, err := strconv.ParseInt(droneStr, 10, 64)
+		if err != nil || id <= 0 {
+			return filter, errors.New("drone_id must be a positive integer")
+		}
+		filter.DroneID = &id
+	}
+
+	if openStr := c.Query(queryParamOpenRecords); openStr != "" {
+		open, err := strconv.ParseBool(openStr)
+		if err != nil {
+			return filter, errors.New("open must be true or false")
+		}
+		filter.Open = &open
+	}
+
+	return filter, nil
+}
+
+func toMaintenanceRecordResponse(record model.MaintenanceRecord) maintenanceRecordResponse {
+	resp := maintenanceRecordResponse{
+		RecordID:   record.ID,
+		DroneID:    record.DroneID,
+		Issue:      string(record.Issue),
+		Notes:      record.Notes,
+		Parts:      record.Parts,
+		Technician: record.Technician,
+		Open:       record.IsOpen(),
+		OpenedAt:   record.OpenedAt,
+		ClosedAt:   record.ClosedAt,
+		UpdatedAt:  record.UpdatedAt,
+	}
+	if resp.Parts == nil {
+		resp.Parts = []string{}
+	}
+	return resp
+}
+
+func toDroneMaintenanceResponse(health model.MaintenanceHealth) droneMaintenanceResponse {
+	resp := droneMaintenanceResponse{
+		DroneID:            health.Usage.DroneID,
+		Status:             string(health.Usage.Status),
+		LastServiceAt:      health.Usage.LastServiceAt,
+		FlightHours:        math.Round(health.Usage.FlightHours()*100) / 100,
+		Cycles:             health.Usage.Cycles,
+		ServiceFlightHours: health.ServiceFlightHours,
+		ServiceCycles:      health.ServiceCycles,
+		CustomSchedule:     !health.Usage.Schedule.IsDefault(),
+		Due:                health.Due,
+	}
+	if health.OpenRecord != nil {
+		record := toMaintenanceRecordResponse(*health.OpenRecord)
+		resp.OpenRecord = &record
+	}
+	return resp
+}
@@ -0,0 +1,256 @@
+package iface
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+const paramNoFlyZoneID = "id"
+
+type NoFlyZoneUsecase interface {
+	CreateNoFlyZone(ctx context.Context, req model.CreateNoFlyZoneRequest) (*model.NoFlyZone, error)
+	ListNoFlyZones(ctx context.Context) ([]model.NoFlyZone, error)
+	UpdateNoFlyZone(ctx context.Context, id int64, req model.UpdateNoFlyZoneRequest) (*model.NoFlyZone, error)
+	DeleteNoFlyZone(ctx context.Context, id int64) error
+}
+
+type NoFlyZoneHandler struct {
+	uc NoFlyZoneUsecase
+}
+
+func NewNoFlyZoneHandler(uc NoFlyZoneUsecase) *NoFlyZoneHandler {
+	return &NoFlyZoneHandler{uc: uc}
+}
+
+type createNoFlyZoneRequest struct {
+	Name     string            `json:"name" binding:"required"`
+	Boundary []geoPointRequest `json:"boundary" binding:"required,dive"`
+	StartsAt *string           `json:"starts_at,omitempty"`
+	EndsAt   *string           `json:"ends_at,omitempty"`
+}
+
+type updateNoFlyZoneRequest struct {
+	Name     *string           `json:"name,omitempty"`
+	Boundary []geoPointRequest `json:"boundary,omitempty" binding:"omitempty,dive"`
+	StartsAt *string           `json:"starts_at,omitempty"`
+	EndsAt   *string           `json:"ends_at,omitempty"`
+}
+
+type noFlyZoneResponse struct {
+	NoFlyZoneID int64              `json:"no_fly_zone_id"`
+	Name        string             `json:"name"`
+	Boundary    []geoPointResponse `json:"boundary"`
+	StartsAt    *time.Time         `json:"starts_at,omitempty"`
+	EndsAt      *time.Time         `json:"ends_at,omitempty"`
+	Active      bool               `json:"active"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+type noFlyZoneListResponse struct {
+	Data []noFlyZoneResponse `json:"data"`
+}
+
+// AdminListNoFlyZones godoc
+// @Summary List no-fly zones (admin)
+// @Description Get every no-fly zone, including expired and upcoming ones; `active` tells whether it is in effect now
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} noFlyZoneListResponse "No-fly zones"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/no-fly-zones [get]
+func (h *NoFlyZoneHandler) AdminListNoFlyZones(c *gin.Context) {
+	zones, err := h.uc.ListNoFlyZones(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	now := time.Now()
+	data := make([]noFlyZoneResponse, len(zones))
+	for i := range zones {
+		data[i] = toNoFlyZoneResponse(zones[i], now)
+	}
+
+	c.JSON(http.StatusOK, noFlyZoneListResponse{Data: data})
+}
+
+// AdminCreateNoFlyZone godoc
+// @Summary Create a no-fly zone (admin)
+// @Description Restrict airspace drones must route around; orders with pickup or dropoff inside an active zone are rejected. Omit starts_at/ends_at (RFC3339) for a permanent zone.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param zone body createNoFlyZoneRequest true "No-fly zone"
+// @Success 201 {object} noFlyZoneResponse "No-fly zone created"
+// @Failure 400 {object} map[string]string "Invalid request, polygon or time window"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/no-fly-zones [post]
+func (h *NoFlyZoneHandler) AdminCreateNoFlyZone(c *gin.Context) {
+	var req createNoFlyZoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "name and boundary with lat and lng for every vertex are required"})
+		return
+	}
+
+	startsAt, endsAt, err := parseZoneWindow(req.StartsAt, req.EndsAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
+		return
+	}
+
+	zone, err := h.uc.CreateNoFlyZone(c.Request.Context(), model.CreateNoFlyZoneRequest{
+		Name:     req.Name,
+		Boundary: toPolygon(req.Boundary),
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toNoFlyZoneResponse(*zone, time.Now()))
+}
+
+// AdminUpdateNoFlyZone godoc
+// @Summary Update a no-fly zone (admin)
+// @Description Rename, redraw or reschedule a zone; an empty starts_at/ends_at removes that bound. Existing orders are not re-validated.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "No-fly zone ID"
+// @Param zone body updateNoFlyZoneRequest true "Fields to change"
+// @Success 200 {object} noFlyZoneResponse "No-fly zone updated"
+// @Failure 400 {object} map[string]string "Invalid request, polygon or time window"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "No-fly zone not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/no-fly-zones/{id} [patch]
+func (h *NoFlyZoneHandler) AdminUpdateNoFlyZone(c *gin.Context) {
+	zoneID, ok := parseNoFlyZoneID(c)
+	if !ok {
+		return
+	}
+
+	var req updateNoFlyZoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid json body"})
+		return
+	}
+
+	startsAt, endsAt, err := parseZoneWindow(req.StartsAt, req.EndsAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
+		return
+	}
+
+	zone, err := h.uc.UpdateNoFlyZone(c.Request.Context(), zoneID, model.UpdateNoFlyZoneRequest{
+		Name:     req.Name,
+		Boundary: toPolygon(req.Boundary),
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toNoFlyZoneResponse(*zone, time.Now()))
+}
+
+// AdminDeleteNoFlyZone godoc
+// @Summary Delete a no-fly zone (admin)
+// @Tags admin
+// @Security BearerAuth
+// @Param id path int true "No-fly zone ID"
+// @Success 204 "No-fly zone deleted"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "No-fly zone not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/no-fly-zones/{id} [delete]
+func (h *NoFlyZoneHandler) AdminDeleteNoFlyZone(c *gin.Context) {
+	zoneID, ok := parseNoFlyZoneID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeleteNoFlyZone(c.Request.Context(), zoneID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func parseNoFlyZoneID(c *gin.Context) (int64, bool) {
+	zoneID, err := strconv.ParseInt(c.Param(paramNoFlyZoneID), 10, 64)
+	if err != nil || zoneID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid no-fly zone id"})
+		return 0, false
+	}
+	return zoneID, true
+}
+
+// parseZoneWindow parses RFC3339 bounds; an empty string becomes the zero
+// time, which the model treats as "no bound".
+func parseZoneWindow(startsAt, endsAt *string) (*time.Time, *time.Time, error) {
+	start, err := parseOptionalTimestamp(startsAt)
+	if err != nil {
+		return nil, nil, errors.New("starts_at must be an RFC3339 timestamp")
+	}
+	end, err := parseOptionalTimestamp(endsAt)
+	if err != nil {
+		return nil, nil, errors.New("ends_at must be an RFC3339 timestamp")
+	}
+	return start, end, nil
+}
+
+func parseOptionalTimestamp(value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if *value == "" {
+		return &time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func toNoFlyZoneResponse(zone model.NoFlyZone, now time.Time) noFlyZoneResponse {
+	boundary := make([]geoPointResponse, len(zone.Boundary))
+	for i, v := range zone.Boundary {
+		boundary[i] = geoPointResponse{Lat: v.Lat, Lng: v.Lng}
+	}
+
+	return noFlyZoneResponse{
+		NoFlyZoneID: zone.ID,
+		Name:        zone.Name,
+		Boundary:    boundary,
+		StartsAt:    zone.StartsAt,
+		EndsAt:      zone.EndsAt,
+		Active:      zone.ActiveAt(now),
+		CreatedAt:   zone.CreatedAt,
+		UpdatedAt:   zone.UpdatedAt,
+	}
+}
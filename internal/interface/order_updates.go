@@ -0,0 +1,114 @@
+package iface
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const (
+	messageTypeOrderUpdate    = "order_update"
+	messageTypeOrderUpdateAck = "order_update_ack"
+)
+
+// orderUpdateMessage tells a drone that an order it was offered or is flying
+// was canceled, rerouted or has to be handed off.
+type orderUpdateMessage struct {
+	Type        string            `json:"type"`
+	Event       string            `json:"event"`
+	DroneID     int64             `json:"drone_id"`
+	OrderID     int64             `json:"order_id"`
+	OrderStatus string            `json:"order_status"`
+	PickupLat   float64           `json:"pickup_lat"`
+	PickupLng   float64           `json:"pickup_lng"`
+	DropoffLat  float64           `json:"dropoff_lat"`
+	DropoffLng  float64           `json:"dropoff_lng"`
+	HandoffLat  *float64          `json:"handoff_lat,omitempty"`
+	HandoffLng  *float64          `json:"handoff_lng,omitempty"`
+	Waypoints   []waypointMessage `json:"waypoints,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+type orderUpdateAckRequest struct {
+	Type    string `json:"type"`
+	OrderID int64  `json:"order_id"`
+	Event   string `json:"event"`
+	Status  string `json:"status"`
+	Note    string `json:"note,omitempty"`
+}
+
+type orderUpdateAckResponse struct {
+	Type    string `json:"type"`
+	OrderID int64  `json:"order_id"`
+	Event   string `json:"event"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+func (h *DroneWSHandler) NotifyOrderUpdate(ctx context.Context, notice model.OrderUpdateNotice) error {
+	if h == nil || h.registry == nil {
+		return nil
+	}
+	return h.registry.Send(notice.DroneID, toOrderUpdateMessage(notice))
+}
+
+func (h *DroneWSHandler) processOrderUpdateAck(client *wsClient, droneID int64, ack orderUpdateAckRequest) {
+	if ack.OrderID == 0 {
+		h.writeError(client, errors.New("order_id is required for order update ack"))
+		return
+	}
+
+	event := model.OrderUpdateEvent(strings.ToLower(ack.Event))
+	switch event {
+	case model.OrderUpdateCanceled, model.OrderUpdateRouteUpdated, model.OrderUpdateHandoffRequired:
+	default:
+		h.writeError(client, errors.New("event must be order_canceled, route_updated or handoff_required"))
+		return
+	}
+
+	status := strings.ToLower(ack.Status)
+	if status != "accepted" && status != "rejected" {
+		h.writeError(client, errors.New("status must be accepted or rejected"))
+		return
+	}
+
+	log.Printf("order update ack: drone=%d order=%d event=%s status=%s note=%s", droneID, ack.OrderID, event, status, ack.Note)
+
+	resp := orderUpdateAckResponse{
+		Type:    messageTypeOrderUpdateAck,
+		OrderID: ack.OrderID,
+		Event:   string(event),
+		Status:  status,
+		Message: "acknowledged",
+	}
+	if err := client.Send(resp); err != nil {
+		log.Printf("order update ack response error: %v", err)
+	}
+}
+
+func toOrderUpdateMessage(notice model.OrderUpdateNotice) orderUpdateMessage {
+	var waypoints []waypointMessage
+	for _, p := range notice.Waypoints {
+		waypoints = append(waypoints, waypointMessage{Lat: p.Lat, Lng: p.Lng})
+	}
+
+	return orderUpdateMessage{
+		Type:        messageTypeOrderUpdate,
+		Event:       string(notice.Event),
+		DroneID:     notice.DroneID,
+		OrderID:     notice.OrderID,
+		OrderStatus: string(notice.OrderStatus),
+		PickupLat:   notice.PickupLat,
+		PickupLng:   notice.PickupLng,
+		DropoffLat:  notice.DropoffLat,
+		DropoffLng:  notice.DropoffLng,
+		HandoffLat:  notice.HandoffLat,
+		HandoffLng:  notice.HandoffLng,
+		Waypoints:   waypoints,
+		CreatedAt:   time.Now().UTC(),
+	}
+}
@@ -3,8 +3,10 @@ package iface
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
@@ -16,6 +18,10 @@ const (
 	queryParamStatus        = "status"
 	queryParamEnduserID     = "enduser_id"
 	queryParamAssignedDrone = "assigned_drone_id"
+	queryParamCreatedFrom   = "from"
+	queryParamCreatedTo     = "to"
+
+	dateOnlyLayout = "2006-01-02"
 )
 
 type OrderUsecase interface {
@@ -24,10 +30,12 @@ type OrderUsecase interface {
 	GetOrder(ctx context.Context, userID, orderID int64) (*model.OrderDetails, error)
 	ReserveOrder(ctx context.Context, droneID, orderID int64) (*model.Order, error)
 	PickupOrder(ctx context.Context, droneID, orderID int64) (*model.Order, error)
-	DeliverOrder(ctx context.Context, droneID, orderID int64) (*model.Order, error)
+	DeliverOrder(ctx context.Context, droneID, orderID int64, proof model.DeliveryProof) (*model.Order, error)
 	FailOrder(ctx context.Context, droneID, orderID int64) (*model.Order, error)
+	ReturnOrder(ctx context.Context, droneID, orderID int64) (*model.Order, error)
 	UpdateRoute(ctx context.Context, orderID int64, req model.UpdateRouteRequest) (*model.Order, error)
 	ListOrders(ctx context.Context, filters model.OrderListFilters, page, pageSize int) ([]model.Order, model.Pagination, error)
+	ListEnduserOrders(ctx context.Context, userID int64, filters model.OrderListFilters, page, pageSize int) ([]model.OrderDetails, model.Pagination, error)
 }
 
 type OrderHandler struct {
@@ -38,38 +46,76 @@ func NewOrderHandler(uc OrderUsecase) *OrderHandler {
 	return &OrderHandler{uc: uc}
 }
 
+// createOrderRequest takes each endpoint as coordinates, a saved address id
+// or a free-text address to geocode.
 type createOrderRequest struct {
-	PickupLat  *float64 `json:"pickup_lat" binding:"required"`
-	PickupLng  *float64 `json:"pickup_lng" binding:"required"`
-	DropoffLat *float64 `json:"dropoff_lat" binding:"required"`
-	DropoffLng *float64 `json:"dropoff_lng" binding:"required"`
+	PickupAddressID  *int64   `json:"pickup_address_id,omitempty"`
+	PickupAddress    *string  `json:"pickup_address,omitempty"`
+	PickupLat        *float64 `json:"pickup_lat,omitempty"`
+	PickupLng        *float64 `json:"pickup_lng,omitempty"`
+	DropoffAddressID *int64   `json:"dropoff_address_id,omitempty"`
+	DropoffAddress   *string  `json:"dropoff_address,omitempty"`
+	DropoffLat       *float64 `json:"dropoff_lat,omitempty"`
+	DropoffLng       *float64 `json:"dropoff_lng,omitempty"`
 }
 
 type locationResponse struct {
-	Lat float64 `json:"lat"`
-	Lng float64 `json:"lng"`
+	Lat     float64 `json:"lat"`
+	Lng     float64 `json:"lng"`
+	Address *string `json:"address,omitempty"`
+}
+
+type legETAResponse struct {
+	Kind        string    `json:"kind"`
+	Lat         float64   `json:"lat"`
+	Lng         float64   `json:"lng"`
+	StopsBefore int       `json:"stops_before"`
+	ETAMinutes  model.ETA `json:"eta_minutes"`
 }
 
 type orderResponse struct {
-	OrderID         int64             `json:"order_id"`
-	Status          string            `json:"status"`
-	Pickup          locationResponse  `json:"pickup"`
-	Dropoff         locationResponse  `json:"dropoff"`
-	CreatedAt       time.Time         `json:"created_at"`
-	UpdatedAt       time.Time         `json:"updated_at,omitempty"`
-	CanceledAt      *time.Time        `json:"canceled_at,omitempty"`
-	AssignedDroneID *int64            `json:"assigned_drone_id,omitempty"`
-	DroneLocation   *locationResponse `json:"drone_location,omitempty"`
-	ETAMinutes      *model.ETA        `json:"eta_minutes,omitempty"`
-	HandoffLat      *float64          `json:"handoff_lat,omitempty"`
-	HandoffLng      *float64          `json:"handoff_lng,omitempty"`
+	OrderID            int64             `json:"order_id"`
+	Status             string            `json:"status"`
+	Pickup             locationResponse  `json:"pickup"`
+	Dropoff            locationResponse  `json:"dropoff"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at,omitempty"`
+	CanceledAt         *time.Time        `json:"canceled_at,omitempty"`
+	PickupAddressID    *int64            `json:"pickup_address_id,omitempty"`
+	DropoffAddressID   *int64            `json:"dropoff_address_id,omitempty"`
+	DeliveryNotes      *string           `json:"delivery_notes,omitempty"`
+	AccessInstructions *string           `json:"access_instructions,omitempty"`
+	AssignedDroneID    *int64            `json:"assigned_drone_id,omitempty"`
+	DroneLocation      *locationResponse `json:"drone_location,omitempty"`
+	ETAMinutes         *model.ETA        `json:"eta_minutes,omitempty"`
+	Legs               []legETAResponse  `json:"legs,omitempty"`
+	HandoffLat         *float64          `json:"handoff_lat,omitempty"`
+	HandoffLng         *float64          `json:"handoff_lng,omitempty"`
+	ReturnLat          *float64          `json:"return_lat,omitempty"`
+	ReturnLng          *float64          `json:"return_lng,omitempty"`
+	DeliveryPIN        *string           `json:"delivery_pin,omitempty"`
+}
+
+type deliverOrderRequest struct {
+	PIN      *string                  `json:"pin,omitempty"`
+	Evidence *deliveryEvidenceRequest `json:"evidence,omitempty"`
+}
+
+type deliveryEvidenceRequest struct {
+	PhotoHash  string    `json:"photo_hash" binding:"required"`
+	Lat        *float64  `json:"lat" binding:"required"`
+	Lng        *float64  `json:"lng" binding:"required"`
+	CapturedAt time.Time `json:"captured_at" binding:"required"`
+	Signature  string    `json:"signature" binding:"required"`
 }
 
 type updateRouteRequest struct {
-	PickupLat  *float64 `json:"pickup_lat,omitempty"`
-	PickupLng  *float64 `json:"pickup_lng,omitempty"`
-	DropoffLat *float64 `json:"dropoff_lat,omitempty"`
-	DropoffLng *float64 `json:"dropoff_lng,omitempty"`
+	PickupAddress  *string  `json:"pickup_address,omitempty"`
+	DropoffAddress *string  `json:"dropoff_address,omitempty"`
+	PickupLat      *float64 `json:"pickup_lat,omitempty"`
+	PickupLng      *float64 `json:"pickup_lng,omitempty"`
+	DropoffLat     *float64 `json:"dropoff_lat,omitempty"`
+	DropoffLng     *float64 `json:"dropoff_lng,omitempty"`
 }
 
 type orderListResponse struct {
@@ -79,7 +125,7 @@ type orderListResponse struct {
 
 // CreateOrder godoc
 // @Summary Create a new delivery order
-// @Description Create a new delivery order with pickup and dropoff locations
+// @Description Create a new delivery order; each of pickup and dropoff is given as coordinates, a saved address id (coordinates and notes are copied onto the order) or a free-text address resolved by the geocoder
 // @Tags orders
 // @Accept json
 // @Produce json
@@ -97,14 +143,12 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		return
 	}
 
-	if *req.PickupLat < -90 || *req.PickupLat > 90 ||
-		*req.DropoffLat < -90 || *req.DropoffLat > 90 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "latitude must be between -90 and 90"})
+	if err := validateOrderEndpoint("pickup", req.PickupAddressID, req.PickupAddress, req.PickupLat, req.PickupLng); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
 		return
 	}
-	if *req.PickupLng < -180 || *req.PickupLng > 180 ||
-		*req.DropoffLng < -180 || *req.DropoffLng > 180 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "longitude must be between -180 and 180"})
+	if err := validateOrderEndpoint("dropoff", req.DropoffAddressID, req.DropoffAddress, req.DropoffLat, req.DropoffLng); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
 		return
 	}
 
@@ -128,7 +172,7 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, toOrderResponse(*order))
+	c.JSON(http.StatusCreated, withDeliveryPIN(toOrderResponse(*order), *order))
 }
 
 // CancelOrder godoc
@@ -174,6 +218,73 @@ func (h *OrderHandler) CancelOrder(c *gin.Context) {
 	c.JSON(http.StatusOK, toOrderResponse(*order))
 }
 
+// ListMyOrders godoc
+// @Summary List my orders
+// @Description Get the caller's order history, newest first; active orders include drone location and ETA
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Page size (default: 10)"
+// @Param status query string false "Filter by order status"
+// @Param from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Created at or before (RFC3339 or YYYY-MM-DD, inclusive of the whole day)"
+// @Success 200 {object} orderListResponse "List of orders"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /orders [get]
+func (h *OrderHandler) ListMyOrders(c *gin.Context) {
+	userIDStr, exists := c.Get(CtxUserID)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized", "message": "missing user id"})
+		return
+	}
+	userID, err := strconv.ParseInt(userIDStr.(string), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized", "message": "invalid user id"})
+		return
+	}
+
+	var filters model.OrderListFilters
+	if err := parseStatusFilter(c, &filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
+		return
+	}
+	if err := parseCreatedRange(c, &filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
+		return
+	}
+
+	page, pageSize, err := parsePaginationParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
+		return
+	}
+
+	orders, pagination, err := h.uc.ListEnduserOrders(c.Request.Context(), userID, filters, page, pageSize)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	data := make([]orderResponse, len(orders))
+	for i := range orders {
+		data[i] = toOrderDetailsResponse(orders[i])
+	}
+
+	c.JSON(http.StatusOK, orderListResponse{
+		Data: data,
+		Meta: paginationMeta{
+			Page:     pagination.Page,
+			PageSize: pagination.PageSize,
+			HasNext:  pagination.HasNext(len(orders)),
+		},
+	})
+}
+
 // GetOrder godoc
 // @Summary Get order details
 // @Description Get detailed information about a specific order
@@ -304,17 +415,23 @@ func (h *OrderHandler) PickupOrder(c *gin.Context) {
 
 // DeliverOrder godoc
 // @Summary Deliver an order (Drone action)
-// @Description Drone delivers an order to the dropoff location
+// @Description Drone delivers an order to the dropoff location. The drone must submit the recipient PIN or a signed evidence payload,
+// @Description and its last heartbeat must be within the configured radius of the dropoff.
+// @Description Wrong PINs are counted per order; the one that uses up the last attempt fails the order (a parcel on board is returned) and answers `delivery_pin_locked`.
+// @Description Evidence signatures are hex HMAC-SHA256 over `order_id|photo_hash|lat|lng|captured_at` (coordinates with 6 decimals, RFC3339 UTC timestamp),
+// @Description keyed with the drone's own key, HMAC-SHA256(DELIVERY_EVIDENCE_SECRET, drone_id).
 // @Tags drone-actions
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Order ID"
+// @Param proof body deliverOrderRequest true "Proof of delivery"
 // @Success 200 {object} orderResponse "Order delivered successfully"
-// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 400 {object} map[string]string "Invalid request or missing proof"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Proof rejected or pin proof locked"
 // @Failure 404 {object} map[string]string "Order not found"
-// @Failure 409 {object} map[string]string "Order cannot be delivered"
+// @Failure 409 {object} map[string]string "Order cannot be delivered or drone is not at the dropoff"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /orders/{id}/deliver [post]
 func (h *OrderHandler) DeliverOrder(c *gin.Context) {
@@ -336,7 +453,13 @@ func (h *OrderHandler) DeliverOrder(c *gin.Context) {
 		return
 	}
 
-	order, err := h.uc.DeliverOrder(c.Request.Context(), droneID, orderID)
+	var req deliverOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid proof of delivery"})
+		return
+	}
+
+	order, err := h.uc.DeliverOrder(c.Request.Context(), droneID, orderID, toDeliveryProofModel(req))
 	if err != nil {
 		c.Error(err)
 		return
@@ -347,13 +470,13 @@ func (h *OrderHandler) DeliverOrder(c *gin.Context) {
 
 // FailOrder godoc
 // @Summary Fail an order (Drone action)
-// @Description Drone reports that an order delivery has failed
+// @Description Drone reports that an order delivery has failed. Orders already picked up move to returning and are routed back to their pickup location.
 // @Tags drone-actions
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Order ID"
-// @Success 200 {object} orderResponse "Order marked as failed"
+// @Success 200 {object} orderResponse "Order marked as failed or returning"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 404 {object} map[string]string "Order not found"
@@ -388,9 +511,52 @@ func (h *OrderHandler) FailOrder(c *gin.Context) {
 	c.JSON(http.StatusOK, toOrderResponse(*order))
 }
 
+// ReturnOrder godoc
+// @Summary Confirm a returned order (Drone action)
+// @Description Drone confirms that a returning parcel has been brought back to its origin
+// @Tags drone-actions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} orderResponse "Order marked as returned"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Order not found"
+// @Failure 409 {object} map[string]string "Order is not returning"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /orders/{id}/return [post]
+func (h *OrderHandler) ReturnOrder(c *gin.Context) {
+	idStr := c.Param(paramOrderID)
+	orderID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || orderID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid order id"})
+		return
+	}
+
+	droneIDStr, exists := c.Get(CtxUserID)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized", "message": "missing drone id"})
+		return
+	}
+	droneID, err := strconv.ParseInt(droneIDStr.(string), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized", "message": "invalid drone id"})
+		return
+	}
+
+	order, err := h.uc.ReturnOrder(c.Request.Context(), droneID, orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toOrderResponse(*order))
+}
+
 // AdminUpdateRoute godoc
 // @Summary Update order route (Admin action)
-// @Description Admin updates the pickup or dropoff location of an order
+// @Description Admin updates the pickup or dropoff location of an order, as coordinates or a free-text address
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -422,10 +588,12 @@ func (h *OrderHandler) AdminUpdateRoute(c *gin.Context) {
 	}
 
 	modelReq := model.UpdateRouteRequest{
-		PickupLat:  req.PickupLat,
-		PickupLng:  req.PickupLng,
-		DropoffLat: req.DropoffLat,
-		DropoffLng: req.DropoffLng,
+		PickupQuery:  req.PickupAddress,
+		DropoffQuery: req.DropoffAddress,
+		PickupLat:    req.PickupLat,
+		PickupLng:    req.PickupLng,
+		DropoffLat:   req.DropoffLat,
+		DropoffLng:   req.DropoffLng,
 	}
 
 	order, err := h.uc.UpdateRoute(c.Request.Context(), orderID, modelReq)
@@ -437,12 +605,62 @@ func (h *OrderHandler) AdminUpdateRoute(c *gin.Context) {
 	c.JSON(http.StatusOK, toOrderResponse(*order))
 }
 
+// validateOrderEndpoint checks that exactly one of a saved address id, a
+// free-text address or a coordinate pair was given for the endpoint.
+func validateOrderEndpoint(name string, addressID *int64, query *string, lat, lng *float64) error {
+	sources := 0
+	if addressID != nil {
+		sources++
+	}
+	if query != nil {
+		sources++
+	}
+	if lat != nil || lng != nil {
+		sources++
+	}
+
+	switch {
+	case sources == 0:
+		return errors.New(name + "_address_id, " + name + "_address or " + name + "_lat and " + name + "_lng are required")
+	case sources > 1:
+		return errors.New("provide only one of " + name + "_address_id, " + name + "_address or " + name + "_lat/" + name + "_lng")
+	case addressID != nil:
+		if *addressID <= 0 {
+			return errors.New(name + "_address_id must be a positive integer")
+		}
+		return nil
+	case query != nil:
+		if strings.TrimSpace(*query) == "" {
+			return errors.New(name + "_address must not be empty")
+		}
+		return nil
+	}
+
+	if lat == nil || lng == nil {
+		return errors.New(name + "_lat and " + name + "_lng must both be provided")
+	}
+	if *lat < -90 || *lat > 90 {
+		return errors.New("latitude must be between -90 and 90")
+	}
+	if *lng < -180 || *lng > 180 {
+		return errors.New("longitude must be between -180 and 180")
+	}
+	return nil
+}
+
 func validateRouteUpdate(req updateRouteRequest) error {
 	hasPickup := req.PickupLat != nil || req.PickupLng != nil
 	hasDropoff := req.DropoffLat != nil || req.DropoffLng != nil
 
-	if !hasPickup && !hasDropoff {
-		return errors.New("pickup or dropoff coordinates are required")
+	if !hasPickup && !hasDropoff && req.PickupAddress == nil && req.DropoffAddress == nil {
+		return errors.New("pickup or dropoff coordinates or address are required")
+	}
+
+	if err := validateRouteAddress("pickup", req.PickupAddress, hasPickup); err != nil {
+		return err
+	}
+	if err := validateRouteAddress("dropoff", req.DropoffAddress, hasDropoff); err != nil {
+		return err
 	}
 
 	if hasPickup {
@@ -472,6 +690,19 @@ func validateRouteUpdate(req updateRouteRequest) error {
 	return nil
 }
 
+func validateRouteAddress(name string, address *string, hasCoordinates bool) error {
+	if address == nil {
+		return nil
+	}
+	if hasCoordinates {
+		return errors.New("provide either " + name + "_address or " + name + "_lat/" + name + "_lng, not both")
+	}
+	if strings.TrimSpace(*address) == "" {
+		return errors.New(name + "_address must not be empty")
+	}
+	return nil
+}
+
 func parseOrderID(c *gin.Context) (int64, error) {
 	idStr := c.Param(paramOrderID)
 	orderID, err := strconv.ParseInt(idStr, 10, 64)
@@ -494,6 +725,8 @@ func parseOrderID(c *gin.Context) (int64, error) {
 // @Param status query string false "Filter by order status"
 // @Param enduser_id query int false "Filter by end user ID"
 // @Param assigned_drone_id query int false "Filter by assigned drone ID"
+// @Param from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Created at or before (RFC3339 or YYYY-MM-DD, inclusive of the whole day)"
 // @Success 200 {object} orderListResponse "List of orders"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 401 {object} map[string]string "Unauthorized"
@@ -526,12 +759,8 @@ func (h *OrderHandler) AdminListOrders(c *gin.Context) {
 func parseOrderFilters(c *gin.Context) (model.OrderListFilters, error) {
 	var filters model.OrderListFilters
 
-	if status := c.Query(queryParamStatus); status != "" {
-		s := model.OrderStatus(status)
-		if !model.IsValidOrderStatus(s) {
-			return filters, errors.New("invalid status")
-		}
-		filters.Status = &s
+	if err := parseStatusFilter(c, &filters); err != nil {
+		return filters, err
 	}
 
 	if enduserStr := c.Query(queryParamEnduserID); enduserStr != "" {
@@ -550,9 +779,72 @@ func parseOrderFilters(c *gin.Context) (model.OrderListFilters, error) {
 		filters.AssignedDroneID = &id
 	}
 
+	if err := parseCreatedRange(c, &filters); err != nil {
+		return filters, err
+	}
+
 	return filters, nil
 }
 
+func parseStatusFilter(c *gin.Context, filters *model.OrderListFilters) error {
+	if status := c.Query(queryParamStatus); status != "" {
+		s := model.OrderStatus(status)
+		if !model.IsValidOrderStatus(s) {
+			return errors.New("invalid status")
+		}
+		filters.Status = &s
+	}
+	return nil
+}
+
+func parseCreatedRange(c *gin.Context, filters *model.OrderListFilters) error {
+	from, to, err := parseTimeRange(c)
+	if err != nil {
+		return err
+	}
+	filters.CreatedFrom = from
+	filters.CreatedTo = to
+	return nil
+}
+
+// parseTimeRange reads from/to as RFC3339 timestamps or plain dates; a
+// plain-date "to" covers that whole day.
+func parseTimeRange(c *gin.Context) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+
+	if fromStr := c.Query(queryParamCreatedFrom); fromStr != "" {
+		t, _, err := parseFilterTime(fromStr)
+		if err != nil {
+			return nil, nil, errors.New("from must be an RFC3339 timestamp or YYYY-MM-DD date")
+		}
+		from = &t
+	}
+
+	if toStr := c.Query(queryParamCreatedTo); toStr != "" {
+		t, dateOnly, err := parseFilterTime(toStr)
+		if err != nil {
+			return nil, nil, errors.New("to must be an RFC3339 timestamp or YYYY-MM-DD date")
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		to = &t
+	}
+
+	return from, to, nil
+}
+
+func parseFilterTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), false, nil
+	}
+	t, err := time.Parse(dateOnlyLayout, value)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, true, nil
+}
+
 func toOrderListResponse(orders []model.Order, pagination model.Pagination) orderListResponse {
 	data := make([]orderResponse, len(orders))
 	for i := range orders {
@@ -570,13 +862,22 @@ func toOrderListResponse(orders []model.Order, pagination model.Pagination) orde
 }
 
 func toCreateOrderModel(req createOrderRequest, userID int64) model.CreateOrderRequest {
-	return model.CreateOrderRequest{
-		EnduserID:  userID,
-		PickupLat:  *req.PickupLat,
-		PickupLng:  *req.PickupLng,
-		DropoffLat: *req.DropoffLat,
-		DropoffLng: *req.DropoffLng,
-	}
+	modelReq := model.CreateOrderRequest{
+		EnduserID:        userID,
+		PickupAddressID:  req.PickupAddressID,
+		DropoffAddressID: req.DropoffAddressID,
+		PickupQuery:      req.PickupAddress,
+		DropoffQuery:     req.DropoffAddress,
+	}
+	if req.PickupLat != nil {
+		modelReq.PickupLat = *req.PickupLat
+		modelReq.PickupLng = *req.PickupLng
+	}
+	if req.DropoffLat != nil {
+		modelReq.DropoffLat = *req.DropoffLat
+		modelReq.DropoffLng = *req.DropoffLng
+	}
+	return modelReq
 }
 
 func toOrderResponse(order model.Order) orderResponse {
@@ -591,33 +892,30 @@ func toOrderResponse(order model.Order) orderResponse {
 			Lat: order.DropoffLat,
 			Lng: order.DropoffLng,
 		},
-		CreatedAt:       order.CreatedAt,
-		UpdatedAt:       order.UpdatedAt,
-		CanceledAt:      order.CanceledAt,
-		AssignedDroneID: order.AssignedDroneID,
-		HandoffLat:      order.HandoffLat,
-		HandoffLng:      order.HandoffLng,
+		CreatedAt:          order.CreatedAt,
+		UpdatedAt:          order.UpdatedAt,
+		CanceledAt:         order.CanceledAt,
+		AssignedDroneID:    order.AssignedDroneID,
+		HandoffLat:         order.HandoffLat,
+		HandoffLng:         order.HandoffLng,
+		ReturnLat:          order.ReturnLat,
+		ReturnLng:          order.ReturnLng,
+		PickupAddressID:    order.PickupAddressID,
+		DropoffAddressID:   order.DropoffAddressID,
+		DeliveryNotes:      order.DeliveryNotes,
+		AccessInstructions: order.AccessInstructions,
 	}
 }
 
 func toOrderDetailsResponse(details model.OrderDetails) orderResponse {
-	response := orderResponse{
-		OrderID: details.Order.ID,
-		Status:  string(details.Order.Status),
-		Pickup: locationResponse{
-			Lat: details.Order.PickupLat,
-			Lng: details.Order.PickupLng,
-		},
-		Dropoff: locationResponse{
-			Lat: details.Order.DropoffLat,
-			Lng: details.Order.DropoffLng,
-		},
-		CreatedAt:       details.Order.CreatedAt,
-		UpdatedAt:       details.Order.UpdatedAt,
-		CanceledAt:      details.Order.CanceledAt,
-		AssignedDroneID: details.Order.AssignedDroneID,
-		HandoffLat:      details.Order.HandoffLat,
-		HandoffLng:      details.Order.HandoffLng,
+	response := toOrderResponse(details.Order)
+
+	if details.PickupPlace != nil {
+		response.Pickup.Address = &details.PickupPlace.Name
+	}
+
+	if details.DropoffPlace != nil {
+		response.Dropoff.Address = &details.DropoffPlace.Name
 	}
 
 	if details.DroneLocation != nil {
@@ -631,5 +929,36 @@ func toOrderDetailsResponse(details model.OrderDetails) orderResponse {
 		response.ETAMinutes = details.ETA
 	}
 
+	for _, leg := range details.Legs {
+		response.Legs = append(response.Legs, legETAResponse{
+			Kind:        string(leg.Kind),
+			Lat:         leg.Lat,
+			Lng:         leg.Lng,
+			StopsBefore: leg.StopsBefore,
+			ETAMinutes:  leg.ETA,
+		})
+	}
+
+	return withDeliveryPIN(response, details.Order)
+}
+
+func withDeliveryPIN(response orderResponse, order model.Order) orderResponse {
+	if !order.IsTerminal() {
+		response.DeliveryPIN = order.DeliveryPIN
+	}
 	return response
 }
+
+func toDeliveryProofModel(req deliverOrderRequest) model.DeliveryProof {
+	proof := model.DeliveryProof{PIN: req.PIN}
+	if req.Evidence != nil {
+		proof.Evidence = &model.DeliveryEvidence{
+			PhotoHash:  req.Evidence.PhotoHash,
+			Lat:        *req.Evidence.Lat,
+			Lng:        *req.Evidence.Lng,
+			CapturedAt: req.Evidence.CapturedAt,
+			Signature:  req.Evidence.Signature,
+		}
+	}
+	return proof
+}
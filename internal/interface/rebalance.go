@@ -0,0 +1,225 @@
+package iface
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+type RebalanceUsecase interface {
+	Rebalance(ctx context.Context, req model.RebalanceRequest) (*model.RebalancePlan, error)
+	Report(ctx context.Context, from, to *time.Time) (*model.RebalanceReport, error)
+}
+
+type RebalanceHandler struct {
+	uc RebalanceUsecase
+}
+
+func NewRebalanceHandler(uc RebalanceUsecase) *RebalanceHandler {
+	return &RebalanceHandler{uc: uc}
+}
+
+type rebalanceRunRequest struct {
+	Aggressiveness *float64 `json:"aggressiveness,omitempty" example:"0.5"`
+	DryRun         bool     `json:"dry_run,omitempty"`
+}
+
+type rebalanceCellResponse struct {
+	MinLat               float64 `json:"min_lat"`
+	MinLng               float64 `json:"min_lng"`
+	MaxLat               float64 `json:"max_lat"`
+	MaxLng               float64 `json:"max_lng"`
+	CenterLat            float64 `json:"center_lat"`
+	CenterLng            float64 `json:"center_lng"`
+	OrdersPerHour        float64 `json:"orders_per_hour"`
+	IdleDrones           int     `json:"idle_drones"`
+	TargetDrones         int     `json:"target_drones"`
+	PredictedWaitSeconds *int    `json:"predicted_wait_seconds,omitempty"`
+}
+
+type rebalanceMoveResponse struct {
+	DroneID    int64   `json:"drone_id"`
+	FromLat    float64 `json:"from_lat"`
+	FromLng    float64 `json:"from_lng"`
+	ToLat      float64 `json:"to_lat"`
+	ToLng      float64 `json:"to_lng"`
+	DistanceKm float64 `json:"distance_km"`
+	CommandID  *int64  `json:"command_id,omitempty"`
+}
+
+type rebalancePlanResponse struct {
+	HourOfDay      int                     `json:"hour_of_day"`
+	Aggressiveness float64                 `json:"aggressiveness"`
+	DryRun         bool                    `json:"dry_run"`
+	IdleDrones     int                     `json:"idle_drones"`
+	Cells          []rebalanceCellResponse `json:"cells"`
+	Moves          []rebalanceMoveResponse `json:"moves"`
+	ComputedAt     time.Time               `json:"computed_at"`
+}
+
+type waitComparisonResponse struct {
+	Forecasts            int     `json:"forecasts"`
+	ExpectedOrders       float64 `json:"expected_orders"`
+	Orders               int     `json:"orders"`
+	PickedUp             int     `json:"picked_up"`
+	PredictedWaitSeconds *int    `json:"predicted_wait_seconds,omitempty"`
+	ActualWaitSeconds    *int    `json:"actual_wait_seconds,omitempty"`
+}
+
+type rebalanceCellReportResponse struct {
+	MinLat float64 `json:"min_lat"`
+	MinLng float64 `json:"min_lng"`
+	MaxLat float64 `json:"max_lat"`
+	MaxLng float64 `json:"max_lng"`
+	waitComparisonResponse
+}
+
+type rebalanceReportResponse struct {
+	From    time.Time                     `json:"from"`
+	To      time.Time                     `json:"to"`
+	Overall waitComparisonResponse        `json:"overall"`
+	Cells   []rebalanceCellReportResponse `json:"cells"`
+}
+
+// RunRebalance godoc
+// @Summary Rebalance idle drones (admin)
+// @Description Forecasts the coming hour's pickups per cell (REBALANCE_CELL_KM) from the same hour of the day over REBALANCE_LOOKBACK, spreads the idle drones over the cells in proportion and sends aggressiveness (0..1, default REBALANCE_AGGRESSIVENESS) of the drones out of place off with reposition commands.
+// @Description predicted_wait_seconds is the flight time of the nearest idle drone to the middle of the cell once the moves are made. A dry run only plans; otherwise the forecasts are kept for the wait report. A move without command_id was skipped because the drone took an order meanwhile.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body rebalanceRunRequest false "Aggressiveness and dry run"
+// @Success 200 {object} rebalancePlanResponse "Rebalance plan"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/rebalance/run [post]
+func (h *RebalanceHandler) RunRebalance(c *gin.Context) {
+	var req rebalanceRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid request body"})
+		return
+	}
+
+	plan, err := h.uc.Rebalance(c.Request.Context(), model.RebalanceRequest{Aggressiveness: req.Aggressiveness, DryRun: req.DryRun})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toRebalancePlanResponse(*plan, req.DryRun))
+}
+
+// GetRebalanceReport godoc
+// @Summary Predicted vs actual wait (admin)
+// @Description Sets the wait each rebalance run predicted per cell against the wait orders placed there while the forecast stood actually saw, from creation to pickup. Predictions are weighted by the orders picked up under them.
+// @Description Covers the runs between from and to (default: the last 24 hours, at most 31 days).
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Window start (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Window end (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {object} rebalanceReportResponse "Wait report"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/rebalance/report [get]
+func (h *RebalanceHandler) GetRebalanceReport(c *gin.Context) {
+	from, to, err := parseTimeRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
+		return
+	}
+
+	report, err := h.uc.Report(c.Request.Context(), from, to)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toRebalanceReportResponse(*report))
+}
+
+func toRebalancePlanResponse(plan model.RebalancePlan, dryRun bool) rebalancePlanResponse {
+	resp := rebalancePlanResponse{
+		HourOfDay:      plan.Hour,
+		Aggressiveness: plan.Aggressiveness,
+		DryRun:         dryRun,
+		IdleDrones:     plan.IdleDrones,
+		Cells:          make([]rebalanceCellResponse, len(plan.Cells)),
+		Moves:          make([]rebalanceMoveResponse, len(plan.Moves)),
+		ComputedAt:     plan.ComputedAt,
+	}
+	for i, cell := range plan.Cells {
+		resp.Cells[i] = rebalanceCellResponse{
+			MinLat:               cell.Min.Lat,
+			MinLng:               cell.Min.Lng,
+			MaxLat:               cell.Max.Lat,
+			MaxLng:               cell.Max.Lng,
+			CenterLat:            cell.Center.Lat,
+			CenterLng:            cell.Center.Lng,
+			OrdersPerHour:        cell.OrdersPerHour,
+			IdleDrones:           cell.IdleDrones,
+			TargetDrones:         cell.TargetDrones,
+			PredictedWaitSeconds: durationSeconds(cell.PredictedWait),
+		}
+	}
+	for i, move := range plan.Moves {
+		resp.Moves[i] = rebalanceMoveResponse{
+			DroneID:    move.DroneID,
+			FromLat:    move.From.Lat,
+			FromLng:    move.From.Lng,
+			ToLat:      move.To.Lat,
+			ToLng:      move.To.Lng,
+			DistanceKm: move.DistanceKm,
+			CommandID:  move.CommandID,
+		}
+	}
+	return resp
+}
+
+func toRebalanceReportResponse(report model.RebalanceReport) rebalanceReportResponse {
+	resp := rebalanceReportResponse{
+		From:    report.From,
+		To:      report.To,
+		Overall: toWaitComparisonResponse(report.Overall),
+		Cells:   make([]rebalanceCellReportResponse, len(report.Cells)),
+	}
+	for i, cell := range report.Cells {
+		resp.Cells[i] = rebalanceCellReportResponse{
+			MinLat:                 cell.Min.Lat,
+			MinLng:                 cell.Min.Lng,
+			MaxLat:                 cell.Max.Lat,
+			MaxLng:                 cell.Max.Lng,
+			waitComparisonResponse: toWaitComparisonResponse(cell.WaitComparison),
+		}
+	}
+	return resp
+}
+
+func toWaitComparisonResponse(c model.WaitComparison) waitComparisonResponse {
+	return waitComparisonResponse{
+		Forecasts:            c.Forecasts,
+		ExpectedOrders:       c.ExpectedOrders,
+		Orders:               c.Orders,
+		PickedUp:             c.PickedUp,
+		PredictedWaitSeconds: durationSeconds(c.PredictedWait),
+		ActualWaitSeconds:    durationSeconds(c.ActualWait),
+	}
+}
+
+func durationSeconds(d *time.Duration) *int {
+	if d == nil {
+		return nil
+	}
+	seconds := int(d.Seconds())
+	return &seconds
+}
@@ -6,7 +6,7 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func NewRouter(authHandler *AuthHandler, orderHandler *OrderHandler, droneHandler *DroneHandler, droneWSHandler *DroneWSHandler, authMW gin.HandlerFunc) *gin.Engine {
+func NewRouter(authHandler *AuthHandler, orderHandler *OrderHandler, addressHandler *AddressHandler, geocodeHandler *GeocodeHandler, serviceAreaHandler *ServiceAreaHandler, noFlyZoneHandler *NoFlyZoneHandler, landingSiteHandler *LandingSiteHandler, depotHandler *DepotHandler, droneHandler *DroneHandler, droneWSHandler *DroneWSHandler, breachHandler *GeofenceBreachHandler, trackHandler *TrackHandler, commandHandler *DroneCommandHandler, adminWSHandler *AdminWSHandler, wsMetrics *WSMetrics, leaderHandler *LeaderHandler, dispatchHandler *DispatchHandler, rebalanceHandler *RebalanceHandler, maintenanceHandler *MaintenanceHandler, authMW gin.HandlerFunc) *gin.Engine {
 	r := gin.New()
 	r.Use(gin.Logger(), gin.Recovery(), ErrorHandlerMiddleware())
 
@@ -24,10 +24,37 @@ func NewRouter(authHandler *AuthHandler, orderHandler *OrderHandler, droneHandle
 	enduser.Use(authMW, RequireRoles("enduser"))
 	{
 		enduser.POST("", orderHandler.CreateOrder)
+		enduser.GET("", orderHandler.ListMyOrders)
 		enduser.GET("/:id", orderHandler.GetOrder)
 		enduser.POST("/:id/cancel", orderHandler.CancelOrder)
 	}
 
+	// Enduser address book
+	addresses := r.Group("/addresses")
+	addresses.Use(authMW, RequireRoles("enduser"))
+	{
+		addresses.POST("", addressHandler.CreateAddress)
+		addresses.GET("", addressHandler.ListAddresses)
+		addresses.GET("/:id", addressHandler.GetAddress)
+		addresses.PATCH("/:id", addressHandler.UpdateAddress)
+		addresses.DELETE("/:id", addressHandler.DeleteAddress)
+	}
+
+	// Geocoding preview, available to every role
+	geocode := r.Group("/geocode")
+	geocode.Use(authMW, RequireRoles("enduser", "admin", "drone"))
+	{
+		geocode.GET("", geocodeHandler.Geocode)
+		geocode.GET("/reverse", geocodeHandler.ReverseGeocode)
+	}
+
+	// Service coverage, available to every role
+	serviceAreas := r.Group("/service-areas")
+	serviceAreas.Use(authMW, RequireRoles("enduser", "admin", "drone"))
+	{
+		serviceAreas.GET("", serviceAreaHandler.ListActiveServiceAreas)
+	}
+
 	// Drone order endpoints
 	drone := r.Group("/orders")
 	drone.Use(authMW, RequireRoles("drone"))
@@ -36,6 +63,7 @@ func NewRouter(authHandler *AuthHandler, orderHandler *OrderHandler, droneHandle
 		drone.POST("/:id/pickup", orderHandler.PickupOrder)
 		drone.POST("/:id/deliver", orderHandler.DeliverOrder)
 		drone.POST("/:id/fail", orderHandler.FailOrder)
+		drone.POST("/:id/return", orderHandler.ReturnOrder)
 	}
 
 	ws := r.Group("/ws")
@@ -44,19 +72,73 @@ func NewRouter(authHandler *AuthHandler, orderHandler *OrderHandler, droneHandle
 		ws.GET("/heartbeat", droneWSHandler.HandleHeartbeat)
 	}
 
+	adminWS := r.Group("/ws")
+	adminWS.Use(authMW, RequireRoles("admin"))
+	{
+		adminWS.GET("/admin", adminWSHandler.HandleEvents)
+	}
+
+	adminWSMetrics := r.Group("/admin/ws")
+	adminWSMetrics.Use(authMW, RequireRoles("admin"))
+	{
+		adminWSMetrics.GET("/metrics", wsMetrics.GetMetrics)
+	}
+
+	adminLeader := r.Group("/admin/leader")
+	adminLeader.Use(authMW, RequireRoles("admin"))
+	{
+		adminLeader.GET("", leaderHandler.GetLeader)
+	}
+
+	adminDispatch := r.Group("/admin/dispatch")
+	adminDispatch.Use(authMW, RequireRoles("admin"))
+	{
+		adminDispatch.POST("/dry-run", dispatchHandler.DryRunDispatch)
+	}
+
+	adminRebalance := r.Group("/admin/rebalance")
+	adminRebalance.Use(authMW, RequireRoles("admin"))
+	{
+		adminRebalance.POST("/run", rebalanceHandler.RunRebalance)
+		adminRebalance.GET("/report", rebalanceHandler.GetRebalanceReport)
+	}
+
 	droneMgmt := r.Group("/drones")
 	droneMgmt.Use(authMW, RequireRoles("drone"))
 	{
 		droneMgmt.POST("/:id/broken", droneHandler.MarkBroken)
 		droneMgmt.POST("/:id/fixed", droneHandler.MarkFixed)
+		droneMgmt.GET("/:id/trip", droneHandler.GetTrip)
 	}
 
 	adminDrones := r.Group("/admin/drones")
 	adminDrones.Use(authMW, RequireRoles("admin"))
 	{
 		adminDrones.GET("", droneHandler.List)
+		adminDrones.GET("/:id", droneHandler.Get)
+		adminDrones.PATCH("/:id", droneHandler.UpdateCapacity)
+		adminDrones.GET("/:id/trip", droneHandler.GetTrip)
 		adminDrones.POST("/:id/broken", droneHandler.MarkBroken)
 		adminDrones.POST("/:id/fixed", droneHandler.MarkFixed)
+		adminDrones.GET("/:id/breaches", breachHandler.ListDroneBreaches)
+		adminDrones.GET("/:id/track", trackHandler.GetDroneTrack)
+		adminDrones.POST("/:id/commands", commandHandler.IssueCommand)
+		adminDrones.GET("/:id/commands", commandHandler.ListDroneCommands)
+		adminDrones.GET("/:id/commands/:command_id", commandHandler.GetDroneCommand)
+		adminDrones.PUT("/:id/home-depot", depotHandler.SetHomeDepot)
+		adminDrones.DELETE("/:id/home-depot", depotHandler.ClearHomeDepot)
+		adminDrones.GET("/:id/maintenance", maintenanceHandler.GetDroneMaintenance)
+		adminDrones.POST("/:id/maintenance", maintenanceHandler.OpenDroneMaintenance)
+		adminDrones.PUT("/:id/maintenance-schedule", maintenanceHandler.SetMaintenanceSchedule)
+	}
+
+	adminMaintenance := r.Group("/admin/maintenance")
+	adminMaintenance.Use(authMW, RequireRoles("admin"))
+	{
+		adminMaintenance.GET("", maintenanceHandler.ListMaintenanceRecords)
+		adminMaintenance.GET("/:id", maintenanceHandler.GetMaintenanceRecord)
+		adminMaintenance.PATCH("/:id", maintenanceHandler.UpdateMaintenanceRecord)
+		adminMaintenance.POST("/:id/close", maintenanceHandler.CloseMaintenanceRecord)
 	}
 
 	adminOrders := r.Group("/admin/orders")
@@ -64,6 +146,43 @@ func NewRouter(authHandler *AuthHandler, orderHandler *OrderHandler, droneHandle
 	{
 		adminOrders.GET("", orderHandler.AdminListOrders)
 		adminOrders.PATCH("/:id", orderHandler.AdminUpdateRoute)
+		adminOrders.GET("/:id/track", trackHandler.GetOrderTrack)
+	}
+
+	adminServiceAreas := r.Group("/admin/service-areas")
+	adminServiceAreas.Use(authMW, RequireRoles("admin"))
+	{
+		adminServiceAreas.GET("", serviceAreaHandler.AdminListServiceAreas)
+		adminServiceAreas.POST("", serviceAreaHandler.AdminCreateServiceArea)
+		adminServiceAreas.PATCH("/:id", serviceAreaHandler.AdminUpdateServiceArea)
+		adminServiceAreas.DELETE("/:id", serviceAreaHandler.AdminDeleteServiceArea)
+	}
+
+	adminNoFlyZones := r.Group("/admin/no-fly-zones")
+	adminNoFlyZones.Use(authMW, RequireRoles("admin"))
+	{
+		adminNoFlyZones.GET("", noFlyZoneHandler.AdminListNoFlyZones)
+		adminNoFlyZones.POST("", noFlyZoneHandler.AdminCreateNoFlyZone)
+		adminNoFlyZones.PATCH("/:id", noFlyZoneHandler.AdminUpdateNoFlyZone)
+		adminNoFlyZones.DELETE("/:id", noFlyZoneHandler.AdminDeleteNoFlyZone)
+	}
+
+	adminLandingSites := r.Group("/admin/landing-sites")
+	adminLandingSites.Use(authMW, RequireRoles("admin"))
+	{
+		adminLandingSites.GET("", landingSiteHandler.AdminListLandingSites)
+		adminLandingSites.POST("", landingSiteHandler.AdminCreateLandingSite)
+		adminLandingSites.PATCH("/:id", landingSiteHandler.AdminUpdateLandingSite)
+		adminLandingSites.DELETE("/:id", landingSiteHandler.AdminDeleteLandingSite)
+	}
+
+	adminDepots := r.Group("/admin/depots")
+	adminDepots.Use(authMW, RequireRoles("admin"))
+	{
+		adminDepots.GET("", depotHandler.AdminListDepots)
+		adminDepots.POST("", depotHandler.AdminCreateDepot)
+		adminDepots.PATCH("/:id", depotHandler.AdminUpdateDepot)
+		adminDepots.DELETE("/:id", depotHandler.AdminDeleteDepot)
 	}
 
 	return r
@@ -0,0 +1,249 @@
+package iface
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+const paramServiceAreaID = "id"
+
+type ServiceAreaUsecase interface {
+	CreateServiceArea(ctx context.Context, name string, boundary model.Polygon) (*model.ServiceArea, error)
+	ListServiceAreas(ctx context.Context) ([]model.ServiceArea, error)
+	ListActiveServiceAreas(ctx context.Context) ([]model.ServiceArea, error)
+	UpdateServiceArea(ctx context.Context, id int64, req model.UpdateServiceAreaRequest) (*model.ServiceArea, error)
+	DeleteServiceArea(ctx context.Context, id int64) error
+}
+
+type ServiceAreaHandler struct {
+	uc ServiceAreaUsecase
+}
+
+func NewServiceAreaHandler(uc ServiceAreaUsecase) *ServiceAreaHandler {
+	return &ServiceAreaHandler{uc: uc}
+}
+
+type geoPointRequest struct {
+	Lat *float64 `json:"lat" binding:"required"`
+	Lng *float64 `json:"lng" binding:"required"`
+}
+
+type createServiceAreaRequest struct {
+	Name     string            `json:"name" binding:"required"`
+	Boundary []geoPointRequest `json:"boundary" binding:"required,dive"`
+}
+
+type updateServiceAreaRequest struct {
+	Name     *string           `json:"name,omitempty"`
+	Boundary []geoPointRequest `json:"boundary,omitempty" binding:"omitempty,dive"`
+	Active   *bool             `json:"active,omitempty"`
+}
+
+type geoPointResponse struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+type serviceAreaResponse struct {
+	ServiceAreaID int64              `json:"service_area_id"`
+	Name          string             `json:"name"`
+	Boundary      []geoPointResponse `json:"boundary"`
+	Active        bool               `json:"active"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+}
+
+type serviceAreaListResponse struct {
+	Data []serviceAreaResponse `json:"data"`
+}
+
+// ListActiveServiceAreas godoc
+// @Summary List service coverage
+// @Description Get the active service areas; pickup and dropoff must fall inside one of them. An empty list means coverage is unrestricted.
+// @Tags service-areas
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} serviceAreaListResponse "Active service areas"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /service-areas [get]
+func (h *ServiceAreaHandler) ListActiveServiceAreas(c *gin.Context) {
+	areas, err := h.uc.ListActiveServiceAreas(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toServiceAreaListResponse(areas))
+}
+
+// AdminListServiceAreas godoc
+// @Summary List all service areas (admin)
+// @Description Get every service area, including inactive ones
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} serviceAreaListResponse "Service areas"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/service-areas [get]
+func (h *ServiceAreaHandler) AdminListServiceAreas(c *gin.Context) {
+	areas, err := h.uc.ListServiceAreas(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toServiceAreaListResponse(areas))
+}
+
+// AdminCreateServiceArea godoc
+// @Summary Create a service area (admin)
+// @Description Draw a polygon the fleet operates in; vertices are given in order and the ring is closed automatically
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param area body createServiceAreaRequest true "Service area"
+// @Success 201 {object} serviceAreaResponse "Service area created"
+// @Failure 400 {object} map[string]string "Invalid request or polygon"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/service-areas [post]
+func (h *ServiceAreaHandler) AdminCreateServiceArea(c *gin.Context) {
+	var req createServiceAreaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "name and boundary with lat and lng for every vertex are required"})
+		return
+	}
+
+	area, err := h.uc.CreateServiceArea(c.Request.Context(), req.Name, toPolygon(req.Boundary))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, toServiceAreaResponse(*area))
+}
+
+// AdminUpdateServiceArea godoc
+// @Summary Update a service area (admin)
+// @Description Rename, redraw, or activate/deactivate a service area. Existing orders are not re-validated.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Service area ID"
+// @Param area body updateServiceAreaRequest true "Fields to change"
+// @Success 200 {object} serviceAreaResponse "Service area updated"
+// @Failure 400 {object} map[string]string "Invalid request or polygon"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Service area not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/service-areas/{id} [patch]
+func (h *ServiceAreaHandler) AdminUpdateServiceArea(c *gin.Context) {
+	areaID, ok := parseServiceAreaID(c)
+	if !ok {
+		return
+	}
+
+	var req updateServiceAreaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid json body"})
+		return
+	}
+
+	area, err := h.uc.UpdateServiceArea(c.Request.Context(), areaID, model.UpdateServiceAreaRequest{
+		Name:     req.Name,
+		Boundary: toPolygon(req.Boundary),
+		Active:   req.Active,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toServiceAreaResponse(*area))
+}
+
+// AdminDeleteServiceArea godoc
+// @Summary Delete a service area (admin)
+// @Tags admin
+// @Security BearerAuth
+// @Param id path int true "Service area ID"
+// @Success 204 "Service area deleted"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Service area not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/service-areas/{id} [delete]
+func (h *ServiceAreaHandler) AdminDeleteServiceArea(c *gin.Context) {
+	areaID, ok := parseServiceAreaID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.uc.DeleteServiceArea(c.Request.Context(), areaID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func parseServiceAreaID(c *gin.Context) (int64, bool) {
+	areaID, err := strconv.ParseInt(c.Param(paramServiceAreaID), 10, 64)
+	if err != nil || areaID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid service area id"})
+		return 0, false
+	}
+	return areaID, true
+}
+
+// toPolygon keeps a nil boundary nil so updates can tell "unchanged" apart.
+func toPolygon(points []geoPointRequest) model.Polygon {
+	if points == nil {
+		return nil
+	}
+	polygon := make(model.Polygon, len(points))
+	for i, p := range points {
+		polygon[i] = model.GeoPoint{Lat: *p.Lat, Lng: *p.Lng}
+	}
+	return polygon
+}
+
+func toServiceAreaResponse(area model.ServiceArea) serviceAreaResponse {
+	boundary := make([]geoPointResponse, len(area.Boundary))
+	for i, v := range area.Boundary {
+		boundary[i] = geoPointResponse{Lat: v.Lat, Lng: v.Lng}
+	}
+
+	return serviceAreaResponse{
+		ServiceAreaID: area.ID,
+		Name:          area.Name,
+		Boundary:      boundary,
+		Active:        area.Active,
+		CreatedAt:     area.CreatedAt,
+		UpdatedAt:     area.UpdatedAt,
+	}
+}
+
+func toServiceAreaListResponse(areas []model.ServiceArea) serviceAreaListResponse {
+	data := make([]serviceAreaResponse, len(areas))
+	for i := range areas {
+		data[i] = toServiceAreaResponse(areas[i])
+	}
+	return serviceAreaListResponse{Data: data}
+}
@@ -0,0 +1,169 @@
+package iface
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+	"github.com/gin-gonic/gin"
+)
+
+type TrackUsecase interface {
+	DroneTrack(ctx context.Context, droneID int64, from, to *time.Time) (*model.Track, model.TrackWindow, error)
+	OrderTrack(ctx context.Context, orderID int64) ([]model.Track, error)
+}
+
+type TrackHandler struct {
+	uc TrackUsecase
+}
+
+func NewTrackHandler(uc TrackUsecase) *TrackHandler {
+	return &TrackHandler{uc: uc}
+}
+
+// lineStringGeometry is a GeoJSON LineString; coordinates are [lng, lat].
+type lineStringGeometry struct {
+	Type        string       `json:"type"`
+	Coordinates [][2]float64 `json:"coordinates"`
+}
+
+type trackProperties struct {
+	DroneID    int64       `json:"drone_id"`
+	TripID     *int64      `json:"trip_id,omitempty"`
+	OrderID    *int64      `json:"order_id,omitempty"`
+	From       *time.Time  `json:"from,omitempty"`
+	To         *time.Time  `json:"to,omitempty"`
+	PointCount int         `json:"point_count"`
+	Truncated  bool        `json:"truncated"`
+	Timestamps []time.Time `json:"timestamps"`
+	AltitudeM  []*float64  `json:"altitude_m"`
+	HeadingDeg []*float64  `json:"heading_deg"`
+	SpeedMPS   []*float64  `json:"speed_mps"`
+	BatteryPct []*float64  `json:"battery_pct"`
+}
+
+// trackFeature is a GeoJSON Feature. Geometry is null when fewer than two
+// points were recorded, since a LineString needs at least two positions.
+type trackFeature struct {
+	Type       string              `json:"type"`
+	Geometry   *lineStringGeometry `json:"geometry"`
+	Properties trackProperties     `json:"properties"`
+}
+
+type trackFeatureCollection struct {
+	Type     string         `json:"type"`
+	Features []trackFeature `json:"features"`
+}
+
+// GetDroneTrack godoc
+// @Summary Replay a drone's flight track (admin)
+// @Description Telemetry recorded from the drone's heartbeats as a GeoJSON LineString Feature.
+// @Description The window defaults to the last 24 hours and may span at most 7 days; at most 10000 points are returned.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drone ID"
+// @Param from query string false "Window start (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Window end (RFC3339 or YYYY-MM-DD), defaults to now"
+// @Success 200 {object} trackFeature "Track"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 404 {object} map[string]string "Drone not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/drones/{id}/track [get]
+func (h *TrackHandler) GetDroneTrack(c *gin.Context) {
+	droneID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || droneID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_drone_id", "message": "invalid drone id"})
+		return
+	}
+
+	from, to, err := parseTimeRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": err.Error()})
+		return
+	}
+
+	track, window, err := h.uc.DroneTrack(c.Request.Context(), droneID, from, to)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	feature := toTrackFeature(*track)
+	feature.Properties.From = &window.From
+	feature.Properties.To = &window.To
+
+	c.JSON(http.StatusOK, feature)
+}
+
+// GetOrderTrack godoc
+// @Summary Replay an order's flight track (admin)
+// @Description GeoJSON FeatureCollection with one LineString Feature per trip that carried the order, in flight order
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} trackFeatureCollection "Tracks"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Failure 404 {object} map[string]string "Order not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/orders/{id}/track [get]
+func (h *TrackHandler) GetOrderTrack(c *gin.Context) {
+	orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || orderID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request", "message": "invalid order id"})
+		return
+	}
+
+	tracks, err := h.uc.OrderTrack(c.Request.Context(), orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	features := make([]trackFeature, len(tracks))
+	for i := range tracks {
+		features[i] = toTrackFeature(tracks[i])
+		features[i].Properties.OrderID = &orderID
+	}
+
+	c.JSON(http.StatusOK, trackFeatureCollection{Type: "FeatureCollection", Features: features})
+}
+
+func toTrackFeature(track model.Track) trackFeature {
+	props := trackProperties{
+		DroneID:    track.DroneID,
+		TripID:     track.TripID,
+		PointCount: len(track.Points),
+		Truncated:  track.Truncated,
+		Timestamps: make([]time.Time, len(track.Points)),
+		AltitudeM:  make([]*float64, len(track.Points)),
+		HeadingDeg: make([]*float64, len(track.Points)),
+		SpeedMPS:   make([]*float64, len(track.Points)),
+		BatteryPct: make([]*float64, len(track.Points)),
+	}
+
+	coords := make([][2]float64, len(track.Points))
+	for i, p := range track.Points {
+		coords[i] = [2]float64{p.Lng, p.Lat}
+		props.Timestamps[i] = p.RecordedAt
+		props.AltitudeM[i] = p.AltitudeM
+		props.HeadingDeg[i] = p.HeadingDeg
+		props.SpeedMPS[i] = p.GroundSpeedMPS
+		props.BatteryPct[i] = p.BatteryPct
+	}
+
+	feature := trackFeature{Type: "Feature", Properties: props}
+	if len(coords) >= 2 {
+		feature.Geometry = &lineStringGeometry{Type: "LineString", Coordinates: coords}
+	}
+	return feature
+}
@@ -0,0 +1,180 @@
+package iface
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	errClientClosed   = errors.New("websocket client closed")
+	errSlowConsumer   = errors.New("websocket client too slow, connection closed")
+	errMessageDropped = errors.New("websocket client too slow, message dropped")
+)
+
+// SlowClientPolicy decides what happens when a client's send queue is full.
+type SlowClientPolicy string
+
+const (
+	// SlowClientClose disconnects the client; drones resume and get the
+	// messages replayed.
+	SlowClientClose SlowClientPolicy = "close"
+	// SlowClientDrop discards the message and keeps the connection.
+	SlowClientDrop SlowClientPolicy = "drop"
+)
+
+func ParseSlowClientPolicy(s string) (SlowClientPolicy, error) {
+	switch p := SlowClientPolicy(s); p {
+	case SlowClientClose, SlowClientDrop:
+		return p, nil
+	}
+	return "", errors.New("slow client policy must be close or drop")
+}
+
+// WSConfig tunes every websocket the server holds open. PongWait is the read
+// deadline, pushed back by every frame the peer sends, so it must be longer
+// than PingInterval.
+type WSConfig struct {
+	PingInterval time.Duration
+	PongWait     time.Duration
+	WriteWait    time.Duration
+	SendBuffer   int
+	SlowClient   SlowClientPolicy
+	ResumeWindow time.Duration
+	ResumeBuffer int
+}
+
+// wsClient owns one connection. Writes go through a buffered queue drained
+// by a single write pump, which also sends the keepalive pings.
+type wsClient struct {
+	conn      *websocket.Conn
+	cfg       WSConfig
+	metrics   *WSMetrics
+	kind      string
+	send      chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newWSClient(conn *websocket.Conn, cfg WSConfig, metrics *WSMetrics, kind string) *wsClient {
+	c := &wsClient{
+		conn:    conn,
+		cfg:     cfg,
+		metrics: metrics,
+		kind:    kind,
+		send:    make(chan []byte, cfg.SendBuffer),
+		done:    make(chan struct{}),
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(cfg.PongWait))
+	conn.SetPongHandler(func(string) error {
+		metrics.pongReceived()
+		return conn.SetReadDeadline(time.Now().Add(cfg.PongWait))
+	})
+
+	metrics.connected(kind)
+	go c.writePump()
+	return c
+}
+
+func (c *wsClient) Send(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.SendRaw(data)
+}
+
+// SendRaw queues data without blocking. A full queue is handled by the slow
+// client policy.
+func (c *wsClient) SendRaw(data []byte) error {
+	select {
+	case <-c.done:
+		return errClientClosed
+	default:
+	}
+
+	select {
+	case c.send <- data:
+		return nil
+	case <-c.done:
+		return errClientClosed
+	default:
+	}
+
+	if c.cfg.SlowClient == SlowClientDrop {
+		c.metrics.messageDropped()
+		return errMessageDropped
+	}
+	c.closeWith(disconnectSlowConsumer)
+	return errSlowConsumer
+}
+
+// ReadMessage reads the next message and pushes the read deadline back. A
+// failed read closes the client, recording why.
+func (c *wsClient) ReadMessage() ([]byte, error) {
+	_, data, err := c.conn.ReadMessage()
+	if err != nil {
+		c.closeWith(readErrorReason(err))
+		return nil, err
+	}
+	_ = c.conn.SetReadDeadline(time.Now().Add(c.cfg.PongWait))
+	return data, nil
+}
+
+func (c *wsClient) Close() {
+	c.closeWith(disconnectServerClosed)
+}
+
+func (c *wsClient) closeWith(reason string) {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		_ = c.conn.Close()
+		c.metrics.disconnected(c.kind, reason)
+	})
+}
+
+func (c *wsClient) writePump() {
+	ticker := time.NewTicker(c.cfg.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case data := <-c.send:
+			if err := c.write(websocket.TextMessage, data); err != nil {
+				c.closeWith(disconnectWriteError)
+				return
+			}
+			c.metrics.messageSent()
+		case <-ticker.C:
+			if err := c.write(websocket.PingMessage, nil); err != nil {
+				c.closeWith(disconnectWriteError)
+				return
+			}
+			c.metrics.pingSent()
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *wsClient) write(messageType int, data []byte) error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.cfg.WriteWait))
+	return c.conn.WriteMessage(messageType, data)
+}
+
+func readErrorReason(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return disconnectReadTimeout
+	case websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived):
+		return disconnectPeerClosed
+	default:
+		return disconnectReadError
+	}
+}
@@ -0,0 +1,254 @@
+package iface
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+// clusterOpTimeout bounds the presence and relay calls made on the send and
+// connect paths.
+const clusterOpTimeout = 2 * time.Second
+
+// MessageBus carries drone payloads between API nodes. The MySQL polling bus
+// needs no extra infrastructure; Redis or NATS can sit behind the same
+// interface.
+type MessageBus interface {
+	Publish(ctx context.Context, msg model.RelayedMessage) error
+	// Subscribe delivers messages addressed to nodeID until ctx is done.
+	Subscribe(ctx context.Context, nodeID string, handle func(model.RelayedMessage)) error
+}
+
+// PresenceStore is the cluster-wide record of which node holds each drone's
+// websocket, and which held it last.
+type PresenceStore interface {
+	Upsert(ctx context.Context, droneID int64, nodeID string) error
+	Release(ctx context.Context, droneID int64, nodeID string) error
+	FindByDrone(ctx context.Context, droneID int64, maxAge time.Duration) (*model.DronePresence, error)
+	FindLast(ctx context.Context, droneID int64, maxAge time.Duration) (*model.DronePresence, error)
+	Touch(ctx context.Context, nodeID string) error
+	DeleteByNode(ctx context.Context, nodeID string) error
+}
+
+// ClusterConfig identifies this node. A presence not refreshed within
+// PresenceTTL belongs to a dead node and is ignored.
+type ClusterConfig struct {
+	NodeID      string
+	PresenceTTL time.Duration
+}
+
+type clusterLink struct {
+	cfg      ClusterConfig
+	presence PresenceStore
+	bus      MessageBus
+}
+
+// JoinCluster makes the registry publish where its drones are connected and
+// relay messages for drones connected to other nodes. It must be called
+// before the server accepts connections; the background work stops with ctx.
+func (r *ConnectionRegistry) JoinCluster(ctx context.Context, cfg ClusterConfig, presence PresenceStore, bus MessageBus) error {
+	if err := presence.DeleteByNode(ctx, cfg.NodeID); err != nil {
+		return err
+	}
+
+	r.cluster = &clusterLink{cfg: cfg, presence: presence, bus: bus}
+
+	go func() {
+		if err := bus.Subscribe(ctx, cfg.NodeID, r.deliverRelayed); err != nil && ctx.Err() == nil {
+			log.Printf("cluster relay for node %s stopped: %v", cfg.NodeID, err)
+		}
+	}()
+	go r.keepPresence(ctx)
+
+	return nil
+}
+
+// NodeID is this node's name in the cluster, empty when running alone.
+func (r *ConnectionRegistry) NodeID() string {
+	if r.cluster == nil {
+		return ""
+	}
+	return r.cluster.cfg.NodeID
+}
+
+func (r *ConnectionRegistry) keepPresence(ctx context.Context) {
+	ticker := time.NewTicker(max(r.cluster.cfg.PresenceTTL/3, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.cluster.presence.Touch(ctx, r.cluster.cfg.NodeID); err != nil {
+				log.Printf("refresh presence for node %s failed: %v", r.cluster.cfg.NodeID, err)
+			}
+		}
+	}
+}
+
+func (r *ConnectionRegistry) announce(droneID int64) {
+	if r.cluster == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), clusterOpTimeout)
+	defer cancel()
+
+	if err := r.cluster.presence.Upsert(ctx, droneID, r.cluster.cfg.NodeID); err != nil {
+		log.Printf("publish presence of drone %d failed: %v", droneID, err)
+	}
+}
+
+func (r *ConnectionRegistry) withdraw(droneID int64) {
+	if r.cluster == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), clusterOpTimeout)
+	defer cancel()
+
+	if err := r.cluster.presence.Release(ctx, droneID, r.cluster.cfg.NodeID); err != nil {
+		log.Printf("withdraw presence of drone %d failed: %v", droneID, err)
+	}
+}
+
+// remoteNode reports the other node holding the drone's websocket, if any.
+func (r *ConnectionRegistry) remoteNode(droneID int64) (string, bool) {
+	if r.cluster == nil {
+		return "", false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), clusterOpTimeout)
+	defer cancel()
+
+	presence, err := r.cluster.presence.FindByDrone(ctx, droneID, r.cluster.cfg.PresenceTTL)
+	if err != nil {
+		log.Printf("locate drone %d failed: %v", droneID, err)
+		return "", false
+	}
+	if presence == nil || presence.NodeID == r.cluster.cfg.NodeID {
+		return "", false
+	}
+	return presence.NodeID, true
+}
+
+// previousNode reports the other live node that held the drone's websocket
+// last, connected or not. It must be asked before the drone is announced
+// here.
+func (r *ConnectionRegistry) previousNode(droneID int64) string {
+	if r.cluster == nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), clusterOpTimeout)
+	defer cancel()
+
+	presence, err := r.cluster.presence.FindLast(ctx, droneID, r.cluster.cfg.PresenceTTL)
+	if err != nil {
+		log.Printf("locate previous node of drone %d failed: %v", droneID, err)
+		return ""
+	}
+	if presence == nil || presence.NodeID == r.cluster.cfg.NodeID {
+		return ""
+	}
+	return presence.NodeID
+}
+
+// requestTakeover asks the node the drone was connected to before to send
+// over whatever it still holds for the drone past lastSeq.
+func (r *ConnectionRegistry) requestTakeover(nodeID string, droneID, lastSeq int64) {
+	data, err := json.Marshal(model.SessionTakeover{NodeID: r.cluster.cfg.NodeID, LastSeq: lastSeq})
+	if err != nil {
+		log.Printf("takeover request for drone %d failed: %v", droneID, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clusterOpTimeout)
+	defer cancel()
+
+	if err := r.cluster.bus.Publish(ctx, model.RelayedMessage{
+		NodeID:  nodeID,
+		DroneID: droneID,
+		Kind:    model.RelayTakeover,
+		Payload: data,
+	}); err != nil {
+		log.Printf("takeover request for drone %d to node %s failed: %v", droneID, nodeID, err)
+	}
+}
+
+// handOver answers a takeover: the drone's session here is given up and its
+// unacked messages are relayed to the node the drone resumed on, which
+// numbers them in its own sequence.
+func (r *ConnectionRegistry) handOver(droneID int64, takeover model.SessionTakeover) {
+	r.mu.RLock()
+	session, ok := r.sessions[droneID]
+	r.mu.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	session.mu.Lock()
+	if session.client != nil {
+		// the drone is on the other node now; this connection is stale
+		session.client.closeWith(disconnectReplaced)
+		session.detach()
+	}
+	if takeover.LastSeq <= session.lastSeq {
+		session.ack(takeover.LastSeq)
+	}
+	payloads := make([][]byte, 0, len(session.pending))
+	for _, msg := range session.pending {
+		payload, err := withoutSeq(msg.data)
+		if err != nil {
+			log.Printf("drone %d message %d not handed over: %v", droneID, msg.seq, err)
+			continue
+		}
+		payloads = append(payloads, payload)
+	}
+	session.pending = nil
+	session.mu.Unlock()
+
+	for _, payload := range payloads {
+		if err := r.relay(takeover.NodeID, droneID, json.RawMessage(payload)); err != nil {
+			log.Printf("drone %d message not handed over to node %s: %v", droneID, takeover.NodeID, err)
+		}
+	}
+	if len(payloads) > 0 {
+		log.Printf("handed %d unacked messages for drone %d over to node %s", len(payloads), droneID, takeover.NodeID)
+	}
+}
+
+// relay hands the payload to the node holding the drone, which numbers and
+// buffers it like any local message.
+func (r *ConnectionRegistry) relay(nodeID string, droneID int64, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clusterOpTimeout)
+	defer cancel()
+
+	return r.cluster.bus.Publish(ctx, model.RelayedMessage{
+		NodeID:  nodeID,
+		DroneID: droneID,
+		Payload: data,
+	})
+}
+
+func (r *ConnectionRegistry) deliverRelayed(msg model.RelayedMessage) {
+	if msg.Kind == model.RelayTakeover {
+		var takeover model.SessionTakeover
+		if err := json.Unmarshal(msg.Payload, &takeover); err != nil {
+			log.Printf("invalid takeover request for drone %d: %v", msg.DroneID, err)
+			return
+		}
+		r.handOver(msg.DroneID, takeover)
+		return
+	}
+
+	if err := r.sendLocal(msg.DroneID, json.RawMessage(msg.Payload)); err != nil {
+		log.Printf("relayed message for drone %d not delivered: %v", msg.DroneID, err)
+	}
+}
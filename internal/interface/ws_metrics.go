@@ -0,0 +1,119 @@
+package iface
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	wsKindDrone = "drone"
+	wsKindAdmin = "admin"
+)
+
+// Reasons a websocket was closed, as counted in the metrics.
+const (
+	disconnectPeerClosed   = "peer_closed"
+	disconnectReadTimeout  = "read_timeout"
+	disconnectReadError    = "read_error"
+	disconnectWriteError   = "write_error"
+	disconnectSlowConsumer = "slow_consumer"
+	disconnectReplaced     = "replaced"
+	disconnectServerClosed = "server_closed"
+)
+
+var disconnectReasons = []string{
+	disconnectPeerClosed,
+	disconnectReadTimeout,
+	disconnectReadError,
+	disconnectWriteError,
+	disconnectSlowConsumer,
+	disconnectReplaced,
+	disconnectServerClosed,
+}
+
+// WSMetrics counts connection health across drone and admin websockets
+// since the server started.
+type WSMetrics struct {
+	active          map[string]*atomic.Int64
+	opened          atomic.Int64
+	disconnects     map[string]*atomic.Int64
+	messagesSent    atomic.Int64
+	messagesDropped atomic.Int64
+	pingsSent       atomic.Int64
+	pongsReceived   atomic.Int64
+}
+
+type wsMetricsResponse struct {
+	ActiveConnections map[string]int64 `json:"active_connections"`
+	Opened            int64            `json:"connections_opened"`
+	Disconnects       map[string]int64 `json:"disconnects"`
+	MessagesSent      int64            `json:"messages_sent"`
+	MessagesDropped   int64            `json:"messages_dropped"`
+	PingsSent         int64            `json:"pings_sent"`
+	PongsReceived     int64            `json:"pongs_received"`
+}
+
+func NewWSMetrics() *WSMetrics {
+	m := &WSMetrics{
+		active: map[string]*atomic.Int64{
+			wsKindDrone: {},
+			wsKindAdmin: {},
+		},
+		disconnects: make(map[string]*atomic.Int64, len(disconnectReasons)),
+	}
+	for _, reason := range disconnectReasons {
+		m.disconnects[reason] = &atomic.Int64{}
+	}
+	return m
+}
+
+// GetMetrics godoc
+// @Summary Websocket connection health (admin)
+// @Description Live connection counts per kind (drone, admin) and counters since start: connections opened, disconnects by reason
+// @Description (peer_closed, read_timeout, read_error, write_error, slow_consumer, replaced, server_closed), messages sent and dropped, pings and pongs.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} wsMetricsResponse "Metrics"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden - Admin only"
+// @Router /admin/ws/metrics [get]
+func (m *WSMetrics) GetMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, m.snapshot())
+}
+
+func (m *WSMetrics) snapshot() wsMetricsResponse {
+	resp := wsMetricsResponse{
+		ActiveConnections: make(map[string]int64, len(m.active)),
+		Opened:            m.opened.Load(),
+		Disconnects:       make(map[string]int64, len(m.disconnects)),
+		MessagesSent:      m.messagesSent.Load(),
+		MessagesDropped:   m.messagesDropped.Load(),
+		PingsSent:         m.pingsSent.Load(),
+		PongsReceived:     m.pongsReceived.Load(),
+	}
+	for kind, n := range m.active {
+		resp.ActiveConnections[kind] = n.Load()
+	}
+	for reason, n := range m.disconnects {
+		resp.Disconnects[reason] = n.Load()
+	}
+	return resp
+}
+
+func (m *WSMetrics) connected(kind string) {
+	m.opened.Add(1)
+	m.active[kind].Add(1)
+}
+
+func (m *WSMetrics) disconnected(kind, reason string) {
+	m.active[kind].Add(-1)
+	m.disconnects[reason].Add(1)
+}
+
+func (m *WSMetrics) messageSent()    { m.messagesSent.Add(1) }
+func (m *WSMetrics) messageDropped() { m.messagesDropped.Add(1) }
+func (m *WSMetrics) pingSent()       { m.pingsSent.Add(1) }
+func (m *WSMetrics) pongReceived()   { m.pongsReceived.Add(1) }
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	maxAddressLabelLength = 64
+	maxAddressNoteLength  = 500
+)
+
+// Address is an enduser's saved location; orders copy its coordinates and
+// notes at creation time, so later edits never move an in-flight delivery.
+type Address struct {
+	ID                 int64
+	UserID             int64
+	Label              string
+	Lat                float64
+	Lng                float64
+	DeliveryNotes      *string
+	AccessInstructions *string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+type CreateAddressRequest struct {
+	UserID             int64
+	Label              string
+	Lat                float64
+	Lng                float64
+	DeliveryNotes      *string
+	AccessInstructions *string
+}
+
+type UpdateAddressRequest struct {
+	Label              *string
+	Lat                *float64
+	Lng                *float64
+	DeliveryNotes      *string
+	AccessInstructions *string
+}
+
+func NewAddress(req CreateAddressRequest) (*Address, error) {
+	address := &Address{UserID: req.UserID}
+	err := address.Update(UpdateAddressRequest{
+		Label:              &req.Label,
+		Lat:                &req.Lat,
+		Lng:                &req.Lng,
+		DeliveryNotes:      req.DeliveryNotes,
+		AccessInstructions: req.AccessInstructions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+func (a *Address) BelongsTo(userID int64) error {
+	if a.UserID != userID {
+		return ErrAddressNotOwned()
+	}
+	return nil
+}
+
+// Update applies the provided fields; an empty notes string clears the note.
+func (a *Address) Update(req UpdateAddressRequest) error {
+	if req.Label == nil && req.Lat == nil && req.Lng == nil &&
+		req.DeliveryNotes == nil && req.AccessInstructions == nil {
+		return ErrInvalidAddress("no fields to update")
+	}
+
+	if req.Label != nil {
+		label := strings.TrimSpace(*req.Label)
+		if label == "" || len(label) > maxAddressLabelLength {
+			return ErrInvalidAddress("label must be 1-64 characters")
+		}
+		a.Label = label
+	}
+
+	if req.Lat != nil || req.Lng != nil {
+		lat, lng := a.Lat, a.Lng
+		if req.Lat != nil {
+			lat = *req.Lat
+		}
+		if req.Lng != nil {
+			lng = *req.Lng
+		}
+		if lat < -90 || lat > 90 {
+			return ErrInvalidLatitude(lat)
+		}
+		if lng < -180 || lng > 180 {
+			return ErrInvalidLongitude(lng)
+		}
+		a.Lat, a.Lng = lat, lng
+	}
+
+	if req.DeliveryNotes != nil {
+		notes, err := normalizeAddressNote(*req.DeliveryNotes, "delivery_notes")
+		if err != nil {
+			return err
+		}
+		a.DeliveryNotes = notes
+	}
+
+	if req.AccessInstructions != nil {
+		instructions, err := normalizeAddressNote(*req.AccessInstructions, "access_instructions")
+		if err != nil {
+			return err
+		}
+		a.AccessInstructions = instructions
+	}
+
+	return nil
+}
+
+func normalizeAddressNote(value, field string) (*string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if len(value) > maxAddressNoteLength {
+		return nil, ErrInvalidAddress(field + " must be at most 500 characters")
+	}
+	return &value, nil
+}
@@ -5,6 +5,7 @@ type AssignmentDescription string
 const (
 	AssignmentNewOrder AssignmentDescription = "new_order"
 	AssignmentHandoff  AssignmentDescription = "handoff"
+	AssignmentReturn   AssignmentDescription = "return_handoff"
 )
 
 type AssignmentNotice struct {
@@ -14,17 +15,38 @@ type AssignmentNotice struct {
 	PickupLng   float64
 	DropoffLat  float64
 	DropoffLng  float64
+	ReturnLat   *float64
+	ReturnLng   *float64
+	HandoffLat  *float64
+	HandoffLng  *float64
+	TripID      *int64
 	EnduserID   int64
 	OrderStatus OrderStatus
 	Description AssignmentDescription
+	Waypoints   []GeoPoint
 }
 
-func NewAssignmentNotice(order Order, drone Drone) AssignmentNotice {
+// NewAssignmentNotice includes the flight path from the drone's position via
+// the pickup point to the destination, planned around the airspace.
+func NewAssignmentNotice(order Order, drone Drone, airspace Airspace) AssignmentNotice {
 	description := AssignmentNewOrder
+	var handoffLat, handoffLng *float64
 	if order.Status == OrderHandoffPending {
 		description = AssignmentHandoff
+		if order.IsReturn() {
+			description = AssignmentReturn
+		}
+		handoffLat, handoffLng = order.HandoffLat, order.HandoffLng
 	}
 
+	pickupLat, pickupLng := order.PickupPoint()
+	destLat, destLng := order.DestinationPoint()
+	path := airspace.PlanPath(
+		GeoPoint{Lat: drone.Lat, Lng: drone.Lng},
+		GeoPoint{Lat: pickupLat, Lng: pickupLng},
+		GeoPoint{Lat: destLat, Lng: destLng},
+	)
+
 	return AssignmentNotice{
 		OrderID:     order.ID,
 		DroneID:     drone.ID,
@@ -32,8 +54,71 @@ func NewAssignmentNotice(order Order, drone Drone) AssignmentNotice {
 		PickupLng:   order.PickupLng,
 		DropoffLat:  order.DropoffLat,
 		DropoffLng:  order.DropoffLng,
+		ReturnLat:   order.ReturnLat,
+		ReturnLng:   order.ReturnLng,
+		HandoffLat:  handoffLat,
+		HandoffLng:  handoffLng,
+		TripID:      drone.CurrentTripID,
 		EnduserID:   order.EnduserID,
 		OrderStatus: order.Status,
 		Description: description,
+		Waypoints:   path.Waypoints,
+	}
+}
+
+// OrderUpdateEvent names a change to an order that its drone has to react to.
+type OrderUpdateEvent string
+
+const (
+	OrderUpdateCanceled        OrderUpdateEvent = "order_canceled"
+	OrderUpdateRouteUpdated    OrderUpdateEvent = "route_updated"
+	OrderUpdateHandoffRequired OrderUpdateEvent = "handoff_required"
+)
+
+// OrderUpdateNotice tells a drone that an order it was offered or is flying
+// changed underneath it. HandoffLat/HandoffLng are where a parcel already on
+// board has to be left; Waypoints is the re-planned path of a moved route.
+type OrderUpdateNotice struct {
+	Event       OrderUpdateEvent
+	OrderID     int64
+	DroneID     int64
+	OrderStatus OrderStatus
+	PickupLat   float64
+	PickupLng   float64
+	DropoffLat  float64
+	DropoffLng  float64
+	HandoffLat  *float64
+	HandoffLng  *float64
+	Waypoints   []GeoPoint
+}
+
+func NewOrderUpdateNotice(event OrderUpdateEvent, order Order, droneID int64) OrderUpdateNotice {
+	return OrderUpdateNotice{
+		Event:       event,
+		OrderID:     order.ID,
+		DroneID:     droneID,
+		OrderStatus: order.Status,
+		PickupLat:   order.PickupLat,
+		PickupLng:   order.PickupLng,
+		DropoffLat:  order.DropoffLat,
+		DropoffLng:  order.DropoffLng,
+		HandoffLat:  order.HandoffLat,
+		HandoffLng:  order.HandoffLng,
 	}
 }
+
+// NewRouteUpdateNotice re-plans the flight path from the drone's position via
+// the moved pickup to the moved destination.
+func NewRouteUpdateNotice(order Order, drone Drone, airspace Airspace) OrderUpdateNotice {
+	notice := NewOrderUpdateNotice(OrderUpdateRouteUpdated, order, drone.ID)
+
+	pickupLat, pickupLng := order.PickupPoint()
+	destLat, destLng := order.DestinationPoint()
+	path := airspace.PlanPath(
+		GeoPoint{Lat: drone.Lat, Lng: drone.Lng},
+		GeoPoint{Lat: pickupLat, Lng: pickupLng},
+		GeoPoint{Lat: destLat, Lng: destLng},
+	)
+	notice.Waypoints = path.Waypoints
+	return notice
+}
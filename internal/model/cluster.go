@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// DronePresence records which API node holds a drone's websocket, or held
+// it last (ReleasedAt) while that node may still keep its resume buffer.
+type DronePresence struct {
+	DroneID     int64
+	NodeID      string
+	ConnectedAt time.Time
+	SeenAt      time.Time
+	ReleasedAt  *time.Time
+}
+
+type RelayKind string
+
+const (
+	// RelayMessage payloads are pushed to the drone.
+	RelayMessage RelayKind = "message"
+	// RelayTakeover asks the node that held the drone before to hand its
+	// unacked messages to the node the drone resumed on.
+	RelayTakeover RelayKind = "takeover"
+)
+
+// RelayedMessage is a websocket payload handed from the node that produced
+// it to the node holding the drone's connection.
+type RelayedMessage struct {
+	ID        int64
+	NodeID    string
+	DroneID   int64
+	Kind      RelayKind
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+// SessionTakeover is the payload of a RelayTakeover message: LastSeq is the
+// highest seq, in the old node's numbering, the drone has received.
+type SessionTakeover struct {
+	NodeID  string `json:"node_id"`
+	LastSeq int64  `json:"last_seq"`
+}
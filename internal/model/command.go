@@ -0,0 +1,205 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+const maxCommandNoteLength = 255
+
+type CommandType string
+
+const (
+	CommandReturnToHome     CommandType = "return_to_home"
+	CommandHoldPosition     CommandType = "hold_position"
+	CommandLandNow          CommandType = "land_now"
+	CommandDivert           CommandType = "divert"
+	CommandCancelAssignment CommandType = "cancel_assignment"
+	// CommandReposition sends an idle drone to wait for orders elsewhere
+	CommandReposition CommandType = "reposition"
+)
+
+func IsValidCommandType(t CommandType) bool {
+	switch t {
+	case CommandReturnToHome, CommandHoldPosition, CommandLandNow, CommandDivert, CommandCancelAssignment, CommandReposition:
+		return true
+	}
+	return false
+}
+
+// CommandStatus tracks a command from creation to the drone's final report:
+// pending -> sent | undelivered, sent -> acknowledged | rejected,
+// acknowledged -> completed | failed. A pending command may be acknowledged
+// directly when the drone answers before the send has been recorded, and an
+// undelivered one when the drone reconnects in time to have it replayed.
+type CommandStatus string
+
+const (
+	CommandPending      CommandStatus = "pending"
+	CommandSent         CommandStatus = "sent"
+	CommandUndelivered  CommandStatus = "undelivered"
+	CommandAcknowledged CommandStatus = "acknowledged"
+	CommandRejected     CommandStatus = "rejected"
+	CommandCompleted    CommandStatus = "completed"
+	CommandFailed       CommandStatus = "failed"
+)
+
+var allowedCommandTransitions = map[CommandStatus][]CommandStatus{
+	CommandPending:      {CommandSent, CommandUndelivered, CommandAcknowledged, CommandRejected},
+	CommandSent:         {CommandAcknowledged, CommandRejected},
+	CommandUndelivered:  {CommandAcknowledged, CommandRejected},
+	CommandAcknowledged: {CommandCompleted, CommandFailed},
+}
+
+// DroneCommand is an instruction pushed to a drone over its websocket. Lat
+// and Lng are the target of a divert or reposition or the depot of a
+// return_to_home; OrderID names the assignment a cancel refers to.
+type DroneCommand struct {
+	ID          int64
+	DroneID     int64
+	Type        CommandType
+	OrderID     *int64
+	Lat         *float64
+	Lng         *float64
+	IssuedBy    *int64
+	Status      CommandStatus
+	Note        *string
+	CreatedAt   time.Time
+	SentAt      *time.Time
+	AckedAt     *time.Time
+	CompletedAt *time.Time
+	UpdatedAt   time.Time
+}
+
+type CreateDroneCommandRequest struct {
+	DroneID  int64
+	Type     CommandType
+	OrderID  *int64
+	Lat      *float64
+	Lng      *float64
+	IssuedBy *int64
+}
+
+func NewDroneCommand(req CreateDroneCommandRequest, now time.Time) (*DroneCommand, error) {
+	if !IsValidCommandType(req.Type) {
+		return nil, ErrInvalidDroneCommand("type must be one of return_to_home, hold_position, land_now, divert, cancel_assignment, reposition")
+	}
+
+	cmd := &DroneCommand{
+		DroneID:   req.DroneID,
+		Type:      req.Type,
+		OrderID:   req.OrderID,
+		IssuedBy:  req.IssuedBy,
+		Status:    CommandPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	switch req.Type {
+	case CommandDivert, CommandReposition:
+		if req.Lat == nil || req.Lng == nil {
+			return nil, ErrInvalidDroneCommand(string(req.Type) + " requires lat and lng")
+		}
+		if err := cmd.setTarget(req.Lat, req.Lng); err != nil {
+			return nil, err
+		}
+	case CommandReturnToHome:
+		// without a target the drone flies to its own idea of home
+		if (req.Lat == nil) != (req.Lng == nil) {
+			return nil, ErrInvalidDroneCommand("return_to_home takes both lat and lng or neither")
+		}
+		if req.Lat != nil {
+			if err := cmd.setTarget(req.Lat, req.Lng); err != nil {
+				return nil, err
+			}
+		}
+	case CommandCancelAssignment:
+		if req.OrderID == nil {
+			return nil, ErrInvalidDroneCommand("cancel_assignment requires order_id")
+		}
+	default:
+		if req.Lat != nil || req.Lng != nil {
+			return nil, ErrInvalidDroneCommand("lat and lng only apply to divert, reposition and return_to_home")
+		}
+	}
+
+	return cmd, nil
+}
+
+func (c *DroneCommand) setTarget(lat, lng *float64) error {
+	if *lat < -90 || *lat > 90 {
+		return ErrInvalidLatitude(*lat)
+	}
+	if *lng < -180 || *lng > 180 {
+		return ErrInvalidLongitude(*lng)
+	}
+	c.Lat, c.Lng = lat, lng
+	return nil
+}
+
+// MarkSent records delivery to the websocket. If the drone's ack already
+// moved the command on, only the send time is filled in.
+func (c *DroneCommand) MarkSent(now time.Time) error {
+	if c.Status == CommandPending {
+		if err := c.transition(CommandSent, now); err != nil {
+			return err
+		}
+	}
+	if c.SentAt == nil {
+		c.SentAt = &now
+	}
+	return nil
+}
+
+func (c *DroneCommand) MarkUndelivered(now time.Time) error {
+	return c.transition(CommandUndelivered, now)
+}
+
+// Acknowledge records whether the drone accepted the command.
+func (c *DroneCommand) Acknowledge(accepted bool, note string, now time.Time) error {
+	next := CommandAcknowledged
+	if !accepted {
+		next = CommandRejected
+	}
+	if err := c.transition(next, now); err != nil {
+		return err
+	}
+	c.AckedAt = &now
+	return c.setNote(note)
+}
+
+// Complete records the drone's final report on an accepted command.
+func (c *DroneCommand) Complete(succeeded bool, note string, now time.Time) error {
+	next := CommandCompleted
+	if !succeeded {
+		next = CommandFailed
+	}
+	if err := c.transition(next, now); err != nil {
+		return err
+	}
+	c.CompletedAt = &now
+	return c.setNote(note)
+}
+
+func (c *DroneCommand) transition(next CommandStatus, now time.Time) error {
+	for _, allowed := range allowedCommandTransitions[c.Status] {
+		if allowed == next {
+			c.Status = next
+			c.UpdatedAt = now
+			return nil
+		}
+	}
+	return ErrCommandTransitionNotAllowed(string(c.Status), string(next))
+}
+
+func (c *DroneCommand) setNote(note string) error {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil
+	}
+	if len(note) > maxCommandNoteLength {
+		return ErrInvalidDroneCommand("note must be at most 255 characters")
+	}
+	c.Note = &note
+	return nil
+}
@@ -0,0 +1,195 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const deliveryPINDigits = 6
+
+type DeliveryProofMethod string
+
+const (
+	DeliveryProofPIN      DeliveryProofMethod = "pin"
+	DeliveryProofEvidence DeliveryProofMethod = "evidence"
+)
+
+type DeliveryPolicy struct {
+	RadiusMeters   float64
+	EvidenceKey    []byte
+	EvidenceMaxAge time.Duration
+	MaxPINAttempts int
+}
+
+type DeliveryEvidence struct {
+	PhotoHash  string
+	Lat        float64
+	Lng        float64
+	CapturedAt time.Time
+	Signature  string
+}
+
+type DeliveryProof struct {
+	PIN      *string
+	Evidence *DeliveryEvidence
+}
+
+// DeliveryProofRecord is the audit trail persisted alongside a delivered order.
+type DeliveryProofRecord struct {
+	OrderID        int64
+	DroneID        int64
+	Method         DeliveryProofMethod
+	Evidence       *DeliveryEvidence
+	DroneLat       float64
+	DroneLng       float64
+	DistanceMeters float64
+	CreatedAt      time.Time
+}
+
+func (p DeliveryProof) Method() DeliveryProofMethod {
+	if p.Evidence != nil {
+		return DeliveryProofEvidence
+	}
+	return DeliveryProofPIN
+}
+
+func GenerateDeliveryPIN() (string, error) {
+	upper := big.NewInt(1)
+	for i := 0; i < deliveryPINDigits; i++ {
+		upper.Mul(upper, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, upper)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", deliveryPINDigits, n.Int64()), nil
+}
+
+/*
+EvidencePayload: canonical string signed by drone firmware with HMAC-SHA256,
+binding the evidence to a single order so it cannot be replayed elsewhere
+*/
+func EvidencePayload(orderID int64, e DeliveryEvidence) string {
+	return strings.Join([]string{
+		strconv.FormatInt(orderID, 10),
+		e.PhotoHash,
+		strconv.FormatFloat(e.Lat, 'f', 6, 64),
+		strconv.FormatFloat(e.Lng, 'f', 6, 64),
+		e.CapturedAt.UTC().Format(time.RFC3339),
+	}, "|")
+}
+
+// DroneEvidenceKey derives the key a single drone signs evidence with, so a
+// leaked drone key cannot forge evidence for the rest of the fleet.
+func DroneEvidenceKey(secret []byte, droneID int64) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(droneID, 10)))
+	return mac.Sum(nil)
+}
+
+func SignEvidence(key []byte, orderID int64, e DeliveryEvidence) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(EvidencePayload(orderID, e)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (o *Order) DeliverWithProof(proof DeliveryProof, drone *Drone, policy DeliveryPolicy, now time.Time) (*DeliveryProofRecord, error) {
+	if proof.Evidence == nil && proof.PIN != nil && o.PINLocked(policy) {
+		return nil, ErrDeliveryPINLocked(o.PINAttempts)
+	}
+	if !o.IsStatusTransitionAllowed(OrderDelivered) {
+		return nil, ErrOrderTransitionNotAllowed(string(o.Status), string(OrderDelivered))
+	}
+
+	if err := o.verifyDeliveryProof(proof, drone.ID, policy, now); err != nil {
+		return nil, err
+	}
+
+	distance, err := drone.DistanceFromMeters(o.DropoffLat, o.DropoffLng)
+	if err != nil {
+		return nil, err
+	}
+	if distance > policy.RadiusMeters {
+		return nil, ErrDroneNotAtDropoff(distance, policy.RadiusMeters)
+	}
+
+	if err := o.Deliver(); err != nil {
+		return nil, err
+	}
+
+	return &DeliveryProofRecord{
+		OrderID:        o.ID,
+		DroneID:        drone.ID,
+		Method:         proof.Method(),
+		Evidence:       proof.Evidence,
+		DroneLat:       drone.Lat,
+		DroneLng:       drone.Lng,
+		DistanceMeters: distance,
+		CreatedAt:      now,
+	}, nil
+}
+
+func (o *Order) verifyDeliveryProof(proof DeliveryProof, droneID int64, policy DeliveryPolicy, now time.Time) error {
+	switch {
+	case proof.Evidence != nil:
+		return o.verifyEvidence(*proof.Evidence, droneID, policy, now)
+	case proof.PIN != nil:
+		if o.DeliveryPIN == nil || subtle.ConstantTimeCompare([]byte(*o.DeliveryPIN), []byte(*proof.PIN)) != 1 {
+			o.PINAttempts++
+			if o.PINLocked(policy) {
+				return ErrDeliveryPINLocked(o.PINAttempts)
+			}
+			return ErrInvalidDeliveryPIN()
+		}
+		return nil
+	default:
+		return ErrDeliveryProofRequired()
+	}
+}
+
+// PINLocked reports whether the order has used up its wrong PINs and no
+// longer accepts PIN proof.
+func (o *Order) PINLocked(policy DeliveryPolicy) bool {
+	return policy.MaxPINAttempts > 0 && o.PINAttempts >= policy.MaxPINAttempts
+}
+
+func (o *Order) verifyEvidence(e DeliveryEvidence, droneID int64, policy DeliveryPolicy, now time.Time) error {
+	if len(policy.EvidenceKey) == 0 {
+		return ErrInvalidDeliveryEvidence("evidence signatures are not enabled")
+	}
+	if e.PhotoHash == "" || e.Signature == "" {
+		return ErrInvalidDeliveryEvidence("photo_hash and signature are required")
+	}
+	if e.CapturedAt.IsZero() || e.CapturedAt.After(now.Add(time.Minute)) {
+		return ErrInvalidDeliveryEvidence("captured_at is not a valid timestamp")
+	}
+	if policy.EvidenceMaxAge > 0 && now.Sub(e.CapturedAt) > policy.EvidenceMaxAge {
+		return ErrInvalidDeliveryEvidence("evidence is too old")
+	}
+
+	expected := SignEvidence(DroneEvidenceKey(policy.EvidenceKey, droneID), o.ID, e)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(e.Signature))) {
+		return ErrInvalidDeliveryEvidence("signature does not match evidence")
+	}
+
+	distance := haversineDistance(e.Lat, e.Lng, o.DropoffLat, o.DropoffLng) * metersPerKilometer
+	if distance > policy.RadiusMeters {
+		return ErrInvalidDeliveryEvidence("evidence location is outside the dropoff radius")
+	}
+	return nil
+}
+
+func (d *Drone) DistanceFromMeters(lat, lng float64) (float64, error) {
+	if d.LastHeartbeat == nil {
+		return 0, ErrDroneLocationUnknown()
+	}
+	return haversineDistance(d.Lat, d.Lng, lat, lng) * metersPerKilometer, nil
+}
@@ -0,0 +1,165 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	maxDepotNameLength = 100
+	MaxDepotCapacity   = 100
+)
+
+// Depot is a base with charging pads that drones return to between trips.
+// Occupied counts the drones holding a pad: parked, charging, or on their
+// way to do either; Charging is the part of them recharging.
+type Depot struct {
+	ID        int64
+	Name      string
+	Lat       float64
+	Lng       float64
+	Capacity  int
+	Active    bool
+	Occupied  int
+	Charging  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type UpdateDepotRequest struct {
+	Name     *string
+	Lat      *float64
+	Lng      *float64
+	Capacity *int
+	Active   *bool
+}
+
+func NewDepot(name string, lat, lng float64, capacity int) (*Depot, error) {
+	depot := &Depot{Active: true}
+	if err := depot.Update(UpdateDepotRequest{Name: &name, Lat: &lat, Lng: &lng, Capacity: &capacity}); err != nil {
+		return nil, err
+	}
+	return depot, nil
+}
+
+// Update changes the depot. Lowering the capacity below the current
+// occupancy sends nobody away; it only stops new arrivals.
+func (d *Depot) Update(req UpdateDepotRequest) error {
+	if req.Name == nil && req.Lat == nil && req.Lng == nil && req.Capacity == nil && req.Active == nil {
+		return ErrInvalidDepot("no fields to update")
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || len(name) > maxDepotNameLength {
+			return ErrInvalidDepot("name must be 1-100 characters")
+		}
+		d.Name = name
+	}
+
+	if req.Lat != nil || req.Lng != nil {
+		if req.Lat == nil || req.Lng == nil {
+			return ErrInvalidDepot("lat and lng must be given together")
+		}
+		lat, lng, err := validateRouteCoordinates(req.Lat, req.Lng)
+		if err != nil {
+			return err
+		}
+		d.Lat, d.Lng = lat, lng
+	}
+
+	if req.Capacity != nil {
+		if *req.Capacity < 1 || *req.Capacity > MaxDepotCapacity {
+			return ErrInvalidDepot("capacity must be between 1 and 100 pads")
+		}
+		d.Capacity = *req.Capacity
+	}
+
+	if req.Active != nil {
+		d.Active = *req.Active
+	}
+
+	return nil
+}
+
+// FreePads is how many more drones the depot can take.
+func (d *Depot) FreePads() int {
+	return max(d.Capacity-d.Occupied, 0)
+}
+
+// BaseReason says why a drone heads back to a depot.
+type BaseReason string
+
+const (
+	BaseToCharge BaseReason = "charge"
+	BaseToPark   BaseReason = "park"
+)
+
+// BasePolicy decides when an idle drone goes back to a depot: to recharge
+// once its battery drops below ChargeBelowPct, and, with ParkWhenIdle, to
+// park when it finishes a trip and no order is waiting for a drone. A
+// charging drone is back in service at ChargedPct.
+type BasePolicy struct {
+	ChargeBelowPct float64
+	ChargedPct     float64
+	ParkWhenIdle   bool
+}
+
+// NeedsCharge: an idle drone whose battery has dropped below the threshold.
+// Drones that do not report their battery are never sent to charge.
+func (p BasePolicy) NeedsCharge(d Drone) bool {
+	return d.Status == DroneIdle && d.Readings.BatteryPct != nil && *d.Readings.BatteryPct < p.ChargeBelowPct
+}
+
+// Charged: a charging drone whose battery is back at the charged level.
+func (p BasePolicy) Charged(d Drone) bool {
+	return d.Status == DroneCharging && d.Readings.BatteryPct != nil && *d.Readings.BatteryPct >= p.ChargedPct
+}
+
+// Reason is why the drone should head back to a depot now, if at all;
+// demand says whether orders are waiting for a drone. A drone already
+// holding a pad is not sent to park again.
+func (p BasePolicy) Reason(d Drone, demand bool) (BaseReason, bool) {
+	if p.NeedsCharge(d) {
+		return BaseToCharge, true
+	}
+	if p.ParkWhenIdle && !demand && d.Status == DroneIdle && d.DepotID == nil {
+		return BaseToPark, true
+	}
+	return "", false
+}
+
+/*
+ChooseDepot: the depot whose pad the drone already holds, else its home
+depot when a pad is free there, else the nearest active depot with a free
+pad. Nil when every pad is taken.
+*/
+func ChooseDepot(d Drone, depots []Depot) *Depot {
+	for _, preferred := range []*int64{d.DepotID, d.HomeDepotID} {
+		if preferred == nil {
+			continue
+		}
+		for i := range depots {
+			depot := &depots[i]
+			if depot.ID != *preferred || !depot.Active {
+				continue
+			}
+			if preferred == d.DepotID || depot.FreePads() > 0 {
+				return depot
+			}
+		}
+	}
+
+	var nearest *Depot
+	nearestKm := 0.0
+	for i := range depots {
+		depot := &depots[i]
+		if !depot.Active || depot.FreePads() == 0 {
+			continue
+		}
+		if km := haversineDistance(d.Lat, d.Lng, depot.Lat, depot.Lng); nearest == nil || km < nearestKm {
+			nearest, nearestKm = depot, km
+		}
+	}
+	return nearest
+}
@@ -0,0 +1,217 @@
+package model
+
+import (
+	"errors"
+	"math"
+)
+
+// DispatchCost is how far a drone is from an order's pickup when pairing
+// them: straight-line, or along the path planned around no-fly zones.
+type DispatchCost string
+
+const (
+	DispatchCostHaversine DispatchCost = "haversine"
+	DispatchCostRoute     DispatchCost = "route"
+)
+
+func ParseDispatchCost(s string) (DispatchCost, error) {
+	switch c := DispatchCost(s); c {
+	case DispatchCostHaversine, DispatchCostRoute:
+		return c, nil
+	}
+	return "", errors.New("dispatch cost must be haversine or route")
+}
+
+// Km is the distance in kilometers the drone flies to reach the order's
+// pickup (the handoff point for handoffs).
+func (c DispatchCost) Km(order Order, drone Drone, airspace Airspace) float64 {
+	lat, lng := order.PickupPoint()
+	if c == DispatchCostRoute {
+		return airspace.DistanceKm(drone.Lat, drone.Lng, lat, lng)
+	}
+	return haversineDistance(drone.Lat, drone.Lng, lat, lng)
+}
+
+// DispatchMode is how waiting orders are paired with drones: greedy offers
+// each order to the nearest drone as it arrives, batch matches them all at
+// once by minimum total cost.
+type DispatchMode string
+
+const (
+	DispatchGreedy DispatchMode = "greedy"
+	DispatchBatch  DispatchMode = "batch"
+)
+
+func ParseDispatchMode(s string) (DispatchMode, error) {
+	switch m := DispatchMode(s); m {
+	case DispatchGreedy, DispatchBatch:
+		return m, nil
+	}
+	return "", errors.New("dispatch strategy must be greedy or batch")
+}
+
+// Match pairs orders with drones the way the mode would.
+func (m DispatchMode) Match(orders []Order, drones []Drone, costKm func(Order, Drone) float64) []DispatchPair {
+	if m == DispatchBatch {
+		return MatchOptimal(orders, drones, costKm)
+	}
+	return MatchGreedy(orders, drones, costKm)
+}
+
+// DispatchPair offers Order to Drone; CostKm is the flight to the pickup.
+type DispatchPair struct {
+	Order  Order
+	Drone  Drone
+	CostKm float64
+}
+
+// dispatchSlot is one order a drone can still take; drones with spare
+// capacity for several orders get several slots.
+type dispatchSlot struct {
+	drone int
+}
+
+func dispatchSlots(drones []Drone, maxPerDrone int) []dispatchSlot {
+	var slots []dispatchSlot
+	for i := range drones {
+		for range min(drones[i].SpareCapacity(), maxPerDrone) {
+			slots = append(slots, dispatchSlot{drone: i})
+		}
+	}
+	return slots
+}
+
+/*
+MatchGreedy: takes the orders in the given (arrival) order and pairs each
+with the cheapest drone that still has room, like offering every order on
+its own as it comes in. Orders left over when the drones run out are not
+paired.
+*/
+func MatchGreedy(orders []Order, drones []Drone, costKm func(Order, Drone) float64) []DispatchPair {
+	slots := dispatchSlots(drones, len(orders))
+	taken := make([]bool, len(slots))
+
+	var pairs []DispatchPair
+	for _, order := range orders {
+		best, bestCost := -1, math.Inf(1)
+		for s, slot := range slots {
+			if taken[s] {
+				continue
+			}
+			if c := costKm(order, drones[slot.drone]); c < bestCost {
+				best, bestCost = s, c
+			}
+		}
+		if best < 0 {
+			break
+		}
+		taken[best] = true
+		pairs = append(pairs, DispatchPair{Order: order, Drone: drones[slots[best].drone], CostKm: bestCost})
+	}
+	return pairs
+}
+
+/*
+MatchOptimal: minimum-cost bipartite matching between orders and drone
+slots (Hungarian algorithm, O(n²·m) for n orders ≤ m slots), minimizing the
+total distance flown to the pickups. When orders outnumber slots only the
+first ones, oldest when callers pass them in arrival order, are matched so
+that far-away orders are not starved by cheaper newcomers.
+*/
+func MatchOptimal(orders []Order, drones []Drone, costKm func(Order, Drone) float64) []DispatchPair {
+	slots := dispatchSlots(drones, len(orders))
+	if len(orders) > len(slots) {
+		orders = orders[:len(slots)]
+	}
+	if len(orders) == 0 {
+		return nil
+	}
+
+	cost := make([][]float64, len(orders))
+	for o, order := range orders {
+		cost[o] = make([]float64, len(slots))
+		for s, slot := range slots {
+			cost[o][s] = costKm(order, drones[slot.drone])
+		}
+	}
+
+	pairs := make([]DispatchPair, len(orders))
+	for o, s := range minCostAssignment(cost) {
+		pairs[o] = DispatchPair{Order: orders[o], Drone: drones[slots[s].drone], CostKm: cost[o][s]}
+	}
+	return pairs
+}
+
+// TotalCostKm sums the distance flown to the pickups.
+func TotalCostKm(pairs []DispatchPair) float64 {
+	total := 0.0
+	for _, p := range pairs {
+		total += p.CostKm
+	}
+	return total
+}
+
+// minCostAssignment solves the rectangular assignment problem for
+// len(cost) rows ≤ len(cost[0]) columns with the potentials form of the
+// Hungarian algorithm, returning the column given to each row.
+func minCostAssignment(cost [][]float64) []int {
+	n, m := len(cost), len(cost[0])
+
+	// 1-based: column 0 is the virtual start of each augmenting path
+	u := make([]float64, n+1)
+	v := make([]float64, m+1)
+	rowOf := make([]int, m+1)
+	way := make([]int, m+1)
+
+	for i := 1; i <= n; i++ {
+		rowOf[0] = i
+		j0 := 0
+		minv := make([]float64, m+1)
+		used := make([]bool, m+1)
+		for j := range minv {
+			minv[j] = math.Inf(1)
+		}
+
+		for {
+			used[j0] = true
+			i0, delta, j1 := rowOf[j0], math.Inf(1), 0
+			for j := 1; j <= m; j++ {
+				if used[j] {
+					continue
+				}
+				if cur := cost[i0-1][j-1] - u[i0] - v[j]; cur < minv[j] {
+					minv[j], way[j] = cur, j0
+				}
+				if minv[j] < delta {
+					delta, j1 = minv[j], j
+				}
+			}
+			for j := 0; j <= m; j++ {
+				if used[j] {
+					u[rowOf[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+			if rowOf[j0] == 0 {
+				break
+			}
+		}
+
+		for j0 != 0 {
+			j1 := way[j0]
+			rowOf[j0] = rowOf[j1]
+			j0 = j1
+		}
+	}
+
+	assignment := make([]int, n)
+	for j := 1; j <= m; j++ {
+		if rowOf[j] != 0 {
+			assignment[rowOf[j]-1] = j - 1
+		}
+	}
+	return assignment
+}
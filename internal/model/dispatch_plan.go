@@ -0,0 +1,210 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxDispatchScenarioSize caps the orders and the drones in a dry-run
+// scenario.
+const MaxDispatchScenarioSize = 500
+
+// DispatchPlanSource says where a dry run got its orders and drones.
+type DispatchPlanSource string
+
+const (
+	DispatchPlanLive     DispatchPlanSource = "live"
+	DispatchPlanScenario DispatchPlanSource = "scenario"
+)
+
+// DispatchPlanRequest asks what dispatch would do without offering
+// anything. Empty Mode and Cost fall back to the configured ones; without a
+// Scenario the orders waiting for an offer and the available drones are
+// used.
+type DispatchPlanRequest struct {
+	Mode     string
+	Cost     string
+	Scenario *DispatchScenario
+}
+
+// DispatchScenario is a hypothetical fleet and demand to dispatch.
+type DispatchScenario struct {
+	Orders []ScenarioOrder
+	Drones []ScenarioDrone
+}
+
+// ScenarioOrder is a pending order; a zero ID is numbered by position.
+type ScenarioOrder struct {
+	ID                     int64
+	PickupLat, PickupLng   float64
+	DropoffLat, DropoffLng float64
+}
+
+// ScenarioDrone is a drone that can take orders; a zero ID is numbered by
+// position, a zero Capacity means the default and SpeedMPS, when set, is
+// its ground speed for ETAs.
+type ScenarioDrone struct {
+	ID           int64
+	Lat, Lng     float64
+	Capacity     int
+	ActiveOrders int
+	SpeedMPS     *float64
+}
+
+// Build validates the scenario and turns it into pending orders and drones.
+func (s DispatchScenario) Build() ([]Order, []Drone, error) {
+	if len(s.Orders) == 0 || len(s.Drones) == 0 {
+		return nil, nil, ErrInvalidDispatchPlan("scenario needs at least one order and one drone")
+	}
+	if len(s.Orders) > MaxDispatchScenarioSize || len(s.Drones) > MaxDispatchScenarioSize {
+		return nil, nil, ErrInvalidDispatchPlan(fmt.Sprintf("scenario is limited to %d orders and %d drones", MaxDispatchScenarioSize, MaxDispatchScenarioSize))
+	}
+
+	orders := make([]Order, len(s.Orders))
+	seenOrders := make(map[int64]bool, len(s.Orders))
+	for i, o := range s.Orders {
+		id := o.ID
+		if id == 0 {
+			id = int64(i + 1)
+		}
+		if id < 0 || seenOrders[id] {
+			return nil, nil, ErrInvalidDispatchPlan(fmt.Sprintf("order id %d is invalid or repeated", id))
+		}
+		seenOrders[id] = true
+		for _, p := range []GeoPoint{{Lat: o.PickupLat, Lng: o.PickupLng}, {Lat: o.DropoffLat, Lng: o.DropoffLng}} {
+			if err := validateScenarioPoint(p); err != nil {
+				return nil, nil, err
+			}
+		}
+		orders[i] = Order{
+			ID:         id,
+			Status:     OrderPending,
+			PickupLat:  o.PickupLat,
+			PickupLng:  o.PickupLng,
+			DropoffLat: o.DropoffLat,
+			DropoffLng: o.DropoffLng,
+		}
+	}
+
+	drones := make([]Drone, len(s.Drones))
+	seenDrones := make(map[int64]bool, len(s.Drones))
+	for i, d := range s.Drones {
+		id := d.ID
+		if id == 0 {
+			id = int64(i + 1)
+		}
+		if id < 0 || seenDrones[id] {
+			return nil, nil, ErrInvalidDispatchPlan(fmt.Sprintf("drone id %d is invalid or repeated", id))
+		}
+		seenDrones[id] = true
+		if err := validateScenarioPoint(GeoPoint{Lat: d.Lat, Lng: d.Lng}); err != nil {
+			return nil, nil, err
+		}
+
+		drone := Drone{ID: id, Status: DroneIdle, Lat: d.Lat, Lng: d.Lng, ActiveOrders: d.ActiveOrders}
+		if d.Capacity != 0 {
+			if err := drone.SetCapacity(d.Capacity); err != nil {
+				return nil, nil, err
+			}
+		}
+		if d.ActiveOrders < 0 || d.ActiveOrders > drone.capacity() {
+			return nil, nil, ErrInvalidDispatchPlan(fmt.Sprintf("drone %d active_orders must be between 0 and its capacity", id))
+		}
+		if d.ActiveOrders > 0 {
+			drone.Status = DroneReserved
+		}
+		if d.SpeedMPS != nil {
+			if *d.SpeedMPS <= 0 || *d.SpeedMPS > maxGroundSpeedMPS {
+				return nil, nil, ErrInvalidDispatchPlan(fmt.Sprintf("drone %d speed_mps must be in (0, %g]", id, maxGroundSpeedMPS))
+			}
+			drone.Readings.GroundSpeedMPS = d.SpeedMPS
+		}
+		drones[i] = drone
+	}
+
+	return orders, drones, nil
+}
+
+func validateScenarioPoint(p GeoPoint) error {
+	if p.Lat < -90 || p.Lat > 90 {
+		return ErrInvalidLatitude(p.Lat)
+	}
+	if p.Lng < -180 || p.Lng > 180 {
+		return ErrInvalidLongitude(p.Lng)
+	}
+	return nil
+}
+
+// DispatchProposal is one offer a dry run would make. DistanceKm is the
+// dispatch cost to the pickup; ETAs follow the path planned around the
+// no-fly zones, to the pickup and on to the destination.
+type DispatchProposal struct {
+	OrderID            int64
+	DroneID            int64
+	DistanceKm         float64
+	DeliveryDistanceKm float64
+	PickupETA          ETA
+	DeliveryETA        ETA
+}
+
+// DispatchPlan is what dispatch would offer, committed to nothing.
+type DispatchPlan struct {
+	Mode                    DispatchMode
+	Cost                    DispatchCost
+	Source                  DispatchPlanSource
+	OrdersConsidered        int
+	DronesConsidered        int
+	Proposals               []DispatchProposal
+	UnassignedOrderIDs      []int64
+	TotalDistanceKm         float64
+	TotalDeliveryDistanceKm float64
+	ComputedAt              time.Time
+}
+
+// PlanDispatch runs the mode's matcher over orders and drones and prices
+// every proposal.
+func PlanDispatch(mode DispatchMode, cost DispatchCost, source DispatchPlanSource, orders []Order, drones []Drone, airspace Airspace, now time.Time) DispatchPlan {
+	pairs := mode.Match(orders, drones, func(order Order, drone Drone) float64 {
+		return cost.Km(order, drone, airspace)
+	})
+
+	plan := DispatchPlan{
+		Mode:               mode,
+		Cost:               cost,
+		Source:             source,
+		OrdersConsidered:   len(orders),
+		DronesConsidered:   len(drones),
+		Proposals:          make([]DispatchProposal, 0, len(pairs)),
+		UnassignedOrderIDs: []int64{},
+		ComputedAt:         now,
+	}
+
+	assigned := make(map[int64]bool, len(pairs))
+	for _, pair := range pairs {
+		assigned[pair.Order.ID] = true
+
+		pickupLat, pickupLng := pair.Order.PickupPoint()
+		destLat, destLng := pair.Order.DestinationPoint()
+		toPickupKm := airspace.DistanceKm(pair.Drone.Lat, pair.Drone.Lng, pickupLat, pickupLng)
+		deliveryKm := toPickupKm + airspace.DistanceKm(pickupLat, pickupLng, destLat, destLng)
+		speed := pair.Drone.speedMPS()
+
+		plan.Proposals = append(plan.Proposals, DispatchProposal{
+			OrderID:            pair.Order.ID,
+			DroneID:            pair.Drone.ID,
+			DistanceKm:         pair.CostKm,
+			DeliveryDistanceKm: deliveryKm,
+			PickupETA:          etaForDistance(toPickupKm, speed),
+			DeliveryETA:        etaForDistance(deliveryKm, speed),
+		})
+		plan.TotalDistanceKm += pair.CostKm
+		plan.TotalDeliveryDistanceKm += deliveryKm
+	}
+
+	for _, order := range orders {
+		if !assigned[order.ID] {
+			plan.UnassignedOrderIDs = append(plan.UnassignedOrderIDs, order.ID)
+		}
+	}
+	return plan
+}
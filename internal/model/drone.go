@@ -1,22 +1,93 @@
 package model
 
 import (
+	"fmt"
 	"time"
 )
 
+// DroneHeartbeat is a position report plus whatever flight readings the
+// drone's autopilot sends along with it.
 type DroneHeartbeat struct {
 	Lat float64
 	Lng float64
+	FlightReadings
 }
 
+// FlightReadings are the optional sensor values carried by a heartbeat. A
+// nil field (or empty GPSFix) means the drone did not report it.
+type FlightReadings struct {
+	AltitudeM      *float64
+	HeadingDeg     *float64
+	GroundSpeedMPS *float64
+	BatteryPct     *float64
+	GPSFix         GPSFix
+	GPSAccuracyM   *float64
+	// DeviceTime is the drone's own clock at the time of the reading; it
+	// orders heartbeats that arrive out of sequence.
+	DeviceTime *time.Time
+}
+
+type GPSFix string
+
+const (
+	GPSFixNone GPSFix = "none"
+	GPSFix2D   GPSFix = "2d"
+	GPSFix3D   GPSFix = "3d"
+	GPSFixDGPS GPSFix = "dgps"
+	GPSFixRTK  GPSFix = "rtk"
+)
+
+func IsValidGPSFix(fix GPSFix) bool {
+	switch fix {
+	case GPSFixNone, GPSFix2D, GPSFix3D, GPSFixDGPS, GPSFixRTK:
+		return true
+	}
+	return false
+}
+
+const (
+	defaultDroneCapacity = 1
+	MaxDroneCapacity     = 8
+
+	minAltitudeM      = -500.0
+	maxAltitudeM      = 10000.0
+	maxGroundSpeedMPS = 100.0
+	// maxDeviceClockSkew is how far ahead of the server a drone's clock may
+	// run before its timestamps are rejected.
+	maxDeviceClockSkew = 5 * time.Minute
+)
+
+// Drone.CurrentOrderID points at the order of the next stop on the current
+// trip; ActiveOrders counts every order the drone is still responsible for.
+// DepotID is the depot whose pad the drone holds while it is parked or
+// charging there, or on its way to.
 type Drone struct {
 	ID             int64
 	Status         DroneStatus
 	CurrentOrderID *int64
+	CurrentTripID  *int64
+	Capacity       int
+	ActiveOrders   int
+	HomeDepotID    *int64
+	DepotID        *int64
 	Lat, Lng       float64
 	LastHeartbeat  *time.Time
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
+	// Readings are the flight readings from the latest heartbeat.
+	Readings  FlightReadings
+	Usage     DroneUsage
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// DroneUsage counts what a drone has done over its life. The counters
+// change with the drone and are saved with it; AirborneTime, the time spent
+// delivering, is clocked where status changes are stored and only read.
+type DroneUsage struct {
+	Deliveries   int
+	Failures     int
+	Breakdowns   int
+	DistanceM    float64
+	AirborneTime time.Duration
 }
 
 type DroneStatus string
@@ -26,12 +97,20 @@ const (
 	DroneReserved   DroneStatus = "reserved"
 	DroneDelivering DroneStatus = "delivering"
 	DroneBroken     DroneStatus = "broken"
+	// DroneCharging drones are out of service until recharged: on their way
+	// to a depot or on its pad.
+	DroneCharging DroneStatus = "charging"
+	// DroneMaintenance drones are out of service while a maintenance record
+	// is open on them.
+	DroneMaintenance DroneStatus = "maintenance"
 )
 
 var allowedDroneTransitions = map[DroneStatus][]DroneStatus{
 	DroneIdle: {
 		DroneReserved,
 		DroneBroken,
+		DroneCharging,
+		DroneMaintenance,
 	},
 	DroneReserved: {
 		DroneDelivering,
@@ -45,6 +124,15 @@ var allowedDroneTransitions = map[DroneStatus][]DroneStatus{
 	DroneBroken: {
 		DroneIdle,
 	},
+	DroneCharging: {
+		DroneIdle,
+		DroneBroken,
+		DroneMaintenance,
+	},
+	DroneMaintenance: {
+		DroneIdle,
+		DroneBroken,
+	},
 }
 
 func (d *Drone) IsStatusTransitionAllowed(newStatus DroneStatus) bool {
@@ -72,33 +160,156 @@ func (d *Drone) UpdateStatus(newStatus DroneStatus) error {
 }
 
 func (d *Drone) Reserve(orderID int64) error {
-	if err := d.UpdateStatus(DroneReserved); err != nil {
-		return err
+	switch d.Status {
+	case DroneReserved, DroneDelivering:
+		if !d.HasCapacity() {
+			return ErrDroneAtCapacity(d.capacity())
+		}
+	default:
+		if err := d.UpdateStatus(DroneReserved); err != nil {
+			return err
+		}
+		// leaves the depot pad for the next drone
+		d.DepotID = nil
+	}
+
+	d.ActiveOrders++
+	if d.CurrentOrderID == nil {
+		d.CurrentOrderID = &orderID
 	}
-	d.CurrentOrderID = &orderID
 	return nil
 }
 
-func (d *Drone) CompleteDelivery() error {
-	if err := d.UpdateStatus(DroneIdle); err != nil {
-		return err
+func (d *Drone) HasCapacity() bool {
+	return d.ActiveOrders < d.capacity()
+}
+
+// SpareCapacity is how many more orders the drone can take on.
+func (d *Drone) SpareCapacity() int {
+	return max(d.capacity()-d.ActiveOrders, 0)
+}
+
+func (d *Drone) capacity() int {
+	if d.Capacity < 1 {
+		return defaultDroneCapacity
 	}
-	d.CurrentOrderID = nil
+	return d.Capacity
+}
+
+func (d *Drone) SetCapacity(capacity int) error {
+	if capacity < 1 || capacity > MaxDroneCapacity {
+		return ErrInvalidDroneCapacity(capacity)
+	}
+	d.Capacity = capacity
 	return nil
 }
 
+func (d *Drone) CompleteDelivery() error {
+	d.Usage.Deliveries++
+	return d.releaseOrder()
+}
+
 func (d *Drone) StartDelivery() error {
+	if d.Status == DroneDelivering {
+		return nil
+	}
 	return d.UpdateStatus(DroneDelivering)
 }
 
-func (d *Drone) FailDelivery() error {
+// FailDelivery counts the failure when the order first leaves its route, so
+// a returning parcel that then fails outright is not counted again; a drone
+// bringing the parcel back keeps the order until the return is confirmed.
+func (d *Drone) FailDelivery(from, to OrderStatus) error {
+	if from != OrderReturning {
+		d.Usage.Failures++
+	}
+	if to == OrderReturning {
+		return nil
+	}
+	return d.releaseOrder()
+}
+
+func (d *Drone) CompleteReturn() error {
+	return d.releaseOrder()
+}
+
+// releaseOrder frees one order slot; the drone only goes idle once nothing
+// else is left on its trip.
+func (d *Drone) releaseOrder() error {
+	if d.ActiveOrders > 1 {
+		d.ActiveOrders--
+		return nil
+	}
+
 	if err := d.UpdateStatus(DroneIdle); err != nil {
 		return err
 	}
+	d.ActiveOrders = 0
 	d.CurrentOrderID = nil
+	d.CurrentTripID = nil
 	return nil
 }
 
+// ReturnToBase sends an idle drone to the depot, holding one of its pads; a
+// drone going to charge is out of service until it is charged.
+func (d *Drone) ReturnToBase(depotID int64, reason BaseReason) error {
+	if d.Status != DroneIdle {
+		return ErrDroneTransitionNotAllowed(string(d.Status), string(DroneCharging))
+	}
+	if reason == BaseToCharge {
+		if err := d.UpdateStatus(DroneCharging); err != nil {
+			return err
+		}
+	}
+	d.DepotID = &depotID
+	return nil
+}
+
+// FinishCharging puts the drone back in service; it keeps its pad until it
+// takes an order.
+func (d *Drone) FinishCharging() error {
+	return d.UpdateStatus(DroneIdle)
+}
+
+// StartMaintenance takes an idle or charging drone out of service and frees
+// its depot pad. A broken drone is out already and stays broken until fixed.
+func (d *Drone) StartMaintenance() error {
+	if d.Status == DroneBroken {
+		return nil
+	}
+	if err := d.UpdateStatus(DroneMaintenance); err != nil {
+		return err
+	}
+	d.DepotID = nil
+	return nil
+}
+
+// EndMaintenance puts the drone back in service once its record is closed;
+// a broken drone has to be reported fixed instead.
+func (d *Drone) EndMaintenance() error {
+	switch d.Status {
+	case DroneMaintenance:
+		return d.UpdateStatus(DroneIdle)
+	case DroneBroken:
+		return ErrMaintenanceDroneBroken()
+	}
+	return nil
+}
+
+func (d *Drone) FollowTrip(trip *Trip) {
+	if trip == nil || !trip.IsActive() {
+		d.CurrentTripID = nil
+		return
+	}
+
+	tripID := trip.ID
+	d.CurrentTripID = &tripID
+	if next := trip.NextStop(); next != nil {
+		orderID := next.OrderID
+		d.CurrentOrderID = &orderID
+	}
+}
+
 func (d *Drone) IsBroken() bool {
 	return d.Status == DroneBroken
 }
@@ -113,15 +324,86 @@ func (d *Drone) Validate() error {
 	return nil
 }
 
+// ApplyHeartbeat validates the update and moves the drone. A heartbeat whose
+// device timestamp is not newer than the last applied one is rejected so a
+// delayed report cannot move the drone backwards.
 func (d *Drone) ApplyHeartbeat(update DroneHeartbeat, now time.Time) error {
-	if err := d.Validate(); err != nil {
+	if err := update.Validate(now); err != nil {
 		return err
 	}
 
+	if update.DeviceTime != nil && d.Readings.DeviceTime != nil && !update.DeviceTime.After(*d.Readings.DeviceTime) {
+		return ErrStaleHeartbeat(*update.DeviceTime, *d.Readings.DeviceTime)
+	}
+
+	// the first heartbeat only places the drone, and GPS jitter on the
+	// ground is not distance flown
+	if d.LastHeartbeat != nil && d.airborne(update.FlightReadings) {
+		d.Usage.DistanceM += haversineDistance(d.Lat, d.Lng, update.Lat, update.Lng) * metersPerKilometer
+	}
+
 	d.Lat = update.Lat
 	d.Lng = update.Lng
 	d.LastHeartbeat = &now
 
+	// Keep the last device time when this report has none, so ordering
+	// still holds once the drone starts sending it again.
+	lastDeviceTime := d.Readings.DeviceTime
+	d.Readings = update.FlightReadings
+	if d.Readings.DeviceTime == nil {
+		d.Readings.DeviceTime = lastDeviceTime
+	}
+
+	return nil
+}
+
+// airborne: the drone is flying to a pickup or dropoff, or heading to the
+// depot whose pad it holds. The server does not know when a drone lands on
+// its pad, so that leg counts only while the drone reports moving.
+func (d *Drone) airborne(r FlightReadings) bool {
+	switch d.Status {
+	case DroneReserved, DroneDelivering:
+		return true
+	case DroneIdle, DroneCharging:
+		return d.DepotID != nil && r.GroundSpeedMPS != nil && *r.GroundSpeedMPS >= minReportedSpeedMPS
+	default:
+		return false
+	}
+}
+
+func (hb DroneHeartbeat) Validate(now time.Time) error {
+	if hb.Lat < -90 || hb.Lat > 90 {
+		return ErrInvalidLatitude(hb.Lat)
+	}
+	if hb.Lng < -180 || hb.Lng > 180 {
+		return ErrInvalidLongitude(hb.Lng)
+	}
+
+	r := hb.FlightReadings
+	if r.AltitudeM != nil && (*r.AltitudeM < minAltitudeM || *r.AltitudeM > maxAltitudeM) {
+		return ErrInvalidHeartbeat("altitude_m", fmt.Sprintf("must be between %.0f and %.0f", minAltitudeM, maxAltitudeM))
+	}
+	if r.HeadingDeg != nil && (*r.HeadingDeg < 0 || *r.HeadingDeg >= 360) {
+		return ErrInvalidHeartbeat("heading_deg", "must be in [0, 360)")
+	}
+	if r.GroundSpeedMPS != nil && (*r.GroundSpeedMPS < 0 || *r.GroundSpeedMPS > maxGroundSpeedMPS) {
+		return ErrInvalidHeartbeat("speed_mps", fmt.Sprintf("must be between 0 and %.0f", maxGroundSpeedMPS))
+	}
+	if r.BatteryPct != nil && (*r.BatteryPct < 0 || *r.BatteryPct > 100) {
+		return ErrInvalidHeartbeat("battery_pct", "must be between 0 and 100")
+	}
+	if r.GPSFix != "" && !IsValidGPSFix(r.GPSFix) {
+		return ErrInvalidHeartbeat("gps_fix", "must be one of none, 2d, 3d, dgps, rtk")
+	}
+	if r.GPSFix == GPSFixNone {
+		return ErrInvalidHeartbeat("gps_fix", "position reported without a GPS fix")
+	}
+	if r.GPSAccuracyM != nil && *r.GPSAccuracyM < 0 {
+		return ErrInvalidHeartbeat("gps_accuracy_m", "must not be negative")
+	}
+	if r.DeviceTime != nil && r.DeviceTime.After(now.Add(maxDeviceClockSkew)) {
+		return ErrInvalidHeartbeat("device_time", "is too far in the future")
+	}
 	return nil
 }
 
@@ -137,15 +419,22 @@ func (d *Drone) ReportBroken(location DroneHeartbeat) error {
 		if err := d.UpdateStatus(DroneBroken); err != nil {
 			return err
 		}
+		d.Usage.Breakdowns++
 	}
 
 	d.Lat = location.Lat
 	d.Lng = location.Lng
 	d.CurrentOrderID = nil
+	d.CurrentTripID = nil
+	d.ActiveOrders = 0
+	d.DepotID = nil
 
 	return nil
 }
 
+// ReportFixed puts a drone back in service; fixing an idle drone only moves
+// it. A drone in maintenance comes back when its record is closed and a
+// charging one when it is charged, so neither can be fixed.
 func (d *Drone) ReportFixed(location DroneHeartbeat) error {
 	if location.Lat < -90 || location.Lat > 90 {
 		return ErrInvalidLatitude(location.Lat)
@@ -154,7 +443,11 @@ func (d *Drone) ReportFixed(location DroneHeartbeat) error {
 		return ErrInvalidLongitude(location.Lng)
 	}
 
-	if d.Status != DroneIdle {
+	switch d.Status {
+	case DroneMaintenance, DroneCharging:
+		return ErrDroneTransitionNotAllowed(string(d.Status), string(DroneIdle))
+	case DroneIdle:
+	default:
 		if err := d.UpdateStatus(DroneIdle); err != nil {
 			return err
 		}
@@ -163,6 +456,9 @@ func (d *Drone) ReportFixed(location DroneHeartbeat) error {
 	d.Lat = location.Lat
 	d.Lng = location.Lng
 	d.CurrentOrderID = nil
+	d.CurrentTripID = nil
+	d.ActiveOrders = 0
+	d.DepotID = nil
 
 	return nil
 }
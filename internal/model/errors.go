@@ -1,6 +1,9 @@
 package model
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type DomainError struct {
 	Code       string
@@ -64,6 +67,37 @@ const (
 	ErrCodeOrderRouteLocked                = "order_route_locked"
 	ErrCodeInvalidRouteUpdate              = "invalid_route_update"
 	ErrCodeInvalidPagination               = "invalid_pagination"
+	ErrCodeDeliveryProofRequired           = "delivery_proof_required"
+	ErrCodeInvalidDeliveryPIN              = "invalid_delivery_pin"
+	ErrCodeDeliveryPINLocked               = "delivery_pin_locked"
+	ErrCodeInvalidDeliveryEvidence         = "invalid_delivery_evidence"
+	ErrCodeDroneLocationUnknown            = "drone_location_unknown"
+	ErrCodeDroneNotAtDropoff               = "drone_not_at_dropoff"
+	ErrCodeDroneAtCapacity                 = "drone_at_capacity"
+	ErrCodeInvalidDroneCapacity            = "invalid_drone_capacity"
+	ErrCodeDroneNotOnTrip                  = "drone_not_on_trip"
+	ErrCodeInvalidDateRange                = "invalid_date_range"
+	ErrCodeAddressNotOwned                 = "address_not_owned"
+	ErrCodeInvalidAddress                  = "invalid_address"
+	ErrCodeAddressNotGeocoded              = "address_not_geocoded"
+	ErrCodeInvalidPolygon                  = "invalid_polygon"
+	ErrCodeInvalidServiceArea              = "invalid_service_area"
+	ErrCodeOutsideServiceArea              = "outside_service_area"
+	ErrCodeInvalidNoFlyZone                = "invalid_no_fly_zone"
+	ErrCodeInsideNoFlyZone                 = "inside_no_fly_zone"
+	ErrCodeTrackWindowTooLarge             = "track_window_too_large"
+	ErrCodeInvalidHeartbeat                = "invalid_heartbeat"
+	ErrCodeStaleHeartbeat                  = "stale_heartbeat"
+	ErrCodeInvalidDroneCommand             = "invalid_drone_command"
+	ErrCodeCommandTransitionNotAllowed     = "command_transition_not_allowed"
+	ErrCodeInvalidDispatchPlan             = "invalid_dispatch_plan"
+	ErrCodeInvalidLandingSite              = "invalid_landing_site"
+	ErrCodeInvalidDepot                    = "invalid_depot"
+	ErrCodeInvalidRebalance                = "invalid_rebalance"
+	ErrCodeInvalidMaintenance              = "invalid_maintenance"
+	ErrCodeMaintenanceAlreadyOpen          = "maintenance_already_open"
+	ErrCodeMaintenanceClosed               = "maintenance_closed"
+	ErrCodeMaintenanceDroneBroken          = "maintenance_drone_broken"
 )
 
 func ErrOrderTransitionNotAllowed(from, to string) *DomainError {
@@ -158,3 +192,295 @@ func ErrInvalidPagination() *DomainError {
 		StatusCode: 400,
 	}
 }
+
+func ErrDeliveryProofRequired() *DomainError {
+	return &DomainError{
+		Code:       ErrCodeDeliveryProofRequired,
+		Message:    "delivery requires a recipient pin or signed evidence",
+		StatusCode: 400,
+	}
+}
+
+func ErrInvalidDeliveryPIN() *DomainError {
+	return &DomainError{
+		Code:       ErrCodeInvalidDeliveryPIN,
+		Message:    "recipient pin does not match",
+		StatusCode: 403,
+	}
+}
+
+func ErrDeliveryPINLocked(attempts int) *DomainError {
+	return &DomainError{
+		Code:       ErrCodeDeliveryPINLocked,
+		Message:    "too many wrong recipient pins, pin proof is locked for this order",
+		Details:    map[string]interface{}{"pin_attempts": attempts},
+		StatusCode: 403,
+	}
+}
+
+func ErrInvalidDeliveryEvidence(reason string) *DomainError {
+	return &DomainError{
+		Code:       ErrCodeInvalidDeliveryEvidence,
+		Message:    "delivery evidence rejected",
+		Details:    map[string]interface{}{"reason": reason},
+		StatusCode: 403,
+	}
+}
+
+func ErrDroneLocationUnknown() *DomainError {
+	return &DomainError{
+		Code:       ErrCodeDroneLocationUnknown,
+		Message:    "drone has not reported a heartbeat location",
+		StatusCode: 409,
+	}
+}
+
+func ErrDroneNotAtDropoff(distanceMeters, radiusMeters float64) *DomainError {
+	return &DomainError{
+		Code:    ErrCodeDroneNotAtDropoff,
+		Message: "drone is not within the dropoff radius",
+		Details: map[string]interface{}{
+			"distance_meters": distanceMeters,
+			"radius_meters":   radiusMeters,
+		},
+		StatusCode: 409,
+	}
+}
+
+func ErrDroneAtCapacity(capacity int) *DomainError {
+	return &DomainError{
+		Code:       ErrCodeDroneAtCapacity,
+		Message:    fmt.Sprintf("drone is already carrying its maximum of %d orders", capacity),
+		Details:    map[string]interface{}{"capacity": capacity},
+		StatusCode: 409,
+	}
+}
+
+func ErrInvalidDroneCapacity(capacity int) *DomainError {
+	return &DomainError{
+		Code:       ErrCodeInvalidDroneCapacity,
+		Message:    fmt.Sprintf("capacity must be between 1 and %d", MaxDroneCapacity),
+		Details:    map[string]interface{}{"capacity": capacity},
+		StatusCode: 400,
+	}
+}
+
+func ErrDroneNotOnTrip() *DomainError {
+	return &DomainError{
+		Code:       ErrCodeDroneNotOnTrip,
+		Message:    "drone has no active trip",
+		StatusCode: 404,
+	}
+}
+
+func ErrInvalidDateRange() *DomainError {
+	return &DomainError{
+		Code:       ErrCodeInvalidDateRange,
+		Message:    "from must not be after to",
+		StatusCode: 400,
+	}
+}
+
+func ErrAddressNotOwned() *DomainError {
+	return &DomainError{
+		Code:       ErrCodeAddressNotOwned,
+		Message:    "address does not belong to user",
+		StatusCode: 403,
+	}
+}
+
+func ErrInvalidAddress(reason string) *DomainError {
+	return &DomainError{
+		Code:       ErrCodeInvalidAddress,
+		Message:    "invalid address",
+		Details:    map[string]interface{}{"reason": reason},
+		StatusCode: 400,
+	}
+}
+
+func ErrAddressNotGeocoded(query string) *DomainError {
+	return &DomainError{
+		Code:       ErrCodeAddressNotGeocoded,
+		Message:    "address could not be resolved to coordinates",
+		Details:    map[string]interface{}{"query": query},
+		StatusCode: 422,
+	}
+}
+
+func ErrInvalidPolygon(reason string) *DomainError {
+	return &DomainError{
+		Code:       ErrCodeInvalidPolygon,
+		Message:    "invalid polygon",
+		Details:    map[string]interface{}{"reason": reason},
+		StatusCode: 400,
+	}
+}
+
+func ErrInvalidServiceArea(reason string) *DomainError {
+	return &DomainError{
+		Code:       ErrCodeInvalidServiceArea,
+		Message:    "invalid service area",
+		Details:    map[string]interface{}{"reason": reason},
+		StatusCode: 400,
+	}
+}
+
+func ErrOutsideServiceArea(point string, lat, lng float64) *DomainError {
+	return &DomainError{
+		Code:    ErrCodeOutsideServiceArea,
+		Message: point + " is outside the service area",
+		Details: map[string]interface{}{
+			"point": point,
+			"lat":   lat,
+			"lng":   lng,
+		},
+		StatusCode: 422,
+	}
+}
+
+func ErrInvalidNoFlyZone(reason string) *DomainError {
+	return &DomainError{
+		Code:       ErrCodeInvalidNoFlyZone,
+		Message:    "invalid no-fly zone",
+		Details:    map[string]interface{}{"reason": reason},
+		StatusCode: 400,
+	}
+}
+
+func ErrInsideNoFlyZone(point, zone string, lat, lng float64) *DomainError {
+	return &DomainError{
+		Code:    ErrCodeInsideNoFlyZone,
+		Message: point + " is inside no-fly zone " + zone,
+		Details: map[string]interface{}{
+			"point": point,
+			"zone":  zone,
+			"lat":   lat,
+			"lng":   lng,
+		},
+		StatusCode: 422,
+	}
+}
+
+func ErrTrackWindowTooLarge(max time.Duration) *DomainError {
+	return &DomainError{
+		Code:       ErrCodeTrackWindowTooLarge,
+		Message:    "track window must not exceed " + max.String(),
+		Details:    map[string]interface{}{"max_hours": max.Hours()},
+		StatusCode: 400,
+	}
+}
+
+func ErrInvalidHeartbeat(field, reason string) *DomainError {
+	return &DomainError{
+		Code:    ErrCodeInvalidHeartbeat,
+		Message: field + " " + reason,
+		Details: map[string]interface{}{
+			"field":  field,
+			"reason": reason,
+		},
+		StatusCode: 400,
+	}
+}
+
+func ErrStaleHeartbeat(deviceTime, lastDeviceTime time.Time) *DomainError {
+	return &DomainError{
+		Code:    ErrCodeStaleHeartbeat,
+		Message: "heartbeat is older than the last one applied",
+		Details: map[string]interface{}{
+			"device_time":      deviceTime,
+			"last_device_time": lastDeviceTime,
+		},
+		StatusCode: 409,
+	}
+}
+
+func ErrInvalidDroneCommand(reason string) *DomainError {
+	return &DomainError{
+		Code:       ErrCodeInvalidDroneCommand,
+		Message:    "invalid drone command",
+		Details:    map[string]interface{}{"reason": reason},
+		StatusCode: 400,
+	}
+}
+
+func ErrCommandTransitionNotAllowed(from, to string) *DomainError {
+	return &DomainError{
+		Code:    ErrCodeCommandTransitionNotAllowed,
+		Message: fmt.Sprintf("command cannot move from %s to %s", from, to),
+		Details: map[string]interface{}{
+			"from": from,
+			"to":   to,
+		},
+		StatusCode: 409,
+	}
+}
+
+func ErrInvalidDispatchPlan(reason string) *DomainError {
+	return &DomainError{
+		Code:       ErrCodeInvalidDispatchPlan,
+		Message:    "invalid dispatch dry run",
+		Details:    map[string]interface{}{"reason": reason},
+		StatusCode: 400,
+	}
+}
+
+func ErrInvalidLandingSite(reason string) *DomainError {
+	return &DomainError{
+		Code:       ErrCodeInvalidLandingSite,
+		Message:    "invalid landing site",
+		Details:    map[string]interface{}{"reason": reason},
+		StatusCode: 400,
+	}
+}
+
+func ErrInvalidDepot(reason string) *DomainError {
+	return &DomainError{
+		Code:       ErrCodeInvalidDepot,
+		Message:    "invalid depot",
+		Details:    map[string]interface{}{"reason": reason},
+		StatusCode: 400,
+	}
+}
+
+func ErrInvalidRebalance(reason string) *DomainError {
+	return &DomainError{
+		Code:       ErrCodeInvalidRebalance,
+		Message:    "invalid rebalance request",
+		Details:    map[string]interface{}{"reason": reason},
+		StatusCode: 400,
+	}
+}
+
+func ErrInvalidMaintenance(reason string) *DomainError {
+	return &DomainError{
+		Code:       ErrCodeInvalidMaintenance,
+		Message:    "invalid maintenance record",
+		Details:    map[string]interface{}{"reason": reason},
+		StatusCode: 400,
+	}
+}
+
+func ErrMaintenanceAlreadyOpen(recordID int64) *DomainError {
+	return &DomainError{
+		Code:       ErrCodeMaintenanceAlreadyOpen,
+		Message:    "drone already has an open maintenance record",
+		Details:    map[string]interface{}{"record_id": recordID},
+		StatusCode: 409,
+	}
+}
+
+func ErrMaintenanceClosed() *DomainError {
+	return &DomainError{
+		Code:       ErrCodeMaintenanceClosed,
+		Message:    "maintenance record is already closed",
+		StatusCode: 409,
+	}
+}
+
+func ErrMaintenanceDroneBroken() *DomainError {
+	return &DomainError{
+		Code:       ErrCodeMaintenanceDroneBroken,
+		Message:    "drone is broken; mark it fixed to close the record",
+		StatusCode: 409,
+	}
+}
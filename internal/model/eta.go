@@ -2,32 +2,61 @@ package model
 
 import "math"
 
+// DroneCruiseSpeedMPS is the nominal ground speed ETAs assume for a drone
+// that is not reporting a usable one.
+const DroneCruiseSpeedMPS = 10.0
+
 const (
 	earthRadiusKm      = 6371.0
-	droneSpeedMPS      = 10.0
 	metersPerKilometer = 1000.0
+	// minReportedSpeedMPS: below this the drone is hovering or loitering and
+	// its reported speed says nothing about how fast it will cruise.
+	minReportedSpeedMPS = 1.0
 )
 
 type ETA int
 
-func CalculateETA(drone *Drone, order *Order) ETA {
+// CalculateETA estimates minutes to the order's destination along the
+// planned path around the given airspace, at the drone's reported ground
+// speed when it is moving.
+func CalculateETA(drone *Drone, order *Order, airspace Airspace) ETA {
 	if drone == nil {
 		return 0
 	}
 
 	var distanceKm float64
 
-	if order.Status == OrderPending || order.Status == OrderReserved {
-		droneToPickup := haversineDistance(drone.Lat, drone.Lng, order.PickupLat, order.PickupLng)
-		pickupToDropoff := haversineDistance(order.PickupLat, order.PickupLng, order.DropoffLat, order.DropoffLng)
-		distanceKm = droneToPickup + pickupToDropoff
-	} else {
-		distanceKm = haversineDistance(drone.Lat, drone.Lng, order.DropoffLat, order.DropoffLng)
+	switch {
+	case order.Status == OrderReturning && order.IsReturn():
+		distanceKm = airspace.DistanceKm(drone.Lat, drone.Lng, *order.ReturnLat, *order.ReturnLng)
+	case order.Status == OrderPending || order.Status == OrderReserved:
+		// handoffs are collected at the handoff point and returns flown
+		// back to the origin
+		pickupLat, pickupLng := order.PickupPoint()
+		destLat, destLng := order.DestinationPoint()
+		droneToPickup := airspace.DistanceKm(drone.Lat, drone.Lng, pickupLat, pickupLng)
+		pickupToDestination := airspace.DistanceKm(pickupLat, pickupLng, destLat, destLng)
+		distanceKm = droneToPickup + pickupToDestination
+	default:
+		distanceKm = airspace.DistanceKm(drone.Lat, drone.Lng, order.DropoffLat, order.DropoffLng)
+	}
+
+	return etaForDistance(distanceKm, drone.speedMPS())
+}
+
+// speedMPS is the drone's reported ground speed when it is actually moving,
+// otherwise the nominal cruise speed.
+func (d *Drone) speedMPS() float64 {
+	if s := d.Readings.GroundSpeedMPS; s != nil && *s >= minReportedSpeedMPS {
+		return *s
 	}
+	return DroneCruiseSpeedMPS
+}
 
+func etaForDistance(distanceKm, speedMPS float64) ETA {
 	distanceMeters := distanceKm * metersPerKilometer
 
-	timeSeconds := distanceMeters / droneSpeedMPS
+	timeSeconds := distanceMeters / speedMPS
 	timeMinutes := timeSeconds / 60.0
 
 	eta := int(math.Ceil(timeMinutes))
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type GeofenceBreachKind string
+
+const (
+	BreachNoFlyZone          GeofenceBreachKind = "no_fly_zone"
+	BreachOutsideServiceArea GeofenceBreachKind = "outside_service_area"
+)
+
+// BreachAction is the command sent back to a drone that breaches a geofence.
+type BreachAction string
+
+const (
+	BreachActionNone BreachAction = "none"
+	BreachActionHold BreachAction = "hold"
+	BreachActionLand BreachAction = "land"
+)
+
+func ParseBreachAction(value string) (BreachAction, error) {
+	switch action := BreachAction(strings.ToLower(strings.TrimSpace(value))); action {
+	case BreachActionNone, BreachActionHold, BreachActionLand:
+		return action, nil
+	default:
+		return "", fmt.Errorf("unknown breach action %q", value)
+	}
+}
+
+// GeofenceBreach records a drone entering a no-fly zone or leaving the
+// service coverage; OrderID is the order it was flying at the time.
+type GeofenceBreach struct {
+	ID         int64
+	DroneID    int64
+	OrderID    *int64
+	Kind       GeofenceBreachKind
+	ZoneID     *int64
+	ZoneName   *string
+	Lat        float64
+	Lng        float64
+	Action     BreachAction
+	DetectedAt time.Time
+}
+
+// Geofence is what heartbeats are checked against.
+type Geofence struct {
+	Airspace Airspace
+	Coverage ServiceCoverage
+}
+
+/*
+Entered: violations at next that did not already hold at prev, so a drone
+loitering inside a zone raises one breach rather than one per heartbeat.
+prev is nil for a drone's first reported position.
+*/
+func (g Geofence) Entered(drone Drone, prev *GeoPoint, next GeoPoint, action BreachAction, now time.Time) []GeofenceBreach {
+	var previous map[string]bool
+	if prev != nil {
+		previous = make(map[string]bool)
+		for _, v := range g.violations(*prev) {
+			previous[v.key()] = true
+		}
+	}
+
+	var breaches []GeofenceBreach
+	for _, v := range g.violations(next) {
+		if previous[v.key()] {
+			continue
+		}
+		v.DroneID = drone.ID
+		v.OrderID = drone.CurrentOrderID
+		v.Action = action
+		v.DetectedAt = now
+		breaches = append(breaches, v)
+	}
+	return breaches
+}
+
+func (g Geofence) violations(p GeoPoint) []GeofenceBreach {
+	var out []GeofenceBreach
+	for i := range g.Airspace {
+		zone := &g.Airspace[i]
+		if zone.Boundary.Contains(p.Lat, p.Lng) {
+			out = append(out, GeofenceBreach{
+				Kind:     BreachNoFlyZone,
+				ZoneID:   &zone.ID,
+				ZoneName: &zone.Name,
+				Lat:      p.Lat,
+				Lng:      p.Lng,
+			})
+		}
+	}
+	if !g.Coverage.Covers(p.Lat, p.Lng) {
+		out = append(out, GeofenceBreach{Kind: BreachOutsideServiceArea, Lat: p.Lat, Lng: p.Lng})
+	}
+	return out
+}
+
+func (b GeofenceBreach) key() string {
+	if b.ZoneID != nil {
+		return fmt.Sprintf("%s:%d", b.Kind, *b.ZoneID)
+	}
+	return string(b.Kind)
+}
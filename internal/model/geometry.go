@@ -0,0 +1,124 @@
+package model
+
+import "math"
+
+type GeoPoint struct {
+	Lat float64
+	Lng float64
+}
+
+// Polygon is a closed ring of vertices; the closing vertex is implied, so
+// the first point is not repeated at the end.
+type Polygon []GeoPoint
+
+const minPolygonVertices = 3
+
+func (p Polygon) Validate() error {
+	if len(p) < minPolygonVertices {
+		return ErrInvalidPolygon("polygon needs at least 3 vertices")
+	}
+
+	distinct := make(map[GeoPoint]struct{}, len(p))
+	for _, v := range p {
+		if v.Lat < -90 || v.Lat > 90 {
+			return ErrInvalidLatitude(v.Lat)
+		}
+		if v.Lng < -180 || v.Lng > 180 {
+			return ErrInvalidLongitude(v.Lng)
+		}
+		distinct[v] = struct{}{}
+	}
+	if len(distinct) < minPolygonVertices {
+		return ErrInvalidPolygon("polygon needs at least 3 distinct vertices")
+	}
+
+	return nil
+}
+
+/*
+Contains: even-odd ray casting on the lat/lng plane. Good enough for
+city-scale areas; polygons spanning the antimeridian are not supported.
+*/
+func (p Polygon) Contains(lat, lng float64) bool {
+	inside := false
+	for i, j := 0, len(p)-1; i < len(p); j, i = i, i+1 {
+		a, b := p[i], p[j]
+		if (a.Lat > lat) != (b.Lat > lat) &&
+			lng < (b.Lng-a.Lng)*(lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Normalize drops a repeated closing vertex if the caller supplied one.
+func (p Polygon) Normalize() Polygon {
+	if len(p) > 1 && p[0] == p[len(p)-1] {
+		return p[:len(p)-1]
+	}
+	return p
+}
+
+// Crosses reports whether the segment from a to b enters the polygon, either
+// by crossing or touching an edge or by lying entirely inside it.
+func (p Polygon) Crosses(a, b GeoPoint) bool {
+	for i, j := 0, len(p)-1; i < len(p); j, i = i, i+1 {
+		if segmentsIntersect(a, b, p[j], p[i]) {
+			return true
+		}
+	}
+	return p.Contains(a.Lat, a.Lng) || p.Contains((a.Lat+b.Lat)/2, (a.Lng+b.Lng)/2)
+}
+
+// offsetCorners pushes every vertex away from the vertex centroid by the
+// given distance, giving route waypoints that clear the polygon's corners.
+func (p Polygon) offsetCorners(meters float64) []GeoPoint {
+	var center GeoPoint
+	for _, v := range p {
+		center.Lat += v.Lat / float64(len(p))
+		center.Lng += v.Lng / float64(len(p))
+	}
+	metersPerDegreeLng := metersPerDegreeLat * math.Cos(center.Lat*math.Pi/180)
+
+	corners := make([]GeoPoint, 0, len(p))
+	for _, v := range p {
+		dx := (v.Lng - center.Lng) * metersPerDegreeLng
+		dy := (v.Lat - center.Lat) * metersPerDegreeLat
+		norm := math.Hypot(dx, dy)
+		if norm == 0 {
+			continue
+		}
+		corners = append(corners, GeoPoint{
+			Lat: v.Lat + dy/norm*meters/metersPerDegreeLat,
+			Lng: v.Lng + dx/norm*meters/metersPerDegreeLng,
+		})
+	}
+	return corners
+}
+
+func segmentsIntersect(p1, p2, q1, q2 GeoPoint) bool {
+	d1 := orientation(q1, q2, p1)
+	d2 := orientation(q1, q2, p2)
+	d3 := orientation(p1, p2, q1)
+	d4 := orientation(p1, p2, q2)
+
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) &&
+		((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+
+	return (d1 == 0 && onSegment(q1, q2, p1)) ||
+		(d2 == 0 && onSegment(q1, q2, p2)) ||
+		(d3 == 0 && onSegment(p1, p2, q1)) ||
+		(d4 == 0 && onSegment(p1, p2, q2))
+}
+
+// orientation is the cross product sign of (b-a) x (c-a) on the lng/lat plane.
+func orientation(a, b, c GeoPoint) float64 {
+	return (b.Lng-a.Lng)*(c.Lat-a.Lat) - (b.Lat-a.Lat)*(c.Lng-a.Lng)
+}
+
+func onSegment(a, b, c GeoPoint) bool {
+	return math.Min(a.Lng, b.Lng) <= c.Lng && c.Lng <= math.Max(a.Lng, b.Lng) &&
+		math.Min(a.Lat, b.Lat) <= c.Lat && c.Lat <= math.Max(a.Lat, b.Lat)
+}
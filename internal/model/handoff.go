@@ -0,0 +1,56 @@
+package model
+
+import "errors"
+
+// HandoffRendezvous is where the parcels of a drone that broke down wait for
+// the replacement: where it broke down, or the nearest landing site.
+type HandoffRendezvous string
+
+const (
+	HandoffAtBreakdown   HandoffRendezvous = "breakdown"
+	HandoffAtNearestSite HandoffRendezvous = "nearest_site"
+)
+
+func ParseHandoffRendezvous(s string) (HandoffRendezvous, error) {
+	switch r := HandoffRendezvous(s); r {
+	case HandoffAtBreakdown, HandoffAtNearestSite:
+		return r, nil
+	}
+	return "", errors.New("handoff rendezvous must be breakdown or nearest_site")
+}
+
+// HandoffPolicy picks the rendezvous for handoffs. MaxDetourKm caps how far a
+// drone that just broke down is sent to reach a landing site.
+type HandoffPolicy struct {
+	Rendezvous  HandoffRendezvous
+	MaxDetourKm float64
+}
+
+/*
+HandoffPoint: the breakdown point itself, or with nearest_site the active
+landing site closest along the path planned around the airspace, as long as
+it lies within MaxDetourKm and outside every zone in effect. The chosen site
+is returned too, nil when the parcels stay at the breakdown point.
+*/
+func (p HandoffPolicy) HandoffPoint(brokeAt GeoPoint, sites []LandingSite, airspace Airspace) (GeoPoint, *LandingSite) {
+	if p.Rendezvous != HandoffAtNearestSite {
+		return brokeAt, nil
+	}
+
+	var best *LandingSite
+	bestKm := p.MaxDetourKm
+	for i := range sites {
+		site := &sites[i]
+		if !site.Active || airspace.ZoneAt(site.Lat, site.Lng) != nil {
+			continue
+		}
+		if km := airspace.DistanceKm(brokeAt.Lat, brokeAt.Lng, site.Lat, site.Lng); km <= bestKm {
+			best, bestKm = site, km
+		}
+	}
+
+	if best == nil {
+		return brokeAt, nil
+	}
+	return GeoPoint{Lat: best.Lat, Lng: best.Lng}, best
+}
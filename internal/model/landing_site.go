@@ -0,0 +1,67 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+const maxLandingSiteNameLength = 100
+
+// LandingSite is a place a drone that broke down can set down safely and
+// leave its parcels for the replacement: a rooftop pad, a locker bank, a
+// staffed yard.
+type LandingSite struct {
+	ID        int64
+	Name      string
+	Lat       float64
+	Lng       float64
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type UpdateLandingSiteRequest struct {
+	Name   *string
+	Lat    *float64
+	Lng    *float64
+	Active *bool
+}
+
+func NewLandingSite(name string, lat, lng float64) (*LandingSite, error) {
+	site := &LandingSite{Active: true}
+	if err := site.Update(UpdateLandingSiteRequest{Name: &name, Lat: &lat, Lng: &lng}); err != nil {
+		return nil, err
+	}
+	return site, nil
+}
+
+func (s *LandingSite) Update(req UpdateLandingSiteRequest) error {
+	if req.Name == nil && req.Lat == nil && req.Lng == nil && req.Active == nil {
+		return ErrInvalidLandingSite("no fields to update")
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || len(name) > maxLandingSiteNameLength {
+			return ErrInvalidLandingSite("name must be 1-100 characters")
+		}
+		s.Name = name
+	}
+
+	if req.Lat != nil || req.Lng != nil {
+		if req.Lat == nil || req.Lng == nil {
+			return ErrInvalidLandingSite("lat and lng must be given together")
+		}
+		lat, lng, err := validateRouteCoordinates(req.Lat, req.Lng)
+		if err != nil {
+			return err
+		}
+		s.Lat, s.Lng = lat, lng
+	}
+
+	if req.Active != nil {
+		s.Active = *req.Active
+	}
+
+	return nil
+}
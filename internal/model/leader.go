@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// LeaderLease is a time-bound claim by one API node to run the singleton
+// background jobs. Term grows each time another node takes over.
+type LeaderLease struct {
+	Name       string
+	Holder     string
+	Term       int64
+	AcquiredAt time.Time
+	RenewedAt  time.Time
+	ExpiresAt  time.Time
+	// Expired is judged by the database clock, which every node shares.
+	Expired bool
+}
+
+// HeldBy reports whether nodeID currently leads.
+func (l *LeaderLease) HeldBy(nodeID string) bool {
+	return l != nil && !l.Expired && l.Holder == nodeID
+}
+
+// LeaderStatus is one node's view of the election.
+type LeaderStatus struct {
+	NodeID   string
+	IsLeader bool
+	// Lease is nil until some node has campaigned.
+	Lease *LeaderLease
+	Jobs  []string
+}
@@ -0,0 +1,236 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	maxMaintenanceNotesLength = 2000
+	maxTechnicianLength       = 100
+	maxMaintenanceParts       = 50
+	maxPartNameLength         = 100
+
+	MaxServiceFlightHours = 10000.0
+	MaxServiceCycles      = 100000
+)
+
+type MaintenanceIssue string
+
+const (
+	// MaintenanceBreakdown records are opened when a drone is reported broken
+	// and closed when it is reported fixed.
+	MaintenanceBreakdown MaintenanceIssue = "breakdown"
+	// MaintenanceScheduled records are opened once a drone has flown its
+	// service interval.
+	MaintenanceScheduled  MaintenanceIssue = "scheduled"
+	MaintenanceInspection MaintenanceIssue = "inspection"
+	MaintenanceBattery    MaintenanceIssue = "battery"
+	MaintenancePropulsion MaintenanceIssue = "propulsion"
+	MaintenanceSensors    MaintenanceIssue = "sensors"
+	MaintenanceAirframe   MaintenanceIssue = "airframe"
+	MaintenanceOther      MaintenanceIssue = "other"
+)
+
+func IsValidMaintenanceIssue(issue MaintenanceIssue) bool {
+	switch issue {
+	case MaintenanceBreakdown, MaintenanceScheduled, MaintenanceInspection, MaintenanceBattery,
+		MaintenancePropulsion, MaintenanceSensors, MaintenanceAirframe, MaintenanceOther:
+		return true
+	}
+	return false
+}
+
+// MaintenanceRecord is one stretch of work on a drone, from the moment it
+// was taken out of service until it was put back (ClosedAt). A drone has at
+// most one open record.
+type MaintenanceRecord struct {
+	ID         int64
+	DroneID    int64
+	Issue      MaintenanceIssue
+	Notes      string
+	Parts      []string
+	Technician string
+	OpenedAt   time.Time
+	ClosedAt   *time.Time
+	UpdatedAt  time.Time
+}
+
+// MaintenanceDetails changes a record; nil fields are left as they are and
+// an empty Parts list clears the parts.
+type MaintenanceDetails struct {
+	Issue      *MaintenanceIssue
+	Notes      *string
+	Parts      []string
+	Technician *string
+}
+
+func (d MaintenanceDetails) IsEmpty() bool {
+	return d.Issue == nil && d.Notes == nil && d.Parts == nil && d.Technician == nil
+}
+
+type MaintenanceFilter struct {
+	DroneID *int64
+	Open    *bool
+}
+
+func NewMaintenanceRecord(droneID int64, issue MaintenanceIssue, details MaintenanceDetails, now time.Time) (*MaintenanceRecord, error) {
+	details.Issue = &issue
+	record := &MaintenanceRecord{DroneID: droneID, OpenedAt: now}
+	if err := record.apply(details); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (r *MaintenanceRecord) IsOpen() bool {
+	return r.ClosedAt == nil
+}
+
+// Update edits the record, open or closed.
+func (r *MaintenanceRecord) Update(details MaintenanceDetails) error {
+	if details.IsEmpty() {
+		return ErrInvalidMaintenance("no fields to update")
+	}
+	return r.apply(details)
+}
+
+// Close ends the work, taking any last details along.
+func (r *MaintenanceRecord) Close(details MaintenanceDetails, now time.Time) error {
+	if !r.IsOpen() {
+		return ErrMaintenanceClosed()
+	}
+	if err := r.apply(details); err != nil {
+		return err
+	}
+	r.ClosedAt = &now
+	return nil
+}
+
+func (r *MaintenanceRecord) apply(details MaintenanceDetails) error {
+	if details.Issue != nil {
+		if !IsValidMaintenanceIssue(*details.Issue) {
+			return ErrInvalidMaintenance("issue must be one of breakdown, scheduled, inspection, battery, propulsion, sensors, airframe, other")
+		}
+		r.Issue = *details.Issue
+	}
+
+	if details.Notes != nil {
+		notes := strings.TrimSpace(*details.Notes)
+		if len(notes) > maxMaintenanceNotesLength {
+			return ErrInvalidMaintenance("notes must be at most 2000 characters")
+		}
+		r.Notes = notes
+	}
+
+	if details.Parts != nil {
+		if len(details.Parts) > maxMaintenanceParts {
+			return ErrInvalidMaintenance("at most 50 parts")
+		}
+		parts := make([]string, 0, len(details.Parts))
+		for _, part := range details.Parts {
+			part = strings.TrimSpace(part)
+			if part == "" || len(part) > maxPartNameLength {
+				return ErrInvalidMaintenance("part names must be 1-100 characters")
+			}
+			parts = append(parts, part)
+		}
+		r.Parts = parts
+	}
+
+	if details.Technician != nil {
+		technician := strings.TrimSpace(*details.Technician)
+		if len(technician) > maxTechnicianLength {
+			return ErrInvalidMaintenance("technician must be at most 100 characters")
+		}
+		r.Technician = technician
+	}
+
+	return nil
+}
+
+// MaintenanceSchedule is a drone's own service interval. A nil field
+// follows the fleet policy and zero never calls for service by that measure.
+type MaintenanceSchedule struct {
+	FlightHours *float64
+	Cycles      *int
+}
+
+func (s MaintenanceSchedule) Validate() error {
+	if s.FlightHours != nil && (*s.FlightHours < 0 || *s.FlightHours > MaxServiceFlightHours) {
+		return ErrInvalidMaintenance("flight_hours must be between 0 and 10000")
+	}
+	if s.Cycles != nil && (*s.Cycles < 0 || *s.Cycles > MaxServiceCycles) {
+		return ErrInvalidMaintenance("cycles must be between 0 and 100000")
+	}
+	return nil
+}
+
+func (s MaintenanceSchedule) IsDefault() bool {
+	return s.FlightHours == nil && s.Cycles == nil
+}
+
+// MaintenancePolicy is the fleet's service interval: a drone is due once it
+// has flown FlightHours or finished Cycles trips since its last service.
+// Zero turns a measure off. Due drones are checked every CheckInterval and
+// whenever they finish a trip.
+type MaintenancePolicy struct {
+	FlightHours   float64
+	Cycles        int
+	CheckInterval time.Duration
+}
+
+// MaintenanceUsage is what a drone has flown since its last service (the
+// last closed record), or ever when it was never serviced. Every trip that
+// ended, completed or aborted, counts as a cycle, and its flight time runs
+// from the reservation that started it.
+type MaintenanceUsage struct {
+	DroneID       int64
+	Status        DroneStatus
+	LastServiceAt *time.Time
+	FlightTime    time.Duration
+	Cycles        int
+	Schedule      MaintenanceSchedule
+}
+
+func (u MaintenanceUsage) FlightHours() float64 {
+	return u.FlightTime.Hours()
+}
+
+// Interval is the service interval that applies to the drone.
+func (p MaintenancePolicy) Interval(s MaintenanceSchedule) (flightHours float64, cycles int) {
+	flightHours, cycles = p.FlightHours, p.Cycles
+	if s.FlightHours != nil {
+		flightHours = *s.FlightHours
+	}
+	if s.Cycles != nil {
+		cycles = *s.Cycles
+	}
+	return flightHours, cycles
+}
+
+// Due tells whether the drone has flown its service interval.
+func (p MaintenancePolicy) Due(u MaintenanceUsage) bool {
+	flightHours, cycles := p.Interval(u.Schedule)
+	return (flightHours > 0 && u.FlightHours() >= flightHours) || (cycles > 0 && u.Cycles >= cycles)
+}
+
+// MaintenanceHealth is a drone's standing against its service interval.
+type MaintenanceHealth struct {
+	Usage              MaintenanceUsage
+	ServiceFlightHours float64
+	ServiceCycles      int
+	Due                bool
+	OpenRecord         *MaintenanceRecord
+}
+
+func (p MaintenancePolicy) Health(u MaintenanceUsage, open *MaintenanceRecord) MaintenanceHealth {
+	flightHours, cycles := p.Interval(u.Schedule)
+	return MaintenanceHealth{
+		Usage:              u,
+		ServiceFlightHours: flightHours,
+		ServiceCycles:      cycles,
+		Due:                p.Due(u),
+		OpenRecord:         open,
+	}
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+const maxNoFlyZoneNameLength = 100
+
+// NoFlyZone is restricted airspace drones must route around. Zones without
+// StartsAt/EndsAt are permanent (airports); bounded ones cover temporary
+// restrictions such as stadium events.
+type NoFlyZone struct {
+	ID        int64
+	Name      string
+	Boundary  Polygon
+	StartsAt  *time.Time
+	EndsAt    *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type CreateNoFlyZoneRequest struct {
+	Name     string
+	Boundary Polygon
+	StartsAt *time.Time
+	EndsAt   *time.Time
+}
+
+// UpdateNoFlyZoneRequest changes the provided fields; a zero StartsAt or
+// EndsAt clears that bound.
+type UpdateNoFlyZoneRequest struct {
+	Name     *string
+	Boundary Polygon
+	StartsAt *time.Time
+	EndsAt   *time.Time
+}
+
+func NewNoFlyZone(req CreateNoFlyZoneRequest) (*NoFlyZone, error) {
+	zone := &NoFlyZone{}
+	err := zone.Update(UpdateNoFlyZoneRequest{
+		Name:     &req.Name,
+		Boundary: req.Boundary,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if zone.Boundary == nil {
+		return nil, ErrInvalidPolygon("polygon needs at least 3 vertices")
+	}
+	return zone, nil
+}
+
+func (z *NoFlyZone) Update(req UpdateNoFlyZoneRequest) error {
+	if req.Name == nil && req.Boundary == nil && req.StartsAt == nil && req.EndsAt == nil {
+		return ErrInvalidNoFlyZone("no fields to update")
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || len(name) > maxNoFlyZoneNameLength {
+			return ErrInvalidNoFlyZone("name must be 1-100 characters")
+		}
+		z.Name = name
+	}
+
+	if req.Boundary != nil {
+		boundary := req.Boundary.Normalize()
+		if err := boundary.Validate(); err != nil {
+			return err
+		}
+		z.Boundary = boundary
+	}
+
+	if req.StartsAt != nil {
+		z.StartsAt = optionalTime(*req.StartsAt)
+	}
+	if req.EndsAt != nil {
+		z.EndsAt = optionalTime(*req.EndsAt)
+	}
+	if z.StartsAt != nil && z.EndsAt != nil && !z.EndsAt.After(*z.StartsAt) {
+		return ErrInvalidNoFlyZone("ends_at must be after starts_at")
+	}
+
+	return nil
+}
+
+func (z *NoFlyZone) ActiveAt(t time.Time) bool {
+	if z.StartsAt != nil && t.Before(*z.StartsAt) {
+		return false
+	}
+	if z.EndsAt != nil && !t.Before(*z.EndsAt) {
+		return false
+	}
+	return true
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
@@ -5,18 +5,63 @@ import (
 )
 
 type CreateOrderRequest struct {
-	EnduserID  int64
-	PickupLat  float64
-	PickupLng  float64
-	DropoffLat float64
-	DropoffLng float64
+	EnduserID          int64
+	PickupAddressID    *int64
+	DropoffAddressID   *int64
+	PickupQuery        *string
+	DropoffQuery       *string
+	PickupLat          float64
+	PickupLng          float64
+	DropoffLat         float64
+	DropoffLng         float64
+	DeliveryNotes      *string
+	AccessInstructions *string
+}
+
+// UsePickupAddress snapshots a saved address as the order's origin.
+func (r *CreateOrderRequest) UsePickupAddress(address Address) {
+	r.PickupAddressID = &address.ID
+	r.PickupLat = address.Lat
+	r.PickupLng = address.Lng
+}
+
+func (r *CreateOrderRequest) UsePickupPlace(place Place) {
+	r.PickupLat = place.Lat
+	r.PickupLng = place.Lng
+}
+
+func (r *CreateOrderRequest) UseDropoffPlace(place Place) {
+	r.DropoffLat = place.Lat
+	r.DropoffLng = place.Lng
+}
+
+// UseDropoffAddress snapshots a saved address, notes included, as the
+// order's destination.
+func (r *CreateOrderRequest) UseDropoffAddress(address Address) {
+	r.DropoffAddressID = &address.ID
+	r.DropoffLat = address.Lat
+	r.DropoffLng = address.Lng
+	r.DeliveryNotes = address.DeliveryNotes
+	r.AccessInstructions = address.AccessInstructions
 }
 
 type UpdateRouteRequest struct {
-	PickupLat  *float64
-	PickupLng  *float64
-	DropoffLat *float64
-	DropoffLng *float64
+	PickupQuery  *string
+	DropoffQuery *string
+	PickupLat    *float64
+	PickupLng    *float64
+	DropoffLat   *float64
+	DropoffLng   *float64
+}
+
+func (r *UpdateRouteRequest) UsePickupPlace(place Place) {
+	r.PickupLat = &place.Lat
+	r.PickupLng = &place.Lng
+}
+
+func (r *UpdateRouteRequest) UseDropoffPlace(place Place) {
+	r.DropoffLat = &place.Lat
+	r.DropoffLng = &place.Lng
 }
 
 type DroneLocation struct {
@@ -28,9 +73,15 @@ type OrderDetails struct {
 	Order         Order
 	DroneLocation *DroneLocation
 	ETA           *ETA
+	Legs          []TripLegETA
+	PickupPlace   *Place
+	DropoffPlace  *Place
 }
 
-func NewOrderDetails(order Order, drone *Drone) OrderDetails {
+// NewOrderDetails prefers the drone's trip plan for ETAs so that stops for
+// other orders sharing the trip are accounted for; trip may be nil. Distances
+// follow the planned path around the airspace.
+func NewOrderDetails(order Order, drone *Drone, trip *Trip, airspace Airspace) OrderDetails {
 	details := OrderDetails{
 		Order: order,
 	}
@@ -41,7 +92,14 @@ func NewOrderDetails(order Order, drone *Drone) OrderDetails {
 			Lng: drone.Lng,
 		}
 
-		eta := CalculateETA(drone, &order)
+		if trip != nil && trip.IsActive() {
+			details.Legs = trip.LegETAs(drone, order.ID, airspace)
+		}
+
+		eta := CalculateETA(drone, &order, airspace)
+		if len(details.Legs) > 0 {
+			eta = details.Legs[len(details.Legs)-1].ETA
+		}
 		if eta > 0 {
 			details.ETA = &eta
 		}
@@ -60,6 +118,8 @@ const (
 	OrderDelivered      OrderStatus = "delivered"
 	OrderFailed         OrderStatus = "failed"
 	OrderCanceled       OrderStatus = "canceled"
+	OrderReturning      OrderStatus = "returning"
+	OrderReturned       OrderStatus = "returned"
 )
 
 var allowedOrderTransitions = map[OrderStatus][]OrderStatus{
@@ -69,36 +129,53 @@ var allowedOrderTransitions = map[OrderStatus][]OrderStatus{
 	},
 	OrderReserved: {
 		OrderPickedUp,
+		OrderReturning,
 		OrderFailed,
 	},
 	OrderPickedUp: {
 		OrderHandoffPending,
 		OrderDelivered,
+		OrderReturning,
 		OrderFailed,
 	},
 	OrderHandoffPending: {
 		OrderReserved,
 		OrderFailed,
 	},
+	OrderReturning: {
+		OrderHandoffPending,
+		OrderReturned,
+		OrderFailed,
+	},
 	OrderDelivered: {},
 	OrderFailed:    {},
 	OrderCanceled:  {},
+	OrderReturned:  {},
 }
 
 type Order struct {
-	ID              int64
-	EnduserID       int64
-	AssignedDroneID *int64
-	PickupLat       float64
-	PickupLng       float64
-	DropoffLat      float64
-	DropoffLng      float64
-	HandoffLat      *float64
-	HandoffLng      *float64
-	Status          OrderStatus
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
-	CanceledAt      *time.Time
+	ID                 int64
+	EnduserID          int64
+	AssignedDroneID    *int64
+	OfferedDroneID     *int64
+	PickupAddressID    *int64
+	DropoffAddressID   *int64
+	PickupLat          float64
+	PickupLng          float64
+	DropoffLat         float64
+	DropoffLng         float64
+	DeliveryNotes      *string
+	AccessInstructions *string
+	HandoffLat         *float64
+	HandoffLng         *float64
+	ReturnLat          *float64
+	ReturnLng          *float64
+	DeliveryPIN        *string
+	PINAttempts        int
+	Status             OrderStatus
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	CanceledAt         *time.Time
 }
 
 func (o *Order) BelongsTo(userID int64) error {
@@ -108,6 +185,11 @@ func (o *Order) BelongsTo(userID int64) error {
 	return nil
 }
 
+func (o *Order) IsTerminal() bool {
+	transitions, exists := allowedOrderTransitions[o.Status]
+	return exists && len(transitions) == 0
+}
+
 func (o *Order) IsAssignedTo(droneID int64) error {
 	if o.AssignedDroneID == nil || *o.AssignedDroneID != droneID {
 		return ErrOrderNotAssignedToDrone()
@@ -115,14 +197,27 @@ func (o *Order) IsAssignedTo(droneID int64) error {
 	return nil
 }
 
+// AffectedDroneID is the drone that has to hear about changes to the order:
+// the one it is assigned to or, while pending, the one it was last offered to.
+func (o *Order) AffectedDroneID() *int64 {
+	if o.AssignedDroneID != nil {
+		return o.AssignedDroneID
+	}
+	return o.OfferedDroneID
+}
+
 func NewOrder(req CreateOrderRequest) *Order {
 	return &Order{
-		EnduserID:  req.EnduserID,
-		PickupLat:  req.PickupLat,
-		PickupLng:  req.PickupLng,
-		DropoffLat: req.DropoffLat,
-		DropoffLng: req.DropoffLng,
-		Status:     OrderPending,
+		EnduserID:          req.EnduserID,
+		PickupAddressID:    req.PickupAddressID,
+		DropoffAddressID:   req.DropoffAddressID,
+		PickupLat:          req.PickupLat,
+		PickupLng:          req.PickupLng,
+		DropoffLat:         req.DropoffLat,
+		DropoffLng:         req.DropoffLng,
+		DeliveryNotes:      req.DeliveryNotes,
+		AccessInstructions: req.AccessInstructions,
+		Status:             OrderPending,
 	}
 }
 
@@ -163,23 +258,83 @@ func (o *Order) Deliver() error {
 }
 
 func (o *Order) Pickup() error {
+	if o.IsReturn() {
+		return o.UpdateStatus(OrderReturning)
+	}
 	return o.UpdateStatus(OrderPickedUp)
 }
 
+// Fail sends a parcel that is already on board back to its origin instead of
+// dropping it; orders that were never picked up fail terminally.
 func (o *Order) Fail() error {
+	if o.Status == OrderPickedUp {
+		return o.StartReturn()
+	}
 	return o.UpdateStatus(OrderFailed)
 }
 
+func (o *Order) StartReturn() error {
+	if err := o.UpdateStatus(OrderReturning); err != nil {
+		return err
+	}
+	returnLat := o.PickupLat
+	returnLng := o.PickupLng
+	o.ReturnLat = &returnLat
+	o.ReturnLng = &returnLng
+	return nil
+}
+
+func (o *Order) ConfirmReturn() error {
+	return o.UpdateStatus(OrderReturned)
+}
+
+func (o *Order) IsReturn() bool {
+	return o.ReturnLat != nil && o.ReturnLng != nil
+}
+
+func (o *Order) IsReturning() bool {
+	return o.Status == OrderReturning
+}
+
+// PickupPoint is where the assigned drone collects the parcel: the handoff
+// location when a broken drone left it mid-route, otherwise the origin.
+func (o *Order) PickupPoint() (float64, float64) {
+	if o.HandoffLat != nil && o.HandoffLng != nil {
+		return *o.HandoffLat, *o.HandoffLng
+	}
+	return o.PickupLat, o.PickupLng
+}
+
+func (o *Order) DestinationPoint() (float64, float64) {
+	if o.IsReturn() {
+		return *o.ReturnLat, *o.ReturnLng
+	}
+	return o.DropoffLat, o.DropoffLng
+}
+
+func (o *Order) DestinationKind() TripStopKind {
+	if o.IsReturn() {
+		return StopReturn
+	}
+	return StopDropoff
+}
+
+// HandoffOrder releases the order from its drone. A parcel on board waits
+// for the next drone at the handoff point; one not collected yet stays where
+// it is, at its origin or at the rendezvous of an earlier handoff.
 func (o *Order) HandoffOrder(handoffLat, handoffLng float64) bool {
 	switch o.Status {
-	case OrderPending, OrderReserved:
+	case OrderPending, OrderReserved, OrderHandoffPending:
 		o.AssignedDroneID = nil
+		o.OfferedDroneID = nil
 		o.Status = OrderPending
-		o.HandoffLat = nil
-		o.HandoffLng = nil
+		if o.HandoffLat != nil && o.HandoffLng != nil {
+			o.Status = OrderHandoffPending
+		}
 		return true
-	case OrderPickedUp, OrderHandoffPending:
+	case OrderPickedUp, OrderReturning:
 		o.AssignedDroneID = nil
+		o.OfferedDroneID = nil
 		o.HandoffLat = &handoffLat
 		o.HandoffLng = &handoffLng
 		o.Status = OrderHandoffPending
@@ -189,7 +344,9 @@ func (o *Order) HandoffOrder(handoffLat, handoffLng float64) bool {
 	}
 }
 
-func (o *Order) UpdateRoute(req UpdateRouteRequest) error {
+// UpdateRoute moves the pickup and/or dropoff of a pending order; each moved
+// endpoint must stay inside the service coverage and outside no-fly zones.
+func (o *Order) UpdateRoute(req UpdateRouteRequest, coverage ServiceCoverage, airspace Airspace) error {
 	if o.Status != OrderPending {
 		return ErrOrderRouteLocked()
 	}
@@ -205,8 +362,15 @@ func (o *Order) UpdateRoute(req UpdateRouteRequest) error {
 		if err != nil {
 			return err
 		}
+		if err := coverage.CheckPickup(lat, lng); err != nil {
+			return err
+		}
+		if err := airspace.CheckPickup(lat, lng); err != nil {
+			return err
+		}
 		o.PickupLat = lat
 		o.PickupLng = lng
+		o.PickupAddressID = nil
 	}
 
 	if hasDropoff {
@@ -214,8 +378,18 @@ func (o *Order) UpdateRoute(req UpdateRouteRequest) error {
 		if err != nil {
 			return err
 		}
+		if err := coverage.CheckDropoff(lat, lng); err != nil {
+			return err
+		}
+		if err := airspace.CheckDropoff(lat, lng); err != nil {
+			return err
+		}
 		o.DropoffLat = lat
 		o.DropoffLng = lng
+		// the saved address's notes describe the old destination
+		o.DropoffAddressID = nil
+		o.DeliveryNotes = nil
+		o.AccessInstructions = nil
 	}
 
 	o.HandoffLat = nil
@@ -1,13 +1,27 @@
 package model
 
+import "time"
+
+// OrderListFilters narrows order listings; CreatedFrom and CreatedTo are both
+// inclusive bounds on the order's creation time.
 type OrderListFilters struct {
 	Status          *OrderStatus
 	EnduserID       *int64
 	AssignedDroneID *int64
+	CreatedFrom     *time.Time
+	CreatedTo       *time.Time
 }
 
 func (f OrderListFilters) HasAssignedFilters() bool {
-	return f.Status != nil || f.EnduserID != nil || f.AssignedDroneID != nil
+	return f.Status != nil || f.EnduserID != nil || f.AssignedDroneID != nil ||
+		f.CreatedFrom != nil || f.CreatedTo != nil
+}
+
+func (f OrderListFilters) Validate() error {
+	if f.CreatedFrom != nil && f.CreatedTo != nil && f.CreatedFrom.After(*f.CreatedTo) {
+		return ErrInvalidDateRange()
+	}
+	return nil
 }
 
 func IsValidOrderStatus(status OrderStatus) bool {
@@ -0,0 +1,34 @@
+package model
+
+import "strings"
+
+// Place is a named location produced by a geocoder.
+type Place struct {
+	Name string
+	Lat  float64
+	Lng  float64
+}
+
+func (p Place) DistanceMeters(lat, lng float64) float64 {
+	return haversineDistance(p.Lat, p.Lng, lat, lng) * metersPerKilometer
+}
+
+// NormalizeAddressQuery lowercases free text and collapses punctuation and
+// whitespace so "Abdali  Boulevard," and "abdali boulevard" match and share a
+// cache entry.
+func NormalizeAddressQuery(query string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(query) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r > 127 {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+		space = true
+	}
+	return b.String()
+}
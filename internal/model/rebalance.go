@@ -0,0 +1,434 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	kmPerDegreeLat = 111.32
+	// minCellCos keeps cells near the poles from growing without bound
+	minCellCos = 0.01
+	// MaxRebalanceReportWindow caps the span of a wait report.
+	MaxRebalanceReportWindow     = 31 * 24 * time.Hour
+	defaultRebalanceReportWindow = 24 * time.Hour
+)
+
+// RebalancePolicy tunes pre-positioning: demand is the pickups of the last
+// Lookback placed in the same hour of the day, bucketed into CellKm cells,
+// and a run every Interval moves Aggressiveness (0..1) of the idle drones
+// that are out of place.
+type RebalancePolicy struct {
+	Aggressiveness float64
+	CellKm         float64
+	Lookback       time.Duration
+	Interval       time.Duration
+}
+
+// RebalanceRequest runs the rebalancer on demand. A nil Aggressiveness
+// falls back to the configured one; a DryRun only plans.
+type RebalanceRequest struct {
+	Aggressiveness *float64
+	DryRun         bool
+}
+
+func ValidateAggressiveness(a float64) error {
+	if a < 0 || a > 1 || math.IsNaN(a) {
+		return ErrInvalidRebalance("aggressiveness must be between 0 and 1")
+	}
+	return nil
+}
+
+// DemandGrid buckets points into cells about CellKm on a side: rows are
+// CellKm of latitude, columns CellKm of longitude at the row's middle.
+type DemandGrid struct {
+	CellKm float64
+}
+
+type GridCell struct {
+	Row, Col int
+}
+
+func (g DemandGrid) latStep() float64 {
+	return g.CellKm / kmPerDegreeLat
+}
+
+func (g DemandGrid) lngStep(row int) float64 {
+	middleLat := (float64(row) + 0.5) * g.latStep()
+	return g.CellKm / (kmPerDegreeLat * math.Max(math.Cos(middleLat*math.Pi/180), minCellCos))
+}
+
+func (g DemandGrid) CellOf(lat, lng float64) GridCell {
+	row := int(math.Floor(lat / g.latStep()))
+	return GridCell{Row: row, Col: int(math.Floor(lng / g.lngStep(row)))}
+}
+
+// Bounds are the cell's south-west and north-east corners; a cell holds
+// the points with min <= p < max.
+func (g DemandGrid) Bounds(c GridCell) (GeoPoint, GeoPoint) {
+	latStep, lngStep := g.latStep(), g.lngStep(c.Row)
+	return GeoPoint{Lat: float64(c.Row) * latStep, Lng: float64(c.Col) * lngStep},
+		GeoPoint{Lat: float64(c.Row+1) * latStep, Lng: float64(c.Col+1) * lngStep}
+}
+
+func (g DemandGrid) Center(c GridCell) GeoPoint {
+	sw, ne := g.Bounds(c)
+	return GeoPoint{Lat: (sw.Lat + ne.Lat) / 2, Lng: (sw.Lng + ne.Lng) / 2}
+}
+
+// PickupCount is how many past orders were picked up around a point.
+type PickupCount struct {
+	Lat, Lng float64
+	Orders   int
+}
+
+// CellDemand is the orders a cell is expected to see in the coming hour.
+type CellDemand struct {
+	Cell          GridCell
+	OrdersPerHour float64
+}
+
+// ForecastDemand buckets past pickups into cells, spread over the days of
+// history they cover, busiest cell first.
+func (g DemandGrid) ForecastDemand(pickups []PickupCount, days float64) []CellDemand {
+	byCell := map[GridCell]int{}
+	for _, p := range pickups {
+		byCell[g.CellOf(p.Lat, p.Lng)] += p.Orders
+	}
+
+	demand := make([]CellDemand, 0, len(byCell))
+	for cell, orders := range byCell {
+		demand = append(demand, CellDemand{Cell: cell, OrdersPerHour: float64(orders) / max(days, 1)})
+	}
+	sort.Slice(demand, func(i, j int) bool {
+		if demand[i].OrdersPerHour != demand[j].OrdersPerHour {
+			return demand[i].OrdersPerHour > demand[j].OrdersPerHour
+		}
+		if demand[i].Cell.Row != demand[j].Cell.Row {
+			return demand[i].Cell.Row < demand[j].Cell.Row
+		}
+		return demand[i].Cell.Col < demand[j].Cell.Col
+	})
+	return demand
+}
+
+// RebalanceMove sends an idle drone to wait in another cell. CommandID is
+// the reposition command once it has been issued.
+type RebalanceMove struct {
+	DroneID    int64
+	From, To   GeoPoint
+	DistanceKm float64
+	CommandID  *int64
+}
+
+// CellForecast is what a run expects of a cell with demand: its share of
+// the idle drones and, with them in place, how long the nearest would take
+// to reach the middle of the cell. PredictedWait is nil without drones.
+type CellForecast struct {
+	Cell          GridCell
+	Min, Max      GeoPoint
+	Center        GeoPoint
+	OrdersPerHour float64
+	IdleDrones    int
+	TargetDrones  int
+	PredictedWait *time.Duration
+}
+
+type RebalancePlan struct {
+	Hour           int
+	Aggressiveness float64
+	IdleDrones     int
+	Cells          []CellForecast
+	Moves          []RebalanceMove
+	ComputedAt     time.Time
+}
+
+/*
+PlanRebalance spreads the idle drones over the cells in proportion to their
+demand (largest remainder, busier cells first on ties). Drones beyond their
+cell's share, the farthest from its middle first, fill the cells short of
+theirs by shortest flight first, and only aggressiveness of those moves is
+made; the rest wait for a later run.
+*/
+func PlanRebalance(grid DemandGrid, demand []CellDemand, drones []Drone, aggressiveness float64) RebalancePlan {
+	plan := RebalancePlan{Aggressiveness: aggressiveness, IdleDrones: len(drones)}
+
+	targets := targetDrones(demand, len(drones))
+	target := make(map[GridCell]int, len(demand))
+	for i, d := range demand {
+		target[d.Cell] = targets[i]
+	}
+
+	inCell := map[GridCell][]Drone{}
+	for _, d := range drones {
+		cell := grid.CellOf(d.Lat, d.Lng)
+		inCell[cell] = append(inCell[cell], d)
+	}
+
+	var surplus []Drone
+	for cell, cellDrones := range inCell {
+		if len(cellDrones) <= target[cell] {
+			continue
+		}
+		center := grid.Center(cell)
+		sort.Slice(cellDrones, func(i, j int) bool {
+			di := haversineDistance(cellDrones[i].Lat, cellDrones[i].Lng, center.Lat, center.Lng)
+			dj := haversineDistance(cellDrones[j].Lat, cellDrones[j].Lng, center.Lat, center.Lng)
+			if di != dj {
+				return di < dj
+			}
+			return cellDrones[i].ID < cellDrones[j].ID
+		})
+		surplus = append(surplus, cellDrones[target[cell]:]...)
+	}
+
+	var slots []GeoPoint
+	for _, d := range demand {
+		for range target[d.Cell] - len(inCell[d.Cell]) {
+			slots = append(slots, grid.Center(d.Cell))
+		}
+	}
+
+	budget := int(math.Round(aggressiveness * float64(len(slots))))
+	plan.Moves = nearestMoves(surplus, slots, budget)
+
+	positions := make([]GeoPoint, 0, len(drones))
+	moved := make(map[int64]GeoPoint, len(plan.Moves))
+	for _, m := range plan.Moves {
+		moved[m.DroneID] = m.To
+	}
+	for _, d := range drones {
+		if to, ok := moved[d.ID]; ok {
+			positions = append(positions, to)
+			continue
+		}
+		positions = append(positions, GeoPoint{Lat: d.Lat, Lng: d.Lng})
+	}
+
+	plan.Cells = make([]CellForecast, len(demand))
+	for i, d := range demand {
+		sw, ne := grid.Bounds(d.Cell)
+		center := grid.Center(d.Cell)
+		plan.Cells[i] = CellForecast{
+			Cell:          d.Cell,
+			Min:           sw,
+			Max:           ne,
+			Center:        center,
+			OrdersPerHour: d.OrdersPerHour,
+			IdleDrones:    len(inCell[d.Cell]),
+			TargetDrones:  targets[i],
+			PredictedWait: nearestFlight(positions, center),
+		}
+	}
+	return plan
+}
+
+func targetDrones(demand []CellDemand, drones int) []int {
+	targets := make([]int, len(demand))
+	total := 0.0
+	for _, d := range demand {
+		total += d.OrdersPerHour
+	}
+	if total == 0 || drones == 0 {
+		return targets
+	}
+
+	assigned := 0
+	remainders := make([]int, len(demand))
+	shares := make([]float64, len(demand))
+	for i, d := range demand {
+		shares[i] = float64(drones) * d.OrdersPerHour / total
+		targets[i] = int(shares[i])
+		assigned += targets[i]
+		remainders[i] = i
+	}
+	sort.SliceStable(remainders, func(a, b int) bool {
+		i, j := remainders[a], remainders[b]
+		return shares[i]-float64(targets[i]) > shares[j]-float64(targets[j])
+	})
+	for k := 0; assigned < drones && k < len(remainders); k++ {
+		targets[remainders[k]]++
+		assigned++
+	}
+	return targets
+}
+
+// nearestMoves pairs drones with slots, shortest flight first, up to limit.
+func nearestMoves(drones []Drone, slots []GeoPoint, limit int) []RebalanceMove {
+	type pair struct {
+		drone, slot int
+		km          float64
+	}
+	pairs := make([]pair, 0, len(drones)*len(slots))
+	for i, d := range drones {
+		for j, s := range slots {
+			pairs = append(pairs, pair{drone: i, slot: j, km: haversineDistance(d.Lat, d.Lng, s.Lat, s.Lng)})
+		}
+	}
+	sort.SliceStable(pairs, func(a, b int) bool { return pairs[a].km < pairs[b].km })
+
+	var moves []RebalanceMove
+	usedDrone := make([]bool, len(drones))
+	usedSlot := make([]bool, len(slots))
+	for _, p := range pairs {
+		if len(moves) >= limit {
+			break
+		}
+		if usedDrone[p.drone] || usedSlot[p.slot] {
+			continue
+		}
+		usedDrone[p.drone], usedSlot[p.slot] = true, true
+		d := drones[p.drone]
+		moves = append(moves, RebalanceMove{
+			DroneID:    d.ID,
+			From:       GeoPoint{Lat: d.Lat, Lng: d.Lng},
+			To:         slots[p.slot],
+			DistanceKm: p.km,
+		})
+	}
+	return moves
+}
+
+// nearestFlight is how long the nearest drone takes to reach the point at
+// cruise speed.
+func nearestFlight(positions []GeoPoint, to GeoPoint) *time.Duration {
+	if len(positions) == 0 {
+		return nil
+	}
+	nearestKm := math.Inf(1)
+	for _, p := range positions {
+		nearestKm = math.Min(nearestKm, haversineDistance(p.Lat, p.Lng, to.Lat, to.Lng))
+	}
+	wait := time.Duration(nearestKm * metersPerKilometer / DroneCruiseSpeedMPS * float64(time.Second)).Round(time.Second)
+	return &wait
+}
+
+// ForecastOutcome is a stored cell forecast with the orders placed in its
+// cell while it stood: how many, how many were picked up and their summed
+// wait from placement to pickup.
+type ForecastOutcome struct {
+	Min, Max      GeoPoint
+	OrdersPerHour float64
+	Window        time.Duration
+	PredictedWait *time.Duration
+	Orders        int
+	PickedUp      int
+	WaitTotal     time.Duration
+}
+
+// WaitComparison sets the predicted wait against the one orders saw. The
+// prediction is weighted by the orders picked up under each forecast, or
+// plainly averaged when none were.
+type WaitComparison struct {
+	Forecasts      int
+	ExpectedOrders float64
+	Orders         int
+	PickedUp       int
+	PredictedWait  *time.Duration
+	ActualWait     *time.Duration
+}
+
+type CellWaitReport struct {
+	Min, Max GeoPoint
+	WaitComparison
+}
+
+type RebalanceReport struct {
+	From, To time.Time
+	Overall  WaitComparison
+	Cells    []CellWaitReport
+}
+
+type waitTally struct {
+	comparison                   WaitComparison
+	weightedPrediction, weighted float64
+	plainPrediction              float64
+	plain                        int
+	waitTotal                    time.Duration
+}
+
+func (t *waitTally) add(o ForecastOutcome) {
+	t.comparison.Forecasts++
+	t.comparison.ExpectedOrders += o.OrdersPerHour * o.Window.Hours()
+	t.comparison.Orders += o.Orders
+	t.comparison.PickedUp += o.PickedUp
+	t.waitTotal += o.WaitTotal
+	if o.PredictedWait != nil {
+		t.plainPrediction += o.PredictedWait.Seconds()
+		t.plain++
+		t.weightedPrediction += o.PredictedWait.Seconds() * float64(o.PickedUp)
+		t.weighted += float64(o.PickedUp)
+	}
+}
+
+func (t *waitTally) result() WaitComparison {
+	c := t.comparison
+	switch {
+	case t.weighted > 0:
+		c.PredictedWait = roundedSeconds(t.weightedPrediction / t.weighted)
+	case t.plain > 0:
+		c.PredictedWait = roundedSeconds(t.plainPrediction / float64(t.plain))
+	}
+	if c.PickedUp > 0 {
+		c.ActualWait = roundedSeconds(t.waitTotal.Seconds() / float64(c.PickedUp))
+	}
+	return c
+}
+
+func roundedSeconds(s float64) *time.Duration {
+	d := time.Duration(s * float64(time.Second)).Round(time.Second)
+	return &d
+}
+
+// RebalanceReportWindow defaults to the last 24 hours before to (or now)
+// and caps the span at MaxRebalanceReportWindow.
+func RebalanceReportWindow(from, to *time.Time, now time.Time) (time.Time, time.Time, error) {
+	end := now.UTC()
+	if to != nil {
+		end = to.UTC()
+	}
+	start := end.Add(-defaultRebalanceReportWindow)
+	if from != nil {
+		start = from.UTC()
+	}
+
+	if start.After(end) {
+		return time.Time{}, time.Time{}, ErrInvalidDateRange()
+	}
+	if end.Sub(start) > MaxRebalanceReportWindow {
+		return time.Time{}, time.Time{}, ErrInvalidRebalance("the report covers at most 31 days")
+	}
+	return start, end, nil
+}
+
+// BuildRebalanceReport totals the outcomes per cell, busiest cell first.
+func BuildRebalanceReport(from, to time.Time, outcomes []ForecastOutcome) RebalanceReport {
+	var overall waitTally
+	byCell := map[GeoPoint]*waitTally{}
+	maxOf := map[GeoPoint]GeoPoint{}
+	var order []GeoPoint
+	for _, o := range outcomes {
+		overall.add(o)
+		tally, ok := byCell[o.Min]
+		if !ok {
+			tally = &waitTally{}
+			byCell[o.Min] = tally
+			maxOf[o.Min] = o.Max
+			order = append(order, o.Min)
+		}
+		tally.add(o)
+	}
+
+	report := RebalanceReport{From: from, To: to, Overall: overall.result(), Cells: make([]CellWaitReport, len(order))}
+	for i, sw := range order {
+		report.Cells[i] = CellWaitReport{Min: sw, Max: maxOf[sw], WaitComparison: byCell[sw].result()}
+	}
+	sort.SliceStable(report.Cells, func(i, j int) bool {
+		if report.Cells[i].Orders != report.Cells[j].Orders {
+			return report.Cells[i].Orders > report.Cells[j].Orders
+		}
+		return report.Cells[i].ExpectedOrders > report.Cells[j].ExpectedOrders
+	})
+	return report
+}
@@ -0,0 +1,151 @@
+package model
+
+import "math"
+
+const (
+	// routeClearanceMeters is how far outside a zone corner the planner
+	// places its detour waypoint.
+	routeClearanceMeters = 50.0
+	metersPerDegreeLat   = 111320.0
+)
+
+// Route is a flight path; Waypoints include the origin and destination.
+type Route struct {
+	Waypoints  []GeoPoint
+	DistanceKm float64
+}
+
+// Airspace is the set of no-fly zones in effect for planning. An empty
+// airspace flies every leg as a straight great-circle line.
+type Airspace []NoFlyZone
+
+func (a Airspace) ZoneAt(lat, lng float64) *NoFlyZone {
+	for i := range a {
+		if a[i].Boundary.Contains(lat, lng) {
+			return &a[i]
+		}
+	}
+	return nil
+}
+
+func (a Airspace) CheckPickup(lat, lng float64) error {
+	if zone := a.ZoneAt(lat, lng); zone != nil {
+		return ErrInsideNoFlyZone("pickup", zone.Name, lat, lng)
+	}
+	return nil
+}
+
+func (a Airspace) CheckDropoff(lat, lng float64) error {
+	if zone := a.ZoneAt(lat, lng); zone != nil {
+		return ErrInsideNoFlyZone("dropoff", zone.Name, lat, lng)
+	}
+	return nil
+}
+
+func (a Airspace) DistanceKm(fromLat, fromLng, toLat, toLng float64) float64 {
+	return a.PlanRoute(GeoPoint{Lat: fromLat, Lng: fromLng}, GeoPoint{Lat: toLat, Lng: toLng}).DistanceKm
+}
+
+// PlanPath chains planned legs through the given stops, dropping the
+// duplicated joint between consecutive legs.
+func (a Airspace) PlanPath(stops ...GeoPoint) Route {
+	if len(stops) == 0 {
+		return Route{}
+	}
+
+	path := Route{Waypoints: []GeoPoint{stops[0]}}
+	for i := 1; i < len(stops); i++ {
+		leg := a.PlanRoute(stops[i-1], stops[i])
+		path.Waypoints = append(path.Waypoints, leg.Waypoints[1:]...)
+		path.DistanceKm += leg.DistanceKm
+	}
+	return path
+}
+
+/*
+PlanRoute: shortest path over a visibility graph whose nodes are the origin,
+the destination and every zone corner pushed routeClearanceMeters outward.
+Edges are straight segments that cross no zone, weighted by great-circle
+distance. When no detour exists (e.g. the origin itself is inside a zone)
+the straight line is returned so callers always get a usable route.
+*/
+func (a Airspace) PlanRoute(from, to GeoPoint) Route {
+	if a.clear(from, to) {
+		return straightRoute(from, to)
+	}
+
+	nodes := []GeoPoint{from, to}
+	for _, zone := range a {
+		for _, corner := range zone.Boundary.offsetCorners(routeClearanceMeters) {
+			if a.ZoneAt(corner.Lat, corner.Lng) == nil {
+				nodes = append(nodes, corner)
+			}
+		}
+	}
+
+	// Dijkstra with lazily evaluated edges; node counts stay small.
+	const source, target = 0, 1
+	dist := make([]float64, len(nodes))
+	prev := make([]int, len(nodes))
+	done := make([]bool, len(nodes))
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	dist[source] = 0
+
+	for {
+		u := -1
+		for i := range nodes {
+			if !done[i] && !math.IsInf(dist[i], 1) && (u < 0 || dist[i] < dist[u]) {
+				u = i
+			}
+		}
+		if u < 0 || u == target {
+			break
+		}
+		done[u] = true
+
+		for v := range nodes {
+			if done[v] || !a.clear(nodes[u], nodes[v]) {
+				continue
+			}
+			d := dist[u] + haversineDistance(nodes[u].Lat, nodes[u].Lng, nodes[v].Lat, nodes[v].Lng)
+			if d < dist[v] {
+				dist[v] = d
+				prev[v] = u
+			}
+		}
+	}
+
+	if math.IsInf(dist[target], 1) {
+		return straightRoute(from, to)
+	}
+
+	var reversed []GeoPoint
+	for i := target; i >= 0; i = prev[i] {
+		reversed = append(reversed, nodes[i])
+	}
+	waypoints := make([]GeoPoint, len(reversed))
+	for i := range reversed {
+		waypoints[i] = reversed[len(reversed)-1-i]
+	}
+
+	return Route{Waypoints: waypoints, DistanceKm: dist[target]}
+}
+
+func (a Airspace) clear(from, to GeoPoint) bool {
+	for _, zone := range a {
+		if zone.Boundary.Crosses(from, to) {
+			return false
+		}
+	}
+	return true
+}
+
+func straightRoute(from, to GeoPoint) Route {
+	return Route{
+		Waypoints:  []GeoPoint{from, to},
+		DistanceKm: haversineDistance(from.Lat, from.Lng, to.Lat, to.Lng),
+	}
+}
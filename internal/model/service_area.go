@@ -0,0 +1,96 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+const maxServiceAreaNameLength = 100
+
+// ServiceArea is a polygon the fleet operates in. Orders must start and end
+// inside an active area once at least one exists.
+type ServiceArea struct {
+	ID        int64
+	Name      string
+	Boundary  Polygon
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type UpdateServiceAreaRequest struct {
+	Name     *string
+	Boundary Polygon
+	Active   *bool
+}
+
+func NewServiceArea(name string, boundary Polygon) (*ServiceArea, error) {
+	area := &ServiceArea{Active: true}
+	if err := area.Update(UpdateServiceAreaRequest{Name: &name, Boundary: boundary}); err != nil {
+		return nil, err
+	}
+	return area, nil
+}
+
+func (a *ServiceArea) Update(req UpdateServiceAreaRequest) error {
+	if req.Name == nil && req.Boundary == nil && req.Active == nil {
+		return ErrInvalidServiceArea("no fields to update")
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || len(name) > maxServiceAreaNameLength {
+			return ErrInvalidServiceArea("name must be 1-100 characters")
+		}
+		a.Name = name
+	}
+
+	if req.Boundary != nil {
+		boundary := req.Boundary.Normalize()
+		if err := boundary.Validate(); err != nil {
+			return err
+		}
+		a.Boundary = boundary
+	}
+
+	if req.Active != nil {
+		a.Active = *req.Active
+	}
+
+	return nil
+}
+
+// ServiceCoverage is the set of active service areas. Until an admin draws
+// the first area coverage is unrestricted, so a fresh deployment accepts
+// orders anywhere; once any area exists, even an inactive one, only points
+// inside an active area are covered.
+type ServiceCoverage struct {
+	Areas      []ServiceArea
+	Restricted bool
+}
+
+func (c ServiceCoverage) Covers(lat, lng float64) bool {
+	if !c.Restricted {
+		return true
+	}
+	for _, area := range c.Areas {
+		if area.Active && area.Boundary.Contains(lat, lng) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c ServiceCoverage) CheckPickup(lat, lng float64) error {
+	if !c.Covers(lat, lng) {
+		return ErrOutsideServiceArea("pickup", lat, lng)
+	}
+	return nil
+}
+
+func (c ServiceCoverage) CheckDropoff(lat, lng float64) error {
+	if !c.Covers(lat, lng) {
+		return ErrOutsideServiceArea("dropoff", lat, lng)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package model
+
+import "time"
+
+const (
+	defaultTrackWindow = 24 * time.Hour
+	maxTrackWindow     = 7 * 24 * time.Hour
+	// MaxTrackPoints caps a single track response.
+	MaxTrackPoints = 10000
+)
+
+// TelemetryPoint is one heartbeat in a drone's flight history, tagged with
+// the trip and order it was flying at the time.
+type TelemetryPoint struct {
+	ID      int64
+	DroneID int64
+	TripID  *int64
+	OrderID *int64
+	Lat     float64
+	Lng     float64
+	FlightReadings
+	RecordedAt time.Time
+}
+
+// NewTelemetryPoint snapshots the drone after the heartbeat was applied.
+func NewTelemetryPoint(drone Drone, hb DroneHeartbeat, now time.Time) TelemetryPoint {
+	return TelemetryPoint{
+		DroneID:        drone.ID,
+		TripID:         drone.CurrentTripID,
+		OrderID:        drone.CurrentOrderID,
+		Lat:            drone.Lat,
+		Lng:            drone.Lng,
+		FlightReadings: hb.FlightReadings,
+		RecordedAt:     now,
+	}
+}
+
+// Track is an ordered run of telemetry points flown by one drone; for order
+// tracks TripID identifies the trip the points belong to.
+type Track struct {
+	DroneID   int64
+	TripID    *int64
+	Points    []TelemetryPoint
+	Truncated bool
+}
+
+type TrackWindow struct {
+	From time.Time
+	To   time.Time
+}
+
+// NewTrackWindow defaults to the last 24 hours before to (or now) and caps
+// the span at seven days.
+func NewTrackWindow(from, to *time.Time, now time.Time) (TrackWindow, error) {
+	w := TrackWindow{To: now.UTC()}
+	if to != nil {
+		w.To = to.UTC()
+	}
+	w.From = w.To.Add(-defaultTrackWindow)
+	if from != nil {
+		w.From = from.UTC()
+	}
+
+	if w.From.After(w.To) {
+		return TrackWindow{}, ErrInvalidDateRange()
+	}
+	if w.To.Sub(w.From) > maxTrackWindow {
+		return TrackWindow{}, ErrTrackWindowTooLarge(maxTrackWindow)
+	}
+	return w, nil
+}
+
+// TelemetryPolicy controls how long flight history is kept. Points older
+// than DownsampleAfter are thinned to one per DownsampleInterval per drone;
+// points older than Retention are deleted.
+type TelemetryPolicy struct {
+	Retention          time.Duration
+	DownsampleAfter    time.Duration
+	DownsampleInterval time.Duration
+}
+
+func (p TelemetryPolicy) RetentionCutoff(now time.Time) time.Time {
+	return now.UTC().Add(-p.Retention)
+}
+
+// DownsampleCutoff is aligned to the interval so a bucket is never split
+// between a thinned and an unthinned run.
+func (p TelemetryPolicy) DownsampleCutoff(now time.Time) time.Time {
+	return now.UTC().Add(-p.DownsampleAfter).Truncate(p.DownsampleInterval)
+}
+
+// TracksByTrip splits time-ordered points into one track per consecutive
+// (drone, trip) run, so a handoff shows up as two tracks.
+func TracksByTrip(points []TelemetryPoint) []Track {
+	var tracks []Track
+	for _, p := range points {
+		if n := len(tracks); n > 0 && tracks[n-1].DroneID == p.DroneID && sameTrip(tracks[n-1].TripID, p.TripID) {
+			tracks[n-1].Points = append(tracks[n-1].Points, p)
+			continue
+		}
+		tracks = append(tracks, Track{DroneID: p.DroneID, TripID: p.TripID, Points: []TelemetryPoint{p}})
+	}
+	return tracks
+}
+
+func sameTrip(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
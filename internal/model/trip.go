@@ -0,0 +1,232 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+type TripStatus string
+
+const (
+	TripActive    TripStatus = "active"
+	TripCompleted TripStatus = "completed"
+	TripAborted   TripStatus = "aborted"
+)
+
+type TripStopKind string
+
+const (
+	StopPickup  TripStopKind = "pickup"
+	StopDropoff TripStopKind = "dropoff"
+	StopReturn  TripStopKind = "return"
+)
+
+type TripStop struct {
+	OrderID     int64
+	Kind        TripStopKind
+	Lat         float64
+	Lng         float64
+	CompletedAt *time.Time
+}
+
+func (s TripStop) IsCompleted() bool {
+	return s.CompletedAt != nil
+}
+
+// Trip sequences the pickups and dropoffs a drone flies in one outing; Stops
+// is kept in flight order, completed stops first.
+type Trip struct {
+	ID          int64
+	DroneID     int64
+	Status      TripStatus
+	Stops       []TripStop
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// TripLegETA is the arrival estimate for one of an order's own stops; StopsBefore
+// counts the other customers' stops flown first.
+type TripLegETA struct {
+	Kind        TripStopKind
+	Lat         float64
+	Lng         float64
+	StopsBefore int
+	ETA         ETA
+}
+
+func NewTrip(droneID int64) *Trip {
+	return &Trip{
+		DroneID: droneID,
+		Status:  TripActive,
+	}
+}
+
+/*
+AddOrder: cheapest-insertion of the order's pickup and destination into the
+remaining stops, keeping pickup ahead of destination and never reordering
+stops that were already flown
+*/
+func (t *Trip) AddOrder(order Order, fromLat, fromLng float64) {
+	pickupLat, pickupLng := order.PickupPoint()
+	destLat, destLng := order.DestinationPoint()
+
+	pickup := TripStop{OrderID: order.ID, Kind: StopPickup, Lat: pickupLat, Lng: pickupLng}
+	dest := TripStop{OrderID: order.ID, Kind: order.DestinationKind(), Lat: destLat, Lng: destLng}
+
+	first := t.firstRemaining()
+	remaining := t.Stops[first:]
+
+	bestCost := math.Inf(1)
+	bestI, bestJ := len(remaining), len(remaining)
+	for i := 0; i <= len(remaining); i++ {
+		for j := i; j <= len(remaining); j++ {
+			candidate := insertStops(remaining, pickup, i, dest, j)
+			if cost := routeDistanceKm(fromLat, fromLng, candidate); cost < bestCost {
+				bestCost, bestI, bestJ = cost, i, j
+			}
+		}
+	}
+
+	t.Stops = append(t.Stops[:first:first], insertStops(remaining, pickup, bestI, dest, bestJ)...)
+}
+
+// RemoveOrder drops the order's outstanding stops, e.g. after a failure or handoff.
+func (t *Trip) RemoveOrder(orderID int64, now time.Time) {
+	kept := t.Stops[:0]
+	for _, stop := range t.Stops {
+		if stop.OrderID == orderID && !stop.IsCompleted() {
+			continue
+		}
+		kept = append(kept, stop)
+	}
+	t.Stops = kept
+	t.closeIfDone(now)
+}
+
+// RerouteToReturn swaps a parcel's outstanding dropoff for its return point.
+func (t *Trip) RerouteToReturn(order Order) {
+	if !order.IsReturn() {
+		return
+	}
+	for i := range t.Stops {
+		stop := &t.Stops[i]
+		if stop.OrderID == order.ID && stop.Kind == StopDropoff && !stop.IsCompleted() {
+			stop.Kind = StopReturn
+			stop.Lat = *order.ReturnLat
+			stop.Lng = *order.ReturnLng
+		}
+	}
+}
+
+func (t *Trip) CompleteStop(orderID int64, kind TripStopKind, now time.Time) {
+	for i := range t.Stops {
+		stop := &t.Stops[i]
+		if stop.OrderID == orderID && stop.Kind == kind && !stop.IsCompleted() {
+			stop.CompletedAt = &now
+			break
+		}
+	}
+
+	// stops may be served out of the planned order; keep flown stops ahead
+	completed := make([]TripStop, 0, len(t.Stops))
+	remaining := make([]TripStop, 0, len(t.Stops))
+	for _, stop := range t.Stops {
+		if stop.IsCompleted() {
+			completed = append(completed, stop)
+		} else {
+			remaining = append(remaining, stop)
+		}
+	}
+	t.Stops = append(completed, remaining...)
+	t.closeIfDone(now)
+}
+
+func (t *Trip) Abort(now time.Time) {
+	if t.Status != TripActive {
+		return
+	}
+	t.Status = TripAborted
+	t.CompletedAt = &now
+}
+
+func (t *Trip) IsActive() bool {
+	return t.Status == TripActive
+}
+
+func (t *Trip) NextStop() *TripStop {
+	first := t.firstRemaining()
+	if first == len(t.Stops) {
+		return nil
+	}
+	return &t.Stops[first]
+}
+
+func (t *Trip) RemainingStops() []TripStop {
+	return t.Stops[t.firstRemaining():]
+}
+
+// LegETAs walks the remaining stops from the drone's position and reports the
+// arrival estimate at each stop that belongs to the given order, flying each
+// leg around the airspace.
+func (t *Trip) LegETAs(drone *Drone, orderID int64, airspace Airspace) []TripLegETA {
+	var legs []TripLegETA
+	lat, lng := drone.Lat, drone.Lng
+	distanceKm := 0.0
+	others := 0
+
+	for _, stop := range t.RemainingStops() {
+		distanceKm += airspace.DistanceKm(lat, lng, stop.Lat, stop.Lng)
+		lat, lng = stop.Lat, stop.Lng
+
+		if stop.OrderID != orderID {
+			others++
+			continue
+		}
+		legs = append(legs, TripLegETA{
+			Kind:        stop.Kind,
+			Lat:         stop.Lat,
+			Lng:         stop.Lng,
+			StopsBefore: others,
+			ETA:         etaForDistance(distanceKm, drone.speedMPS()),
+		})
+	}
+
+	return legs
+}
+
+func (t *Trip) closeIfDone(now time.Time) {
+	if t.Status == TripActive && t.firstRemaining() == len(t.Stops) {
+		t.Status = TripCompleted
+		t.CompletedAt = &now
+	}
+}
+
+func (t *Trip) firstRemaining() int {
+	for i, stop := range t.Stops {
+		if !stop.IsCompleted() {
+			return i
+		}
+	}
+	return len(t.Stops)
+}
+
+func insertStops(stops []TripStop, pickup TripStop, i int, dest TripStop, j int) []TripStop {
+	out := make([]TripStop, 0, len(stops)+2)
+	out = append(out, stops[:i]...)
+	out = append(out, pickup)
+	out = append(out, stops[i:j]...)
+	out = append(out, dest)
+	out = append(out, stops[j:]...)
+	return out
+}
+
+func routeDistanceKm(fromLat, fromLng float64, stops []TripStop) float64 {
+	total := 0.0
+	lat, lng := fromLat, fromLng
+	for _, stop := range stops {
+		total += haversineDistance(lat, lng, stop.Lat, stop.Lng)
+		lat, lng = stop.Lat, stop.Lng
+	}
+	return total
+}
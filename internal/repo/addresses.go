@@ -0,0 +1,223 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const (
+	insertAddressQuery = `
+		INSERT INTO addresses (user_id, label, lat, lng, delivery_notes, access_instructions)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	getAddressByIDQuery = `
+		SELECT id, user_id, label, lat, lng, delivery_notes, access_instructions, created_at, updated_at
+		FROM addresses
+		WHERE id = ?
+	`
+	listAddressesByUserQuery = `
+		SELECT id, user_id, label, lat, lng, delivery_notes, access_instructions, created_at, updated_at
+		FROM addresses
+		WHERE user_id = ?
+		ORDER BY label, id
+		LIMIT ? OFFSET ?
+	`
+	updateAddressQuery = `
+		UPDATE addresses
+		SET label = ?, lat = ?, lng = ?, delivery_notes = ?, access_instructions = ?, updated_at = NOW()
+		WHERE id = ?
+	`
+	deleteAddressQuery = `
+		DELETE FROM addresses WHERE id = ?
+	`
+)
+
+type addressDBO struct {
+	ID                 int64          `dbo:"id"`
+	UserID             int64          `dbo:"user_id"`
+	Label              string         `dbo:"label"`
+	Lat                float64        `dbo:"lat"`
+	Lng                float64        `dbo:"lng"`
+	DeliveryNotes      sql.NullString `dbo:"delivery_notes"`
+	AccessInstructions sql.NullString `dbo:"access_instructions"`
+	CreatedAt          sql.NullTime   `dbo:"created_at"`
+	UpdatedAt          sql.NullTime   `dbo:"updated_at"`
+}
+
+type AddressRepo struct {
+	db *sql.DB
+}
+
+func NewAddressRepo(db *sql.DB) *AddressRepo {
+	return &AddressRepo{db: db}
+}
+
+func (r *AddressRepo) Insert(ctx context.Context, address *model.Address) (*model.Address, error) {
+	dbo := toAddressDBO(address)
+
+	result, err := r.db.ExecContext(ctx, insertAddressQuery,
+		dbo.UserID,
+		dbo.Label,
+		dbo.Lat,
+		dbo.Lng,
+		dbo.DeliveryNotes,
+		dbo.AccessInstructions,
+	)
+	if err != nil {
+		if isFKConstraintError(err) {
+			return nil, ErrInvalidEnduserID()
+		}
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *AddressRepo) GetByID(ctx context.Context, id int64) (*model.Address, error) {
+	var dbo addressDBO
+	err := r.db.QueryRowContext(ctx, getAddressByIDQuery, id).Scan(
+		&dbo.ID,
+		&dbo.UserID,
+		&dbo.Label,
+		&dbo.Lat,
+		&dbo.Lng,
+		&dbo.DeliveryNotes,
+		&dbo.AccessInstructions,
+		&dbo.CreatedAt,
+		&dbo.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAddressNotFound()
+		}
+		return nil, err
+	}
+
+	return dbo.toModel(), nil
+}
+
+func (r *AddressRepo) ListByUser(ctx context.Context, userID int64, limit, offset int) ([]model.Address, error) {
+	rows, err := r.db.QueryContext(ctx, listAddressesByUserQuery, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var addresses []model.Address
+	for rows.Next() {
+		var dbo addressDBO
+		if err := rows.Scan(
+			&dbo.ID,
+			&dbo.UserID,
+			&dbo.Label,
+			&dbo.Lat,
+			&dbo.Lng,
+			&dbo.DeliveryNotes,
+			&dbo.AccessInstructions,
+			&dbo.CreatedAt,
+			&dbo.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, *dbo.toModel())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return addresses, nil
+}
+
+func (r *AddressRepo) Update(ctx context.Context, address *model.Address) (*model.Address, error) {
+	dbo := toAddressDBO(address)
+
+	_, err := r.db.ExecContext(ctx, updateAddressQuery,
+		dbo.Label,
+		dbo.Lat,
+		dbo.Lng,
+		dbo.DeliveryNotes,
+		dbo.AccessInstructions,
+		dbo.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, address.ID)
+}
+
+// Delete removes the address; orders created from it keep their snapshot and
+// lose only the reference.
+func (r *AddressRepo) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, deleteAddressQuery, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrAddressNotFound()
+	}
+
+	return nil
+}
+
+func (dbo *addressDBO) toModel() *model.Address {
+	address := &model.Address{
+		ID:     dbo.ID,
+		UserID: dbo.UserID,
+		Label:  dbo.Label,
+		Lat:    dbo.Lat,
+		Lng:    dbo.Lng,
+	}
+
+	if dbo.DeliveryNotes.Valid {
+		address.DeliveryNotes = &dbo.DeliveryNotes.String
+	}
+
+	if dbo.AccessInstructions.Valid {
+		address.AccessInstructions = &dbo.AccessInstructions.String
+	}
+
+	if dbo.CreatedAt.Valid {
+		address.CreatedAt = dbo.CreatedAt.Time
+	}
+
+	if dbo.UpdatedAt.Valid {
+		address.UpdatedAt = dbo.UpdatedAt.Time
+	}
+
+	return address
+}
+
+func toAddressDBO(address *model.Address) addressDBO {
+	dbo := addressDBO{
+		ID:     address.ID,
+		UserID: address.UserID,
+		Label:  address.Label,
+		Lat:    address.Lat,
+		Lng:    address.Lng,
+	}
+
+	if address.DeliveryNotes != nil {
+		dbo.DeliveryNotes = sql.NullString{String: *address.DeliveryNotes, Valid: true}
+	}
+
+	if address.AccessInstructions != nil {
+		dbo.AccessInstructions = sql.NullString{String: *address.AccessInstructions, Valid: true}
+	}
+
+	return dbo
+}
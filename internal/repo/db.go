@@ -101,3 +101,12 @@ func MigrateIfEmpty(db *sql.DB, migrationsDir string) error {
 	log.Println("database is empty; running migrations...")
 	return MigrateUp(db, migrationsDir)
 }
+
+// inList expands ids into the placeholders and args of an IN (...) list.
+func inList(ids []int64) (string, []any) {
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return strings.TrimSuffix(strings.Repeat("?,", len(ids)), ","), args
+}
@@ -0,0 +1,76 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const (
+	insertDeliveryProofQuery = `
+		INSERT INTO delivery_proofs (order_id, drone_id, method, photo_hash, evidence_lat, evidence_lng,
+		                             captured_at, signature, drone_lat, drone_lng, distance_m, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+)
+
+type deliveryProofDBO struct {
+	OrderID        int64           `dbo:"order_id"`
+	DroneID        int64           `dbo:"drone_id"`
+	Method         string          `dbo:"method"`
+	PhotoHash      sql.NullString  `dbo:"photo_hash"`
+	EvidenceLat    sql.NullFloat64 `dbo:"evidence_lat"`
+	EvidenceLng    sql.NullFloat64 `dbo:"evidence_lng"`
+	CapturedAt     sql.NullTime    `dbo:"captured_at"`
+	Signature      sql.NullString  `dbo:"signature"`
+	DroneLat       float64         `dbo:"drone_lat"`
+	DroneLng       float64         `dbo:"drone_lng"`
+	DistanceMeters float64         `dbo:"distance_m"`
+	CreatedAt      sql.NullTime    `dbo:"created_at"`
+}
+
+func (r *OrderRepo) InsertDeliveryProofTx(ctx context.Context, tx *sql.Tx, record *model.DeliveryProofRecord) error {
+	dbo := toDeliveryProofDBO(record)
+
+	_, err := tx.ExecContext(ctx, insertDeliveryProofQuery,
+		dbo.OrderID,
+		dbo.DroneID,
+		dbo.Method,
+		dbo.PhotoHash,
+		dbo.EvidenceLat,
+		dbo.EvidenceLng,
+		dbo.CapturedAt,
+		dbo.Signature,
+		dbo.DroneLat,
+		dbo.DroneLng,
+		dbo.DistanceMeters,
+		dbo.CreatedAt,
+	)
+	return err
+}
+
+func toDeliveryProofDBO(record *model.DeliveryProofRecord) deliveryProofDBO {
+	dbo := deliveryProofDBO{
+		OrderID:        record.OrderID,
+		DroneID:        record.DroneID,
+		Method:         string(record.Method),
+		DroneLat:       record.DroneLat,
+		DroneLng:       record.DroneLng,
+		DistanceMeters: record.DistanceMeters,
+	}
+
+	if e := record.Evidence; e != nil {
+		dbo.PhotoHash = sql.NullString{String: e.PhotoHash, Valid: true}
+		dbo.EvidenceLat = sql.NullFloat64{Float64: e.Lat, Valid: true}
+		dbo.EvidenceLng = sql.NullFloat64{Float64: e.Lng, Valid: true}
+		dbo.CapturedAt = sql.NullTime{Time: e.CapturedAt, Valid: true}
+		dbo.Signature = sql.NullString{String: e.Signature, Valid: true}
+	}
+
+	if !record.CreatedAt.IsZero() {
+		dbo.CreatedAt = sql.NullTime{Time: record.CreatedAt, Valid: true}
+	}
+
+	return dbo
+}
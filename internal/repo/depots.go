@@ -0,0 +1,261 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+// depotOccupancyColumns count the drones holding a pad at depot d.
+const depotOccupancyColumns = `
+		       (SELECT COUNT(*) FROM drone_status ds WHERE ds.depot_id = d.id) AS occupied,
+		       (SELECT COUNT(*) FROM drone_status ds WHERE ds.depot_id = d.id AND ds.status = 'charging') AS charging`
+
+const (
+	insertDepotQuery = `
+		INSERT INTO depots (name, lat, lng, capacity, active)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	selectDepotColumns = `
+		SELECT d.id, d.name, d.lat, d.lng, d.capacity, d.active,` + depotOccupancyColumns + `,
+		       d.created_at, d.updated_at
+		FROM depots d`
+	getDepotByIDQuery = selectDepotColumns + ` WHERE d.id = ?`
+	listDepotsQuery   = selectDepotColumns + ` ORDER BY d.id`
+	// the occupancy is read separately, after the depots are locked, so it
+	// sees pads taken by transactions that committed while this one waited
+	listActiveDepotsForUpdateQuery = `
+		SELECT d.id, d.name, d.lat, d.lng, d.capacity, d.active, 0, 0, d.created_at, d.updated_at
+		FROM depots d
+		WHERE d.active = 1
+		ORDER BY d.id
+		FOR UPDATE
+	`
+	depotOccupancyQuery = `
+		SELECT depot_id, COUNT(*), COALESCE(SUM(status = 'charging'), 0)
+		FROM drone_status
+		WHERE depot_id IN (%s)
+		GROUP BY depot_id
+	`
+	updateDepotQuery = `
+		UPDATE depots
+		SET name = ?, lat = ?, lng = ?, capacity = ?, active = ?, updated_at = NOW()
+		WHERE id = ?
+	`
+	deleteDepotQuery = `
+		DELETE FROM depots WHERE id = ?
+	`
+)
+
+type depotDBO struct {
+	ID        int64        `dbo:"id"`
+	Name      string       `dbo:"name"`
+	Lat       float64      `dbo:"lat"`
+	Lng       float64      `dbo:"lng"`
+	Capacity  int          `dbo:"capacity"`
+	Active    bool         `dbo:"active"`
+	Occupied  int          `dbo:"occupied"`
+	Charging  int          `dbo:"charging"`
+	CreatedAt sql.NullTime `dbo:"created_at"`
+	UpdatedAt sql.NullTime `dbo:"updated_at"`
+}
+
+type DepotRepo struct {
+	db *sql.DB
+}
+
+func NewDepotRepo(db *sql.DB) *DepotRepo {
+	return &DepotRepo{db: db}
+}
+
+func (r *DepotRepo) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, nil)
+}
+
+func (r *DepotRepo) Insert(ctx context.Context, depot *model.Depot) (*model.Depot, error) {
+	result, err := r.db.ExecContext(ctx, insertDepotQuery,
+		depot.Name,
+		depot.Lat,
+		depot.Lng,
+		depot.Capacity,
+		depot.Active,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *DepotRepo) GetByID(ctx context.Context, id int64) (*model.Depot, error) {
+	depot, err := scanDepot(r.db.QueryRowContext(ctx, getDepotByIDQuery, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDepotNotFound()
+		}
+		return nil, err
+	}
+
+	return depot, nil
+}
+
+func (r *DepotRepo) List(ctx context.Context) ([]model.Depot, error) {
+	rows, err := r.db.QueryContext(ctx, listDepotsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanDepots(rows)
+}
+
+// ListActiveForUpdate locks the active depots, so drones are sent to their
+// pads one at a time, and returns them with their current occupancy. The
+// depot locks are what serialise claimants, so the drones holding pads are
+// counted through the depot_id index without locking them: a claimant keeps
+// its own drone row locked, and shared locks on other drones' rows could
+// deadlock two claimants. The count is the transaction's first plain read,
+// so its snapshot is taken only once the depots are locked.
+func (r *DepotRepo) ListActiveForUpdate(ctx context.Context, tx *sql.Tx) ([]model.Depot, error) {
+	rows, err := tx.QueryContext(ctx, listActiveDepotsForUpdateQuery)
+	if err != nil {
+		return nil, err
+	}
+	depots, err := scanDepots(rows)
+	rows.Close()
+	if err != nil || len(depots) == 0 {
+		return depots, err
+	}
+
+	byID := make(map[int64]*model.Depot, len(depots))
+	ids := make([]int64, len(depots))
+	for i := range depots {
+		byID[depots[i].ID] = &depots[i]
+		ids[i] = depots[i].ID
+	}
+
+	placeholders, args := inList(ids)
+	rows, err = tx.QueryContext(ctx, fmt.Sprintf(depotOccupancyQuery, placeholders), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var depotID int64
+		var occupied, charging int
+		if err := rows.Scan(&depotID, &occupied, &charging); err != nil {
+			return nil, err
+		}
+		if depot, ok := byID[depotID]; ok {
+			depot.Occupied, depot.Charging = occupied, charging
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return depots, nil
+}
+
+func (r *DepotRepo) Update(ctx context.Context, depot *model.Depot) (*model.Depot, error) {
+	_, err := r.db.ExecContext(ctx, updateDepotQuery,
+		depot.Name,
+		depot.Lat,
+		depot.Lng,
+		depot.Capacity,
+		depot.Active,
+		depot.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, depot.ID)
+}
+
+// Delete removes the depot; drones based there keep flying without one.
+func (r *DepotRepo) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, deleteDepotQuery, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrDepotNotFound()
+	}
+
+	return nil
+}
+
+func scanDepots(rows *sql.Rows) ([]model.Depot, error) {
+	var depots []model.Depot
+	for rows.Next() {
+		depot, err := scanDepot(rows)
+		if err != nil {
+			return nil, err
+		}
+		depots = append(depots, *depot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return depots, nil
+}
+
+func scanDepot(row rowScanner) (*model.Depot, error) {
+	var dbo depotDBO
+	if err := row.Scan(
+		&dbo.ID,
+		&dbo.Name,
+		&dbo.Lat,
+		&dbo.Lng,
+		&dbo.Capacity,
+		&dbo.Active,
+		&dbo.Occupied,
+		&dbo.Charging,
+		&dbo.CreatedAt,
+		&dbo.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return dbo.toModel(), nil
+}
+
+func (dbo *depotDBO) toModel() *model.Depot {
+	depot := &model.Depot{
+		ID:       dbo.ID,
+		Name:     dbo.Name,
+		Lat:      dbo.Lat,
+		Lng:      dbo.Lng,
+		Capacity: dbo.Capacity,
+		Active:   dbo.Active,
+		Occupied: dbo.Occupied,
+		Charging: dbo.Charging,
+	}
+
+	if dbo.CreatedAt.Valid {
+		depot.CreatedAt = dbo.CreatedAt.Time
+	}
+
+	if dbo.UpdatedAt.Valid {
+		depot.UpdatedAt = dbo.UpdatedAt.Time
+	}
+
+	return depot
+}
@@ -0,0 +1,260 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const (
+	insertDroneCommandQuery = `
+		INSERT INTO drone_commands (drone_id, type, order_id, lat, lng, issued_by, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	selectDroneCommandColumns = `
+		SELECT id, drone_id, type, order_id, lat, lng, issued_by, status, note,
+		       created_at, sent_at, acked_at, completed_at, updated_at
+		FROM drone_commands`
+	getDroneCommandQuery = selectDroneCommandColumns + `
+		WHERE id = ? AND drone_id = ?
+	`
+	getDroneCommandForUpdateQuery = selectDroneCommandColumns + `
+		WHERE id = ? AND drone_id = ?
+		FOR UPDATE
+	`
+	listDroneCommandsByDroneQuery = selectDroneCommandColumns + `
+		WHERE drone_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+	updateDroneCommandQuery = `
+		UPDATE drone_commands
+		SET status = ?, note = ?, sent_at = ?, acked_at = ?, completed_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+)
+
+type droneCommandDBO struct {
+	ID          int64           `dbo:"id"`
+	DroneID     int64           `dbo:"drone_id"`
+	Type        string          `dbo:"type"`
+	OrderID     sql.NullInt64   `dbo:"order_id"`
+	Lat         sql.NullFloat64 `dbo:"lat"`
+	Lng         sql.NullFloat64 `dbo:"lng"`
+	IssuedBy    sql.NullInt64   `dbo:"issued_by"`
+	Status      string          `dbo:"status"`
+	Note        sql.NullString  `dbo:"note"`
+	CreatedAt   time.Time       `dbo:"created_at"`
+	SentAt      sql.NullTime    `dbo:"sent_at"`
+	AckedAt     sql.NullTime    `dbo:"acked_at"`
+	CompletedAt sql.NullTime    `dbo:"completed_at"`
+	UpdatedAt   time.Time       `dbo:"updated_at"`
+}
+
+type DroneCommandRepo struct {
+	db *sql.DB
+}
+
+func NewDroneCommandRepo(db *sql.DB) *DroneCommandRepo {
+	return &DroneCommandRepo{db: db}
+}
+
+func (r *DroneCommandRepo) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, nil)
+}
+
+// Insert stores a new command and fills in its ID.
+func (r *DroneCommandRepo) Insert(ctx context.Context, cmd *model.DroneCommand) error {
+	dbo := toDroneCommandDBO(cmd)
+
+	result, err := r.db.ExecContext(ctx, insertDroneCommandQuery,
+		dbo.DroneID,
+		dbo.Type,
+		dbo.OrderID,
+		dbo.Lat,
+		dbo.Lng,
+		dbo.IssuedBy,
+		dbo.Status,
+		dbo.CreatedAt,
+		dbo.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	cmd.ID = id
+
+	return nil
+}
+
+// GetByDrone only finds the command when it was sent to droneID.
+func (r *DroneCommandRepo) GetByDrone(ctx context.Context, droneID, id int64) (*model.DroneCommand, error) {
+	return scanDroneCommand(r.db.QueryRowContext(ctx, getDroneCommandQuery, id, droneID))
+}
+
+func (r *DroneCommandRepo) GetByDroneForUpdate(ctx context.Context, tx *sql.Tx, droneID, id int64) (*model.DroneCommand, error) {
+	return scanDroneCommand(tx.QueryRowContext(ctx, getDroneCommandForUpdateQuery, id, droneID))
+}
+
+func (r *DroneCommandRepo) UpdateTx(ctx context.Context, tx *sql.Tx, cmd *model.DroneCommand) error {
+	dbo := toDroneCommandDBO(cmd)
+
+	_, err := tx.ExecContext(ctx, updateDroneCommandQuery,
+		dbo.Status,
+		dbo.Note,
+		dbo.SentAt,
+		dbo.AckedAt,
+		dbo.CompletedAt,
+		dbo.UpdatedAt,
+		dbo.ID,
+	)
+	return err
+}
+
+func (r *DroneCommandRepo) ListByDrone(ctx context.Context, droneID int64, limit, offset int) ([]model.DroneCommand, error) {
+	rows, err := r.db.QueryContext(ctx, listDroneCommandsByDroneQuery, droneID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var commands []model.DroneCommand
+	for rows.Next() {
+		cmd, err := scanDroneCommand(rows)
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, *cmd)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return commands, nil
+}
+
+func scanDroneCommand(row rowScanner) (*model.DroneCommand, error) {
+	var dbo droneCommandDBO
+	err := row.Scan(
+		&dbo.ID,
+		&dbo.DroneID,
+		&dbo.Type,
+		&dbo.OrderID,
+		&dbo.Lat,
+		&dbo.Lng,
+		&dbo.IssuedBy,
+		&dbo.Status,
+		&dbo.Note,
+		&dbo.CreatedAt,
+		&dbo.SentAt,
+		&dbo.AckedAt,
+		&dbo.CompletedAt,
+		&dbo.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCommandNotFound()
+		}
+		return nil, err
+	}
+
+	return dbo.toModel(), nil
+}
+
+func (dbo *droneCommandDBO) toModel() *model.DroneCommand {
+	cmd := &model.DroneCommand{
+		ID:        dbo.ID,
+		DroneID:   dbo.DroneID,
+		Type:      model.CommandType(dbo.Type),
+		Status:    model.CommandStatus(dbo.Status),
+		CreatedAt: dbo.CreatedAt,
+		UpdatedAt: dbo.UpdatedAt,
+	}
+
+	if dbo.OrderID.Valid {
+		cmd.OrderID = &dbo.OrderID.Int64
+	}
+
+	if dbo.Lat.Valid {
+		cmd.Lat = &dbo.Lat.Float64
+	}
+
+	if dbo.Lng.Valid {
+		cmd.Lng = &dbo.Lng.Float64
+	}
+
+	if dbo.IssuedBy.Valid {
+		cmd.IssuedBy = &dbo.IssuedBy.Int64
+	}
+
+	if dbo.Note.Valid {
+		cmd.Note = &dbo.Note.String
+	}
+
+	if dbo.SentAt.Valid {
+		cmd.SentAt = &dbo.SentAt.Time
+	}
+
+	if dbo.AckedAt.Valid {
+		cmd.AckedAt = &dbo.AckedAt.Time
+	}
+
+	if dbo.CompletedAt.Valid {
+		cmd.CompletedAt = &dbo.CompletedAt.Time
+	}
+
+	return cmd
+}
+
+func toDroneCommandDBO(cmd *model.DroneCommand) droneCommandDBO {
+	dbo := droneCommandDBO{
+		ID:        cmd.ID,
+		DroneID:   cmd.DroneID,
+		Type:      string(cmd.Type),
+		Status:    string(cmd.Status),
+		CreatedAt: cmd.CreatedAt,
+		UpdatedAt: cmd.UpdatedAt,
+	}
+
+	if cmd.OrderID != nil {
+		dbo.OrderID = sql.NullInt64{Int64: *cmd.OrderID, Valid: true}
+	}
+
+	if cmd.Lat != nil {
+		dbo.Lat = sql.NullFloat64{Float64: *cmd.Lat, Valid: true}
+	}
+
+	if cmd.Lng != nil {
+		dbo.Lng = sql.NullFloat64{Float64: *cmd.Lng, Valid: true}
+	}
+
+	if cmd.IssuedBy != nil {
+		dbo.IssuedBy = sql.NullInt64{Int64: *cmd.IssuedBy, Valid: true}
+	}
+
+	if cmd.Note != nil {
+		dbo.Note = sql.NullString{String: *cmd.Note, Valid: true}
+	}
+
+	if cmd.SentAt != nil {
+		dbo.SentAt = sql.NullTime{Time: *cmd.SentAt, Valid: true}
+	}
+
+	if cmd.AckedAt != nil {
+		dbo.AckedAt = sql.NullTime{Time: *cmd.AckedAt, Valid: true}
+	}
+
+	if cmd.CompletedAt != nil {
+		dbo.CompletedAt = sql.NullTime{Time: *cmd.CompletedAt, Valid: true}
+	}
+
+	return dbo
+}
@@ -4,50 +4,98 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
 )
 
+// activeOrdersSubquery counts orders a drone still owns; it is the source of
+// truth for Drone.ActiveOrders rather than a stored counter.
+const activeOrdersSubquery = `
+		SELECT COUNT(*) FROM orders o
+		WHERE o.assigned_drone_id = ds.drone_id
+		  AND o.status IN ('reserved','picked_up','returning')`
+
+// airborneSecondsColumn adds the stretch a delivering drone is still
+// flying to the ones it has finished.
+const airborneSecondsColumn = `ds.airborne_seconds + IF(ds.status = 'delivering' AND ds.delivering_since IS NOT NULL,
+		       TIMESTAMPDIFF(MICROSECOND, ds.delivering_since, NOW(3)) / 1000000, 0)`
+
+// droneColumns is the select list read by scanDrone.
+const droneColumns = `ds.drone_id, ds.status, ds.current_order_id,
+		       ds.current_trip_id, ds.capacity, (` + activeOrdersSubquery + `) AS active_orders,
+		       ds.home_depot_id, ds.depot_id,
+		       ds.lat, ds.lng, ds.last_heartbeat_at,
+		       ds.altitude_m, ds.heading_deg, ds.speed_mps, ds.battery_pct,
+		       ds.gps_fix, ds.gps_accuracy_m, ds.device_time,
+		       ds.deliveries, ds.failures, ds.breakdowns, ds.distance_m,
+		       ` + airborneSecondsColumn + ` AS airborne_seconds,
+		       u.created_at, u.updated_at`
+
 const (
 	getDroneByIDQuery = `
-		SELECT ds.drone_id, ds.status, ds.current_order_id,
-		       ds.lat, ds.lng,
-		       ds.last_heartbeat_at, u.created_at, u.updated_at
+		SELECT ` + droneColumns + `
 		FROM drone_status ds
 		JOIN users u ON u.id = ds.drone_id
 		WHERE ds.drone_id = ? AND u.type = 'drone'
 	`
 	getDroneByIDForUpdateQuery = `
-		SELECT ds.drone_id, ds.status, ds.current_order_id,
-		       ds.lat, ds.lng,
-		       ds.last_heartbeat_at, u.created_at, u.updated_at
+		SELECT ` + droneColumns + `
 		FROM drone_status ds
 		JOIN users u ON u.id = ds.drone_id
 		WHERE ds.drone_id = ? AND u.type = 'drone'
 		FOR UPDATE
 	`
-	findNearestIdleQuery = `
-		SELECT ds.drone_id, ds.status, ds.current_order_id,
-		       ds.lat, ds.lng,
-		       ds.last_heartbeat_at, u.created_at, u.updated_at
+	findNearestAvailableQuery = `
+		SELECT ` + droneColumns + `
 		FROM drone_status ds
 		JOIN users u ON u.id = ds.drone_id
-		WHERE ds.status = 'idle'
+		WHERE (ds.status = 'idle'
+		       OR (ds.status IN ('reserved','delivering') AND ds.capacity > (` + activeOrdersSubquery + `)))
 		ORDER BY ST_Distance_Sphere(
 			ds.location,
 			ST_SRID(POINT(?, ?), 4326)
 		), ds.drone_id
 		LIMIT 1
 	`
+	// drones still weighing an offer made within the timeout are left out
+	listAvailableDronesQuery = `
+		SELECT ` + droneColumns + `
+		FROM drone_status ds
+		JOIN users u ON u.id = ds.drone_id
+		WHERE (ds.status = 'idle'
+		       OR (ds.status IN ('reserved','delivering') AND ds.capacity > (` + activeOrdersSubquery + `)))
+		  AND NOT EXISTS (
+			SELECT 1 FROM orders o
+			WHERE o.offered_drone_id = ds.drone_id
+			  AND o.status IN ('pending','handoff_pending')
+			  AND o.offered_at >= NOW(3) - INTERVAL ? MICROSECOND
+		  )
+		ORDER BY ds.drone_id
+	`
+	// every status change passes through here, so this is where time spent
+	// delivering is clocked. MySQL assigns left to right: the first two
+	// columns still see the status being replaced.
 	updateDroneQuery = `
 		UPDATE drone_status 
-		SET status = ?, current_order_id = ?, lat = ?, lng = ?, location = ST_SRID(POINT(?, ?), 4326), last_heartbeat_at = ?, updated_at = NOW()
+		SET airborne_seconds = airborne_seconds + IF(status = 'delivering' AND ? <> 'delivering' AND delivering_since IS NOT NULL,
+		        TIMESTAMPDIFF(MICROSECOND, delivering_since, NOW(3)) / 1000000, 0),
+		    delivering_since = IF(? = 'delivering', IF(status = 'delivering', delivering_since, NOW(3)), NULL),
+		    status = ?, current_order_id = ?, current_trip_id = ?, capacity = ?, home_depot_id = ?, depot_id = ?, lat = ?, lng = ?, location = ST_SRID(POINT(?, ?), 4326), last_heartbeat_at = ?,
+		    altitude_m = ?, heading_deg = ?, speed_mps = ?, battery_pct = ?, gps_fix = ?, gps_accuracy_m = ?, device_time = ?,
+		    deliveries = ?, failures = ?, breakdowns = ?, distance_m = ?,
+		    updated_at = NOW()
 		WHERE drone_id = ?
 	`
+	listDronesByIDsQuery = `
+		SELECT ` + droneColumns + `
+		FROM drone_status ds
+		JOIN users u ON u.id = ds.drone_id
+		WHERE u.type = 'drone' AND ds.drone_id IN (%s)
+	`
 	listDronesQuery = `
-		SELECT ds.drone_id, ds.status, ds.current_order_id,
-		       ds.lat, ds.lng, ds.last_heartbeat_at,
-		       u.created_at, u.updated_at
+		SELECT ` + droneColumns + `
 		FROM drone_status ds
 		JOIN users u ON u.id = ds.drone_id
 		WHERE u.type = 'drone'
@@ -57,14 +105,25 @@ const (
 )
 
 type droneDBO struct {
-	ID             int64         `dbo:"id"`
-	Status         string        `dbo:"status"`
-	CurrentOrderID sql.NullInt64 `dbo:"current_order_id"`
-	Lat            float64       `dbo:"lat"`
-	Lng            float64       `dbo:"lng"`
-	LastHeartbeat  sql.NullTime  `dbo:"last_heartbeat_at"`
-	CreatedAt      sql.NullTime  `dbo:"created_at"`
-	UpdatedAt      sql.NullTime  `dbo:"updated_at"`
+	ID              int64         `dbo:"id"`
+	Status          string        `dbo:"status"`
+	CurrentOrderID  sql.NullInt64 `dbo:"current_order_id"`
+	CurrentTripID   sql.NullInt64 `dbo:"current_trip_id"`
+	Capacity        int           `dbo:"capacity"`
+	ActiveOrders    int           `dbo:"active_orders"`
+	HomeDepotID     sql.NullInt64 `dbo:"home_depot_id"`
+	DepotID         sql.NullInt64 `dbo:"depot_id"`
+	Lat             float64       `dbo:"lat"`
+	Lng             float64       `dbo:"lng"`
+	LastHeartbeat   sql.NullTime  `dbo:"last_heartbeat_at"`
+	Readings        readingsDBO
+	Deliveries      int          `dbo:"deliveries"`
+	Failures        int          `dbo:"failures"`
+	Breakdowns      int          `dbo:"breakdowns"`
+	DistanceM       float64      `dbo:"distance_m"`
+	AirborneSeconds float64      `dbo:"airborne_seconds"`
+	CreatedAt       sql.NullTime `dbo:"created_at"`
+	UpdatedAt       sql.NullTime `dbo:"updated_at"`
 }
 
 type DroneRepo struct {
@@ -80,17 +139,7 @@ func (r *DroneRepo) BeginTx(ctx context.Context) (*sql.Tx, error) {
 }
 
 func (r *DroneRepo) GetByID(ctx context.Context, id int64) (*model.Drone, error) {
-	var dbo droneDBO
-	err := r.db.QueryRowContext(ctx, getDroneByIDQuery, id).Scan(
-		&dbo.ID,
-		&dbo.Status,
-		&dbo.CurrentOrderID,
-		&dbo.Lat,
-		&dbo.Lng,
-		&dbo.LastHeartbeat,
-		&dbo.CreatedAt,
-		&dbo.UpdatedAt,
-	)
+	drone, err := scanDrone(r.db.QueryRowContext(ctx, getDroneByIDQuery, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrDroneNotFound()
@@ -98,21 +147,11 @@ func (r *DroneRepo) GetByID(ctx context.Context, id int64) (*model.Drone, error)
 		return nil, err
 	}
 
-	return dbo.toModel(), nil
+	return drone, nil
 }
 
 func (r *DroneRepo) GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (*model.Drone, error) {
-	var dbo droneDBO
-	err := tx.QueryRowContext(ctx, getDroneByIDForUpdateQuery, id).Scan(
-		&dbo.ID,
-		&dbo.Status,
-		&dbo.CurrentOrderID,
-		&dbo.Lat,
-		&dbo.Lng,
-		&dbo.LastHeartbeat,
-		&dbo.CreatedAt,
-		&dbo.UpdatedAt,
-	)
+	drone, err := scanDrone(tx.QueryRowContext(ctx, getDroneByIDForUpdateQuery, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrDroneNotFound()
@@ -120,20 +159,37 @@ func (r *DroneRepo) GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64)
 		return nil, err
 	}
 
-	return dbo.toModel(), nil
+	return drone, nil
 }
 
 func (r *DroneRepo) UpdateTx(ctx context.Context, tx *sql.Tx, drone *model.Drone) (*model.Drone, error) {
 	dbo := toDroneDBO(drone)
 
 	_, err := tx.ExecContext(ctx, updateDroneQuery,
+		dbo.Status,
+		dbo.Status,
 		dbo.Status,
 		dbo.CurrentOrderID,
+		dbo.CurrentTripID,
+		dbo.Capacity,
+		dbo.HomeDepotID,
+		dbo.DepotID,
 		dbo.Lat,
 		dbo.Lng,
 		dbo.Lng,
 		dbo.Lat,
 		dbo.LastHeartbeat,
+		dbo.Readings.AltitudeM,
+		dbo.Readings.HeadingDeg,
+		dbo.Readings.GroundSpeedMPS,
+		dbo.Readings.BatteryPct,
+		dbo.Readings.GPSFix,
+		dbo.Readings.GPSAccuracyM,
+		dbo.Readings.DeviceTime,
+		dbo.Deliveries,
+		dbo.Failures,
+		dbo.Breakdowns,
+		dbo.DistanceM,
 		dbo.ID)
 	if err != nil {
 		return nil, err
@@ -142,18 +198,10 @@ func (r *DroneRepo) UpdateTx(ctx context.Context, tx *sql.Tx, drone *model.Drone
 	return r.GetByIDForUpdate(ctx, tx, drone.ID)
 }
 
-func (r *DroneRepo) FindNearestIdle(ctx context.Context, lat, lng float64) (*model.Drone, error) {
-	var dbo droneDBO
-	err := r.db.QueryRowContext(ctx, findNearestIdleQuery, lng, lat).Scan(
-		&dbo.ID,
-		&dbo.Status,
-		&dbo.CurrentOrderID,
-		&dbo.Lat,
-		&dbo.Lng,
-		&dbo.LastHeartbeat,
-		&dbo.CreatedAt,
-		&dbo.UpdatedAt,
-	)
+// FindNearestAvailable returns the closest drone that can take another order:
+// idle drones, or drones already on a trip with spare capacity.
+func (r *DroneRepo) FindNearestAvailable(ctx context.Context, lat, lng float64) (*model.Drone, error) {
+	drone, err := scanDrone(r.db.QueryRowContext(ctx, findNearestAvailableQuery, lng, lat))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrDroneNotFound()
@@ -161,7 +209,62 @@ func (r *DroneRepo) FindNearestIdle(ctx context.Context, lat, lng float64) (*mod
 		return nil, err
 	}
 
-	return dbo.toModel(), nil
+	return drone, nil
+}
+
+// ListAvailable returns every drone that can take another order and has no
+// offer younger than offerTimeout still outstanding.
+func (r *DroneRepo) ListAvailable(ctx context.Context, offerTimeout time.Duration) ([]model.Drone, error) {
+	rows, err := r.db.QueryContext(ctx, listAvailableDronesQuery, offerTimeout.Microseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var drones []model.Drone
+	for rows.Next() {
+		drone, err := scanDrone(rows)
+		if err != nil {
+			return nil, err
+		}
+		drones = append(drones, *drone)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return drones, nil
+}
+
+// ListByIDs loads the drones in one query; ids that are not drones are left
+// out.
+func (r *DroneRepo) ListByIDs(ctx context.Context, ids []int64) ([]model.Drone, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	placeholders, args := inList(ids)
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(listDronesByIDsQuery, placeholders), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var drones []model.Drone
+	for rows.Next() {
+		drone, err := scanDrone(rows)
+		if err != nil {
+			return nil, err
+		}
+		drones = append(drones, *drone)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return drones, nil
 }
 
 func (r *DroneRepo) List(ctx context.Context, limit, offset int) ([]model.Drone, error) {
@@ -173,20 +276,11 @@ func (r *DroneRepo) List(ctx context.Context, limit, offset int) ([]model.Drone,
 
 	var drones []model.Drone
 	for rows.Next() {
-		var dbo droneDBO
-		if err := rows.Scan(
-			&dbo.ID,
-			&dbo.Status,
-			&dbo.CurrentOrderID,
-			&dbo.Lat,
-			&dbo.Lng,
-			&dbo.LastHeartbeat,
-			&dbo.CreatedAt,
-			&dbo.UpdatedAt,
-		); err != nil {
+		drone, err := scanDrone(rows)
+		if err != nil {
 			return nil, err
 		}
-		drones = append(drones, *dbo.toModel())
+		drones = append(drones, *drone)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -196,18 +290,78 @@ func (r *DroneRepo) List(ctx context.Context, limit, offset int) ([]model.Drone,
 	return drones, nil
 }
 
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDrone(row rowScanner) (*model.Drone, error) {
+	var dbo droneDBO
+	if err := row.Scan(
+		&dbo.ID,
+		&dbo.Status,
+		&dbo.CurrentOrderID,
+		&dbo.CurrentTripID,
+		&dbo.Capacity,
+		&dbo.ActiveOrders,
+		&dbo.HomeDepotID,
+		&dbo.DepotID,
+		&dbo.Lat,
+		&dbo.Lng,
+		&dbo.LastHeartbeat,
+		&dbo.Readings.AltitudeM,
+		&dbo.Readings.HeadingDeg,
+		&dbo.Readings.GroundSpeedMPS,
+		&dbo.Readings.BatteryPct,
+		&dbo.Readings.GPSFix,
+		&dbo.Readings.GPSAccuracyM,
+		&dbo.Readings.DeviceTime,
+		&dbo.Deliveries,
+		&dbo.Failures,
+		&dbo.Breakdowns,
+		&dbo.DistanceM,
+		&dbo.AirborneSeconds,
+		&dbo.CreatedAt,
+		&dbo.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return dbo.toModel(), nil
+}
+
 func (dbo *droneDBO) toModel() *model.Drone {
 	drone := &model.Drone{
-		ID:     dbo.ID,
-		Status: model.DroneStatus(dbo.Status),
-		Lat:    dbo.Lat,
-		Lng:    dbo.Lng,
+		ID:           dbo.ID,
+		Status:       model.DroneStatus(dbo.Status),
+		Capacity:     dbo.Capacity,
+		ActiveOrders: dbo.ActiveOrders,
+		Lat:          dbo.Lat,
+		Lng:          dbo.Lng,
+		Readings:     dbo.Readings.toModel(),
+		Usage: model.DroneUsage{
+			Deliveries:   dbo.Deliveries,
+			Failures:     dbo.Failures,
+			Breakdowns:   dbo.Breakdowns,
+			DistanceM:    dbo.DistanceM,
+			AirborneTime: time.Duration(dbo.AirborneSeconds * float64(time.Second)),
+		},
 	}
 
 	if dbo.CurrentOrderID.Valid {
 		drone.CurrentOrderID = &dbo.CurrentOrderID.Int64
 	}
 
+	if dbo.CurrentTripID.Valid {
+		drone.CurrentTripID = &dbo.CurrentTripID.Int64
+	}
+
+	if dbo.HomeDepotID.Valid {
+		drone.HomeDepotID = &dbo.HomeDepotID.Int64
+	}
+
+	if dbo.DepotID.Valid {
+		drone.DepotID = &dbo.DepotID.Int64
+	}
+
 	if dbo.LastHeartbeat.Valid {
 		drone.LastHeartbeat = &dbo.LastHeartbeat.Time
 	}
@@ -225,16 +379,39 @@ func (dbo *droneDBO) toModel() *model.Drone {
 
 func toDroneDBO(drone *model.Drone) droneDBO {
 	dbo := droneDBO{
-		ID:     drone.ID,
-		Status: string(drone.Status),
-		Lat:    drone.Lat,
-		Lng:    drone.Lng,
+		ID:           drone.ID,
+		Status:       string(drone.Status),
+		Capacity:     drone.Capacity,
+		ActiveOrders: drone.ActiveOrders,
+		Lat:          drone.Lat,
+		Lng:          drone.Lng,
+		Readings:     toReadingsDBO(drone.Readings),
+		Deliveries:   drone.Usage.Deliveries,
+		Failures:     drone.Usage.Failures,
+		Breakdowns:   drone.Usage.Breakdowns,
+		DistanceM:    drone.Usage.DistanceM,
+	}
+
+	if dbo.Capacity < 1 {
+		dbo.Capacity = 1
 	}
 
 	if drone.CurrentOrderID != nil {
 		dbo.CurrentOrderID = sql.NullInt64{Int64: *drone.CurrentOrderID, Valid: true}
 	}
 
+	if drone.CurrentTripID != nil {
+		dbo.CurrentTripID = sql.NullInt64{Int64: *drone.CurrentTripID, Valid: true}
+	}
+
+	if drone.HomeDepotID != nil {
+		dbo.HomeDepotID = sql.NullInt64{Int64: *drone.HomeDepotID, Valid: true}
+	}
+
+	if drone.DepotID != nil {
+		dbo.DepotID = sql.NullInt64{Int64: *drone.DepotID, Valid: true}
+	}
+
 	if drone.LastHeartbeat != nil {
 		dbo.LastHeartbeat = sql.NullTime{Time: *drone.LastHeartbeat, Valid: true}
 	}
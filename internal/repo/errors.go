@@ -42,11 +42,18 @@ func NewRepoErrorWithCause(code, message string, statusCode int, cause error) *R
 }
 
 const (
-	ErrCodeUserNotFound      = "user_not_found"
-	ErrCodeOrderNotFound     = "order_not_found"
-	ErrCodeDroneNotFound     = "drone_not_found"
-	ErrCodeInvalidForeignKey = "invalid_foreign_key"
-	ErrCodeInvalidEnduserID  = "invalid_enduser_id"
+	ErrCodeUserNotFound        = "user_not_found"
+	ErrCodeOrderNotFound       = "order_not_found"
+	ErrCodeDroneNotFound       = "drone_not_found"
+	ErrCodeAddressNotFound     = "address_not_found"
+	ErrCodeServiceAreaNotFound = "service_area_not_found"
+	ErrCodeNoFlyZoneNotFound   = "no_fly_zone_not_found"
+	ErrCodeLandingSiteNotFound = "landing_site_not_found"
+	ErrCodeDepotNotFound       = "depot_not_found"
+	ErrCodeCommandNotFound     = "command_not_found"
+	ErrCodeMaintenanceNotFound = "maintenance_record_not_found"
+	ErrCodeInvalidForeignKey   = "invalid_foreign_key"
+	ErrCodeInvalidEnduserID    = "invalid_enduser_id"
 )
 
 func ErrUserNotFound() *RepoError {
@@ -61,6 +68,34 @@ func ErrDroneNotFound() *RepoError {
 	return NewRepoError(ErrCodeDroneNotFound, "drone not found", 404)
 }
 
+func ErrAddressNotFound() *RepoError {
+	return NewRepoError(ErrCodeAddressNotFound, "address not found", 404)
+}
+
+func ErrServiceAreaNotFound() *RepoError {
+	return NewRepoError(ErrCodeServiceAreaNotFound, "service area not found", 404)
+}
+
+func ErrNoFlyZoneNotFound() *RepoError {
+	return NewRepoError(ErrCodeNoFlyZoneNotFound, "no-fly zone not found", 404)
+}
+
+func ErrLandingSiteNotFound() *RepoError {
+	return NewRepoError(ErrCodeLandingSiteNotFound, "landing site not found", 404)
+}
+
+func ErrDepotNotFound() *RepoError {
+	return NewRepoError(ErrCodeDepotNotFound, "depot not found", 404)
+}
+
+func ErrCommandNotFound() *RepoError {
+	return NewRepoError(ErrCodeCommandNotFound, "command not found", 404)
+}
+
+func ErrMaintenanceRecordNotFound() *RepoError {
+	return NewRepoError(ErrCodeMaintenanceNotFound, "maintenance record not found", 404)
+}
+
 func ErrInvalidEnduserID() *RepoError {
 	return NewRepoError(ErrCodeInvalidEnduserID, "invalid enduser id", 400)
 }
@@ -0,0 +1,159 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const (
+	insertGeofenceBreachQuery = `
+		INSERT INTO geofence_breaches (drone_id, order_id, kind, zone_id, zone_name, lat, lng, action, detected_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	listGeofenceBreachesByDroneQuery = `
+		SELECT id, drone_id, order_id, kind, zone_id, zone_name, lat, lng, action, detected_at
+		FROM geofence_breaches
+		WHERE drone_id = ?
+		ORDER BY detected_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+)
+
+type geofenceBreachDBO struct {
+	ID         int64          `dbo:"id"`
+	DroneID    int64          `dbo:"drone_id"`
+	OrderID    sql.NullInt64  `dbo:"order_id"`
+	Kind       string         `dbo:"kind"`
+	ZoneID     sql.NullInt64  `dbo:"zone_id"`
+	ZoneName   sql.NullString `dbo:"zone_name"`
+	Lat        float64        `dbo:"lat"`
+	Lng        float64        `dbo:"lng"`
+	Action     string         `dbo:"action"`
+	DetectedAt time.Time      `dbo:"detected_at"`
+}
+
+type GeofenceBreachRepo struct {
+	db *sql.DB
+}
+
+func NewGeofenceBreachRepo(db *sql.DB) *GeofenceBreachRepo {
+	return &GeofenceBreachRepo{db: db}
+}
+
+// InsertTx records the breach alongside the heartbeat that revealed it and
+// fills in its ID.
+func (r *GeofenceBreachRepo) InsertTx(ctx context.Context, tx *sql.Tx, breach *model.GeofenceBreach) error {
+	dbo := toGeofenceBreachDBO(breach)
+
+	result, err := tx.ExecContext(ctx, insertGeofenceBreachQuery,
+		dbo.DroneID,
+		dbo.OrderID,
+		dbo.Kind,
+		dbo.ZoneID,
+		dbo.ZoneName,
+		dbo.Lat,
+		dbo.Lng,
+		dbo.Action,
+		dbo.DetectedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	breach.ID = id
+
+	return nil
+}
+
+func (r *GeofenceBreachRepo) ListByDrone(ctx context.Context, droneID int64, limit, offset int) ([]model.GeofenceBreach, error) {
+	rows, err := r.db.QueryContext(ctx, listGeofenceBreachesByDroneQuery, droneID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var breaches []model.GeofenceBreach
+	for rows.Next() {
+		var dbo geofenceBreachDBO
+		if err := rows.Scan(
+			&dbo.ID,
+			&dbo.DroneID,
+			&dbo.OrderID,
+			&dbo.Kind,
+			&dbo.ZoneID,
+			&dbo.ZoneName,
+			&dbo.Lat,
+			&dbo.Lng,
+			&dbo.Action,
+			&dbo.DetectedAt,
+		); err != nil {
+			return nil, err
+		}
+		breaches = append(breaches, *dbo.toModel())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return breaches, nil
+}
+
+func (dbo *geofenceBreachDBO) toModel() *model.GeofenceBreach {
+	breach := &model.GeofenceBreach{
+		ID:         dbo.ID,
+		DroneID:    dbo.DroneID,
+		Kind:       model.GeofenceBreachKind(dbo.Kind),
+		Lat:        dbo.Lat,
+		Lng:        dbo.Lng,
+		Action:     model.BreachAction(dbo.Action),
+		DetectedAt: dbo.DetectedAt,
+	}
+
+	if dbo.OrderID.Valid {
+		breach.OrderID = &dbo.OrderID.Int64
+	}
+
+	if dbo.ZoneID.Valid {
+		breach.ZoneID = &dbo.ZoneID.Int64
+	}
+
+	if dbo.ZoneName.Valid {
+		breach.ZoneName = &dbo.ZoneName.String
+	}
+
+	return breach
+}
+
+func toGeofenceBreachDBO(breach *model.GeofenceBreach) geofenceBreachDBO {
+	dbo := geofenceBreachDBO{
+		ID:         breach.ID,
+		DroneID:    breach.DroneID,
+		Kind:       string(breach.Kind),
+		Lat:        breach.Lat,
+		Lng:        breach.Lng,
+		Action:     string(breach.Action),
+		DetectedAt: breach.DetectedAt,
+	}
+
+	if breach.OrderID != nil {
+		dbo.OrderID = sql.NullInt64{Int64: *breach.OrderID, Valid: true}
+	}
+
+	if breach.ZoneID != nil {
+		dbo.ZoneID = sql.NullInt64{Int64: *breach.ZoneID, Valid: true}
+	}
+
+	if breach.ZoneName != nil {
+		dbo.ZoneName = sql.NullString{String: *breach.ZoneName, Valid: true}
+	}
+
+	return dbo
+}
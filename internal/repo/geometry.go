@@ -0,0 +1,66 @@
+package repo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+// Polygons are exchanged with MySQL as WKT with an explicit long-lat axis
+// order, matching how drone_status.location is written as POINT(lng, lat).
+const wktAxisOrder = "axis-order=long-lat"
+
+func polygonToWKT(polygon model.Polygon) string {
+	var b strings.Builder
+	b.WriteString("POLYGON((")
+	for i, v := range polygon {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s %s", formatCoord(v.Lng), formatCoord(v.Lat))
+	}
+	if len(polygon) > 0 {
+		fmt.Fprintf(&b, ", %s %s", formatCoord(polygon[0].Lng), formatCoord(polygon[0].Lat))
+	}
+	b.WriteString("))")
+	return b.String()
+}
+
+// polygonFromWKT reads the outer ring of a POLYGON and drops the closing vertex.
+func polygonFromWKT(wkt string) (model.Polygon, error) {
+	body := strings.TrimSpace(wkt)
+	if !strings.HasPrefix(strings.ToUpper(body), "POLYGON") {
+		return nil, fmt.Errorf("not a polygon: %q", wkt)
+	}
+
+	start := strings.Index(body, "((")
+	end := strings.Index(body, ")")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("malformed polygon: %q", wkt)
+	}
+
+	var polygon model.Polygon
+	for _, pair := range strings.Split(body[start+2:end], ",") {
+		fields := strings.Fields(pair)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("malformed polygon vertex %q", pair)
+		}
+		lng, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, err
+		}
+		lat, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, err
+		}
+		polygon = append(polygon, model.GeoPoint{Lat: lat, Lng: lng})
+	}
+
+	return polygon.Normalize(), nil
+}
+
+func formatCoord(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,179 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const (
+	insertLandingSiteQuery = `
+		INSERT INTO landing_sites (name, lat, lng, active)
+		VALUES (?, ?, ?, ?)
+	`
+	selectLandingSiteColumns = `
+		SELECT id, name, lat, lng, active, created_at, updated_at
+		FROM landing_sites`
+	getLandingSiteByIDQuery   = selectLandingSiteColumns + ` WHERE id = ?`
+	listLandingSitesQuery     = selectLandingSiteColumns + ` ORDER BY id`
+	listActiveLandingSitesQry = selectLandingSiteColumns + ` WHERE active = 1 ORDER BY id`
+	updateLandingSiteQuery    = `
+		UPDATE landing_sites
+		SET name = ?, lat = ?, lng = ?, active = ?, updated_at = NOW()
+		WHERE id = ?
+	`
+	deleteLandingSiteQuery = `
+		DELETE FROM landing_sites WHERE id = ?
+	`
+)
+
+type landingSiteDBO struct {
+	ID        int64        `dbo:"id"`
+	Name      string       `dbo:"name"`
+	Lat       float64      `dbo:"lat"`
+	Lng       float64      `dbo:"lng"`
+	Active    bool         `dbo:"active"`
+	CreatedAt sql.NullTime `dbo:"created_at"`
+	UpdatedAt sql.NullTime `dbo:"updated_at"`
+}
+
+type LandingSiteRepo struct {
+	db *sql.DB
+}
+
+func NewLandingSiteRepo(db *sql.DB) *LandingSiteRepo {
+	return &LandingSiteRepo{db: db}
+}
+
+func (r *LandingSiteRepo) Insert(ctx context.Context, site *model.LandingSite) (*model.LandingSite, error) {
+	result, err := r.db.ExecContext(ctx, insertLandingSiteQuery,
+		site.Name,
+		site.Lat,
+		site.Lng,
+		site.Active,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *LandingSiteRepo) GetByID(ctx context.Context, id int64) (*model.LandingSite, error) {
+	var dbo landingSiteDBO
+	err := r.db.QueryRowContext(ctx, getLandingSiteByIDQuery, id).Scan(
+		&dbo.ID,
+		&dbo.Name,
+		&dbo.Lat,
+		&dbo.Lng,
+		&dbo.Active,
+		&dbo.CreatedAt,
+		&dbo.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrLandingSiteNotFound()
+		}
+		return nil, err
+	}
+
+	return dbo.toModel(), nil
+}
+
+func (r *LandingSiteRepo) List(ctx context.Context) ([]model.LandingSite, error) {
+	return r.list(ctx, listLandingSitesQuery)
+}
+
+func (r *LandingSiteRepo) ListActive(ctx context.Context) ([]model.LandingSite, error) {
+	return r.list(ctx, listActiveLandingSitesQry)
+}
+
+func (r *LandingSiteRepo) Update(ctx context.Context, site *model.LandingSite) (*model.LandingSite, error) {
+	_, err := r.db.ExecContext(ctx, updateLandingSiteQuery,
+		site.Name,
+		site.Lat,
+		site.Lng,
+		site.Active,
+		site.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, site.ID)
+}
+
+func (r *LandingSiteRepo) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, deleteLandingSiteQuery, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrLandingSiteNotFound()
+	}
+
+	return nil
+}
+
+func (r *LandingSiteRepo) list(ctx context.Context, query string) ([]model.LandingSite, error) {
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sites []model.LandingSite
+	for rows.Next() {
+		var dbo landingSiteDBO
+		if err := rows.Scan(
+			&dbo.ID,
+			&dbo.Name,
+			&dbo.Lat,
+			&dbo.Lng,
+			&dbo.Active,
+			&dbo.CreatedAt,
+			&dbo.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		sites = append(sites, *dbo.toModel())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sites, nil
+}
+
+func (dbo *landingSiteDBO) toModel() *model.LandingSite {
+	site := &model.LandingSite{
+		ID:     dbo.ID,
+		Name:   dbo.Name,
+		Lat:    dbo.Lat,
+		Lng:    dbo.Lng,
+		Active: dbo.Active,
+	}
+
+	if dbo.CreatedAt.Valid {
+		site.CreatedAt = dbo.CreatedAt.Time
+	}
+
+	if dbo.UpdatedAt.Valid {
+		site.UpdatedAt = dbo.UpdatedAt.Time
+	}
+
+	return site
+}
@@ -0,0 +1,118 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const (
+	// MySQL applies SET assignments left to right, so term and acquired_at
+	// must read holder and expires_at before they are overwritten.
+	claimLeaseQuery = `
+		UPDATE leader_leases
+		SET term = IF(holder = ? AND expires_at > NOW(3), term, term + 1),
+			acquired_at = IF(holder = ? AND expires_at > NOW(3), acquired_at, NOW(3)),
+			holder = ?,
+			renewed_at = NOW(3),
+			expires_at = NOW(3) + INTERVAL ? MICROSECOND
+		WHERE name = ? AND (holder = ? OR expires_at <= NOW(3))
+	`
+	insertLeaseQuery = `
+		INSERT INTO leader_leases (name, holder, term, acquired_at, renewed_at, expires_at)
+		VALUES (?, ?, 1, NOW(3), NOW(3), NOW(3) + INTERVAL ? MICROSECOND)
+		ON DUPLICATE KEY UPDATE name = name
+	`
+	releaseLeaseQuery = `
+		UPDATE leader_leases SET expires_at = NOW(3)
+		WHERE name = ? AND holder = ? AND expires_at > NOW(3)
+	`
+	findLeaseQuery = `
+		SELECT name, holder, term, acquired_at, renewed_at, expires_at, expires_at <= NOW(3)
+		FROM leader_leases
+		WHERE name = ?
+	`
+)
+
+type leaseDBO struct {
+	Name       string    `dbo:"name"`
+	Holder     string    `dbo:"holder"`
+	Term       int64     `dbo:"term"`
+	AcquiredAt time.Time `dbo:"acquired_at"`
+	RenewedAt  time.Time `dbo:"renewed_at"`
+	ExpiresAt  time.Time `dbo:"expires_at"`
+	Expired    bool      `dbo:"expired"`
+}
+
+// LeaseRepo keeps leader_leases. Expiry is always judged by the database
+// clock so nodes with drifting clocks still agree on who leads.
+type LeaseRepo struct {
+	db *sql.DB
+}
+
+func NewLeaseRepo(db *sql.DB) *LeaseRepo {
+	return &LeaseRepo{db: db}
+}
+
+// Acquire takes or renews the named lease for holder when it is free,
+// expired or already held by holder, and returns the lease as it stands
+// afterwards, whoever holds it.
+func (r *LeaseRepo) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (*model.LeaderLease, error) {
+	res, err := r.db.ExecContext(ctx, claimLeaseQuery, holder, holder, holder, ttl.Microseconds(), name, holder)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if claimed == 0 {
+		// first campaign for this lease; losing the race leaves the row as is
+		if _, err := r.db.ExecContext(ctx, insertLeaseQuery, name, holder, ttl.Microseconds()); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.FindByName(ctx, name)
+}
+
+// Release expires the lease now if holder still has it, letting another
+// node take over without waiting out the ttl.
+func (r *LeaseRepo) Release(ctx context.Context, name, holder string) error {
+	_, err := r.db.ExecContext(ctx, releaseLeaseQuery, name, holder)
+	return err
+}
+
+// FindByName returns nil when no node has campaigned for the lease yet.
+func (r *LeaseRepo) FindByName(ctx context.Context, name string) (*model.LeaderLease, error) {
+	var dbo leaseDBO
+	err := r.db.QueryRowContext(ctx, findLeaseQuery, name).Scan(
+		&dbo.Name,
+		&dbo.Holder,
+		&dbo.Term,
+		&dbo.AcquiredAt,
+		&dbo.RenewedAt,
+		&dbo.ExpiresAt,
+		&dbo.Expired,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &model.LeaderLease{
+		Name:       dbo.Name,
+		Holder:     dbo.Holder,
+		Term:       dbo.Term,
+		AcquiredAt: dbo.AcquiredAt,
+		RenewedAt:  dbo.RenewedAt,
+		ExpiresAt:  dbo.ExpiresAt,
+		Expired:    dbo.Expired,
+	}, nil
+}
@@ -0,0 +1,397 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const (
+	insertMaintenanceRecordQuery = `
+		INSERT INTO maintenance_records (drone_id, issue, notes, parts, technician, opened_at, closed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	selectMaintenanceRecordColumns = `
+		SELECT id, drone_id, issue, notes, parts, technician, opened_at, closed_at, updated_at
+		FROM maintenance_records`
+	getMaintenanceRecordQuery          = selectMaintenanceRecordColumns + ` WHERE id = ?`
+	getMaintenanceRecordForUpdateQuery = selectMaintenanceRecordColumns + ` WHERE id = ? FOR UPDATE`
+	findOpenMaintenanceRecordQuery     = selectMaintenanceRecordColumns + `
+		WHERE drone_id = ? AND closed_at IS NULL
+		ORDER BY id DESC
+		LIMIT 1
+	`
+	findOpenMaintenanceRecordForUpdateQuery = findOpenMaintenanceRecordQuery + ` FOR UPDATE`
+	listMaintenanceRecordsBaseQuery         = selectMaintenanceRecordColumns + ` WHERE 1 = 1`
+	updateMaintenanceRecordQuery            = `
+		UPDATE maintenance_records
+		SET issue = ?, notes = ?, parts = ?, technician = ?, closed_at = ?
+		WHERE id = ?
+	`
+	// usage since the last service is summed over the trips that ended after it
+	maintenanceUsageQuery = `
+		SELECT ds.drone_id, ds.status, ls.closed_at AS last_service_at,
+		       ms.flight_hours, ms.cycles,
+		       COALESCE(SUM(TIMESTAMPDIFF(SECOND, t.created_at, t.completed_at)), 0) AS flight_seconds,
+		       COUNT(t.id) AS cycles_flown
+		FROM drone_status ds
+		LEFT JOIN (
+			SELECT drone_id, MAX(closed_at) AS closed_at
+			FROM maintenance_records
+			WHERE closed_at IS NOT NULL
+			GROUP BY drone_id
+		) ls ON ls.drone_id = ds.drone_id
+		LEFT JOIN maintenance_schedules ms ON ms.drone_id = ds.drone_id
+		LEFT JOIN trips t
+		       ON t.drone_id = ds.drone_id AND t.completed_at IS NOT NULL
+		      AND (ls.closed_at IS NULL OR t.completed_at > ls.closed_at)
+		WHERE ? = 0 OR ds.drone_id = ?
+		GROUP BY ds.drone_id, ds.status, ls.closed_at, ms.flight_hours, ms.cycles
+		ORDER BY ds.drone_id
+	`
+	upsertMaintenanceScheduleQuery = `
+		INSERT INTO maintenance_schedules (drone_id, flight_hours, cycles)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE flight_hours = VALUES(flight_hours), cycles = VALUES(cycles)
+	`
+	deleteMaintenanceScheduleQuery = `
+		DELETE FROM maintenance_schedules WHERE drone_id = ?
+	`
+)
+
+type maintenanceRecordDBO struct {
+	ID         int64          `dbo:"id"`
+	DroneID    int64          `dbo:"drone_id"`
+	Issue      string         `dbo:"issue"`
+	Notes      sql.NullString `dbo:"notes"`
+	Parts      sql.NullString `dbo:"parts"`
+	Technician sql.NullString `dbo:"technician"`
+	OpenedAt   time.Time      `dbo:"opened_at"`
+	ClosedAt   sql.NullTime   `dbo:"closed_at"`
+	UpdatedAt  time.Time      `dbo:"updated_at"`
+}
+
+type maintenanceUsageDBO struct {
+	DroneID       int64           `dbo:"drone_id"`
+	Status        string          `dbo:"status"`
+	LastServiceAt sql.NullTime    `dbo:"last_service_at"`
+	FlightHours   sql.NullFloat64 `dbo:"flight_hours"`
+	Cycles        sql.NullInt64   `dbo:"cycles"`
+	FlightSeconds int64           `dbo:"flight_seconds"`
+	CyclesFlown   int             `dbo:"cycles_flown"`
+}
+
+// MaintenanceRepo keeps drone maintenance records and service intervals.
+type MaintenanceRepo struct {
+	db *sql.DB
+}
+
+func NewMaintenanceRepo(db *sql.DB) *MaintenanceRepo {
+	return &MaintenanceRepo{db: db}
+}
+
+func (r *MaintenanceRepo) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, nil)
+}
+
+func (r *MaintenanceRepo) InsertTx(ctx context.Context, tx *sql.Tx, record *model.MaintenanceRecord) (*model.MaintenanceRecord, error) {
+	dbo, err := toMaintenanceRecordDBO(record)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tx.ExecContext(ctx, insertMaintenanceRecordQuery,
+		dbo.DroneID,
+		dbo.Issue,
+		dbo.Notes,
+		dbo.Parts,
+		dbo.Technician,
+		dbo.OpenedAt,
+		dbo.ClosedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return getMaintenanceRecord(tx.QueryRowContext(ctx, getMaintenanceRecordQuery, id))
+}
+
+func (r *MaintenanceRepo) UpdateTx(ctx context.Context, tx *sql.Tx, record *model.MaintenanceRecord) (*model.MaintenanceRecord, error) {
+	dbo, err := toMaintenanceRecordDBO(record)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, updateMaintenanceRecordQuery,
+		dbo.Issue,
+		dbo.Notes,
+		dbo.Parts,
+		dbo.Technician,
+		dbo.ClosedAt,
+		dbo.ID,
+	); err != nil {
+		return nil, err
+	}
+
+	return getMaintenanceRecord(tx.QueryRowContext(ctx, getMaintenanceRecordQuery, dbo.ID))
+}
+
+func (r *MaintenanceRepo) GetByID(ctx context.Context, id int64) (*model.MaintenanceRecord, error) {
+	return getMaintenanceRecord(r.db.QueryRowContext(ctx, getMaintenanceRecordQuery, id))
+}
+
+func (r *MaintenanceRepo) GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (*model.MaintenanceRecord, error) {
+	return getMaintenanceRecord(tx.QueryRowContext(ctx, getMaintenanceRecordForUpdateQuery, id))
+}
+
+// FindOpenByDrone returns the drone's open record, or nil when it has none.
+func (r *MaintenanceRepo) FindOpenByDrone(ctx context.Context, droneID int64) (*model.MaintenanceRecord, error) {
+	return findOpenMaintenanceRecord(r.db.QueryRowContext(ctx, findOpenMaintenanceRecordQuery, droneID))
+}
+
+func (r *MaintenanceRepo) FindOpenByDroneForUpdate(ctx context.Context, tx *sql.Tx, droneID int64) (*model.MaintenanceRecord, error) {
+	return findOpenMaintenanceRecord(tx.QueryRowContext(ctx, findOpenMaintenanceRecordForUpdateQuery, droneID))
+}
+
+func (r *MaintenanceRepo) List(ctx context.Context, filter model.MaintenanceFilter, limit, offset int) ([]model.MaintenanceRecord, error) {
+	query := listMaintenanceRecordsBaseQuery
+	args := make([]interface{}, 0, 3)
+
+	if filter.DroneID != nil {
+		query += " AND drone_id = ?"
+		args = append(args, *filter.DroneID)
+	}
+	if filter.Open != nil {
+		if *filter.Open {
+			query += " AND closed_at IS NULL"
+		} else {
+			query += " AND closed_at IS NOT NULL"
+		}
+	}
+
+	query += " ORDER BY opened_at DESC, id DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []model.MaintenanceRecord
+	for rows.Next() {
+		record, err := scanMaintenanceRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, *record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// GetUsage returns what the drone has flown since its last service.
+func (r *MaintenanceRepo) GetUsage(ctx context.Context, droneID int64) (*model.MaintenanceUsage, error) {
+	usage, err := r.listUsage(ctx, droneID)
+	if err != nil {
+		return nil, err
+	}
+	if len(usage) == 0 {
+		return nil, ErrDroneNotFound()
+	}
+	return &usage[0], nil
+}
+
+// ListUsage returns every drone's usage since its last service.
+func (r *MaintenanceRepo) ListUsage(ctx context.Context) ([]model.MaintenanceUsage, error) {
+	return r.listUsage(ctx, 0)
+}
+
+func (r *MaintenanceRepo) listUsage(ctx context.Context, droneID int64) ([]model.MaintenanceUsage, error) {
+	rows, err := r.db.QueryContext(ctx, maintenanceUsageQuery, droneID, droneID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usage []model.MaintenanceUsage
+	for rows.Next() {
+		var dbo maintenanceUsageDBO
+		if err := rows.Scan(
+			&dbo.DroneID,
+			&dbo.Status,
+			&dbo.LastServiceAt,
+			&dbo.FlightHours,
+			&dbo.Cycles,
+			&dbo.FlightSeconds,
+			&dbo.CyclesFlown,
+		); err != nil {
+			return nil, err
+		}
+		usage = append(usage, dbo.toModel())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// SetSchedule stores the drone's own service interval; a schedule with
+// neither field set puts the drone back on the fleet policy.
+func (r *MaintenanceRepo) SetSchedule(ctx context.Context, droneID int64, schedule model.MaintenanceSchedule) error {
+	if schedule.IsDefault() {
+		_, err := r.db.ExecContext(ctx, deleteMaintenanceScheduleQuery, droneID)
+		return err
+	}
+
+	var flightHours sql.NullFloat64
+	if schedule.FlightHours != nil {
+		flightHours = sql.NullFloat64{Float64: *schedule.FlightHours, Valid: true}
+	}
+	var cycles sql.NullInt64
+	if schedule.Cycles != nil {
+		cycles = sql.NullInt64{Int64: int64(*schedule.Cycles), Valid: true}
+	}
+
+	_, err := r.db.ExecContext(ctx, upsertMaintenanceScheduleQuery, droneID, flightHours, cycles)
+	return err
+}
+
+func getMaintenanceRecord(row rowScanner) (*model.MaintenanceRecord, error) {
+	record, err := scanMaintenanceRecord(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMaintenanceRecordNotFound()
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+func findOpenMaintenanceRecord(row rowScanner) (*model.MaintenanceRecord, error) {
+	record, err := scanMaintenanceRecord(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+func scanMaintenanceRecord(row rowScanner) (*model.MaintenanceRecord, error) {
+	var dbo maintenanceRecordDBO
+	err := row.Scan(
+		&dbo.ID,
+		&dbo.DroneID,
+		&dbo.Issue,
+		&dbo.Notes,
+		&dbo.Parts,
+		&dbo.Technician,
+		&dbo.OpenedAt,
+		&dbo.ClosedAt,
+		&dbo.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return dbo.toModel()
+}
+
+func (dbo *maintenanceRecordDBO) toModel() (*model.MaintenanceRecord, error) {
+	record := &model.MaintenanceRecord{
+		ID:         dbo.ID,
+		DroneID:    dbo.DroneID,
+		Issue:      model.MaintenanceIssue(dbo.Issue),
+		Notes:      dbo.Notes.String,
+		Technician: dbo.Technician.String,
+		OpenedAt:   dbo.OpenedAt,
+		UpdatedAt:  dbo.UpdatedAt,
+	}
+
+	if dbo.Parts.Valid {
+		if err := json.Unmarshal([]byte(dbo.Parts.String), &record.Parts); err != nil {
+			return nil, err
+		}
+	}
+
+	if dbo.ClosedAt.Valid {
+		record.ClosedAt = &dbo.ClosedAt.Time
+	}
+
+	return record, nil
+}
+
+func toMaintenanceRecordDBO(record *model.MaintenanceRecord) (maintenanceRecordDBO, error) {
+	dbo := maintenanceRecordDBO{
+		ID:        record.ID,
+		DroneID:   record.DroneID,
+		Issue:     string(record.Issue),
+		OpenedAt:  record.OpenedAt,
+		UpdatedAt: record.UpdatedAt,
+	}
+
+	if record.Notes != "" {
+		dbo.Notes = sql.NullString{String: record.Notes, Valid: true}
+	}
+
+	if len(record.Parts) > 0 {
+		parts, err := json.Marshal(record.Parts)
+		if err != nil {
+			return maintenanceRecordDBO{}, err
+		}
+		// sent as text: MySQL refuses JSON from a binary string
+		dbo.Parts = sql.NullString{String: string(parts), Valid: true}
+	}
+
+	if record.Technician != "" {
+		dbo.Technician = sql.NullString{String: record.Technician, Valid: true}
+	}
+
+	if record.ClosedAt != nil {
+		dbo.ClosedAt = sql.NullTime{Time: *record.ClosedAt, Valid: true}
+	}
+
+	return dbo, nil
+}
+
+func (dbo maintenanceUsageDBO) toModel() model.MaintenanceUsage {
+	usage := model.MaintenanceUsage{
+		DroneID:    dbo.DroneID,
+		Status:     model.DroneStatus(dbo.Status),
+		FlightTime: time.Duration(dbo.FlightSeconds) * time.Second,
+		Cycles:     dbo.CyclesFlown,
+	}
+
+	if dbo.LastServiceAt.Valid {
+		usage.LastServiceAt = &dbo.LastServiceAt.Time
+	}
+
+	if dbo.FlightHours.Valid {
+		flightHours := dbo.FlightHours.Float64
+		usage.Schedule.FlightHours = &flightHours
+	}
+
+	if dbo.Cycles.Valid {
+		cycles := int(dbo.Cycles.Int64)
+		usage.Schedule.Cycles = &cycles
+	}
+
+	return usage
+}
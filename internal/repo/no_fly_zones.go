@@ -0,0 +1,222 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const (
+	insertNoFlyZoneQuery = `
+		INSERT INTO no_fly_zones (name, area, starts_at, ends_at)
+		VALUES (?, ST_GeomFromText(?, 4326, '` + wktAxisOrder + `'), ?, ?)
+	`
+	selectNoFlyZoneColumns = `
+		SELECT id, name, ST_AsText(area, '` + wktAxisOrder + `'), starts_at, ends_at, created_at, updated_at
+		FROM no_fly_zones`
+	getNoFlyZoneByIDQuery       = selectNoFlyZoneColumns + ` WHERE id = ?`
+	listNoFlyZonesQuery         = selectNoFlyZoneColumns + ` ORDER BY id`
+	listNoFlyZonesActiveAtQuery = selectNoFlyZoneColumns + `
+		WHERE (starts_at IS NULL OR starts_at <= ?)
+		  AND (ends_at IS NULL OR ends_at > ?)
+		ORDER BY id`
+	updateNoFlyZoneQuery = `
+		UPDATE no_fly_zones
+		SET name = ?, area = ST_GeomFromText(?, 4326, '` + wktAxisOrder + `'), starts_at = ?, ends_at = ?, updated_at = NOW()
+		WHERE id = ?
+	`
+	deleteNoFlyZoneQuery = `
+		DELETE FROM no_fly_zones WHERE id = ?
+	`
+)
+
+type noFlyZoneDBO struct {
+	ID        int64        `dbo:"id"`
+	Name      string       `dbo:"name"`
+	AreaWKT   string       `dbo:"area"`
+	StartsAt  sql.NullTime `dbo:"starts_at"`
+	EndsAt    sql.NullTime `dbo:"ends_at"`
+	CreatedAt sql.NullTime `dbo:"created_at"`
+	UpdatedAt sql.NullTime `dbo:"updated_at"`
+}
+
+type NoFlyZoneRepo struct {
+	db *sql.DB
+}
+
+func NewNoFlyZoneRepo(db *sql.DB) *NoFlyZoneRepo {
+	return &NoFlyZoneRepo{db: db}
+}
+
+func (r *NoFlyZoneRepo) Insert(ctx context.Context, zone *model.NoFlyZone) (*model.NoFlyZone, error) {
+	dbo := toNoFlyZoneDBO(zone)
+
+	result, err := r.db.ExecContext(ctx, insertNoFlyZoneQuery,
+		dbo.Name,
+		dbo.AreaWKT,
+		dbo.StartsAt,
+		dbo.EndsAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *NoFlyZoneRepo) GetByID(ctx context.Context, id int64) (*model.NoFlyZone, error) {
+	var dbo noFlyZoneDBO
+	err := r.db.QueryRowContext(ctx, getNoFlyZoneByIDQuery, id).Scan(
+		&dbo.ID,
+		&dbo.Name,
+		&dbo.AreaWKT,
+		&dbo.StartsAt,
+		&dbo.EndsAt,
+		&dbo.CreatedAt,
+		&dbo.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoFlyZoneNotFound()
+		}
+		return nil, err
+	}
+
+	return dbo.toModel()
+}
+
+func (r *NoFlyZoneRepo) List(ctx context.Context) ([]model.NoFlyZone, error) {
+	return r.list(ctx, listNoFlyZonesQuery)
+}
+
+// ListActiveAt returns the zones whose time window contains t.
+func (r *NoFlyZoneRepo) ListActiveAt(ctx context.Context, t time.Time) ([]model.NoFlyZone, error) {
+	t = t.UTC()
+	return r.list(ctx, listNoFlyZonesActiveAtQuery, t, t)
+}
+
+func (r *NoFlyZoneRepo) Update(ctx context.Context, zone *model.NoFlyZone) (*model.NoFlyZone, error) {
+	dbo := toNoFlyZoneDBO(zone)
+
+	_, err := r.db.ExecContext(ctx, updateNoFlyZoneQuery,
+		dbo.Name,
+		dbo.AreaWKT,
+		dbo.StartsAt,
+		dbo.EndsAt,
+		dbo.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, zone.ID)
+}
+
+func (r *NoFlyZoneRepo) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, deleteNoFlyZoneQuery, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNoFlyZoneNotFound()
+	}
+
+	return nil
+}
+
+func (r *NoFlyZoneRepo) list(ctx context.Context, query string, args ...interface{}) ([]model.NoFlyZone, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var zones []model.NoFlyZone
+	for rows.Next() {
+		var dbo noFlyZoneDBO
+		if err := rows.Scan(
+			&dbo.ID,
+			&dbo.Name,
+			&dbo.AreaWKT,
+			&dbo.StartsAt,
+			&dbo.EndsAt,
+			&dbo.CreatedAt,
+			&dbo.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		zone, err := dbo.toModel()
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, *zone)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+func (dbo *noFlyZoneDBO) toModel() (*model.NoFlyZone, error) {
+	boundary, err := polygonFromWKT(dbo.AreaWKT)
+	if err != nil {
+		return nil, err
+	}
+
+	zone := &model.NoFlyZone{
+		ID:       dbo.ID,
+		Name:     dbo.Name,
+		Boundary: boundary,
+	}
+
+	if dbo.StartsAt.Valid {
+		zone.StartsAt = &dbo.StartsAt.Time
+	}
+
+	if dbo.EndsAt.Valid {
+		zone.EndsAt = &dbo.EndsAt.Time
+	}
+
+	if dbo.CreatedAt.Valid {
+		zone.CreatedAt = dbo.CreatedAt.Time
+	}
+
+	if dbo.UpdatedAt.Valid {
+		zone.UpdatedAt = dbo.UpdatedAt.Time
+	}
+
+	return zone, nil
+}
+
+func toNoFlyZoneDBO(zone *model.NoFlyZone) noFlyZoneDBO {
+	dbo := noFlyZoneDBO{
+		ID:      zone.ID,
+		Name:    zone.Name,
+		AreaWKT: polygonToWKT(zone.Boundary),
+	}
+
+	if zone.StartsAt != nil {
+		dbo.StartsAt = sql.NullTime{Time: zone.StartsAt.UTC(), Valid: true}
+	}
+
+	if zone.EndsAt != nil {
+		dbo.EndsAt = sql.NullTime{Time: zone.EndsAt.UTC(), Valid: true}
+	}
+
+	return dbo
+}
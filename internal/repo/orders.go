@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
 	"github.com/go-sql-driver/mysql"
@@ -11,20 +12,25 @@ import (
 
 const (
 	insertOrderQuery = `
-		INSERT INTO orders (enduser_id, pickup_lat, pickup_lng, dropoff_lat, dropoff_lng, status)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO orders (enduser_id, pickup_address_id, dropoff_address_id, pickup_lat, pickup_lng,
+		                    dropoff_lat, dropoff_lng, delivery_notes, access_instructions, status, delivery_pin)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	getOrderByIDQuery = `
-		SELECT id, enduser_id, pickup_lat, pickup_lng, dropoff_lat, dropoff_lng, 
-		       status, assigned_drone_id, handoff_lat, handoff_lng, 
-		       created_at, updated_at, canceled_at
+		SELECT id, enduser_id, pickup_address_id, dropoff_address_id,
+		       pickup_lat, pickup_lng, dropoff_lat, dropoff_lng,
+		       delivery_notes, access_instructions,
+		       status, assigned_drone_id, offered_drone_id, handoff_lat, handoff_lng, 
+		       return_lat, return_lng, delivery_pin, pin_attempts, created_at, updated_at, canceled_at
 		FROM orders
 		WHERE id = ?
 	`
 	getOrderByIDForUpdateQuery = `
-		SELECT id, enduser_id, pickup_lat, pickup_lng, dropoff_lat, dropoff_lng, 
-		       status, assigned_drone_id, handoff_lat, handoff_lng, 
-		       created_at, updated_at, canceled_at
+		SELECT id, enduser_id, pickup_address_id, dropoff_address_id,
+		       pickup_lat, pickup_lng, dropoff_lat, dropoff_lng,
+		       delivery_notes, access_instructions,
+		       status, assigned_drone_id, offered_drone_id, handoff_lat, handoff_lng, 
+		       return_lat, return_lng, delivery_pin, pin_attempts, created_at, updated_at, canceled_at
 		FROM orders
 		WHERE id = ? FOR UPDATE
 	`
@@ -32,38 +38,85 @@ const (
 		UPDATE orders 
 		SET status = ?, 
 		    assigned_drone_id = ?, 
+		    offered_drone_id = ?,
 		    pickup_lat = ?,
 		    pickup_lng = ?,
 		    dropoff_lat = ?,
 		    dropoff_lng = ?,
+		    pickup_address_id = ?,
+		    dropoff_address_id = ?,
+		    delivery_notes = ?,
+		    access_instructions = ?,
 		    handoff_lat = ?, 
 		    handoff_lng = ?, 
+		    return_lat = ?,
+		    return_lng = ?,
+		    pin_attempts = ?,
 		    updated_at = NOW(),
 		    canceled_at = CASE WHEN ? = 'canceled' THEN NOW() ELSE canceled_at END
 		WHERE id = ?
 	`
+	setOfferedDroneQuery = `
+		UPDATE orders
+		SET offered_drone_id = ?, offered_at = NOW(3)
+		WHERE id = ? AND status IN ('pending','handoff_pending')
+	`
+	listAwaitingOfferQuery = `
+		SELECT id, enduser_id, pickup_address_id, dropoff_address_id,
+		       pickup_lat, pickup_lng, dropoff_lat, dropoff_lng,
+		       delivery_notes, access_instructions,
+		       status, assigned_drone_id, offered_drone_id, handoff_lat, handoff_lng,
+		       return_lat, return_lng, delivery_pin, pin_attempts, created_at, updated_at, canceled_at
+		FROM orders
+		WHERE status IN ('pending','handoff_pending')
+		  AND (offered_drone_id IS NULL OR offered_at < NOW(3) - INTERVAL ? MICROSECOND)
+		ORDER BY id
+		LIMIT ?
+	`
+	listActiveOrdersByDroneForUpdateQuery = `
+		SELECT id, enduser_id, pickup_address_id, dropoff_address_id,
+		       pickup_lat, pickup_lng, dropoff_lat, dropoff_lng,
+		       delivery_notes, access_instructions,
+		       status, assigned_drone_id, offered_drone_id, handoff_lat, handoff_lng,
+		       return_lat, return_lng, delivery_pin, pin_attempts, created_at, updated_at, canceled_at
+		FROM orders
+		WHERE assigned_drone_id = ? AND status IN ('reserved','picked_up','returning')
+		ORDER BY id
+		FOR UPDATE
+	`
 	listOrdersBaseQuery = `
-		SELECT id, enduser_id, pickup_lat, pickup_lng, dropoff_lat, dropoff_lng,
-		       status, assigned_drone_id, handoff_lat, handoff_lng,
-		       created_at, updated_at, canceled_at
+		SELECT id, enduser_id, pickup_address_id, dropoff_address_id,
+		       pickup_lat, pickup_lng, dropoff_lat, dropoff_lng,
+		       delivery_notes, access_instructions,
+		       status, assigned_drone_id, offered_drone_id, handoff_lat, handoff_lng,
+		       return_lat, return_lng, delivery_pin, pin_attempts, created_at, updated_at, canceled_at
 		FROM orders
 		WHERE 1=1`
 )
 
 type orderDBO struct {
-	ID              int64           `dbo:"id"`
-	EnduserID       int64           `dbo:"enduser_id"`
-	PickupLat       float64         `dbo:"pickup_lat"`
-	PickupLng       float64         `dbo:"pickup_lng"`
-	DropoffLat      float64         `dbo:"dropoff_lat"`
-	DropoffLng      float64         `dbo:"dropoff_lng"`
-	Status          string          `dbo:"status"`
-	AssignedDroneID sql.NullInt64   `dbo:"assigned_drone_id"`
-	HandoffLat      sql.NullFloat64 `dbo:"handoff_lat"`
-	HandoffLng      sql.NullFloat64 `dbo:"handoff_lng"`
-	CreatedAt       sql.NullTime    `dbo:"created_at"`
-	UpdatedAt       sql.NullTime    `dbo:"updated_at"`
-	CanceledAt      sql.NullTime    `dbo:"canceled_at"`
+	ID                 int64           `dbo:"id"`
+	EnduserID          int64           `dbo:"enduser_id"`
+	PickupAddressID    sql.NullInt64   `dbo:"pickup_address_id"`
+	DropoffAddressID   sql.NullInt64   `dbo:"dropoff_address_id"`
+	PickupLat          float64         `dbo:"pickup_lat"`
+	PickupLng          float64         `dbo:"pickup_lng"`
+	DropoffLat         float64         `dbo:"dropoff_lat"`
+	DropoffLng         float64         `dbo:"dropoff_lng"`
+	DeliveryNotes      sql.NullString  `dbo:"delivery_notes"`
+	AccessInstructions sql.NullString  `dbo:"access_instructions"`
+	Status             string          `dbo:"status"`
+	AssignedDroneID    sql.NullInt64   `dbo:"assigned_drone_id"`
+	OfferedDroneID     sql.NullInt64   `dbo:"offered_drone_id"`
+	HandoffLat         sql.NullFloat64 `dbo:"handoff_lat"`
+	HandoffLng         sql.NullFloat64 `dbo:"handoff_lng"`
+	ReturnLat          sql.NullFloat64 `dbo:"return_lat"`
+	ReturnLng          sql.NullFloat64 `dbo:"return_lng"`
+	DeliveryPIN        sql.NullString  `dbo:"delivery_pin"`
+	PINAttempts        int             `dbo:"pin_attempts"`
+	CreatedAt          sql.NullTime    `dbo:"created_at"`
+	UpdatedAt          sql.NullTime    `dbo:"updated_at"`
+	CanceledAt         sql.NullTime    `dbo:"canceled_at"`
 }
 
 type OrderRepo struct {
@@ -83,11 +136,16 @@ func (r *OrderRepo) Insert(ctx context.Context, order *model.Order) (*model.Orde
 
 	result, err := r.db.ExecContext(ctx, insertOrderQuery,
 		dbo.EnduserID,
+		dbo.PickupAddressID,
+		dbo.DropoffAddressID,
 		dbo.PickupLat,
 		dbo.PickupLng,
 		dbo.DropoffLat,
 		dbo.DropoffLng,
+		dbo.DeliveryNotes,
+		dbo.AccessInstructions,
 		dbo.Status,
+		dbo.DeliveryPIN,
 	)
 	if err != nil {
 		if isFKConstraintError(err) {
@@ -109,14 +167,23 @@ func (r *OrderRepo) GetByID(ctx context.Context, id int64) (*model.Order, error)
 	err := r.db.QueryRowContext(ctx, getOrderByIDQuery, id).Scan(
 		&dbo.ID,
 		&dbo.EnduserID,
+		&dbo.PickupAddressID,
+		&dbo.DropoffAddressID,
 		&dbo.PickupLat,
 		&dbo.PickupLng,
 		&dbo.DropoffLat,
 		&dbo.DropoffLng,
+		&dbo.DeliveryNotes,
+		&dbo.AccessInstructions,
 		&dbo.Status,
 		&dbo.AssignedDroneID,
+		&dbo.OfferedDroneID,
 		&dbo.HandoffLat,
 		&dbo.HandoffLng,
+		&dbo.ReturnLat,
+		&dbo.ReturnLng,
+		&dbo.DeliveryPIN,
+		&dbo.PINAttempts,
 		&dbo.CreatedAt,
 		&dbo.UpdatedAt,
 		&dbo.CanceledAt,
@@ -136,14 +203,23 @@ func (r *OrderRepo) GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64)
 	err := tx.QueryRowContext(ctx, getOrderByIDForUpdateQuery, id).Scan(
 		&dbo.ID,
 		&dbo.EnduserID,
+		&dbo.PickupAddressID,
+		&dbo.DropoffAddressID,
 		&dbo.PickupLat,
 		&dbo.PickupLng,
 		&dbo.DropoffLat,
 		&dbo.DropoffLng,
+		&dbo.DeliveryNotes,
+		&dbo.AccessInstructions,
 		&dbo.Status,
 		&dbo.AssignedDroneID,
+		&dbo.OfferedDroneID,
 		&dbo.HandoffLat,
 		&dbo.HandoffLng,
+		&dbo.ReturnLat,
+		&dbo.ReturnLng,
+		&dbo.DeliveryPIN,
+		&dbo.PINAttempts,
 		&dbo.CreatedAt,
 		&dbo.UpdatedAt,
 		&dbo.CanceledAt,
@@ -163,12 +239,20 @@ func (r *OrderRepo) UpdateTx(ctx context.Context, tx *sql.Tx, order *model.Order
 	_, err := tx.ExecContext(ctx, updateOrderQuery,
 		dbo.Status,
 		dbo.AssignedDroneID,
+		dbo.OfferedDroneID,
 		dbo.PickupLat,
 		dbo.PickupLng,
 		dbo.DropoffLat,
 		dbo.DropoffLng,
+		dbo.PickupAddressID,
+		dbo.DropoffAddressID,
+		dbo.DeliveryNotes,
+		dbo.AccessInstructions,
 		dbo.HandoffLat,
 		dbo.HandoffLng,
+		dbo.ReturnLat,
+		dbo.ReturnLng,
+		dbo.PINAttempts,
 		dbo.Status,
 		dbo.ID,
 	)
@@ -179,9 +263,28 @@ func (r *OrderRepo) UpdateTx(ctx context.Context, tx *sql.Tx, order *model.Order
 	return r.GetByIDForUpdate(ctx, tx, order.ID)
 }
 
+// SetOfferedDrone records the drone an assignment offer went to. Orders that
+// were reserved or closed in the meantime are left alone.
+func (r *OrderRepo) SetOfferedDrone(ctx context.Context, orderID, droneID int64) error {
+	_, err := r.db.ExecContext(ctx, setOfferedDroneQuery, droneID, orderID)
+	return err
+}
+
+// ListAwaitingOffer returns orders waiting for a drone that have not been
+// offered to one within offerTimeout, oldest first.
+func (r *OrderRepo) ListAwaitingOffer(ctx context.Context, offerTimeout time.Duration, limit int) ([]model.Order, error) {
+	rows, err := r.db.QueryContext(ctx, listAwaitingOfferQuery, offerTimeout.Microseconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOrders(rows)
+}
+
 func (r *OrderRepo) List(ctx context.Context, filters model.OrderListFilters, limit, offset int) ([]model.Order, error) {
 	query := listOrdersBaseQuery
-	args := make([]interface{}, 0, 5)
+	args := make([]interface{}, 0, 7)
 
 	if filters.Status != nil {
 		query += " AND status = ?"
@@ -195,8 +298,16 @@ func (r *OrderRepo) List(ctx context.Context, filters model.OrderListFilters, li
 		query += " AND assigned_drone_id = ?"
 		args = append(args, *filters.AssignedDroneID)
 	}
+	if filters.CreatedFrom != nil {
+		query += " AND created_at >= ?"
+		args = append(args, *filters.CreatedFrom)
+	}
+	if filters.CreatedTo != nil {
+		query += " AND created_at <= ?"
+		args = append(args, *filters.CreatedTo)
+	}
 
-	query += " ORDER BY created_at DESC LIMIT ? OFFSET ?"
+	query += " ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?"
 	args = append(args, limit, offset)
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -205,20 +316,45 @@ func (r *OrderRepo) List(ctx context.Context, filters model.OrderListFilters, li
 	}
 	defer rows.Close()
 
+	return scanOrders(rows)
+}
+
+// ListActiveByDroneForUpdate locks every order the drone is still carrying or
+// on its way to collect.
+func (r *OrderRepo) ListActiveByDroneForUpdate(ctx context.Context, tx *sql.Tx, droneID int64) ([]model.Order, error) {
+	rows, err := tx.QueryContext(ctx, listActiveOrdersByDroneForUpdateQuery, droneID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOrders(rows)
+}
+
+func scanOrders(rows *sql.Rows) ([]model.Order, error) {
 	var orders []model.Order
 	for rows.Next() {
 		var dbo orderDBO
 		if err := rows.Scan(
 			&dbo.ID,
 			&dbo.EnduserID,
+			&dbo.PickupAddressID,
+			&dbo.DropoffAddressID,
 			&dbo.PickupLat,
 			&dbo.PickupLng,
 			&dbo.DropoffLat,
 			&dbo.DropoffLng,
+			&dbo.DeliveryNotes,
+			&dbo.AccessInstructions,
 			&dbo.Status,
 			&dbo.AssignedDroneID,
+			&dbo.OfferedDroneID,
 			&dbo.HandoffLat,
 			&dbo.HandoffLng,
+			&dbo.ReturnLat,
+			&dbo.ReturnLng,
+			&dbo.DeliveryPIN,
+			&dbo.PINAttempts,
 			&dbo.CreatedAt,
 			&dbo.UpdatedAt,
 			&dbo.CanceledAt,
@@ -237,24 +373,49 @@ func (r *OrderRepo) List(ctx context.Context, filters model.OrderListFilters, li
 
 func toOrderModel(dbo orderDBO) *model.Order {
 	o := &model.Order{
-		ID:         dbo.ID,
-		EnduserID:  dbo.EnduserID,
-		PickupLat:  dbo.PickupLat,
-		PickupLng:  dbo.PickupLng,
-		DropoffLat: dbo.DropoffLat,
-		DropoffLng: dbo.DropoffLng,
-		Status:     model.OrderStatus(dbo.Status),
+		ID:          dbo.ID,
+		EnduserID:   dbo.EnduserID,
+		PickupLat:   dbo.PickupLat,
+		PickupLng:   dbo.PickupLng,
+		DropoffLat:  dbo.DropoffLat,
+		DropoffLng:  dbo.DropoffLng,
+		Status:      model.OrderStatus(dbo.Status),
+		PINAttempts: dbo.PINAttempts,
 	}
 
 	if dbo.AssignedDroneID.Valid {
 		o.AssignedDroneID = &dbo.AssignedDroneID.Int64
 	}
+	if dbo.OfferedDroneID.Valid {
+		o.OfferedDroneID = &dbo.OfferedDroneID.Int64
+	}
+	if dbo.PickupAddressID.Valid {
+		o.PickupAddressID = &dbo.PickupAddressID.Int64
+	}
+	if dbo.DropoffAddressID.Valid {
+		o.DropoffAddressID = &dbo.DropoffAddressID.Int64
+	}
+	if dbo.DeliveryNotes.Valid {
+		o.DeliveryNotes = &dbo.DeliveryNotes.String
+	}
+	if dbo.AccessInstructions.Valid {
+		o.AccessInstructions = &dbo.AccessInstructions.String
+	}
 	if dbo.HandoffLat.Valid {
 		o.HandoffLat = &dbo.HandoffLat.Float64
 	}
 	if dbo.HandoffLng.Valid {
 		o.HandoffLng = &dbo.HandoffLng.Float64
 	}
+	if dbo.ReturnLat.Valid {
+		o.ReturnLat = &dbo.ReturnLat.Float64
+	}
+	if dbo.ReturnLng.Valid {
+		o.ReturnLng = &dbo.ReturnLng.Float64
+	}
+	if dbo.DeliveryPIN.Valid {
+		o.DeliveryPIN = &dbo.DeliveryPIN.String
+	}
 	if dbo.CreatedAt.Valid {
 		o.CreatedAt = dbo.CreatedAt.Time
 	}
@@ -270,24 +431,49 @@ func toOrderModel(dbo orderDBO) *model.Order {
 
 func toOrderDBO(order *model.Order) orderDBO {
 	dbo := orderDBO{
-		ID:         order.ID,
-		EnduserID:  order.EnduserID,
-		PickupLat:  order.PickupLat,
-		PickupLng:  order.PickupLng,
-		DropoffLat: order.DropoffLat,
-		DropoffLng: order.DropoffLng,
-		Status:     string(order.Status),
+		ID:          order.ID,
+		EnduserID:   order.EnduserID,
+		PickupLat:   order.PickupLat,
+		PickupLng:   order.PickupLng,
+		DropoffLat:  order.DropoffLat,
+		DropoffLng:  order.DropoffLng,
+		Status:      string(order.Status),
+		PINAttempts: order.PINAttempts,
 	}
 
 	if order.AssignedDroneID != nil {
 		dbo.AssignedDroneID = sql.NullInt64{Int64: *order.AssignedDroneID, Valid: true}
 	}
+	if order.OfferedDroneID != nil {
+		dbo.OfferedDroneID = sql.NullInt64{Int64: *order.OfferedDroneID, Valid: true}
+	}
+	if order.PickupAddressID != nil {
+		dbo.PickupAddressID = sql.NullInt64{Int64: *order.PickupAddressID, Valid: true}
+	}
+	if order.DropoffAddressID != nil {
+		dbo.DropoffAddressID = sql.NullInt64{Int64: *order.DropoffAddressID, Valid: true}
+	}
+	if order.DeliveryNotes != nil {
+		dbo.DeliveryNotes = sql.NullString{String: *order.DeliveryNotes, Valid: true}
+	}
+	if order.AccessInstructions != nil {
+		dbo.AccessInstructions = sql.NullString{String: *order.AccessInstructions, Valid: true}
+	}
 	if order.HandoffLat != nil {
 		dbo.HandoffLat = sql.NullFloat64{Float64: *order.HandoffLat, Valid: true}
 	}
 	if order.HandoffLng != nil {
 		dbo.HandoffLng = sql.NullFloat64{Float64: *order.HandoffLng, Valid: true}
 	}
+	if order.ReturnLat != nil {
+		dbo.ReturnLat = sql.NullFloat64{Float64: *order.ReturnLat, Valid: true}
+	}
+	if order.ReturnLng != nil {
+		dbo.ReturnLng = sql.NullFloat64{Float64: *order.ReturnLng, Valid: true}
+	}
+	if order.DeliveryPIN != nil {
+		dbo.DeliveryPIN = sql.NullString{String: *order.DeliveryPIN, Valid: true}
+	}
 	if !order.CreatedAt.IsZero() {
 		dbo.CreatedAt = sql.NullTime{Time: order.CreatedAt, Valid: true}
 	}
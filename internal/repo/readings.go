@@ -0,0 +1,87 @@
+package repo
+
+import (
+	"database/sql"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+// readingsDBO holds model.FlightReadings; drone_status keeps the latest set
+// and drone_telemetry one per heartbeat, under the same column names.
+type readingsDBO struct {
+	AltitudeM      sql.NullFloat64 `dbo:"altitude_m"`
+	HeadingDeg     sql.NullFloat64 `dbo:"heading_deg"`
+	GroundSpeedMPS sql.NullFloat64 `dbo:"speed_mps"`
+	BatteryPct     sql.NullFloat64 `dbo:"battery_pct"`
+	GPSFix         sql.NullString  `dbo:"gps_fix"`
+	GPSAccuracyM   sql.NullFloat64 `dbo:"gps_accuracy_m"`
+	DeviceTime     sql.NullTime    `dbo:"device_time"`
+}
+
+func (dbo readingsDBO) toModel() model.FlightReadings {
+	var r model.FlightReadings
+
+	if dbo.AltitudeM.Valid {
+		r.AltitudeM = &dbo.AltitudeM.Float64
+	}
+
+	if dbo.HeadingDeg.Valid {
+		r.HeadingDeg = &dbo.HeadingDeg.Float64
+	}
+
+	if dbo.GroundSpeedMPS.Valid {
+		r.GroundSpeedMPS = &dbo.GroundSpeedMPS.Float64
+	}
+
+	if dbo.BatteryPct.Valid {
+		r.BatteryPct = &dbo.BatteryPct.Float64
+	}
+
+	if dbo.GPSFix.Valid {
+		r.GPSFix = model.GPSFix(dbo.GPSFix.String)
+	}
+
+	if dbo.GPSAccuracyM.Valid {
+		r.GPSAccuracyM = &dbo.GPSAccuracyM.Float64
+	}
+
+	if dbo.DeviceTime.Valid {
+		r.DeviceTime = &dbo.DeviceTime.Time
+	}
+
+	return r
+}
+
+func toReadingsDBO(r model.FlightReadings) readingsDBO {
+	var dbo readingsDBO
+
+	if r.AltitudeM != nil {
+		dbo.AltitudeM = sql.NullFloat64{Float64: *r.AltitudeM, Valid: true}
+	}
+
+	if r.HeadingDeg != nil {
+		dbo.HeadingDeg = sql.NullFloat64{Float64: *r.HeadingDeg, Valid: true}
+	}
+
+	if r.GroundSpeedMPS != nil {
+		dbo.GroundSpeedMPS = sql.NullFloat64{Float64: *r.GroundSpeedMPS, Valid: true}
+	}
+
+	if r.BatteryPct != nil {
+		dbo.BatteryPct = sql.NullFloat64{Float64: *r.BatteryPct, Valid: true}
+	}
+
+	if r.GPSFix != "" {
+		dbo.GPSFix = sql.NullString{String: string(r.GPSFix), Valid: true}
+	}
+
+	if r.GPSAccuracyM != nil {
+		dbo.GPSAccuracyM = sql.NullFloat64{Float64: *r.GPSAccuracyM, Valid: true}
+	}
+
+	if r.DeviceTime != nil {
+		dbo.DeviceTime = sql.NullTime{Time: *r.DeviceTime, Valid: true}
+	}
+
+	return dbo
+}
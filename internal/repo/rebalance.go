@@ -0,0 +1,195 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const (
+	// pickups are rounded to about 100 m before they are bucketed into cells
+	pickupCountsQuery = `
+		SELECT ROUND(pickup_lat, 3) AS lat, ROUND(pickup_lng, 3) AS lng, COUNT(*) AS orders
+		FROM orders
+		WHERE created_at >= ? AND HOUR(created_at) = ?
+		GROUP BY ROUND(pickup_lat, 3), ROUND(pickup_lng, 3)
+	`
+	// a new run replaces the forecasts still standing
+	closeForecastsQuery = `
+		UPDATE rebalance_forecasts SET valid_until = ? WHERE valid_until > ?
+	`
+	insertForecastQuery = `
+		INSERT INTO rebalance_forecasts (run_at, valid_until, hour_of_day, min_lat, min_lng, max_lat, max_lng,
+		                                 orders_per_hour, idle_drones, target_drones, predicted_wait_seconds)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	// the wait of an order runs from its creation to its first pickup
+	listForecastOutcomesQuery = `
+		SELECT f.min_lat, f.min_lng, f.max_lat, f.max_lng, f.orders_per_hour,
+		       TIMESTAMPDIFF(MICROSECOND, f.run_at, f.valid_until) AS window_us,
+		       f.predicted_wait_seconds,
+		       COUNT(o.id) AS orders,
+		       COUNT(p.picked_up_at) AS picked_up,
+		       COALESCE(SUM(TIMESTAMPDIFF(MICROSECOND, o.created_at, p.picked_up_at)), 0) AS wait_total_us
+		FROM rebalance_forecasts f
+		LEFT JOIN orders o
+		       ON o.pickup_lat >= f.min_lat AND o.pickup_lat < f.max_lat
+		      AND o.pickup_lng >= f.min_lng AND o.pickup_lng < f.max_lng
+		      AND o.created_at >= f.run_at AND o.created_at < f.valid_until
+		LEFT JOIN LATERAL (
+			SELECT MIN(ts.completed_at) AS picked_up_at
+			FROM trip_stops ts
+			WHERE ts.order_id = o.id AND ts.kind = 'pickup'
+		) p ON TRUE
+		WHERE f.run_at >= ? AND f.run_at < ?
+		GROUP BY f.id
+		ORDER BY f.run_at, f.id
+	`
+)
+
+type pickupCountDBO struct {
+	Lat    float64 `dbo:"lat"`
+	Lng    float64 `dbo:"lng"`
+	Orders int     `dbo:"orders"`
+}
+
+type forecastOutcomeDBO struct {
+	MinLat         float64       `dbo:"min_lat"`
+	MinLng         float64       `dbo:"min_lng"`
+	MaxLat         float64       `dbo:"max_lat"`
+	MaxLng         float64       `dbo:"max_lng"`
+	OrdersPerHour  float64       `dbo:"orders_per_hour"`
+	WindowUS       int64         `dbo:"window_us"`
+	PredictedWaitS sql.NullInt64 `dbo:"predicted_wait_seconds"`
+	Orders         int           `dbo:"orders"`
+	PickedUp       int           `dbo:"picked_up"`
+	WaitTotalUS    int64         `dbo:"wait_total_us"`
+}
+
+// RebalanceRepo reads pickup history and keeps the forecasts of each
+// rebalance run.
+type RebalanceRepo struct {
+	db *sql.DB
+}
+
+func NewRebalanceRepo(db *sql.DB) *RebalanceRepo {
+	return &RebalanceRepo{db: db}
+}
+
+// PickupCounts counts the orders placed since since in the given hour of
+// the day (UTC), by pickup point.
+func (r *RebalanceRepo) PickupCounts(ctx context.Context, since time.Time, hour int) ([]model.PickupCount, error) {
+	rows, err := r.db.QueryContext(ctx, pickupCountsQuery, since, hour)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []model.PickupCount
+	for rows.Next() {
+		var dbo pickupCountDBO
+		if err := rows.Scan(&dbo.Lat, &dbo.Lng, &dbo.Orders); err != nil {
+			return nil, err
+		}
+		counts = append(counts, model.PickupCount{Lat: dbo.Lat, Lng: dbo.Lng, Orders: dbo.Orders})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// SaveForecasts stores a run's cell forecasts, standing from runAt until
+// validUntil or the next run, whichever comes first.
+func (r *RebalanceRepo) SaveForecasts(ctx context.Context, runAt, validUntil time.Time, hour int, cells []model.CellForecast) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, closeForecastsQuery, runAt, runAt); err != nil {
+		return err
+	}
+
+	for _, cell := range cells {
+		var predictedWait sql.NullInt64
+		if cell.PredictedWait != nil {
+			predictedWait = sql.NullInt64{Int64: int64(cell.PredictedWait.Seconds()), Valid: true}
+		}
+		if _, err := tx.ExecContext(ctx, insertForecastQuery,
+			runAt,
+			validUntil,
+			hour,
+			cell.Min.Lat,
+			cell.Min.Lng,
+			cell.Max.Lat,
+			cell.Max.Lng,
+			cell.OrdersPerHour,
+			cell.IdleDrones,
+			cell.TargetDrones,
+			predictedWait,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListOutcomes returns the forecasts of the runs between from and to with
+// the orders placed in their cells while they stood.
+func (r *RebalanceRepo) ListOutcomes(ctx context.Context, from, to time.Time) ([]model.ForecastOutcome, error) {
+	rows, err := r.db.QueryContext(ctx, listForecastOutcomesQuery, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var outcomes []model.ForecastOutcome
+	for rows.Next() {
+		var dbo forecastOutcomeDBO
+		if err := rows.Scan(
+			&dbo.MinLat,
+			&dbo.MinLng,
+			&dbo.MaxLat,
+			&dbo.MaxLng,
+			&dbo.OrdersPerHour,
+			&dbo.WindowUS,
+			&dbo.PredictedWaitS,
+			&dbo.Orders,
+			&dbo.PickedUp,
+			&dbo.WaitTotalUS,
+		); err != nil {
+			return nil, err
+		}
+		outcomes = append(outcomes, dbo.toModel())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return outcomes, nil
+}
+
+func (dbo forecastOutcomeDBO) toModel() model.ForecastOutcome {
+	outcome := model.ForecastOutcome{
+		Min:           model.GeoPoint{Lat: dbo.MinLat, Lng: dbo.MinLng},
+		Max:           model.GeoPoint{Lat: dbo.MaxLat, Lng: dbo.MaxLng},
+		OrdersPerHour: dbo.OrdersPerHour,
+		Window:        time.Duration(dbo.WindowUS) * time.Microsecond,
+		Orders:        dbo.Orders,
+		PickedUp:      dbo.PickedUp,
+		WaitTotal:     time.Duration(dbo.WaitTotalUS) * time.Microsecond,
+	}
+	if dbo.PredictedWaitS.Valid {
+		wait := time.Duration(dbo.PredictedWaitS.Int64) * time.Second
+		outcome.PredictedWait = &wait
+	}
+	return outcome
+}
@@ -0,0 +1,192 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const (
+	insertServiceAreaQuery = `
+		INSERT INTO service_areas (name, area, active)
+		VALUES (?, ST_GeomFromText(?, 4326, '` + wktAxisOrder + `'), ?)
+	`
+	selectServiceAreaColumns = `
+		SELECT id, name, ST_AsText(area, '` + wktAxisOrder + `'), active, created_at, updated_at
+		FROM service_areas`
+	getServiceAreaByIDQuery   = selectServiceAreaColumns + ` WHERE id = ?`
+	listServiceAreasQuery     = selectServiceAreaColumns + ` ORDER BY id`
+	listActiveServiceAreasQry = selectServiceAreaColumns + ` WHERE active = 1 ORDER BY id`
+	updateServiceAreaQuery    = `
+		UPDATE service_areas
+		SET name = ?, area = ST_GeomFromText(?, 4326, '` + wktAxisOrder + `'), active = ?, updated_at = NOW()
+		WHERE id = ?
+	`
+	anyServiceAreaQuery    = `SELECT EXISTS (SELECT 1 FROM service_areas)`
+	deleteServiceAreaQuery = `
+		DELETE FROM service_areas WHERE id = ?
+	`
+)
+
+type serviceAreaDBO struct {
+	ID        int64        `dbo:"id"`
+	Name      string       `dbo:"name"`
+	AreaWKT   string       `dbo:"area"`
+	Active    bool         `dbo:"active"`
+	CreatedAt sql.NullTime `dbo:"created_at"`
+	UpdatedAt sql.NullTime `dbo:"updated_at"`
+}
+
+type ServiceAreaRepo struct {
+	db *sql.DB
+}
+
+func NewServiceAreaRepo(db *sql.DB) *ServiceAreaRepo {
+	return &ServiceAreaRepo{db: db}
+}
+
+func (r *ServiceAreaRepo) Insert(ctx context.Context, area *model.ServiceArea) (*model.ServiceArea, error) {
+	result, err := r.db.ExecContext(ctx, insertServiceAreaQuery,
+		area.Name,
+		polygonToWKT(area.Boundary),
+		area.Active,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *ServiceAreaRepo) GetByID(ctx context.Context, id int64) (*model.ServiceArea, error) {
+	var dbo serviceAreaDBO
+	err := r.db.QueryRowContext(ctx, getServiceAreaByIDQuery, id).Scan(
+		&dbo.ID,
+		&dbo.Name,
+		&dbo.AreaWKT,
+		&dbo.Active,
+		&dbo.CreatedAt,
+		&dbo.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrServiceAreaNotFound()
+		}
+		return nil, err
+	}
+
+	return dbo.toModel()
+}
+
+func (r *ServiceAreaRepo) List(ctx context.Context) ([]model.ServiceArea, error) {
+	return r.list(ctx, listServiceAreasQuery)
+}
+
+func (r *ServiceAreaRepo) ListActive(ctx context.Context) ([]model.ServiceArea, error) {
+	return r.list(ctx, listActiveServiceAreasQry)
+}
+
+// Exists reports whether any service area has been drawn, active or not.
+func (r *ServiceAreaRepo) Exists(ctx context.Context) (bool, error) {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, anyServiceAreaQuery).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func (r *ServiceAreaRepo) Update(ctx context.Context, area *model.ServiceArea) (*model.ServiceArea, error) {
+	_, err := r.db.ExecContext(ctx, updateServiceAreaQuery,
+		area.Name,
+		polygonToWKT(area.Boundary),
+		area.Active,
+		area.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, area.ID)
+}
+
+func (r *ServiceAreaRepo) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, deleteServiceAreaQuery, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrServiceAreaNotFound()
+	}
+
+	return nil
+}
+
+func (r *ServiceAreaRepo) list(ctx context.Context, query string) ([]model.ServiceArea, error) {
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var areas []model.ServiceArea
+	for rows.Next() {
+		var dbo serviceAreaDBO
+		if err := rows.Scan(
+			&dbo.ID,
+			&dbo.Name,
+			&dbo.AreaWKT,
+			&dbo.Active,
+			&dbo.CreatedAt,
+			&dbo.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		area, err := dbo.toModel()
+		if err != nil {
+			return nil, err
+		}
+		areas = append(areas, *area)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return areas, nil
+}
+
+func (dbo *serviceAreaDBO) toModel() (*model.ServiceArea, error) {
+	boundary, err := polygonFromWKT(dbo.AreaWKT)
+	if err != nil {
+		return nil, err
+	}
+
+	area := &model.ServiceArea{
+		ID:       dbo.ID,
+		Name:     dbo.Name,
+		Boundary: boundary,
+		Active:   dbo.Active,
+	}
+
+	if dbo.CreatedAt.Valid {
+		area.CreatedAt = dbo.CreatedAt.Time
+	}
+
+	if dbo.UpdatedAt.Valid {
+		area.UpdatedAt = dbo.UpdatedAt.Time
+	}
+
+	return area, nil
+}
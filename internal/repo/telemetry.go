@@ -0,0 +1,207 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const (
+	insertTelemetryQuery = `
+		INSERT INTO drone_telemetry (drone_id, trip_id, order_id, lat, lng,
+			altitude_m, heading_deg, speed_mps, battery_pct, gps_fix, gps_accuracy_m, device_time, recorded_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	selectTelemetryColumns = `
+		SELECT id, drone_id, trip_id, order_id, lat, lng,
+		       altitude_m, heading_deg, speed_mps, battery_pct, gps_fix, gps_accuracy_m, device_time, recorded_at
+		FROM drone_telemetry`
+	listTelemetryByDroneQuery = selectTelemetryColumns + `
+		WHERE drone_id = ? AND recorded_at >= ? AND recorded_at <= ?
+		ORDER BY recorded_at, id
+		LIMIT ?
+	`
+	// An order's track is every trip that carried it, in flight order.
+	listTelemetryByOrderQuery = selectTelemetryColumns + `
+		WHERE trip_id IN (SELECT DISTINCT trip_id FROM trip_stops WHERE order_id = ?)
+		ORDER BY recorded_at, id
+		LIMIT ?
+	`
+	deleteTelemetryBeforeQuery = `
+		DELETE FROM drone_telemetry WHERE recorded_at < ? LIMIT ?
+	`
+	// Keeps the first point of every (drone, interval) bucket in the range.
+	downsampleTelemetryQuery = `
+		DELETE t FROM drone_telemetry t
+		JOIN (
+			SELECT id FROM (
+				SELECT id, ROW_NUMBER() OVER (
+					PARTITION BY drone_id, FLOOR(UNIX_TIMESTAMP(recorded_at) / ?)
+					ORDER BY recorded_at, id
+				) AS rn
+				FROM drone_telemetry
+				WHERE recorded_at >= ? AND recorded_at < ?
+			) ranked
+			WHERE ranked.rn > 1
+		) doomed ON doomed.id = t.id
+	`
+)
+
+type telemetryDBO struct {
+	ID         int64         `dbo:"id"`
+	DroneID    int64         `dbo:"drone_id"`
+	TripID     sql.NullInt64 `dbo:"trip_id"`
+	OrderID    sql.NullInt64 `dbo:"order_id"`
+	Lat        float64       `dbo:"lat"`
+	Lng        float64       `dbo:"lng"`
+	Readings   readingsDBO
+	RecordedAt time.Time `dbo:"recorded_at"`
+}
+
+type TelemetryRepo struct {
+	db *sql.DB
+}
+
+func NewTelemetryRepo(db *sql.DB) *TelemetryRepo {
+	return &TelemetryRepo{db: db}
+}
+
+func (r *TelemetryRepo) InsertTx(ctx context.Context, tx *sql.Tx, point *model.TelemetryPoint) error {
+	dbo := toTelemetryDBO(point)
+
+	result, err := tx.ExecContext(ctx, insertTelemetryQuery,
+		dbo.DroneID,
+		dbo.TripID,
+		dbo.OrderID,
+		dbo.Lat,
+		dbo.Lng,
+		dbo.Readings.AltitudeM,
+		dbo.Readings.HeadingDeg,
+		dbo.Readings.GroundSpeedMPS,
+		dbo.Readings.BatteryPct,
+		dbo.Readings.GPSFix,
+		dbo.Readings.GPSAccuracyM,
+		dbo.Readings.DeviceTime,
+		dbo.RecordedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	point.ID = id
+
+	return nil
+}
+
+func (r *TelemetryRepo) ListByDrone(ctx context.Context, droneID int64, from, to time.Time, limit int) ([]model.TelemetryPoint, error) {
+	return r.list(ctx, listTelemetryByDroneQuery, droneID, from, to, limit)
+}
+
+func (r *TelemetryRepo) ListByOrder(ctx context.Context, orderID int64, limit int) ([]model.TelemetryPoint, error) {
+	return r.list(ctx, listTelemetryByOrderQuery, orderID, limit)
+}
+
+// DeleteBefore removes up to limit points older than cutoff and reports how
+// many went, so callers can delete in batches without long locks.
+func (r *TelemetryRepo) DeleteBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	result, err := r.db.ExecContext(ctx, deleteTelemetryBeforeQuery, cutoff, limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// Downsample thins points in [from, to) to one per drone per interval.
+func (r *TelemetryRepo) Downsample(ctx context.Context, from, to time.Time, interval time.Duration) (int64, error) {
+	result, err := r.db.ExecContext(ctx, downsampleTelemetryQuery, max(int64(interval.Seconds()), 1), from, to)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *TelemetryRepo) list(ctx context.Context, query string, args ...interface{}) ([]model.TelemetryPoint, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []model.TelemetryPoint
+	for rows.Next() {
+		var dbo telemetryDBO
+		if err := rows.Scan(
+			&dbo.ID,
+			&dbo.DroneID,
+			&dbo.TripID,
+			&dbo.OrderID,
+			&dbo.Lat,
+			&dbo.Lng,
+			&dbo.Readings.AltitudeM,
+			&dbo.Readings.HeadingDeg,
+			&dbo.Readings.GroundSpeedMPS,
+			&dbo.Readings.BatteryPct,
+			&dbo.Readings.GPSFix,
+			&dbo.Readings.GPSAccuracyM,
+			&dbo.Readings.DeviceTime,
+			&dbo.RecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		points = append(points, *dbo.toModel())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
+
+func (dbo *telemetryDBO) toModel() *model.TelemetryPoint {
+	point := &model.TelemetryPoint{
+		ID:             dbo.ID,
+		DroneID:        dbo.DroneID,
+		Lat:            dbo.Lat,
+		Lng:            dbo.Lng,
+		FlightReadings: dbo.Readings.toModel(),
+		RecordedAt:     dbo.RecordedAt,
+	}
+
+	if dbo.TripID.Valid {
+		point.TripID = &dbo.TripID.Int64
+	}
+
+	if dbo.OrderID.Valid {
+		point.OrderID = &dbo.OrderID.Int64
+	}
+
+	return point
+}
+
+func toTelemetryDBO(point *model.TelemetryPoint) telemetryDBO {
+	dbo := telemetryDBO{
+		ID:         point.ID,
+		DroneID:    point.DroneID,
+		Lat:        point.Lat,
+		Lng:        point.Lng,
+		Readings:   toReadingsDBO(point.FlightReadings),
+		RecordedAt: point.RecordedAt,
+	}
+
+	if point.TripID != nil {
+		dbo.TripID = sql.NullInt64{Int64: *point.TripID, Valid: true}
+	}
+
+	if point.OrderID != nil {
+		dbo.OrderID = sql.NullInt64{Int64: *point.OrderID, Valid: true}
+	}
+
+	return dbo
+}
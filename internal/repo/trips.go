@@ -0,0 +1,344 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const (
+	getActiveTripByDroneQuery = `
+		SELECT id, drone_id, status, created_at, updated_at, completed_at
+		FROM trips
+		WHERE drone_id = ? AND status = 'active'
+		ORDER BY id DESC
+		LIMIT 1
+	`
+	getActiveTripByDroneForUpdateQuery = `
+		SELECT id, drone_id, status, created_at, updated_at, completed_at
+		FROM trips
+		WHERE drone_id = ? AND status = 'active'
+		ORDER BY id DESC
+		LIMIT 1
+		FOR UPDATE
+	`
+	listActiveTripsByDronesQuery = `
+		SELECT id, drone_id, status, created_at, updated_at, completed_at
+		FROM trips
+		WHERE drone_id IN (%s) AND status = 'active'
+		ORDER BY id
+	`
+	listStopsByTripsQuery = `
+		SELECT trip_id, order_id, kind, lat, lng, completed_at
+		FROM trip_stops
+		WHERE trip_id IN (%s)
+		ORDER BY trip_id, sequence
+	`
+	listTripStopsQuery = `
+		SELECT order_id, kind, lat, lng, completed_at
+		FROM trip_stops
+		WHERE trip_id = ?
+		ORDER BY sequence
+	`
+	insertTripQuery = `
+		INSERT INTO trips (drone_id, status, completed_at)
+		VALUES (?, ?, ?)
+	`
+	updateTripQuery = `
+		UPDATE trips
+		SET status = ?, completed_at = ?, updated_at = NOW()
+		WHERE id = ?
+	`
+	deleteTripStopsQuery = `
+		DELETE FROM trip_stops WHERE trip_id = ?
+	`
+	insertTripStopQuery = `
+		INSERT INTO trip_stops (trip_id, sequence, order_id, kind, lat, lng, completed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+)
+
+type tripDBO struct {
+	ID          int64        `dbo:"id"`
+	DroneID     int64        `dbo:"drone_id"`
+	Status      string       `dbo:"status"`
+	CreatedAt   sql.NullTime `dbo:"created_at"`
+	UpdatedAt   sql.NullTime `dbo:"updated_at"`
+	CompletedAt sql.NullTime `dbo:"completed_at"`
+}
+
+type tripStopDBO struct {
+	OrderID     int64        `dbo:"order_id"`
+	Kind        string       `dbo:"kind"`
+	Lat         float64      `dbo:"lat"`
+	Lng         float64      `dbo:"lng"`
+	CompletedAt sql.NullTime `dbo:"completed_at"`
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type TripRepo struct {
+	db *sql.DB
+}
+
+func NewTripRepo(db *sql.DB) *TripRepo {
+	return &TripRepo{db: db}
+}
+
+// FindActiveByDrone returns nil without an error when the drone is not on a trip.
+func (r *TripRepo) FindActiveByDrone(ctx context.Context, droneID int64) (*model.Trip, error) {
+	return r.findActive(ctx, r.db, getActiveTripByDroneQuery, droneID)
+}
+
+// ListActiveByDrones returns the active trip of each drone that is on one,
+// keyed by drone id, loading every trip's stops in a single query.
+func (r *TripRepo) ListActiveByDrones(ctx context.Context, droneIDs []int64) (map[int64]*model.Trip, error) {
+	trips := make(map[int64]*model.Trip)
+	if len(droneIDs) == 0 {
+		return trips, nil
+	}
+
+	placeholders, args := inList(droneIDs)
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(listActiveTripsByDronesQuery, placeholders), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[int64]*model.Trip)
+	var tripIDs []int64
+	for rows.Next() {
+		var dbo tripDBO
+		if err := rows.Scan(
+			&dbo.ID,
+			&dbo.DroneID,
+			&dbo.Status,
+			&dbo.CreatedAt,
+			&dbo.UpdatedAt,
+			&dbo.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		// ordered by id, so the latest trip wins like in FindActiveByDrone
+		trip := dbo.toModel()
+		trips[trip.DroneID] = trip
+		byID[trip.ID] = trip
+		tripIDs = append(tripIDs, trip.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(tripIDs) == 0 {
+		return trips, nil
+	}
+
+	placeholders, args = inList(tripIDs)
+	stopRows, err := r.db.QueryContext(ctx, fmt.Sprintf(listStopsByTripsQuery, placeholders), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer stopRows.Close()
+
+	for stopRows.Next() {
+		var tripID int64
+		var stop tripStopDBO
+		if err := stopRows.Scan(
+			&tripID,
+			&stop.OrderID,
+			&stop.Kind,
+			&stop.Lat,
+			&stop.Lng,
+			&stop.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		byID[tripID].Stops = append(byID[tripID].Stops, stop.toModel())
+	}
+
+	if err := stopRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return trips, nil
+}
+
+func (r *TripRepo) FindActiveByDroneForUpdate(ctx context.Context, tx *sql.Tx, droneID int64) (*model.Trip, error) {
+	return r.findActive(ctx, tx, getActiveTripByDroneForUpdateQuery, droneID)
+}
+
+func (r *TripRepo) InsertTx(ctx context.Context, tx *sql.Tx, trip *model.Trip) (*model.Trip, error) {
+	dbo := toTripDBO(trip)
+
+	result, err := tx.ExecContext(ctx, insertTripQuery, dbo.DroneID, dbo.Status, dbo.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	trip.ID = id
+
+	if err := r.replaceStops(ctx, tx, trip); err != nil {
+		return nil, err
+	}
+
+	return trip, nil
+}
+
+func (r *TripRepo) UpdateTx(ctx context.Context, tx *sql.Tx, trip *model.Trip) (*model.Trip, error) {
+	dbo := toTripDBO(trip)
+
+	if _, err := tx.ExecContext(ctx, updateTripQuery, dbo.Status, dbo.CompletedAt, dbo.ID); err != nil {
+		return nil, err
+	}
+
+	if err := r.replaceStops(ctx, tx, trip); err != nil {
+		return nil, err
+	}
+
+	return trip, nil
+}
+
+func (r *TripRepo) replaceStops(ctx context.Context, tx *sql.Tx, trip *model.Trip) error {
+	if _, err := tx.ExecContext(ctx, deleteTripStopsQuery, trip.ID); err != nil {
+		return err
+	}
+
+	for i, stop := range trip.Stops {
+		dbo := toTripStopDBO(stop)
+		if _, err := tx.ExecContext(ctx, insertTripStopQuery,
+			trip.ID,
+			i,
+			dbo.OrderID,
+			dbo.Kind,
+			dbo.Lat,
+			dbo.Lng,
+			dbo.CompletedAt,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *TripRepo) findActive(ctx context.Context, q queryer, query string, droneID int64) (*model.Trip, error) {
+	var dbo tripDBO
+	err := q.QueryRowContext(ctx, query, droneID).Scan(
+		&dbo.ID,
+		&dbo.DroneID,
+		&dbo.Status,
+		&dbo.CreatedAt,
+		&dbo.UpdatedAt,
+		&dbo.CompletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	trip := dbo.toModel()
+
+	rows, err := q.QueryContext(ctx, listTripStopsQuery, trip.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stop tripStopDBO
+		if err := rows.Scan(
+			&stop.OrderID,
+			&stop.Kind,
+			&stop.Lat,
+			&stop.Lng,
+			&stop.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		trip.Stops = append(trip.Stops, stop.toModel())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return trip, nil
+}
+
+func (dbo *tripDBO) toModel() *model.Trip {
+	trip := &model.Trip{
+		ID:      dbo.ID,
+		DroneID: dbo.DroneID,
+		Status:  model.TripStatus(dbo.Status),
+	}
+
+	if dbo.CreatedAt.Valid {
+		trip.CreatedAt = dbo.CreatedAt.Time
+	}
+
+	if dbo.UpdatedAt.Valid {
+		trip.UpdatedAt = dbo.UpdatedAt.Time
+	}
+
+	if dbo.CompletedAt.Valid {
+		trip.CompletedAt = &dbo.CompletedAt.Time
+	}
+
+	return trip
+}
+
+func (dbo *tripStopDBO) toModel() model.TripStop {
+	stop := model.TripStop{
+		OrderID: dbo.OrderID,
+		Kind:    model.TripStopKind(dbo.Kind),
+		Lat:     dbo.Lat,
+		Lng:     dbo.Lng,
+	}
+
+	if dbo.CompletedAt.Valid {
+		stop.CompletedAt = &dbo.CompletedAt.Time
+	}
+
+	return stop
+}
+
+func toTripDBO(trip *model.Trip) tripDBO {
+	dbo := tripDBO{
+		ID:      trip.ID,
+		DroneID: trip.DroneID,
+		Status:  string(trip.Status),
+	}
+
+	if trip.CompletedAt != nil {
+		dbo.CompletedAt = sql.NullTime{Time: *trip.CompletedAt, Valid: true}
+	}
+
+	return dbo
+}
+
+func toTripStopDBO(stop model.TripStop) tripStopDBO {
+	dbo := tripStopDBO{
+		OrderID: stop.OrderID,
+		Kind:    string(stop.Kind),
+		Lat:     stop.Lat,
+		Lng:     stop.Lng,
+	}
+
+	if stop.CompletedAt != nil {
+		dbo.CompletedAt = sql.NullTime{Time: *stop.CompletedAt, Valid: true}
+	}
+
+	return dbo
+}
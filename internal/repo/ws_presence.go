@@ -0,0 +1,121 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const (
+	upsertPresenceQuery = `
+		INSERT INTO ws_presence (drone_id, node_id, connected_at, seen_at)
+		VALUES (?, ?, NOW(3), NOW(3))
+		ON DUPLICATE KEY UPDATE node_id = VALUES(node_id), connected_at = NOW(3), seen_at = NOW(3), released_at = NULL
+	`
+	releasePresenceQuery = `
+		UPDATE ws_presence SET released_at = NOW(3)
+		WHERE drone_id = ? AND node_id = ? AND released_at IS NULL
+	`
+	findPresenceQuery = `
+		SELECT drone_id, node_id, connected_at, seen_at, released_at
+		FROM ws_presence
+		WHERE drone_id = ? AND released_at IS NULL AND seen_at >= NOW(3) - INTERVAL ? MICROSECOND
+	`
+	findLastPresenceQuery = `
+		SELECT drone_id, node_id, connected_at, seen_at, released_at
+		FROM ws_presence
+		WHERE drone_id = ? AND seen_at >= NOW(3) - INTERVAL ? MICROSECOND
+	`
+	touchPresenceQuery = `
+		UPDATE ws_presence SET seen_at = NOW(3) WHERE node_id = ?
+	`
+	deletePresenceByNodeQuery = `
+		DELETE FROM ws_presence WHERE node_id = ?
+	`
+)
+
+type presenceDBO struct {
+	DroneID     int64        `dbo:"drone_id"`
+	NodeID      string       `dbo:"node_id"`
+	ConnectedAt time.Time    `dbo:"connected_at"`
+	SeenAt      time.Time    `dbo:"seen_at"`
+	ReleasedAt  sql.NullTime `dbo:"released_at"`
+}
+
+// PresenceRepo keeps ws_presence, the cluster-wide map of which node holds
+// each drone's websocket.
+type PresenceRepo struct {
+	db *sql.DB
+}
+
+func NewPresenceRepo(db *sql.DB) *PresenceRepo {
+	return &PresenceRepo{db: db}
+}
+
+func (r *PresenceRepo) Upsert(ctx context.Context, droneID int64, nodeID string) error {
+	_, err := r.db.ExecContext(ctx, upsertPresenceQuery, droneID, nodeID)
+	return err
+}
+
+// Release marks the drone disconnected only if nodeID still owns it, so a
+// late disconnect does not wipe out a newer connection on another node. The
+// row stays behind so the drone's next node can find this one.
+func (r *PresenceRepo) Release(ctx context.Context, droneID int64, nodeID string) error {
+	_, err := r.db.ExecContext(ctx, releasePresenceQuery, droneID, nodeID)
+	return err
+}
+
+// FindByDrone returns the node holding the drone's websocket, or nil when no
+// node has vouched for it within maxAge.
+func (r *PresenceRepo) FindByDrone(ctx context.Context, droneID int64, maxAge time.Duration) (*model.DronePresence, error) {
+	return r.find(ctx, findPresenceQuery, droneID, maxAge)
+}
+
+// FindLast returns the node that holds or last held the drone's websocket,
+// or nil when that node has not been seen within maxAge.
+func (r *PresenceRepo) FindLast(ctx context.Context, droneID int64, maxAge time.Duration) (*model.DronePresence, error) {
+	return r.find(ctx, findLastPresenceQuery, droneID, maxAge)
+}
+
+func (r *PresenceRepo) find(ctx context.Context, query string, droneID int64, maxAge time.Duration) (*model.DronePresence, error) {
+	var dbo presenceDBO
+	err := r.db.QueryRowContext(ctx, query, droneID, maxAge.Microseconds()).Scan(
+		&dbo.DroneID,
+		&dbo.NodeID,
+		&dbo.ConnectedAt,
+		&dbo.SeenAt,
+		&dbo.ReleasedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	presence := &model.DronePresence{
+		DroneID:     dbo.DroneID,
+		NodeID:      dbo.NodeID,
+		ConnectedAt: dbo.ConnectedAt,
+		SeenAt:      dbo.SeenAt,
+	}
+	if dbo.ReleasedAt.Valid {
+		presence.ReleasedAt = &dbo.ReleasedAt.Time
+	}
+	return presence, nil
+}
+
+// Touch refreshes every presence the node owns, marking the node alive.
+func (r *PresenceRepo) Touch(ctx context.Context, nodeID string) error {
+	_, err := r.db.ExecContext(ctx, touchPresenceQuery, nodeID)
+	return err
+}
+
+// DeleteByNode clears what a previous run of the node left behind.
+func (r *PresenceRepo) DeleteByNode(ctx context.Context, nodeID string) error {
+	_, err := r.db.ExecContext(ctx, deletePresenceByNodeQuery, nodeID)
+	return err
+}
@@ -0,0 +1,142 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const (
+	relayBatchSize = 100
+	// relayRetention bounds rows addressed to nodes that died before
+	// draining them.
+	relayRetention  = 5 * time.Minute
+	relayPurgeEvery = time.Minute
+)
+
+const (
+	insertRelayQuery = `
+		INSERT INTO ws_relay (node_id, drone_id, kind, payload) VALUES (?, ?, ?, ?)
+	`
+	listRelayQuery = `
+		SELECT id, node_id, drone_id, kind, payload, created_at
+		FROM ws_relay
+		WHERE node_id = ?
+		ORDER BY id
+		LIMIT ?
+	`
+	deleteRelayUpToQuery = `
+		DELETE FROM ws_relay WHERE node_id = ? AND id <= ?
+	`
+	purgeRelayQuery = `
+		DELETE FROM ws_relay WHERE created_at < NOW(3) - INTERVAL ? MICROSECOND
+	`
+)
+
+type relayDBO struct {
+	ID        int64     `dbo:"id"`
+	NodeID    string    `dbo:"node_id"`
+	DroneID   int64     `dbo:"drone_id"`
+	Kind      string    `dbo:"kind"`
+	Payload   string    `dbo:"payload"`
+	CreatedAt time.Time `dbo:"created_at"`
+}
+
+// MySQLMessageBus relays websocket payloads between API nodes through the
+// ws_relay table: publishers insert rows addressed to a node and every node
+// polls for its own. It needs nothing beyond the database.
+type MySQLMessageBus struct {
+	db           *sql.DB
+	pollInterval time.Duration
+}
+
+func NewMySQLMessageBus(db *sql.DB, pollInterval time.Duration) *MySQLMessageBus {
+	return &MySQLMessageBus{db: db, pollInterval: pollInterval}
+}
+
+func (b *MySQLMessageBus) Publish(ctx context.Context, msg model.RelayedMessage) error {
+	kind := msg.Kind
+	if kind == "" {
+		kind = model.RelayMessage
+	}
+	_, err := b.db.ExecContext(ctx, insertRelayQuery, msg.NodeID, msg.DroneID, string(kind), string(msg.Payload))
+	return err
+}
+
+// Subscribe polls for messages addressed to nodeID and hands them to handle
+// in publish order until ctx is done. Delivered rows are deleted.
+func (b *MySQLMessageBus) Subscribe(ctx context.Context, nodeID string, handle func(model.RelayedMessage)) error {
+	ticker := time.NewTicker(b.pollInterval)
+	defer ticker.Stop()
+
+	lastPurge := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		for {
+			n, err := b.drain(ctx, nodeID, handle)
+			if err != nil {
+				log.Printf("relay poll for node %s failed: %v", nodeID, err)
+				break
+			}
+			if n < relayBatchSize {
+				break
+			}
+		}
+
+		if time.Since(lastPurge) >= relayPurgeEvery {
+			if _, err := b.db.ExecContext(ctx, purgeRelayQuery, relayRetention.Microseconds()); err != nil {
+				log.Printf("relay purge failed: %v", err)
+			}
+			lastPurge = time.Now()
+		}
+	}
+}
+
+func (b *MySQLMessageBus) drain(ctx context.Context, nodeID string, handle func(model.RelayedMessage)) (int, error) {
+	rows, err := b.db.QueryContext(ctx, listRelayQuery, nodeID, relayBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var messages []model.RelayedMessage
+	for rows.Next() {
+		var dbo relayDBO
+		if err := rows.Scan(&dbo.ID, &dbo.NodeID, &dbo.DroneID, &dbo.Kind, &dbo.Payload, &dbo.CreatedAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		messages = append(messages, model.RelayedMessage{
+			ID:        dbo.ID,
+			NodeID:    dbo.NodeID,
+			DroneID:   dbo.DroneID,
+			Kind:      model.RelayKind(dbo.Kind),
+			Payload:   []byte(dbo.Payload),
+			CreatedAt: dbo.CreatedAt,
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	// delete first: a message is delivered at most once, and a drone that
+	// misses it resumes from the receiving node's buffer
+	if _, err := b.db.ExecContext(ctx, deleteRelayUpToQuery, nodeID, messages[len(messages)-1].ID); err != nil {
+		return 0, err
+	}
+	for _, msg := range messages {
+		handle(msg)
+	}
+	return len(messages), nil
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+type AddressRepo interface {
+	Insert(ctx context.Context, address *model.Address) (*model.Address, error)
+	GetByID(ctx context.Context, id int64) (*model.Address, error)
+	ListByUser(ctx context.Context, userID int64, limit, offset int) ([]model.Address, error)
+	Update(ctx context.Context, address *model.Address) (*model.Address, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type AddressUsecase struct {
+	addressRepo AddressRepo
+}
+
+func NewAddressUsecase(addressRepo AddressRepo) *AddressUsecase {
+	return &AddressUsecase{addressRepo: addressRepo}
+}
+
+func (uc *AddressUsecase) CreateAddress(ctx context.Context, req model.CreateAddressRequest) (*model.Address, error) {
+	address, err := model.NewAddress(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.addressRepo.Insert(ctx, address)
+}
+
+func (uc *AddressUsecase) GetAddress(ctx context.Context, userID, addressID int64) (*model.Address, error) {
+	address, err := uc.addressRepo.GetByID(ctx, addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := address.BelongsTo(userID); err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+func (uc *AddressUsecase) ListAddresses(ctx context.Context, userID int64, page, pageSize int) ([]model.Address, model.Pagination, error) {
+	pagination, err := model.NormalizePagination(page, pageSize)
+	if err != nil {
+		return nil, model.Pagination{}, err
+	}
+
+	addresses, err := uc.addressRepo.ListByUser(ctx, userID, pagination.PageSize, pagination.Offset)
+	if err != nil {
+		return nil, model.Pagination{}, err
+	}
+
+	return addresses, pagination, nil
+}
+
+func (uc *AddressUsecase) UpdateAddress(ctx context.Context, userID, addressID int64, req model.UpdateAddressRequest) (*model.Address, error) {
+	address, err := uc.GetAddress(ctx, userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := address.Update(req); err != nil {
+		return nil, err
+	}
+
+	return uc.addressRepo.Update(ctx, address)
+}
+
+func (uc *AddressUsecase) DeleteAddress(ctx context.Context, userID, addressID int64) error {
+	if _, err := uc.GetAddress(ctx, userID, addressID); err != nil {
+		return err
+	}
+
+	return uc.addressRepo.Delete(ctx, addressID)
+}
@@ -0,0 +1,319 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+type DepotRepo interface {
+	BeginTx(ctx context.Context) (*sql.Tx, error)
+	Insert(ctx context.Context, depot *model.Depot) (*model.Depot, error)
+	GetByID(ctx context.Context, id int64) (*model.Depot, error)
+	List(ctx context.Context) ([]model.Depot, error)
+	ListActiveForUpdate(ctx context.Context, tx *sql.Tx) ([]model.Depot, error)
+	Update(ctx context.Context, depot *model.Depot) (*model.Depot, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type DepotDroneRepo interface {
+	GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (*model.Drone, error)
+	UpdateTx(ctx context.Context, tx *sql.Tx, drone *model.Drone) (*model.Drone, error)
+}
+
+// WaitingOrderReader tells whether orders are waiting for a drone.
+type WaitingOrderReader interface {
+	ListAwaitingOffer(ctx context.Context, offerTimeout time.Duration, limit int) ([]model.Order, error)
+}
+
+// CommandIssuer sends a drone a command the way an admin would, recording it.
+type CommandIssuer interface {
+	IssueCommand(ctx context.Context, req model.CreateDroneCommandRequest) (*model.DroneCommand, error)
+}
+
+// padRetryInterval is how long a drone that found every pad taken waits
+// before its heartbeats look for one again, so a full fleet running low does
+// not lock the depots on every heartbeat.
+const padRetryInterval = 30 * time.Second
+
+// DepotUsecase manages depots and sends drones back to them when their
+// battery or the lack of demand calls for it.
+type DepotUsecase struct {
+	depotRepo    DepotRepo
+	droneRepo    DepotDroneRepo
+	orderRepo    WaitingOrderReader
+	commands     CommandIssuer
+	policy       model.BasePolicy
+	offerTimeout time.Duration
+
+	mu      sync.Mutex
+	noPadAt map[int64]time.Time
+}
+
+func NewDepotUsecase(depotRepo DepotRepo, droneRepo DepotDroneRepo, orderRepo WaitingOrderReader, commands CommandIssuer, policy model.BasePolicy, offerTimeout time.Duration) *DepotUsecase {
+	return &DepotUsecase{
+		depotRepo:    depotRepo,
+		droneRepo:    droneRepo,
+		orderRepo:    orderRepo,
+		commands:     commands,
+		policy:       policy,
+		offerTimeout: offerTimeout,
+		noPadAt:      make(map[int64]time.Time),
+	}
+}
+
+func (uc *DepotUsecase) CreateDepot(ctx context.Context, name string, lat, lng float64, capacity int) (*model.Depot, error) {
+	depot, err := model.NewDepot(name, lat, lng, capacity)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := uc.depotRepo.Insert(ctx, depot)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.padsChanged()
+	return created, nil
+}
+
+func (uc *DepotUsecase) ListDepots(ctx context.Context) ([]model.Depot, error) {
+	return uc.depotRepo.List(ctx)
+}
+
+func (uc *DepotUsecase) UpdateDepot(ctx context.Context, id int64, req model.UpdateDepotRequest) (*model.Depot, error) {
+	depot, err := uc.depotRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := depot.Update(req); err != nil {
+		return nil, err
+	}
+
+	updated, err := uc.depotRepo.Update(ctx, depot)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.padsChanged()
+	return updated, nil
+}
+
+// DeleteDepot removes the depot; drones based there lose their home and pad.
+func (uc *DepotUsecase) DeleteDepot(ctx context.Context, id int64) error {
+	if err := uc.depotRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	uc.padsChanged()
+	return nil
+}
+
+// SetHomeDepot sets the depot the drone prefers to return to; nil clears it.
+func (uc *DepotUsecase) SetHomeDepot(ctx context.Context, droneID int64, depotID *int64) (*model.Drone, error) {
+	if depotID != nil {
+		if _, err := uc.depotRepo.GetByID(ctx, *depotID); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := uc.depotRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	drone, err := uc.droneRepo.GetByIDForUpdate(ctx, tx, droneID)
+	if err != nil {
+		return nil, err
+	}
+
+	drone.HomeDepotID = depotID
+	updatedDrone, err := uc.droneRepo.UpdateTx(ctx, tx, drone)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return updatedDrone, nil
+}
+
+// TripEnded considers sending a drone that just finished its trip back to
+// base, to charge or, when no order is waiting, to park.
+func (uc *DepotUsecase) TripEnded(ctx context.Context, drone model.Drone) {
+	if drone.Status != model.DroneIdle {
+		return
+	}
+
+	demand := true
+	if uc.policy.ParkWhenIdle && drone.DepotID == nil {
+		waiting, err := uc.orderRepo.ListAwaitingOffer(ctx, uc.offerTimeout, 1)
+		if err != nil {
+			log.Printf("return to base for drone %d: failed to check waiting orders: %v", drone.ID, err)
+			return
+		}
+		demand = len(waiting) > 0
+	}
+
+	if _, ok := uc.policy.Reason(drone, demand); !ok {
+		return
+	}
+	uc.returnToBase(ctx, drone.ID, demand)
+}
+
+// HeartbeatApplied sends an idle drone running low to charge and puts a
+// charged one back in service.
+func (uc *DepotUsecase) HeartbeatApplied(ctx context.Context, drone model.Drone) {
+	switch {
+	case uc.policy.Charged(drone):
+		if err := uc.finishCharging(ctx, drone.ID); err != nil {
+			log.Printf("failed to end charging of drone %d: %v", drone.ID, err)
+		}
+	case uc.policy.NeedsCharge(drone):
+		if uc.waitingForPad(drone.ID) {
+			return
+		}
+		uc.returnToBase(ctx, drone.ID, true)
+	}
+}
+
+// waitingForPad: the drone found every pad taken less than padRetryInterval
+// ago.
+func (uc *DepotUsecase) waitingForPad(droneID int64) bool {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	at, ok := uc.noPadAt[droneID]
+	return ok && time.Since(at) < padRetryInterval
+}
+
+func (uc *DepotUsecase) setNoPad(droneID int64, none bool) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if none {
+		uc.noPadAt[droneID] = time.Now()
+		return
+	}
+	delete(uc.noPadAt, droneID)
+}
+
+// padsChanged lets waiting drones look again at once, since a pad may have
+// been freed or added.
+func (uc *DepotUsecase) padsChanged() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	clear(uc.noPadAt)
+}
+
+// returnToBase takes a pad for the drone and, once that is committed, sends
+// it there with a return_to_home command. A drone finding every pad taken
+// stays where it is and is reconsidered on its next trip, or on a heartbeat
+// once padRetryInterval has passed or a pad was freed.
+func (uc *DepotUsecase) returnToBase(ctx context.Context, droneID int64, demand bool) {
+	depot, reason, err := uc.takePad(ctx, droneID, demand)
+	if err != nil {
+		log.Printf("return to base for drone %d failed: %v", droneID, err)
+		return
+	}
+	if depot == nil {
+		return
+	}
+
+	log.Printf("drone %d returning to depot %d (%s) to %s", droneID, depot.ID, depot.Name, reason)
+	if _, err := uc.commands.IssueCommand(ctx, model.CreateDroneCommandRequest{
+		DroneID: droneID,
+		Type:    model.CommandReturnToHome,
+		Lat:     &depot.Lat,
+		Lng:     &depot.Lng,
+	}); err != nil {
+		log.Printf("return_to_home for drone %d failed: %v", droneID, err)
+	}
+}
+
+// takePad returns the depot the drone now holds a pad at, or nil when it
+// should stay out or no pad is free. A drone already holding the pad is
+// not sent there again. The drone row is locked before the depots, like
+// every other drone transaction.
+func (uc *DepotUsecase) takePad(ctx context.Context, droneID int64, demand bool) (*model.Depot, model.BaseReason, error) {
+	tx, err := uc.depotRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer tx.Rollback()
+
+	drone, err := uc.droneRepo.GetByIDForUpdate(ctx, tx, droneID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// decided again on the locked row; the drone may have taken an order
+	reason, ok := uc.policy.Reason(*drone, demand)
+	if !ok {
+		return nil, "", nil
+	}
+
+	depots, err := uc.depotRepo.ListActiveForUpdate(ctx, tx)
+	if err != nil {
+		return nil, "", err
+	}
+	depot := model.ChooseDepot(*drone, depots)
+	uc.setNoPad(droneID, depot == nil)
+	if depot == nil {
+		return nil, "", nil
+	}
+	alreadyThere := drone.DepotID != nil && *drone.DepotID == depot.ID
+
+	if err := drone.ReturnToBase(depot.ID, reason); err != nil {
+		return nil, "", err
+	}
+	if _, err := uc.droneRepo.UpdateTx(ctx, tx, drone); err != nil {
+		return nil, "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, "", err
+	}
+
+	if alreadyThere {
+		return nil, "", nil
+	}
+	return depot, reason, nil
+}
+
+func (uc *DepotUsecase) finishCharging(ctx context.Context, droneID int64) error {
+	tx, err := uc.depotRepo.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	drone, err := uc.droneRepo.GetByIDForUpdate(ctx, tx, droneID)
+	if err != nil {
+		return err
+	}
+	if !uc.policy.Charged(*drone) {
+		return nil
+	}
+
+	if err := drone.FinishCharging(); err != nil {
+		return err
+	}
+	if _, err := uc.droneRepo.UpdateTx(ctx, tx, drone); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	uc.padsChanged()
+	log.Printf("drone %d charged, back in service", droneID)
+	return nil
+}
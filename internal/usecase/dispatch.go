@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+// dispatchBatchLimit caps how many waiting orders one batch matches, oldest
+// first; the rest wait for the next round.
+const dispatchBatchLimit = 200
+
+// DispatchStrategy decides how orders waiting for a drone are offered to
+// one.
+type DispatchStrategy interface {
+	// OrderWaiting is told about every order that starts waiting for a
+	// drone: new orders, handoffs and orders a drone released.
+	OrderWaiting(order model.Order)
+}
+
+type DispatchOrderRepo interface {
+	ListAwaitingOffer(ctx context.Context, offerTimeout time.Duration, limit int) ([]model.Order, error)
+}
+
+type DispatchDroneRepo interface {
+	ListAvailable(ctx context.Context, offerTimeout time.Duration) ([]model.Drone, error)
+}
+
+// greedyDispatch offers each order to the nearest available drone the
+// moment it starts waiting.
+type greedyDispatch struct {
+	uc *OrderUsecase
+}
+
+func (d greedyDispatch) OrderWaiting(order model.Order) {
+	d.uc.triggerAssignment(order)
+}
+
+// BatchDispatch collects waiting orders and, every round, offers them to the
+// available drones by minimum-cost matching, so a burst of orders does not
+// send every drone to the first pickup it hears about. An offer the drone
+// has not acted on within offerTimeout lapses: the order goes back into the
+// next round and the drone is available again.
+type BatchDispatch struct {
+	uc           *OrderUsecase
+	orders       DispatchOrderRepo
+	drones       DispatchDroneRepo
+	cost         model.DispatchCost
+	offerTimeout time.Duration
+}
+
+func NewBatchDispatch(uc *OrderUsecase, orders DispatchOrderRepo, drones DispatchDroneRepo, cost model.DispatchCost, offerTimeout time.Duration) *BatchDispatch {
+	return &BatchDispatch{
+		uc:           uc,
+		orders:       orders,
+		drones:       drones,
+		cost:         cost,
+		offerTimeout: offerTimeout,
+	}
+}
+
+// OrderWaiting does nothing: the order is picked up by the next round.
+func (d *BatchDispatch) OrderWaiting(model.Order) {}
+
+// Run dispatches a round every interval until ctx is done. It is a
+// singleton job, run by the elected leader only.
+func (d *BatchDispatch) Run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := d.DispatchRound(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("batch dispatch failed: %v", err)
+		}
+	}
+}
+
+// DispatchRound matches the orders waiting for an offer against the
+// available drones and sends the offers. Offers that cannot be delivered
+// are left for the next round.
+func (d *BatchDispatch) DispatchRound(ctx context.Context) error {
+	orders, err := d.orders.ListAwaitingOffer(ctx, d.offerTimeout, dispatchBatchLimit)
+	if err != nil || len(orders) == 0 {
+		return err
+	}
+
+	drones, err := d.drones.ListAvailable(ctx, d.offerTimeout)
+	if err != nil || len(drones) == 0 {
+		return err
+	}
+
+	airspace, err := d.uc.airspace(ctx)
+	if err != nil {
+		return err
+	}
+
+	pairs := model.MatchOptimal(orders, drones, func(order model.Order, drone model.Drone) float64 {
+		return d.cost.Km(order, drone, airspace)
+	})
+
+	offered := 0
+	for _, pair := range pairs {
+		if err := d.uc.offer(ctx, pair.Order, pair.Drone, airspace); err != nil {
+			log.Printf("batch offer of order %d to drone %d failed: %v", pair.Order.ID, pair.Drone.ID, err)
+			continue
+		}
+		offered++
+	}
+
+	log.Printf("batch dispatch: offered %d of %d waiting orders to %d available drones (%.2f km to pickups)",
+		offered, len(orders), len(drones), model.TotalCostKm(pairs))
+	return nil
+}
+
+// DispatchPlanner answers what dispatch would offer right now, or for a
+// made-up scenario, without offering anything.
+type DispatchPlanner struct {
+	orders       DispatchOrderRepo
+	drones       DispatchDroneRepo
+	zoneRepo     NoFlyZoneReader
+	mode         model.DispatchMode
+	cost         model.DispatchCost
+	offerTimeout time.Duration
+}
+
+func NewDispatchPlanner(orders DispatchOrderRepo, drones DispatchDroneRepo, zoneRepo NoFlyZoneReader, mode model.DispatchMode, cost model.DispatchCost, offerTimeout time.Duration) *DispatchPlanner {
+	return &DispatchPlanner{
+		orders:       orders,
+		drones:       drones,
+		zoneRepo:     zoneRepo,
+		mode:         mode,
+		cost:         cost,
+		offerTimeout: offerTimeout,
+	}
+}
+
+// Plan dry-runs dispatch. Live plans see what the next batch round would:
+// up to dispatchBatchLimit orders without a fresh offer and the drones not
+// weighing one. Either way the no-fly zones in effect now shape the costs
+// and ETAs.
+func (p *DispatchPlanner) Plan(ctx context.Context, req model.DispatchPlanRequest) (*model.DispatchPlan, error) {
+	mode, cost := p.mode, p.cost
+	if req.Mode != "" {
+		m, err := model.ParseDispatchMode(req.Mode)
+		if err != nil {
+			return nil, model.ErrInvalidDispatchPlan(err.Error())
+		}
+		mode = m
+	}
+	if req.Cost != "" {
+		c, err := model.ParseDispatchCost(req.Cost)
+		if err != nil {
+			return nil, model.ErrInvalidDispatchPlan(err.Error())
+		}
+		cost = c
+	}
+
+	var (
+		orders []model.Order
+		drones []model.Drone
+		source = model.DispatchPlanLive
+		err    error
+	)
+	if req.Scenario != nil {
+		source = model.DispatchPlanScenario
+		orders, drones, err = req.Scenario.Build()
+	} else {
+		orders, drones, err = p.live(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	zones, err := p.zoneRepo.ListActiveAt(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := model.PlanDispatch(mode, cost, source, orders, drones, model.Airspace(zones), now)
+	return &plan, nil
+}
+
+func (p *DispatchPlanner) live(ctx context.Context) ([]model.Order, []model.Drone, error) {
+	orders, err := p.orders.ListAwaitingOffer(ctx, p.offerTimeout, dispatchBatchLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+	drones, err := p.drones.ListAvailable(ctx, p.offerTimeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	return orders, drones, nil
+}
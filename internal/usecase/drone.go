@@ -3,26 +3,78 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	"log"
 	"time"
 
 	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
 )
 
 type DroneUsecase struct {
-	droneRepo DroneHeartbeatRepo
+	droneRepo    DroneHeartbeatRepo
+	telemetry    TelemetryWriter
+	geofence     GeofenceLoader
+	breachRepo   GeofenceBreachRepo
+	notifier     BreachNotifier
+	breachAction model.BreachAction
+	base         BaseReturner
 }
 
 type DroneHeartbeatRepo interface {
 	BeginTx(ctx context.Context) (*sql.Tx, error)
+	GetByID(ctx context.Context, id int64) (*model.Drone, error)
 	GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (*model.Drone, error)
 	UpdateTx(ctx context.Context, tx *sql.Tx, drone *model.Drone) (*model.Drone, error)
 }
 
-func NewDroneUsecase(droneRepo DroneHeartbeatRepo) *DroneUsecase {
-	return &DroneUsecase{droneRepo: droneRepo}
+type TelemetryWriter interface {
+	InsertTx(ctx context.Context, tx *sql.Tx, point *model.TelemetryPoint) error
 }
 
+// GeofenceLoader returns the no-fly zones active at now and the service
+// coverage a heartbeat is checked against.
+type GeofenceLoader interface {
+	Geofence(ctx context.Context, now time.Time) (model.Geofence, error)
+}
+
+type GeofenceBreachRepo interface {
+	InsertTx(ctx context.Context, tx *sql.Tx, breach *model.GeofenceBreach) error
+	ListByDrone(ctx context.Context, droneID int64, limit, offset int) ([]model.GeofenceBreach, error)
+}
+
+type BreachNotifier interface {
+	NotifyBreach(ctx context.Context, breach model.GeofenceBreach) error
+}
+
+// BaseReturner sends drones back to a depot when their battery or the lack
+// of demand calls for it. It is best effort: the drone has already been
+// updated and is reconsidered later.
+type BaseReturner interface {
+	TripEnded(ctx context.Context, drone model.Drone)
+	HeartbeatApplied(ctx context.Context, drone model.Drone)
+}
+
+func NewDroneUsecase(droneRepo DroneHeartbeatRepo, telemetry TelemetryWriter, geofence GeofenceLoader, breachRepo GeofenceBreachRepo, notifier BreachNotifier, breachAction model.BreachAction, base BaseReturner) *DroneUsecase {
+	return &DroneUsecase{
+		droneRepo:    droneRepo,
+		telemetry:    telemetry,
+		geofence:     geofence,
+		breachRepo:   breachRepo,
+		notifier:     notifier,
+		breachAction: breachAction,
+		base:         base,
+	}
+}
+
+// Heartbeat stores the drone's position, appends it to the telemetry
+// history and records a breach for every geofence it has just entered or left.
 func (uc *DroneUsecase) Heartbeat(ctx context.Context, droneID int64, hb model.DroneHeartbeat) (*model.Drone, error) {
+	now := time.Now().UTC()
+
+	geofence, err := uc.geofence.Geofence(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
 	tx, err := uc.droneRepo.BeginTx(ctx)
 	if err != nil {
 		return nil, err
@@ -34,11 +86,27 @@ func (uc *DroneUsecase) Heartbeat(ctx context.Context, droneID int64, hb model.D
 		return nil, err
 	}
 
-	now := time.Now().UTC()
+	var prev *model.GeoPoint
+	if drone.LastHeartbeat != nil {
+		prev = &model.GeoPoint{Lat: drone.Lat, Lng: drone.Lng}
+	}
+
 	if err := drone.ApplyHeartbeat(hb, now); err != nil {
 		return nil, err
 	}
 
+	point := model.NewTelemetryPoint(*drone, hb, now)
+	if err := uc.telemetry.InsertTx(ctx, tx, &point); err != nil {
+		return nil, err
+	}
+
+	breaches := geofence.Entered(*drone, prev, model.GeoPoint{Lat: drone.Lat, Lng: drone.Lng}, uc.breachAction, now)
+	for i := range breaches {
+		if err := uc.breachRepo.InsertTx(ctx, tx, &breaches[i]); err != nil {
+			return nil, err
+		}
+	}
+
 	updatedDrone, err := uc.droneRepo.UpdateTx(ctx, tx, drone)
 	if err != nil {
 		return nil, err
@@ -48,5 +116,33 @@ func (uc *DroneUsecase) Heartbeat(ctx context.Context, droneID int64, hb model.D
 		return nil, err
 	}
 
+	for _, breach := range breaches {
+		if err := uc.notifier.NotifyBreach(ctx, breach); err != nil {
+			log.Printf("failed to notify geofence breach %d for drone %d: %v", breach.ID, droneID, err)
+		}
+	}
+
+	if uc.base != nil {
+		uc.base.HeartbeatApplied(ctx, *updatedDrone)
+	}
+
 	return updatedDrone, nil
 }
+
+func (uc *DroneUsecase) ListDroneBreaches(ctx context.Context, droneID int64, page, pageSize int) ([]model.GeofenceBreach, model.Pagination, error) {
+	pagination, err := model.NormalizePagination(page, pageSize)
+	if err != nil {
+		return nil, model.Pagination{}, err
+	}
+
+	if _, err := uc.droneRepo.GetByID(ctx, droneID); err != nil {
+		return nil, model.Pagination{}, err
+	}
+
+	breaches, err := uc.breachRepo.ListByDrone(ctx, droneID, pagination.PageSize, pagination.Offset)
+	if err != nil {
+		return nil, model.Pagination{}, err
+	}
+
+	return breaches, pagination, nil
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+type DroneCommandRepo interface {
+	BeginTx(ctx context.Context) (*sql.Tx, error)
+	Insert(ctx context.Context, cmd *model.DroneCommand) error
+	GetByDrone(ctx context.Context, droneID, id int64) (*model.DroneCommand, error)
+	GetByDroneForUpdate(ctx context.Context, tx *sql.Tx, droneID, id int64) (*model.DroneCommand, error)
+	UpdateTx(ctx context.Context, tx *sql.Tx, cmd *model.DroneCommand) error
+	ListByDrone(ctx context.Context, droneID int64, limit, offset int) ([]model.DroneCommand, error)
+}
+
+type CommandDroneRepo interface {
+	GetByID(ctx context.Context, id int64) (*model.Drone, error)
+}
+
+// CommandSender pushes a command to the drone's live connection; an error
+// means it did not reach the drone.
+type CommandSender interface {
+	SendCommand(ctx context.Context, cmd model.DroneCommand) error
+}
+
+type DroneCommandUsecase struct {
+	commandRepo DroneCommandRepo
+	droneRepo   CommandDroneRepo
+	sender      CommandSender
+}
+
+func NewDroneCommandUsecase(commandRepo DroneCommandRepo, droneRepo CommandDroneRepo, sender CommandSender) *DroneCommandUsecase {
+	return &DroneCommandUsecase{
+		commandRepo: commandRepo,
+		droneRepo:   droneRepo,
+		sender:      sender,
+	}
+}
+
+// IssueCommand stores the command, pushes it to the drone and records
+// whether it got through. An offline drone is not an error: the command is
+// kept as undelivered for the admin to see.
+func (uc *DroneCommandUsecase) IssueCommand(ctx context.Context, req model.CreateDroneCommandRequest) (*model.DroneCommand, error) {
+	if _, err := uc.droneRepo.GetByID(ctx, req.DroneID); err != nil {
+		return nil, err
+	}
+
+	cmd, err := model.NewDroneCommand(req, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.commandRepo.Insert(ctx, cmd); err != nil {
+		return nil, err
+	}
+
+	sendErr := uc.sender.SendCommand(ctx, *cmd)
+	if sendErr != nil {
+		log.Printf("command %d not delivered to drone %d: %v", cmd.ID, cmd.DroneID, sendErr)
+	}
+
+	return uc.update(ctx, cmd.DroneID, cmd.ID, func(c *model.DroneCommand, now time.Time) error {
+		if sendErr != nil {
+			return c.MarkUndelivered(now)
+		}
+		return c.MarkSent(now)
+	})
+}
+
+// AcknowledgeCommand records the drone accepting or rejecting a command
+// sent to it.
+func (uc *DroneCommandUsecase) AcknowledgeCommand(ctx context.Context, droneID, commandID int64, accepted bool, note string) (*model.DroneCommand, error) {
+	return uc.update(ctx, droneID, commandID, func(c *model.DroneCommand, now time.Time) error {
+		return c.Acknowledge(accepted, note, now)
+	})
+}
+
+// CompleteCommand records the drone's final result for an accepted command.
+func (uc *DroneCommandUsecase) CompleteCommand(ctx context.Context, droneID, commandID int64, succeeded bool, note string) (*model.DroneCommand, error) {
+	return uc.update(ctx, droneID, commandID, func(c *model.DroneCommand, now time.Time) error {
+		return c.Complete(succeeded, note, now)
+	})
+}
+
+func (uc *DroneCommandUsecase) GetCommand(ctx context.Context, droneID, commandID int64) (*model.DroneCommand, error) {
+	return uc.commandRepo.GetByDrone(ctx, droneID, commandID)
+}
+
+func (uc *DroneCommandUsecase) ListDroneCommands(ctx context.Context, droneID int64, page, pageSize int) ([]model.DroneCommand, model.Pagination, error) {
+	pagination, err := model.NormalizePagination(page, pageSize)
+	if err != nil {
+		return nil, model.Pagination{}, err
+	}
+
+	if _, err := uc.droneRepo.GetByID(ctx, droneID); err != nil {
+		return nil, model.Pagination{}, err
+	}
+
+	commands, err := uc.commandRepo.ListByDrone(ctx, droneID, pagination.PageSize, pagination.Offset)
+	if err != nil {
+		return nil, model.Pagination{}, err
+	}
+
+	return commands, pagination, nil
+}
+
+func (uc *DroneCommandUsecase) update(ctx context.Context, droneID, commandID int64, apply func(*model.DroneCommand, time.Time) error) (*model.DroneCommand, error) {
+	tx, err := uc.commandRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cmd, err := uc.commandRepo.GetByDroneForUpdate(ctx, tx, droneID, commandID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := apply(cmd, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	if err := uc.commandRepo.UpdateTx(ctx, tx, cmd); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
@@ -3,18 +3,22 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	"log"
+	"time"
 
 	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
 )
 
 type AssignmentScheduler interface {
 	ScheduleAssignment(order model.Order)
+	NotifyHandoff(ctx context.Context, droneID int64, order model.Order)
 }
 
 type DroneStatusRepo interface {
 	BeginTx(ctx context.Context) (*sql.Tx, error)
 	GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (*model.Drone, error)
 	UpdateTx(ctx context.Context, tx *sql.Tx, drone *model.Drone) (*model.Drone, error)
+	GetByID(ctx context.Context, id int64) (*model.Drone, error)
 	List(ctx context.Context, limit, offset int) ([]model.Drone, error)
 }
 
@@ -22,27 +26,58 @@ type DroneOpsOrderRepo interface {
 	BeginTx(ctx context.Context) (*sql.Tx, error)
 	GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (*model.Order, error)
 	UpdateTx(ctx context.Context, tx *sql.Tx, order *model.Order) (*model.Order, error)
+	ListActiveByDroneForUpdate(ctx context.Context, tx *sql.Tx, droneID int64) ([]model.Order, error)
+}
+
+// BreakdownRecorder keeps the maintenance record of a drone that broke down
+// until it is fixed.
+type BreakdownRecorder interface {
+	FindOpenByDroneForUpdate(ctx context.Context, tx *sql.Tx, droneID int64) (*model.MaintenanceRecord, error)
+	InsertTx(ctx context.Context, tx *sql.Tx, record *model.MaintenanceRecord) (*model.MaintenanceRecord, error)
+	UpdateTx(ctx context.Context, tx *sql.Tx, record *model.MaintenanceRecord) (*model.MaintenanceRecord, error)
+}
+
+type LandingSiteReader interface {
+	ListActive(ctx context.Context) ([]model.LandingSite, error)
 }
 
 type DroneOpsUsecase struct {
-	droneRepo DroneStatusRepo
-	orderRepo DroneOpsOrderRepo
-	scheduler AssignmentScheduler
+	droneRepo   DroneStatusRepo
+	orderRepo   DroneOpsOrderRepo
+	tripRepo    TripRepo
+	siteRepo    LandingSiteReader
+	zoneRepo    NoFlyZoneReader
+	scheduler   AssignmentScheduler
+	handoff     model.HandoffPolicy
+	maintenance BreakdownRecorder
 }
 
-func NewDroneOpsUsecase(droneRepo DroneStatusRepo, orderRepo DroneOpsOrderRepo, scheduler AssignmentScheduler) *DroneOpsUsecase {
+func NewDroneOpsUsecase(droneRepo DroneStatusRepo, orderRepo DroneOpsOrderRepo, tripRepo TripRepo, siteRepo LandingSiteReader, zoneRepo NoFlyZoneReader, scheduler AssignmentScheduler, handoff model.HandoffPolicy, maintenance BreakdownRecorder) *DroneOpsUsecase {
 	return &DroneOpsUsecase{
-		droneRepo: droneRepo,
-		orderRepo: orderRepo,
-		scheduler: scheduler,
+		droneRepo:   droneRepo,
+		orderRepo:   orderRepo,
+		tripRepo:    tripRepo,
+		siteRepo:    siteRepo,
+		zoneRepo:    zoneRepo,
+		scheduler:   scheduler,
+		handoff:     handoff,
+		maintenance: maintenance,
 	}
 }
 
-func (uc *DroneOpsUsecase) ReportBroken(ctx context.Context, actorID, droneID int64, actorRole model.Role, location model.DroneHeartbeat) (*model.Drone, *model.Order, error) {
+// ReportBroken hands off every order on the drone's trip: parcels already on
+// board wait at the rendezvous the handoff policy picks, the rest go back to
+// pending.
+func (uc *DroneOpsUsecase) ReportBroken(ctx context.Context, actorID, droneID int64, actorRole model.Role, location model.DroneHeartbeat) (*model.Drone, []model.Order, error) {
 	if actorRole.IsDrone() && actorID != droneID {
 		return nil, nil, model.ErrDroneActionNotAllowed()
 	}
 
+	rendezvous, err := uc.rendezvous(ctx, droneID, model.GeoPoint{Lat: location.Lat, Lng: location.Lng})
+	if err != nil {
+		return nil, nil, err
+	}
+
 	tx, err := uc.droneRepo.BeginTx(ctx)
 	if err != nil {
 		return nil, nil, err
@@ -53,30 +88,18 @@ func (uc *DroneOpsUsecase) ReportBroken(ctx context.Context, actorID, droneID in
 	if err != nil {
 		return nil, nil, err
 	}
-	previousOrderID := drone.CurrentOrderID
 
 	if err := drone.ReportBroken(location); err != nil {
 		return nil, nil, err
 	}
 
-	var updatedOrder *model.Order
-	var needsAssignment bool
-	if previousOrderID != nil {
-		order, err := uc.orderRepo.GetByIDForUpdate(ctx, tx, *previousOrderID)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		if err := order.IsAssignedTo(drone.ID); err != nil {
-			return nil, nil, err
-		}
+	if err := uc.openBreakdownRecord(ctx, tx, droneID); err != nil {
+		return nil, nil, err
+	}
 
-		if needsAssignment = order.HandoffOrder(drone.Lat, drone.Lng); needsAssignment {
-			updatedOrder, err = uc.orderRepo.UpdateTx(ctx, tx, order)
-			if err != nil {
-				return nil, nil, err
-			}
-		}
+	handedOff, err := uc.releaseTrip(ctx, tx, drone, rendezvous)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	updatedDrone, err := uc.droneRepo.UpdateTx(ctx, tx, drone)
@@ -88,14 +111,14 @@ func (uc *DroneOpsUsecase) ReportBroken(ctx context.Context, actorID, droneID in
 		return nil, nil, err
 	}
 
-	if needsAssignment && updatedOrder != nil && uc.scheduler != nil {
-		uc.scheduler.ScheduleAssignment(*updatedOrder)
-	}
+	uc.scheduleAll(ctx, droneID, handedOff)
 
-	return updatedDrone, updatedOrder, nil
+	return updatedDrone, handedOff, nil
 }
 
-func (uc *DroneOpsUsecase) ReportFixed(ctx context.Context, actorID, droneID int64, actorRole model.Role, location model.DroneHeartbeat) (*model.Drone, error) {
+// ReportFixed puts the drone back in service and closes its open maintenance
+// record with the repair details.
+func (uc *DroneOpsUsecase) ReportFixed(ctx context.Context, actorID, droneID int64, actorRole model.Role, location model.DroneHeartbeat, repair model.MaintenanceDetails) (*model.Drone, error) {
 	if actorRole.IsDrone() && actorID != droneID {
 		return nil, model.ErrDroneActionNotAllowed()
 	}
@@ -115,6 +138,17 @@ func (uc *DroneOpsUsecase) ReportFixed(ctx context.Context, actorID, droneID int
 		return nil, err
 	}
 
+	if err := uc.closeMaintenanceRecord(ctx, tx, droneID, repair); err != nil {
+		return nil, err
+	}
+
+	// a fixed drone starts from a clean slate; anything still pinned to it is
+	// handed to the dispatcher rather than silently orphaned
+	released, err := uc.releaseTrip(ctx, tx, drone, model.GeoPoint{Lat: drone.Lat, Lng: drone.Lng})
+	if err != nil {
+		return nil, err
+	}
+
 	updatedDrone, err := uc.droneRepo.UpdateTx(ctx, tx, drone)
 	if err != nil {
 		return nil, err
@@ -124,9 +158,131 @@ func (uc *DroneOpsUsecase) ReportFixed(ctx context.Context, actorID, droneID int
 		return nil, err
 	}
 
+	uc.scheduleAll(ctx, droneID, released)
+
 	return updatedDrone, nil
 }
 
+// openBreakdownRecord opens a breakdown record unless the drone already has
+// a record open, as when it breaks down again or during maintenance.
+func (uc *DroneOpsUsecase) openBreakdownRecord(ctx context.Context, tx *sql.Tx, droneID int64) error {
+	open, err := uc.maintenance.FindOpenByDroneForUpdate(ctx, tx, droneID)
+	if err != nil || open != nil {
+		return err
+	}
+
+	record, err := model.NewMaintenanceRecord(droneID, model.MaintenanceBreakdown, model.MaintenanceDetails{}, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	_, err = uc.maintenance.InsertTx(ctx, tx, record)
+	return err
+}
+
+// closeMaintenanceRecord closes the drone's open record with the repair
+// details. Without an open record, as for drones that broke down before
+// records were kept, the details are kept as a breakdown record of their own.
+func (uc *DroneOpsUsecase) closeMaintenanceRecord(ctx context.Context, tx *sql.Tx, droneID int64, repair model.MaintenanceDetails) error {
+	now := time.Now().UTC()
+
+	record, err := uc.maintenance.FindOpenByDroneForUpdate(ctx, tx, droneID)
+	if err != nil {
+		return err
+	}
+
+	if record == nil {
+		if repair.IsEmpty() {
+			return nil
+		}
+		record, err = model.NewMaintenanceRecord(droneID, model.MaintenanceBreakdown, repair, now)
+		if err != nil {
+			return err
+		}
+		if err := record.Close(model.MaintenanceDetails{}, now); err != nil {
+			return err
+		}
+		_, err = uc.maintenance.InsertTx(ctx, tx, record)
+		return err
+	}
+
+	if err := record.Close(repair, now); err != nil {
+		return err
+	}
+	_, err = uc.maintenance.UpdateTx(ctx, tx, record)
+	return err
+}
+
+// rendezvous is where the parcels of a drone that broke down at brokeAt wait
+// for the next drone.
+func (uc *DroneOpsUsecase) rendezvous(ctx context.Context, droneID int64, brokeAt model.GeoPoint) (model.GeoPoint, error) {
+	if uc.handoff.Rendezvous != model.HandoffAtNearestSite {
+		return brokeAt, nil
+	}
+
+	sites, err := uc.siteRepo.ListActive(ctx)
+	if err != nil {
+		return model.GeoPoint{}, err
+	}
+	zones, err := uc.zoneRepo.ListActiveAt(ctx, time.Now())
+	if err != nil {
+		return model.GeoPoint{}, err
+	}
+
+	point, site := uc.handoff.HandoffPoint(brokeAt, sites, model.Airspace(zones))
+	if site != nil {
+		log.Printf("drone %d broke down, handing off at landing site %d (%s)", droneID, site.ID, site.Name)
+	}
+	return point, nil
+}
+
+// releaseTrip hands off every order still assigned to the drone, parcels on
+// board at the handoff point, and aborts its active trip.
+func (uc *DroneOpsUsecase) releaseTrip(ctx context.Context, tx *sql.Tx, drone *model.Drone, handoffAt model.GeoPoint) ([]model.Order, error) {
+	activeOrders, err := uc.orderRepo.ListActiveByDroneForUpdate(ctx, tx, drone.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var handedOff []model.Order
+	for i := range activeOrders {
+		order := &activeOrders[i]
+		if !order.HandoffOrder(handoffAt.Lat, handoffAt.Lng) {
+			continue
+		}
+
+		updatedOrder, err := uc.orderRepo.UpdateTx(ctx, tx, order)
+		if err != nil {
+			return nil, err
+		}
+		handedOff = append(handedOff, *updatedOrder)
+	}
+
+	trip, err := uc.tripRepo.FindActiveByDroneForUpdate(ctx, tx, drone.ID)
+	if err != nil {
+		return nil, err
+	}
+	if trip != nil {
+		trip.Abort(time.Now().UTC())
+		if _, err := uc.tripRepo.UpdateTx(ctx, tx, trip); err != nil {
+			return nil, err
+		}
+	}
+
+	return handedOff, nil
+}
+
+// scheduleAll tells the releasing drone to hand each order off and offers the
+// orders to other drones.
+func (uc *DroneOpsUsecase) scheduleAll(ctx context.Context, droneID int64, orders []model.Order) {
+	if uc.scheduler == nil {
+		return
+	}
+	for _, order := range orders {
+		uc.scheduler.NotifyHandoff(ctx, droneID, order)
+		uc.scheduler.ScheduleAssignment(order)
+	}
+}
+
 func (uc *DroneOpsUsecase) ListDrones(ctx context.Context, page, pageSize int) ([]model.Drone, model.Pagination, error) {
 	pagination, err := model.NormalizePagination(page, pageSize)
 	if err != nil {
@@ -140,3 +296,56 @@ func (uc *DroneOpsUsecase) ListDrones(ctx context.Context, page, pageSize int) (
 
 	return drones, pagination, nil
 }
+
+func (uc *DroneOpsUsecase) GetDrone(ctx context.Context, droneID int64) (*model.Drone, error) {
+	return uc.droneRepo.GetByID(ctx, droneID)
+}
+
+func (uc *DroneOpsUsecase) UpdateCapacity(ctx context.Context, droneID int64, capacity int) (*model.Drone, error) {
+	tx, err := uc.droneRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	drone, err := uc.droneRepo.GetByIDForUpdate(ctx, tx, droneID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := drone.SetCapacity(capacity); err != nil {
+		return nil, err
+	}
+
+	updatedDrone, err := uc.droneRepo.UpdateTx(ctx, tx, drone)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return updatedDrone, nil
+}
+
+func (uc *DroneOpsUsecase) GetTrip(ctx context.Context, actorID, droneID int64, actorRole model.Role) (*model.Drone, *model.Trip, error) {
+	if actorRole.IsDrone() && actorID != droneID {
+		return nil, nil, model.ErrDroneActionNotAllowed()
+	}
+
+	drone, err := uc.droneRepo.GetByID(ctx, droneID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	trip, err := uc.tripRepo.FindActiveByDrone(ctx, droneID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if trip == nil {
+		return nil, nil, model.ErrDroneNotOnTrip()
+	}
+
+	return drone, trip, nil
+}
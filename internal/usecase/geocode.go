@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+// Geocoder resolves free-text addresses; ReverseGeocode returns nil, nil when
+// no named place is nearby.
+type Geocoder interface {
+	Geocode(ctx context.Context, query string) (*model.Place, error)
+	ReverseGeocode(ctx context.Context, lat, lng float64) (*model.Place, error)
+}
+
+type GeocodeUsecase struct {
+	geocoder Geocoder
+}
+
+func NewGeocodeUsecase(geocoder Geocoder) *GeocodeUsecase {
+	return &GeocodeUsecase{geocoder: geocoder}
+}
+
+func (uc *GeocodeUsecase) Search(ctx context.Context, query string) (*model.Place, error) {
+	return uc.geocoder.Geocode(ctx, query)
+}
+
+func (uc *GeocodeUsecase) Reverse(ctx context.Context, lat, lng float64) (*model.Place, error) {
+	if lat < -90 || lat > 90 {
+		return nil, model.ErrInvalidLatitude(lat)
+	}
+	if lng < -180 || lng > 180 {
+		return nil, model.ErrInvalidLongitude(lng)
+	}
+	return uc.geocoder.ReverseGeocode(ctx, lat, lng)
+}
+
+// placeName is best-effort reverse geocoding for display; failures only log.
+func placeName(ctx context.Context, geocoder Geocoder, lat, lng float64) *model.Place {
+	if geocoder == nil {
+		return nil
+	}
+	place, err := geocoder.ReverseGeocode(ctx, lat, lng)
+	if err != nil {
+		log.Printf("reverse geocode %.6f,%.6f failed: %v", lat, lng, err)
+		return nil
+	}
+	return place
+}
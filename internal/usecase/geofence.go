@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+// geofenceCacheTTL bounds how long heartbeats reuse the zones and areas they
+// last read. Writes through this process clear the cache at once; the TTL
+// covers writes made by other instances.
+const geofenceCacheTTL = 10 * time.Second
+
+type GeofenceZoneLister interface {
+	List(ctx context.Context) ([]model.NoFlyZone, error)
+}
+
+// GeofenceInvalidator is told when no-fly zones or service areas change.
+type GeofenceInvalidator interface {
+	GeofenceChanged()
+}
+
+// GeofenceCache keeps the no-fly zones and service coverage checked on every
+// heartbeat, so heartbeats do not read them from the database each time.
+// All zones are cached and filtered by time window on use, so a zone still
+// switches on and off on schedule while cached.
+type GeofenceCache struct {
+	zoneRepo GeofenceZoneLister
+	areaRepo ServiceAreaReader
+
+	mu       sync.Mutex
+	loadedAt time.Time
+	zones    []model.NoFlyZone
+	coverage model.ServiceCoverage
+}
+
+func NewGeofenceCache(zoneRepo GeofenceZoneLister, areaRepo ServiceAreaReader) *GeofenceCache {
+	return &GeofenceCache{zoneRepo: zoneRepo, areaRepo: areaRepo}
+}
+
+// Geofence returns the zones active at now and the service coverage,
+// reloading them when the cache is empty or older than geofenceCacheTTL.
+func (c *GeofenceCache) Geofence(ctx context.Context, now time.Time) (model.Geofence, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.loadedAt.IsZero() || time.Since(c.loadedAt) >= geofenceCacheTTL {
+		zones, err := c.zoneRepo.List(ctx)
+		if err != nil {
+			return model.Geofence{}, err
+		}
+		coverage, err := loadServiceCoverage(ctx, c.areaRepo)
+		if err != nil {
+			return model.Geofence{}, err
+		}
+		c.zones, c.coverage, c.loadedAt = zones, coverage, time.Now()
+	}
+
+	active := make([]model.NoFlyZone, 0, len(c.zones))
+	for _, zone := range c.zones {
+		if zone.ActiveAt(now) {
+			active = append(active, zone)
+		}
+	}
+
+	return model.Geofence{
+		Airspace: model.Airspace(active),
+		Coverage: c.coverage,
+	}, nil
+}
+
+// GeofenceChanged drops the cached zones and areas so the next heartbeat
+// reads the ones just written.
+func (c *GeofenceCache) GeofenceChanged() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loadedAt = time.Time{}
+}
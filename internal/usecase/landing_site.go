@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+type LandingSiteRepo interface {
+	Insert(ctx context.Context, site *model.LandingSite) (*model.LandingSite, error)
+	GetByID(ctx context.Context, id int64) (*model.LandingSite, error)
+	List(ctx context.Context) ([]model.LandingSite, error)
+	Update(ctx context.Context, site *model.LandingSite) (*model.LandingSite, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type LandingSiteUsecase struct {
+	siteRepo LandingSiteRepo
+}
+
+func NewLandingSiteUsecase(siteRepo LandingSiteRepo) *LandingSiteUsecase {
+	return &LandingSiteUsecase{siteRepo: siteRepo}
+}
+
+func (uc *LandingSiteUsecase) CreateLandingSite(ctx context.Context, name string, lat, lng float64) (*model.LandingSite, error) {
+	site, err := model.NewLandingSite(name, lat, lng)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.siteRepo.Insert(ctx, site)
+}
+
+func (uc *LandingSiteUsecase) ListLandingSites(ctx context.Context) ([]model.LandingSite, error) {
+	return uc.siteRepo.List(ctx)
+}
+
+func (uc *LandingSiteUsecase) UpdateLandingSite(ctx context.Context, id int64, req model.UpdateLandingSiteRequest) (*model.LandingSite, error) {
+	site, err := uc.siteRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := site.Update(req); err != nil {
+		return nil, err
+	}
+
+	return uc.siteRepo.Update(ctx, site)
+}
+
+// DeleteLandingSite removes the site; parcels already waiting there keep
+// their handoff point.
+func (uc *LandingSiteUsecase) DeleteLandingSite(ctx context.Context, id int64) error {
+	return uc.siteRepo.Delete(ctx, id)
+}
@@ -0,0 +1,270 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+// backgroundJobsLease is the lease every singleton job runs under.
+const backgroundJobsLease = "background-jobs"
+
+// leaseReleaseTimeout bounds the release sent while stepping down on
+// shutdown, when the campaign context is already done.
+const leaseReleaseTimeout = 2 * time.Second
+
+type LeaseRepo interface {
+	Acquire(ctx context.Context, name, holder string, ttl time.Duration) (*model.LeaderLease, error)
+	Release(ctx context.Context, name, holder string) error
+	FindByName(ctx context.Context, name string) (*model.LeaderLease, error)
+}
+
+type singletonJob struct {
+	name string
+	run  func(ctx context.Context)
+}
+
+// LeaderElector campaigns for a MySQL lease so that, however many replicas
+// run, exactly one runs the registered singleton jobs. The leader renews
+// every renewEvery; standbys retry on the same beat, so a dead leader is
+// replaced within ttl plus one renewal.
+type LeaderElector struct {
+	leases     LeaseRepo
+	nodeID     string
+	ttl        time.Duration
+	renewEvery time.Duration
+
+	mu         sync.Mutex
+	jobs       []singletonJob
+	leading    bool
+	term       int64
+	renewedAt  time.Time
+	cancelJobs context.CancelFunc
+	running    sync.WaitGroup
+	drained    chan struct{}
+}
+
+func NewLeaderElector(leases LeaseRepo, nodeID string, ttl, renewEvery time.Duration) *LeaderElector {
+	drained := make(chan struct{})
+	close(drained)
+	return &LeaderElector{
+		leases:     leases,
+		nodeID:     nodeID,
+		ttl:        ttl,
+		renewEvery: renewEvery,
+		drained:    drained,
+	}
+}
+
+// Register adds a job that runs only on the leader. run must return once
+// its context is done, which happens when this node loses leadership; it
+// is started again if the node is elected later. Jobs must be registered
+// before Start.
+func (e *LeaderElector) Register(name string, run func(ctx context.Context)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.jobs = append(e.jobs, singletonJob{name: name, run: run})
+}
+
+// Start campaigns until ctx is done, then stops the jobs and releases the
+// lease so a standby can take over at once. The returned channel is closed
+// once that is done.
+func (e *LeaderElector) Start(ctx context.Context) <-chan struct{} {
+	resigned := make(chan struct{})
+	go func() {
+		defer close(resigned)
+		ticker := time.NewTicker(e.renewEvery)
+		defer ticker.Stop()
+
+		for {
+			e.campaign(ctx)
+
+			select {
+			case <-ctx.Done():
+				e.resign()
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return resigned
+}
+
+// Status reports this node's view of the election. The lease is read from
+// the database so any replica can answer.
+func (e *LeaderElector) Status(ctx context.Context) (*model.LeaderStatus, error) {
+	lease, err := e.leases.FindByName(ctx, backgroundJobsLease)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	jobs := make([]string, 0, len(e.jobs))
+	for _, job := range e.jobs {
+		jobs = append(jobs, job.name)
+	}
+
+	return &model.LeaderStatus{
+		NodeID:   e.nodeID,
+		IsLeader: e.leading,
+		Lease:    lease,
+		Jobs:     jobs,
+	}, nil
+}
+
+func (e *LeaderElector) campaign(ctx context.Context) {
+	attemptedAt := time.Now()
+	// a hanging renewal must not outlive the beat, or the lease could lapse
+	// before the failure is noticed
+	acquireCtx, cancel := context.WithTimeout(ctx, e.renewEvery)
+	lease, err := e.leases.Acquire(acquireCtx, backgroundJobsLease, e.nodeID, e.ttl)
+	cancel()
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("leader campaign for node %s failed: %v", e.nodeID, err)
+		// step down while the lease we last renewed is surely still ours,
+		// before another node can take it over
+		if e.isLeading() && !e.canRenewInTime() {
+			e.stepDown("lease renewal keeps failing")
+		}
+		return
+	}
+
+	if lease.HeldBy(e.nodeID) {
+		e.mu.Lock()
+		e.renewedAt = attemptedAt
+		e.mu.Unlock()
+		if !e.isLeading() {
+			e.lead(ctx, lease.Term)
+		}
+		return
+	}
+
+	if e.isLeading() {
+		e.stepDown("lease taken by " + lease.Holder)
+	}
+}
+
+// lead starts the jobs once the ones from an earlier term have returned;
+// until then the node keeps renewing the lease and tries again on the next
+// beat.
+func (e *LeaderElector) lead(ctx context.Context, term int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	select {
+	case <-e.drained:
+	default:
+		log.Printf("node %s holds the lease (term %d) but its previous jobs are still stopping", e.nodeID, term)
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	e.cancelJobs = cancel
+	e.leading = true
+	e.term = term
+
+	for _, job := range e.jobs {
+		e.running.Add(1)
+		go func(job singletonJob) {
+			defer e.running.Done()
+			job.run(jobCtx)
+		}(job)
+	}
+	drained := make(chan struct{})
+	e.drained = drained
+	go func() {
+		e.running.Wait()
+		close(drained)
+	}()
+	go e.watchLease(jobCtx)
+
+	log.Printf("node %s elected leader (term %d), running %d background jobs", e.nodeID, term, len(e.jobs))
+}
+
+// watchLease steps down as soon as the lease can no longer be renewed before
+// it lapses, even while a renewal is still under way. ctx is the term's job
+// context.
+func (e *LeaderElector) watchLease(ctx context.Context) {
+	for {
+		wait := time.Until(e.lastRenewed().Add(e.ttl - e.renewEvery))
+		if wait <= 0 {
+			e.mu.Lock()
+			// a watchdog outliving its term must not end the next one
+			if ctx.Err() == nil {
+				e.stepDownLocked("lease renewal not confirmed in time")
+			}
+			e.mu.Unlock()
+			return
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// stepDown cancels the jobs at once. They drain in the background, and lead
+// does not start the next term's jobs before they have, so two sets never
+// overlap on this node.
+func (e *LeaderElector) stepDown(reason string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stepDownLocked(reason)
+}
+
+func (e *LeaderElector) stepDownLocked(reason string) {
+	if !e.leading {
+		return
+	}
+	e.leading = false
+	e.cancelJobs()
+	log.Printf("node %s stepped down as leader (term %d): %s", e.nodeID, e.term, reason)
+}
+
+func (e *LeaderElector) resign() {
+	if !e.isLeading() {
+		return
+	}
+	e.stepDown("shutting down")
+	e.mu.Lock()
+	drained := e.drained
+	e.mu.Unlock()
+	// the lease is only handed over once the jobs have returned
+	<-drained
+
+	ctx, cancel := context.WithTimeout(context.Background(), leaseReleaseTimeout)
+	defer cancel()
+	if err := e.leases.Release(ctx, backgroundJobsLease, e.nodeID); err != nil {
+		log.Printf("release leader lease for node %s failed: %v", e.nodeID, err)
+	}
+}
+
+func (e *LeaderElector) isLeading() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leading
+}
+
+// canRenewInTime: a renewal started now, which may take up to renewEvery,
+// would still land before the lease last renewed lapses.
+func (e *LeaderElector) canRenewInTime() bool {
+	return time.Since(e.lastRenewed())+e.renewEvery < e.ttl
+}
+
+func (e *LeaderElector) lastRenewed() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.renewedAt
+}
@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+type MaintenanceRepo interface {
+	BeginTx(ctx context.Context) (*sql.Tx, error)
+	InsertTx(ctx context.Context, tx *sql.Tx, record *model.MaintenanceRecord) (*model.MaintenanceRecord, error)
+	UpdateTx(ctx context.Context, tx *sql.Tx, record *model.MaintenanceRecord) (*model.MaintenanceRecord, error)
+	GetByID(ctx context.Context, id int64) (*model.MaintenanceRecord, error)
+	GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (*model.MaintenanceRecord, error)
+	FindOpenByDrone(ctx context.Context, droneID int64) (*model.MaintenanceRecord, error)
+	FindOpenByDroneForUpdate(ctx context.Context, tx *sql.Tx, droneID int64) (*model.MaintenanceRecord, error)
+	List(ctx context.Context, filter model.MaintenanceFilter, limit, offset int) ([]model.MaintenanceRecord, error)
+	GetUsage(ctx context.Context, droneID int64) (*model.MaintenanceUsage, error)
+	ListUsage(ctx context.Context) ([]model.MaintenanceUsage, error)
+	SetSchedule(ctx context.Context, droneID int64, schedule model.MaintenanceSchedule) error
+}
+
+type MaintenanceDroneRepo interface {
+	GetByID(ctx context.Context, id int64) (*model.Drone, error)
+	GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (*model.Drone, error)
+	UpdateTx(ctx context.Context, tx *sql.Tx, drone *model.Drone) (*model.Drone, error)
+}
+
+// MaintenanceUsecase keeps drone maintenance records and takes drones out of
+// service while one is open, including when they have flown their service
+// interval.
+type MaintenanceUsecase struct {
+	repo      MaintenanceRepo
+	droneRepo MaintenanceDroneRepo
+	policy    model.MaintenancePolicy
+}
+
+func NewMaintenanceUsecase(repo MaintenanceRepo, droneRepo MaintenanceDroneRepo, policy model.MaintenancePolicy) *MaintenanceUsecase {
+	return &MaintenanceUsecase{
+		repo:      repo,
+		droneRepo: droneRepo,
+		policy:    policy,
+	}
+}
+
+// OpenRecord takes the drone out of service for the given work. A drone that
+// is flying an order cannot be taken; a broken one stays broken.
+func (uc *MaintenanceUsecase) OpenRecord(ctx context.Context, droneID int64, issue model.MaintenanceIssue, details model.MaintenanceDetails) (*model.MaintenanceRecord, error) {
+	return uc.open(ctx, droneID, issue, details, false)
+}
+
+func (uc *MaintenanceUsecase) GetRecord(ctx context.Context, id int64) (*model.MaintenanceRecord, error) {
+	return uc.repo.GetByID(ctx, id)
+}
+
+func (uc *MaintenanceUsecase) ListRecords(ctx context.Context, filter model.MaintenanceFilter, page, pageSize int) ([]model.MaintenanceRecord, model.Pagination, error) {
+	pagination, err := model.NormalizePagination(page, pageSize)
+	if err != nil {
+		return nil, model.Pagination{}, err
+	}
+
+	records, err := uc.repo.List(ctx, filter, pagination.PageSize, pagination.Offset)
+	if err != nil {
+		return nil, model.Pagination{}, err
+	}
+
+	return records, pagination, nil
+}
+
+func (uc *MaintenanceUsecase) UpdateRecord(ctx context.Context, id int64, details model.MaintenanceDetails) (*model.MaintenanceRecord, error) {
+	tx, err := uc.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	record, err := uc.repo.GetByIDForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := record.Update(details); err != nil {
+		return nil, err
+	}
+
+	updated, err := uc.repo.UpdateTx(ctx, tx, record)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// CloseRecord ends the work and puts the drone back in service. A broken
+// drone's record is closed by reporting the drone fixed.
+func (uc *MaintenanceUsecase) CloseRecord(ctx context.Context, id int64, details model.MaintenanceDetails) (*model.MaintenanceRecord, error) {
+	record, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// the drone is locked first, as everywhere its status changes
+	drone, err := uc.droneRepo.GetByIDForUpdate(ctx, tx, record.DroneID)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err = uc.repo.GetByIDForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := record.Close(details, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	if err := drone.EndMaintenance(); err != nil {
+		return nil, err
+	}
+
+	updated, err := uc.repo.UpdateTx(ctx, tx, record)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.droneRepo.UpdateTx(ctx, tx, drone); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// Health sets what the drone has flown since its last service against its
+// service interval.
+func (uc *MaintenanceUsecase) Health(ctx context.Context, droneID int64) (*model.MaintenanceHealth, error) {
+	usage, err := uc.repo.GetUsage(ctx, droneID)
+	if err != nil {
+		return nil, err
+	}
+
+	open, err := uc.repo.FindOpenByDrone(ctx, droneID)
+	if err != nil {
+		return nil, err
+	}
+
+	health := uc.policy.Health(*usage, open)
+	return &health, nil
+}
+
+// SetSchedule gives the drone its own service interval. The drone is taken
+// out of service when it is next checked, not at once.
+func (uc *MaintenanceUsecase) SetSchedule(ctx context.Context, droneID int64, schedule model.MaintenanceSchedule) (*model.MaintenanceHealth, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.droneRepo.GetByID(ctx, droneID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.SetSchedule(ctx, droneID, schedule); err != nil {
+		return nil, err
+	}
+
+	return uc.Health(ctx, droneID)
+}
+
+// Run takes the drones that have flown their service interval out of
+// service every policy check interval until ctx is done. It is a singleton
+// job, run by the elected leader only.
+func (uc *MaintenanceUsecase) Run(ctx context.Context) {
+	ticker := time.NewTicker(uc.policy.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		usage, err := uc.repo.ListUsage(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("maintenance check failed: %v", err)
+			}
+			continue
+		}
+
+		for _, u := range usage {
+			if (u.Status == model.DroneIdle || u.Status == model.DroneCharging) && uc.policy.Due(u) {
+				uc.startScheduled(ctx, u)
+			}
+		}
+	}
+}
+
+// TripEnded takes a drone that has just flown past its service interval
+// straight into maintenance; it reports whether it did.
+func (uc *MaintenanceUsecase) TripEnded(ctx context.Context, drone model.Drone) bool {
+	if drone.Status != model.DroneIdle {
+		return false
+	}
+
+	usage, err := uc.repo.GetUsage(ctx, drone.ID)
+	if err != nil {
+		log.Printf("maintenance check for drone %d failed: %v", drone.ID, err)
+		return false
+	}
+	if !uc.policy.Due(*usage) {
+		return false
+	}
+
+	return uc.startScheduled(ctx, *usage)
+}
+
+func (uc *MaintenanceUsecase) startScheduled(ctx context.Context, usage model.MaintenanceUsage) bool {
+	notes := fmt.Sprintf("due after %.1f flight hours and %d trips since the last service", usage.FlightHours(), usage.Cycles)
+	record, err := uc.open(ctx, usage.DroneID, model.MaintenanceScheduled, model.MaintenanceDetails{Notes: &notes}, true)
+	if err != nil {
+		log.Printf("scheduled maintenance for drone %d failed: %v", usage.DroneID, err)
+		return false
+	}
+	if record == nil {
+		return false
+	}
+
+	log.Printf("drone %d taken out of service for scheduled maintenance (record %d): %s", usage.DroneID, record.ID, notes)
+	return true
+}
+
+// open inserts the record and takes the drone out of service. A scheduled
+// record waits for the drone to be idle or charging: nil when it is not, or
+// when it already has a record open.
+func (uc *MaintenanceUsecase) open(ctx context.Context, droneID int64, issue model.MaintenanceIssue, details model.MaintenanceDetails, scheduled bool) (*model.MaintenanceRecord, error) {
+	record, err := model.NewMaintenanceRecord(droneID, issue, details, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	drone, err := uc.droneRepo.GetByIDForUpdate(ctx, tx, droneID)
+	if err != nil {
+		return nil, err
+	}
+	if scheduled && drone.Status != model.DroneIdle && drone.Status != model.DroneCharging {
+		return nil, nil
+	}
+
+	open, err := uc.repo.FindOpenByDroneForUpdate(ctx, tx, droneID)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil {
+		if scheduled {
+			return nil, nil
+		}
+		return nil, model.ErrMaintenanceAlreadyOpen(open.ID)
+	}
+
+	if err := drone.StartMaintenance(); err != nil {
+		return nil, err
+	}
+
+	inserted, err := uc.repo.InsertTx(ctx, tx, record)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.droneRepo.UpdateTx(ctx, tx, drone); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return inserted, nil
+}
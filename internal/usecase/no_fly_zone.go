@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+type NoFlyZoneRepo interface {
+	Insert(ctx context.Context, zone *model.NoFlyZone) (*model.NoFlyZone, error)
+	GetByID(ctx context.Context, id int64) (*model.NoFlyZone, error)
+	List(ctx context.Context) ([]model.NoFlyZone, error)
+	Update(ctx context.Context, zone *model.NoFlyZone) (*model.NoFlyZone, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type NoFlyZoneUsecase struct {
+	zoneRepo NoFlyZoneRepo
+	geofence GeofenceInvalidator
+}
+
+func NewNoFlyZoneUsecase(zoneRepo NoFlyZoneRepo, geofence GeofenceInvalidator) *NoFlyZoneUsecase {
+	return &NoFlyZoneUsecase{zoneRepo: zoneRepo, geofence: geofence}
+}
+
+func (uc *NoFlyZoneUsecase) CreateNoFlyZone(ctx context.Context, req model.CreateNoFlyZoneRequest) (*model.NoFlyZone, error) {
+	zone, err := model.NewNoFlyZone(req)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := uc.zoneRepo.Insert(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.geofence.GeofenceChanged()
+	return created, nil
+}
+
+func (uc *NoFlyZoneUsecase) ListNoFlyZones(ctx context.Context) ([]model.NoFlyZone, error) {
+	return uc.zoneRepo.List(ctx)
+}
+
+// UpdateNoFlyZone changes a zone; orders already placed are not re-validated,
+// but ETAs and new assignments route around the new boundary.
+func (uc *NoFlyZoneUsecase) UpdateNoFlyZone(ctx context.Context, id int64, req model.UpdateNoFlyZoneRequest) (*model.NoFlyZone, error) {
+	zone, err := uc.zoneRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := zone.Update(req); err != nil {
+		return nil, err
+	}
+
+	updated, err := uc.zoneRepo.Update(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.geofence.GeofenceChanged()
+	return updated, nil
+}
+
+func (uc *NoFlyZoneUsecase) DeleteNoFlyZone(ctx context.Context, id int64) error {
+	if err := uc.zoneRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	uc.geofence.GeofenceChanged()
+	return nil
+}
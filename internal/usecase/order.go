@@ -16,51 +16,187 @@ type OrderRepo interface {
 	UpdateTx(ctx context.Context, tx *sql.Tx, order *model.Order) (*model.Order, error)
 	BeginTx(ctx context.Context) (*sql.Tx, error)
 	List(ctx context.Context, filters model.OrderListFilters, limit, offset int) ([]model.Order, error)
+	InsertDeliveryProofTx(ctx context.Context, tx *sql.Tx, record *model.DeliveryProofRecord) error
+	SetOfferedDrone(ctx context.Context, orderID, droneID int64) error
 }
 
 type OrderDroneRepo interface {
 	GetByID(ctx context.Context, id int64) (*model.Drone, error)
 	GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (*model.Drone, error)
+	ListByIDs(ctx context.Context, ids []int64) ([]model.Drone, error)
 	UpdateTx(ctx context.Context, tx *sql.Tx, drone *model.Drone) (*model.Drone, error)
 	BeginTx(ctx context.Context) (*sql.Tx, error)
-	FindNearestIdle(ctx context.Context, lat, lng float64) (*model.Drone, error)
+	FindNearestAvailable(ctx context.Context, lat, lng float64) (*model.Drone, error)
 }
 
+type OrderAddressRepo interface {
+	GetByID(ctx context.Context, id int64) (*model.Address, error)
+}
+
+type ServiceAreaReader interface {
+	ListActive(ctx context.Context) ([]model.ServiceArea, error)
+	Exists(ctx context.Context) (bool, error)
+}
+
+type NoFlyZoneReader interface {
+	ListActiveAt(ctx context.Context, t time.Time) ([]model.NoFlyZone, error)
+}
+
+// AssignmentNotifier pushes assignment offers, and later changes to the
+// offered or assigned order, to the drone.
 type AssignmentNotifier interface {
 	NotifyAssignment(ctx context.Context, notice model.AssignmentNotice) error
+	NotifyOrderUpdate(ctx context.Context, notice model.OrderUpdateNotice) error
+}
+
+// MaintenanceScheduler takes a drone that has flown its service interval
+// out of service when it finishes a trip, reporting whether it did.
+type MaintenanceScheduler interface {
+	TripEnded(ctx context.Context, drone model.Drone) bool
 }
 
 type OrderUsecase struct {
-	orderRepo  OrderRepo
-	droneRepo  OrderDroneRepo
-	notifier   AssignmentNotifier
-	assignTTL  time.Duration
-	workerPool chan struct{}
+	orderRepo      OrderRepo
+	droneRepo      OrderDroneRepo
+	tripRepo       TripRepo
+	addressRepo    OrderAddressRepo
+	areaRepo       ServiceAreaReader
+	zoneRepo       NoFlyZoneReader
+	geocoder       Geocoder
+	notifier       AssignmentNotifier
+	deliveryPolicy model.DeliveryPolicy
+	assignTTL      time.Duration
+	workerPool     chan struct{}
+	dispatch       DispatchStrategy
+	base           BaseReturner
+	maintenance    MaintenanceScheduler
 }
 
-func NewOrderUsecase(orderRepo OrderRepo, droneRepo OrderDroneRepo, notifier AssignmentNotifier) *OrderUsecase {
-	return &OrderUsecase{
-		orderRepo:  orderRepo,
-		droneRepo:  droneRepo,
-		notifier:   notifier,
-		assignTTL:  5 * time.Second,
-		workerPool: make(chan struct{}, 4),
-	}
+func NewOrderUsecase(orderRepo OrderRepo, droneRepo OrderDroneRepo, tripRepo TripRepo, addressRepo OrderAddressRepo, areaRepo ServiceAreaReader, zoneRepo NoFlyZoneReader, geocoder Geocoder, notifier AssignmentNotifier, deliveryPolicy model.DeliveryPolicy, base BaseReturner, maintenance MaintenanceScheduler) *OrderUsecase {
+	uc := &OrderUsecase{
+		orderRepo:      orderRepo,
+		droneRepo:      droneRepo,
+		tripRepo:       tripRepo,
+		addressRepo:    addressRepo,
+		areaRepo:       areaRepo,
+		zoneRepo:       zoneRepo,
+		geocoder:       geocoder,
+		notifier:       notifier,
+		deliveryPolicy: deliveryPolicy,
+		base:           base,
+		maintenance:    maintenance,
+		assignTTL:      5 * time.Second,
+		workerPool:     make(chan struct{}, 4),
+	}
+	uc.dispatch = greedyDispatch{uc: uc}
+	return uc
+}
+
+// UseDispatch replaces the default greedy dispatch, which offers every order
+// to the nearest available drone as soon as it starts waiting.
+func (uc *OrderUsecase) UseDispatch(strategy DispatchStrategy) {
+	uc.dispatch = strategy
 }
 
 func (uc *OrderUsecase) CreateOrder(ctx context.Context, req model.CreateOrderRequest) (*model.Order, error) {
+	if req.PickupAddressID != nil {
+		address, err := uc.ownedAddress(ctx, req.EnduserID, *req.PickupAddressID)
+		if err != nil {
+			return nil, err
+		}
+		req.UsePickupAddress(*address)
+	}
+
+	if req.DropoffAddressID != nil {
+		address, err := uc.ownedAddress(ctx, req.EnduserID, *req.DropoffAddressID)
+		if err != nil {
+			return nil, err
+		}
+		req.UseDropoffAddress(*address)
+	}
+
+	if req.PickupQuery != nil {
+		place, err := uc.geocoder.Geocode(ctx, *req.PickupQuery)
+		if err != nil {
+			return nil, err
+		}
+		req.UsePickupPlace(*place)
+	}
+
+	if req.DropoffQuery != nil {
+		place, err := uc.geocoder.Geocode(ctx, *req.DropoffQuery)
+		if err != nil {
+			return nil, err
+		}
+		req.UseDropoffPlace(*place)
+	}
+
+	coverage, err := uc.serviceCoverage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := coverage.CheckPickup(req.PickupLat, req.PickupLng); err != nil {
+		return nil, err
+	}
+	if err := coverage.CheckDropoff(req.DropoffLat, req.DropoffLng); err != nil {
+		return nil, err
+	}
+
+	airspace, err := uc.airspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := airspace.CheckPickup(req.PickupLat, req.PickupLng); err != nil {
+		return nil, err
+	}
+	if err := airspace.CheckDropoff(req.DropoffLat, req.DropoffLng); err != nil {
+		return nil, err
+	}
+
 	order := model.NewOrder(req)
 
+	pin, err := model.GenerateDeliveryPIN()
+	if err != nil {
+		return nil, err
+	}
+	order.DeliveryPIN = &pin
+
 	created, err := uc.orderRepo.Insert(ctx, order)
 	if err != nil {
 		return nil, err
 	}
 
-	uc.triggerAssignment(*created)
+	uc.dispatch.OrderWaiting(*created)
 
 	return created, nil
 }
 
+func (uc *OrderUsecase) ownedAddress(ctx context.Context, userID, addressID int64) (*model.Address, error) {
+	address, err := uc.addressRepo.GetByID(ctx, addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := address.BelongsTo(userID); err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+func (uc *OrderUsecase) serviceCoverage(ctx context.Context) (model.ServiceCoverage, error) {
+	return loadServiceCoverage(ctx, uc.areaRepo)
+}
+
+// airspace is the set of no-fly zones in effect right now.
+func (uc *OrderUsecase) airspace(ctx context.Context) (model.Airspace, error) {
+	zones, err := uc.zoneRepo.ListActiveAt(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return model.Airspace(zones), nil
+}
+
 func (uc *OrderUsecase) CancelOrder(ctx context.Context, userID, orderID int64) (*model.Order, error) {
 	tx, err := uc.orderRepo.BeginTx(ctx)
 	if err != nil {
@@ -90,6 +226,10 @@ func (uc *OrderUsecase) CancelOrder(ctx context.Context, userID, orderID int64)
 		return nil, err
 	}
 
+	if droneID := updatedOrder.AffectedDroneID(); droneID != nil {
+		uc.notifyOrderUpdate(ctx, model.NewOrderUpdateNotice(model.OrderUpdateCanceled, *updatedOrder, *droneID))
+	}
+
 	uc.triggerAssignmentIfPending(updatedOrder)
 
 	return updatedOrder, nil
@@ -105,20 +245,159 @@ func (uc *OrderUsecase) GetOrder(ctx context.Context, userID, orderID int64) (*m
 		return nil, err
 	}
 
-	var drone *model.Drone
-	if order.AssignedDroneID != nil {
-		drone, err = uc.droneRepo.GetByID(ctx, *order.AssignedDroneID)
+	details := uc.orderDetails(ctx, *order, uc.trackOrders(ctx, []model.Order{*order}))
+	return &details, nil
+}
+
+// ListEnduserOrders is the enduser's order history; the caller is always the
+// owner, whatever EnduserID the filters carried.
+func (uc *OrderUsecase) ListEnduserOrders(ctx context.Context, userID int64, filters model.OrderListFilters, page, pageSize int) ([]model.OrderDetails, model.Pagination, error) {
+	filters.EnduserID = &userID
+	filters.AssignedDroneID = nil
+
+	orders, pagination, err := uc.ListOrders(ctx, filters, page, pageSize)
+	if err != nil {
+		return nil, model.Pagination{}, err
+	}
+
+	var open []model.Order
+	for _, order := range orders {
+		if !order.IsTerminal() {
+			open = append(open, order)
+		}
+	}
+	tracking := uc.trackOrders(ctx, open)
+
+	details := make([]model.OrderDetails, len(orders))
+	for i := range orders {
+		if orders[i].IsTerminal() {
+			details[i] = model.NewOrderDetails(orders[i], nil, nil, nil)
+			continue
+		}
+		details[i] = uc.orderDetails(ctx, orders[i], tracking)
+	}
+
+	return details, pagination, nil
+}
+
+// orderTracking holds what orderDetails needs besides the order, loaded once
+// for a whole page: the assigned drones and their active trips by drone id,
+// the no-fly zones, and place names already looked up.
+type orderTracking struct {
+	drones   map[int64]*model.Drone
+	trips    map[int64]*model.Trip
+	airspace model.Airspace
+	places   map[[2]float64]*model.Place
+}
+
+// trackOrders loads the drones and trips of the orders in one query each;
+// lookup failures degrade to orders without tracking data, or to
+// straight-line ETAs when the no-fly zones cannot be loaded.
+func (uc *OrderUsecase) trackOrders(ctx context.Context, orders []model.Order) orderTracking {
+	tracking := orderTracking{
+		drones: make(map[int64]*model.Drone),
+		trips:  make(map[int64]*model.Trip),
+		places: make(map[[2]float64]*model.Place),
+	}
+
+	seen := make(map[int64]bool)
+	var droneIDs []int64
+	for _, order := range orders {
+		if order.AssignedDroneID != nil && !seen[*order.AssignedDroneID] {
+			seen[*order.AssignedDroneID] = true
+			droneIDs = append(droneIDs, *order.AssignedDroneID)
+		}
+	}
+	if len(droneIDs) == 0 {
+		return tracking
+	}
+
+	drones, err := uc.droneRepo.ListByIDs(ctx, droneIDs)
+	if err != nil {
+		log.Printf("failed to get drones %v for order tracking: %v", droneIDs, err)
+	}
+	for i := range drones {
+		tracking.drones[drones[i].ID] = &drones[i]
+	}
+
+	trips, err := uc.tripRepo.ListActiveByDrones(ctx, droneIDs)
+	if err != nil {
+		log.Printf("failed to get trips of drones %v for order tracking: %v", droneIDs, err)
+	} else {
+		tracking.trips = trips
+	}
+
+	if len(tracking.drones) > 0 {
+		tracking.airspace, err = uc.airspace(ctx)
 		if err != nil {
-			log.Printf("failed to get drone %d for order %d: %v", *order.AssignedDroneID, order.ID, err)
-			drone = nil
+			log.Printf("failed to load no-fly zones for order tracking: %v", err)
+			tracking.airspace = nil
 		}
 	}
 
-	details := model.NewOrderDetails(*order, drone)
-	return &details, nil
+	return tracking
+}
+
+// placeName reverse geocodes a point once per page.
+func (t orderTracking) placeName(ctx context.Context, geocoder Geocoder, lat, lng float64) *model.Place {
+	key := [2]float64{lat, lng}
+	if place, ok := t.places[key]; ok {
+		return place
+	}
+	place := placeName(ctx, geocoder, lat, lng)
+	t.places[key] = place
+	return place
+}
+
+// orderDetails enriches an order with its drone's live position and trip
+// ETAs from the tracking data loaded for it.
+func (uc *OrderUsecase) orderDetails(ctx context.Context, order model.Order, tracking orderTracking) model.OrderDetails {
+	var drone *model.Drone
+	var trip *model.Trip
+	if order.AssignedDroneID != nil {
+		drone = tracking.drones[*order.AssignedDroneID]
+		trip = tracking.trips[*order.AssignedDroneID]
+	}
+
+	var airspace model.Airspace
+	if drone != nil {
+		airspace = tracking.airspace
+	}
+
+	details := model.NewOrderDetails(order, drone, trip, airspace)
+	details.PickupPlace = tracking.placeName(ctx, uc.geocoder, order.PickupLat, order.PickupLng)
+	details.DropoffPlace = tracking.placeName(ctx, uc.geocoder, order.DropoffLat, order.DropoffLng)
+	return details
 }
 
 func (uc *OrderUsecase) UpdateRoute(ctx context.Context, orderID int64, req model.UpdateRouteRequest) (*model.Order, error) {
+	// resolve addresses before taking the row lock
+	if req.PickupQuery != nil {
+		place, err := uc.geocoder.Geocode(ctx, *req.PickupQuery)
+		if err != nil {
+			return nil, err
+		}
+		req.UsePickupPlace(*place)
+	}
+
+	if req.DropoffQuery != nil {
+		place, err := uc.geocoder.Geocode(ctx, *req.DropoffQuery)
+		if err != nil {
+			return nil, err
+		}
+		req.UseDropoffPlace(*place)
+	}
+
+	coverage, err := uc.serviceCoverage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	airspace, err := uc.airspace(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	tx, err := uc.orderRepo.BeginTx(ctx)
 	if err != nil {
 		return nil, err
@@ -130,7 +409,7 @@ func (uc *OrderUsecase) UpdateRoute(ctx context.Context, orderID int64, req mode
 		return nil, err
 	}
 
-	if err := order.UpdateRoute(req); err != nil {
+	if err := order.UpdateRoute(req, coverage, airspace); err != nil {
 		return nil, err
 	}
 
@@ -143,21 +422,73 @@ func (uc *OrderUsecase) UpdateRoute(ctx context.Context, orderID int64, req mode
 		return nil, err
 	}
 
+	uc.notifyRouteUpdate(ctx, *updatedOrder, airspace)
+
 	return updatedOrder, nil
 }
 
+// notifyRouteUpdate sends the drone handling the order its re-planned path.
+func (uc *OrderUsecase) notifyRouteUpdate(ctx context.Context, order model.Order, airspace model.Airspace) {
+	droneID := order.AffectedDroneID()
+	if uc.notifier == nil || droneID == nil {
+		return
+	}
+
+	drone, err := uc.droneRepo.GetByID(ctx, *droneID)
+	if err != nil {
+		log.Printf("route update for order %d: failed to get drone %d: %v", order.ID, *droneID, err)
+		return
+	}
+
+	uc.notifyOrderUpdate(ctx, model.NewRouteUpdateNotice(order, *drone, airspace))
+}
+
+// NotifyHandoff tells the drone that released the order to leave it for
+// another drone.
+func (uc *OrderUsecase) NotifyHandoff(ctx context.Context, droneID int64, order model.Order) {
+	uc.notifyOrderUpdate(ctx, model.NewOrderUpdateNotice(model.OrderUpdateHandoffRequired, order, droneID))
+}
+
+// notifyOrderUpdate is best effort: the order change is already committed and
+// an offline drone learns the current state when it next asks for its trip.
+func (uc *OrderUsecase) notifyOrderUpdate(ctx context.Context, notice model.OrderUpdateNotice) {
+	if uc.notifier == nil {
+		return
+	}
+	if err := uc.notifier.NotifyOrderUpdate(ctx, notice); err != nil {
+		log.Printf("%s for order %d not delivered to drone %d: %v", notice.Event, notice.OrderID, notice.DroneID, err)
+	}
+}
+
 func (uc *OrderUsecase) AssignOrder(ctx context.Context, order model.Order) error {
 	if uc.notifier == nil {
 		return nil
 	}
 
-	drone, err := uc.droneRepo.FindNearestIdle(ctx, order.PickupLat, order.PickupLng)
+	pickupLat, pickupLng := order.PickupPoint()
+	drone, err := uc.droneRepo.FindNearestAvailable(ctx, pickupLat, pickupLng)
+	if err != nil {
+		return err
+	}
+
+	airspace, err := uc.airspace(ctx)
 	if err != nil {
 		return err
 	}
 
-	notice := model.NewAssignmentNotice(order, *drone)
-	return uc.notifier.NotifyAssignment(ctx, notice)
+	return uc.offer(ctx, order, *drone, airspace)
+}
+
+// offer pushes the assignment offer to the drone.
+func (uc *OrderUsecase) offer(ctx context.Context, order model.Order, drone model.Drone, airspace model.Airspace) error {
+	notice := model.NewAssignmentNotice(order, drone, airspace)
+	if err := uc.notifier.NotifyAssignment(ctx, notice); err != nil {
+		return err
+	}
+
+	// remembered so the drone hears about a cancel or reroute before it
+	// reserves the order
+	return uc.orderRepo.SetOfferedDrone(ctx, order.ID, drone.ID)
 }
 
 func (uc *OrderUsecase) ReserveOrder(ctx context.Context, droneID, orderID int64) (*model.Order, error) {
@@ -185,6 +516,25 @@ func (uc *OrderUsecase) ReserveOrder(ctx context.Context, droneID, orderID int64
 		return nil, err
 	}
 
+	trip, err := uc.tripRepo.FindActiveByDroneForUpdate(ctx, tx, droneID)
+	if err != nil {
+		return nil, err
+	}
+	if trip == nil {
+		trip = model.NewTrip(droneID)
+	}
+	trip.AddOrder(*order, drone.Lat, drone.Lng)
+
+	if trip.ID == 0 {
+		trip, err = uc.tripRepo.InsertTx(ctx, tx, trip)
+	} else {
+		trip, err = uc.tripRepo.UpdateTx(ctx, tx, trip)
+	}
+	if err != nil {
+		return nil, err
+	}
+	drone.FollowTrip(trip)
+
 	updatedOrder, err := uc.orderRepo.UpdateTx(ctx, tx, order)
 	if err != nil {
 		return nil, err
@@ -226,7 +576,7 @@ func (uc *OrderUsecase) triggerAssignment(order model.Order) {
 	}(order)
 }
 
-func (uc *OrderUsecase) DeliverOrder(ctx context.Context, droneID, orderID int64) (*model.Order, error) {
+func (uc *OrderUsecase) DeliverOrder(ctx context.Context, droneID, orderID int64, proof model.DeliveryProof) (*model.Order, error) {
 	tx, err := uc.orderRepo.BeginTx(ctx)
 	if err != nil {
 		return nil, err
@@ -247,7 +597,13 @@ func (uc *OrderUsecase) DeliverOrder(ctx context.Context, droneID, orderID int64
 		return nil, err
 	}
 
-	if err := order.Deliver(); err != nil {
+	now := time.Now().UTC()
+	attempts := order.PINAttempts
+	record, err := order.DeliverWithProof(proof, drone, uc.deliveryPolicy, now)
+	if err != nil {
+		if order.PINAttempts != attempts {
+			return nil, uc.recordPINMiss(ctx, tx, order, drone, err)
+		}
 		return nil, err
 	}
 
@@ -255,12 +611,22 @@ func (uc *OrderUsecase) DeliverOrder(ctx context.Context, droneID, orderID int64
 		return nil, err
 	}
 
+	if err := advanceTrip(ctx, tx, uc.tripRepo, drone, func(trip *model.Trip) {
+		trip.CompleteStop(orderID, model.StopDropoff, now)
+	}); err != nil {
+		return nil, err
+	}
+
 	updatedOrder, err := uc.orderRepo.UpdateTx(ctx, tx, order)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = uc.droneRepo.UpdateTx(ctx, tx, drone)
+	if err := uc.orderRepo.InsertDeliveryProofTx(ctx, tx, record); err != nil {
+		return nil, err
+	}
+
+	updatedDrone, err := uc.droneRepo.UpdateTx(ctx, tx, drone)
 	if err != nil {
 		return nil, err
 	}
@@ -269,6 +635,8 @@ func (uc *OrderUsecase) DeliverOrder(ctx context.Context, droneID, orderID int64
 		return nil, err
 	}
 
+	uc.tripEnded(ctx, *updatedDrone)
+
 	return updatedOrder, nil
 }
 
@@ -301,6 +669,13 @@ func (uc *OrderUsecase) PickupOrder(ctx context.Context, droneID, orderID int64)
 		return nil, err
 	}
 
+	now := time.Now().UTC()
+	if err := advanceTrip(ctx, tx, uc.tripRepo, drone, func(trip *model.Trip) {
+		trip.CompleteStop(orderID, model.StopPickup, now)
+	}); err != nil {
+		return nil, err
+	}
+
 	updatedOrder, err := uc.orderRepo.UpdateTx(ctx, tx, order)
 	if err != nil {
 		return nil, err
@@ -339,11 +714,123 @@ func (uc *OrderUsecase) FailOrder(ctx context.Context, droneID, orderID int64) (
 		return nil, err
 	}
 
+	updatedOrder, updatedDrone, err := uc.failAssignedTx(ctx, tx, order, drone)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	uc.tripEnded(ctx, *updatedDrone)
+
+	uc.triggerAssignmentIfPending(updatedOrder)
+
+	return updatedOrder, nil
+}
+
+// failAssignedTx fails an order on its locked drone: a parcel on board is
+// rerouted back to its origin, anything else drops off the drone's trip.
+func (uc *OrderUsecase) failAssignedTx(ctx context.Context, tx *sql.Tx, order *model.Order, drone *model.Drone) (*model.Order, *model.Drone, error) {
+	from := order.Status
 	if err := order.Fail(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := drone.FailDelivery(from, order.Status); err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := advanceTrip(ctx, tx, uc.tripRepo, drone, func(trip *model.Trip) {
+		if order.IsReturning() {
+			trip.RerouteToReturn(*order)
+			return
+		}
+		trip.RemoveOrder(order.ID, now)
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	updatedOrder, err := uc.orderRepo.UpdateTx(ctx, tx, order)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updatedDrone, err := uc.droneRepo.UpdateTx(ctx, tx, drone)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return updatedOrder, updatedDrone, nil
+}
+
+// recordPINMiss keeps a wrong delivery PIN on the order even though the
+// delivery itself is rejected. The miss that uses up the last attempt fails
+// the order, sending the parcel back to its origin.
+func (uc *OrderUsecase) recordPINMiss(ctx context.Context, tx *sql.Tx, order *model.Order, drone *model.Drone, rejection error) error {
+	if !order.PINLocked(uc.deliveryPolicy) {
+		if _, err := uc.orderRepo.UpdateTx(ctx, tx, order); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		return rejection
+	}
+
+	updatedOrder, updatedDrone, err := uc.failAssignedTx(ctx, tx, order, drone)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	log.Printf("order %d: delivery pin locked after %d wrong attempts, order is %s", order.ID, order.PINAttempts, updatedOrder.Status)
+
+	uc.tripEnded(ctx, *updatedDrone)
+
+	uc.triggerAssignmentIfPending(updatedOrder)
+
+	return rejection
+}
+
+func (uc *OrderUsecase) ReturnOrder(ctx context.Context, droneID, orderID int64) (*model.Order, error) {
+	tx, err := uc.orderRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	order, err := uc.orderRepo.GetByIDForUpdate(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := order.IsAssignedTo(droneID); err != nil {
+		return nil, err
+	}
+
+	drone, err := uc.droneRepo.GetByIDForUpdate(ctx, tx, droneID)
+	if err != nil {
 		return nil, err
 	}
 
-	if err := drone.FailDelivery(); err != nil {
+	if err := order.ConfirmReturn(); err != nil {
+		return nil, err
+	}
+
+	if err := drone.CompleteReturn(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := advanceTrip(ctx, tx, uc.tripRepo, drone, func(trip *model.Trip) {
+		trip.CompleteStop(orderID, model.StopReturn, now)
+	}); err != nil {
 		return nil, err
 	}
 
@@ -352,7 +839,7 @@ func (uc *OrderUsecase) FailOrder(ctx context.Context, droneID, orderID int64) (
 		return nil, err
 	}
 
-	_, err = uc.droneRepo.UpdateTx(ctx, tx, drone)
+	updatedDrone, err := uc.droneRepo.UpdateTx(ctx, tx, drone)
 	if err != nil {
 		return nil, err
 	}
@@ -361,12 +848,27 @@ func (uc *OrderUsecase) FailOrder(ctx context.Context, droneID, orderID int64) (
 		return nil, err
 	}
 
-	uc.triggerAssignmentIfPending(updatedOrder)
+	uc.tripEnded(ctx, *updatedDrone)
 
 	return updatedOrder, nil
 }
 
+// tripEnded sends a drone that has nothing left to fly to maintenance when
+// it is due, otherwise lets it go back to base.
+func (uc *OrderUsecase) tripEnded(ctx context.Context, drone model.Drone) {
+	if uc.maintenance != nil && uc.maintenance.TripEnded(ctx, drone) {
+		return
+	}
+	if uc.base != nil {
+		uc.base.TripEnded(ctx, drone)
+	}
+}
+
 func (uc *OrderUsecase) ListOrders(ctx context.Context, filters model.OrderListFilters, page, pageSize int) ([]model.Order, model.Pagination, error) {
+	if err := filters.Validate(); err != nil {
+		return nil, model.Pagination{}, err
+	}
+
 	pagination, err := model.NormalizePagination(page, pageSize)
 	if err != nil {
 		return nil, model.Pagination{}, err
@@ -385,7 +887,7 @@ func (uc *OrderUsecase) triggerAssignmentIfPending(order *model.Order) {
 		return
 	}
 	if needsAssignment(order.Status) {
-		uc.triggerAssignment(*order)
+		uc.dispatch.OrderWaiting(*order)
 	}
 }
 
@@ -394,5 +896,5 @@ func needsAssignment(status model.OrderStatus) bool {
 }
 
 func (uc *OrderUsecase) ScheduleAssignment(order model.Order) {
-	uc.triggerAssignment(order)
+	uc.dispatch.OrderWaiting(order)
 }
@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+type RebalanceRepo interface {
+	PickupCounts(ctx context.Context, since time.Time, hour int) ([]model.PickupCount, error)
+	SaveForecasts(ctx context.Context, runAt, validUntil time.Time, hour int, cells []model.CellForecast) error
+	ListOutcomes(ctx context.Context, from, to time.Time) ([]model.ForecastOutcome, error)
+}
+
+type RebalanceDroneRepo interface {
+	BeginTx(ctx context.Context) (*sql.Tx, error)
+	ListAvailable(ctx context.Context, offerTimeout time.Duration) ([]model.Drone, error)
+	GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int64) (*model.Drone, error)
+	UpdateTx(ctx context.Context, tx *sql.Tx, drone *model.Drone) (*model.Drone, error)
+}
+
+// Rebalancer pre-positions idle drones where orders are expected: it
+// forecasts the coming hour's pickups per cell from the same hour on past
+// days and sends drones in excess in one cell to cells short of their share.
+type Rebalancer struct {
+	repo         RebalanceRepo
+	drones       RebalanceDroneRepo
+	commands     CommandIssuer
+	policy       model.RebalancePolicy
+	offerTimeout time.Duration
+}
+
+func NewRebalancer(repo RebalanceRepo, drones RebalanceDroneRepo, commands CommandIssuer, policy model.RebalancePolicy, offerTimeout time.Duration) *Rebalancer {
+	return &Rebalancer{
+		repo:         repo,
+		drones:       drones,
+		commands:     commands,
+		policy:       policy,
+		offerTimeout: offerTimeout,
+	}
+}
+
+// Run rebalances every policy interval until ctx is done. It is a singleton
+// job, run by the elected leader only.
+func (r *Rebalancer) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.policy.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := r.Rebalance(ctx, model.RebalanceRequest{}); err != nil && ctx.Err() == nil {
+			log.Printf("rebalance failed: %v", err)
+		}
+	}
+}
+
+// Rebalance plans a run and, unless it is a dry run, stores its forecasts
+// for the wait report and sends the planned drones off with reposition
+// commands. A drone that took an order in the meantime stays where it is
+// and its move has no command.
+func (r *Rebalancer) Rebalance(ctx context.Context, req model.RebalanceRequest) (*model.RebalancePlan, error) {
+	aggressiveness := r.policy.Aggressiveness
+	if req.Aggressiveness != nil {
+		if err := model.ValidateAggressiveness(*req.Aggressiveness); err != nil {
+			return nil, err
+		}
+		aggressiveness = *req.Aggressiveness
+	}
+
+	now := time.Now().UTC()
+	hour := now.Hour()
+	pickups, err := r.repo.PickupCounts(ctx, now.Add(-r.policy.Lookback), hour)
+	if err != nil {
+		return nil, err
+	}
+
+	available, err := r.drones.ListAvailable(ctx, r.offerTimeout)
+	if err != nil {
+		return nil, err
+	}
+	// drones that never reported a position have none worth planning from
+	var idle []model.Drone
+	for _, d := range available {
+		if d.Status == model.DroneIdle && d.LastHeartbeat != nil {
+			idle = append(idle, d)
+		}
+	}
+
+	grid := model.DemandGrid{CellKm: r.policy.CellKm}
+	demand := grid.ForecastDemand(pickups, r.policy.Lookback.Hours()/24)
+	plan := model.PlanRebalance(grid, demand, idle, aggressiveness)
+	plan.Hour = hour
+	plan.ComputedAt = now
+	if req.DryRun {
+		return &plan, nil
+	}
+
+	if err := r.repo.SaveForecasts(ctx, now, now.Add(r.policy.Interval), hour, plan.Cells); err != nil {
+		return nil, err
+	}
+
+	sent := 0
+	for i := range plan.Moves {
+		commandID, err := r.reposition(ctx, plan.Moves[i])
+		if err != nil {
+			log.Printf("reposition of drone %d failed: %v", plan.Moves[i].DroneID, err)
+			continue
+		}
+		if commandID != nil {
+			plan.Moves[i].CommandID = commandID
+			sent++
+		}
+	}
+
+	log.Printf("rebalance: %d idle drones over %d cells with demand at %02d:00, repositioned %d of %d planned",
+		len(idle), len(plan.Cells), hour, sent, len(plan.Moves))
+	return &plan, nil
+}
+
+// reposition releases the drone's depot pad, if it holds one, and sends it
+// to wait at the move's target. Nil when the drone is no longer idle.
+func (r *Rebalancer) reposition(ctx context.Context, move model.RebalanceMove) (*int64, error) {
+	tx, err := r.drones.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	drone, err := r.drones.GetByIDForUpdate(ctx, tx, move.DroneID)
+	if err != nil {
+		return nil, err
+	}
+	if drone.Status != model.DroneIdle {
+		return nil, nil
+	}
+
+	drone.DepotID = nil
+	if _, err := r.drones.UpdateTx(ctx, tx, drone); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	cmd, err := r.commands.IssueCommand(ctx, model.CreateDroneCommandRequest{
+		DroneID: move.DroneID,
+		Type:    model.CommandReposition,
+		Lat:     &move.To.Lat,
+		Lng:     &move.To.Lng,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &cmd.ID, nil
+}
+
+// Report sets the waits each run predicted against the ones orders placed
+// while its forecasts stood actually saw, per cell and overall.
+func (r *Rebalancer) Report(ctx context.Context, from, to *time.Time) (*model.RebalanceReport, error) {
+	start, end, err := model.RebalanceReportWindow(from, to, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes, err := r.repo.ListOutcomes(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := model.BuildRebalanceReport(start, end, outcomes)
+	return &report, nil
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+type ServiceAreaRepo interface {
+	Insert(ctx context.Context, area *model.ServiceArea) (*model.ServiceArea, error)
+	GetByID(ctx context.Context, id int64) (*model.ServiceArea, error)
+	List(ctx context.Context) ([]model.ServiceArea, error)
+	ListActive(ctx context.Context) ([]model.ServiceArea, error)
+	Update(ctx context.Context, area *model.ServiceArea) (*model.ServiceArea, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// loadServiceCoverage reads the active areas, and whether any area exists
+// at all, which is what decides that coverage is restricted.
+func loadServiceCoverage(ctx context.Context, areaRepo ServiceAreaReader) (model.ServiceCoverage, error) {
+	restricted, err := areaRepo.Exists(ctx)
+	if err != nil || !restricted {
+		return model.ServiceCoverage{}, err
+	}
+
+	areas, err := areaRepo.ListActive(ctx)
+	if err != nil {
+		return model.ServiceCoverage{}, err
+	}
+	return model.ServiceCoverage{Areas: areas, Restricted: true}, nil
+}
+
+type ServiceAreaUsecase struct {
+	areaRepo ServiceAreaRepo
+	geofence GeofenceInvalidator
+}
+
+func NewServiceAreaUsecase(areaRepo ServiceAreaRepo, geofence GeofenceInvalidator) *ServiceAreaUsecase {
+	return &ServiceAreaUsecase{areaRepo: areaRepo, geofence: geofence}
+}
+
+func (uc *ServiceAreaUsecase) CreateServiceArea(ctx context.Context, name string, boundary model.Polygon) (*model.ServiceArea, error) {
+	area, err := model.NewServiceArea(name, boundary)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := uc.areaRepo.Insert(ctx, area)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.geofence.GeofenceChanged()
+	return created, nil
+}
+
+func (uc *ServiceAreaUsecase) ListServiceAreas(ctx context.Context) ([]model.ServiceArea, error) {
+	return uc.areaRepo.List(ctx)
+}
+
+func (uc *ServiceAreaUsecase) ListActiveServiceAreas(ctx context.Context) ([]model.ServiceArea, error) {
+	return uc.areaRepo.ListActive(ctx)
+}
+
+func (uc *ServiceAreaUsecase) UpdateServiceArea(ctx context.Context, id int64, req model.UpdateServiceAreaRequest) (*model.ServiceArea, error) {
+	area, err := uc.areaRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := area.Update(req); err != nil {
+		return nil, err
+	}
+
+	updated, err := uc.areaRepo.Update(ctx, area)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.geofence.GeofenceChanged()
+	return updated, nil
+}
+
+// DeleteServiceArea removes the area; existing orders are not re-validated.
+func (uc *ServiceAreaUsecase) DeleteServiceArea(ctx context.Context, id int64) error {
+	if err := uc.areaRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	uc.geofence.GeofenceChanged()
+	return nil
+}
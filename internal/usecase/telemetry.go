@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+const telemetryDeleteBatch = 5000
+
+type TelemetryRepo interface {
+	ListByDrone(ctx context.Context, droneID int64, from, to time.Time, limit int) ([]model.TelemetryPoint, error)
+	ListByOrder(ctx context.Context, orderID int64, limit int) ([]model.TelemetryPoint, error)
+	DeleteBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+	Downsample(ctx context.Context, from, to time.Time, interval time.Duration) (int64, error)
+}
+
+type TrackDroneRepo interface {
+	GetByID(ctx context.Context, id int64) (*model.Drone, error)
+}
+
+type TrackOrderRepo interface {
+	GetByID(ctx context.Context, id int64) (*model.Order, error)
+}
+
+type TelemetryUsecase struct {
+	telemetryRepo TelemetryRepo
+	droneRepo     TrackDroneRepo
+	orderRepo     TrackOrderRepo
+	policy        model.TelemetryPolicy
+	// downsampledUntil is where the previous maintenance run stopped, so each
+	// run only thins the newly aged slice of history.
+	downsampledUntil time.Time
+}
+
+func NewTelemetryUsecase(telemetryRepo TelemetryRepo, droneRepo TrackDroneRepo, orderRepo TrackOrderRepo, policy model.TelemetryPolicy) *TelemetryUsecase {
+	return &TelemetryUsecase{
+		telemetryRepo: telemetryRepo,
+		droneRepo:     droneRepo,
+		orderRepo:     orderRepo,
+		policy:        policy,
+	}
+}
+
+func (uc *TelemetryUsecase) DroneTrack(ctx context.Context, droneID int64, from, to *time.Time) (*model.Track, model.TrackWindow, error) {
+	window, err := model.NewTrackWindow(from, to, time.Now())
+	if err != nil {
+		return nil, model.TrackWindow{}, err
+	}
+
+	if _, err := uc.droneRepo.GetByID(ctx, droneID); err != nil {
+		return nil, model.TrackWindow{}, err
+	}
+
+	points, err := uc.telemetryRepo.ListByDrone(ctx, droneID, window.From, window.To, model.MaxTrackPoints+1)
+	if err != nil {
+		return nil, model.TrackWindow{}, err
+	}
+
+	track := &model.Track{DroneID: droneID, Points: points}
+	if len(points) > model.MaxTrackPoints {
+		track.Points = points[:model.MaxTrackPoints]
+		track.Truncated = true
+	}
+
+	return track, window, nil
+}
+
+// OrderTrack returns the flight path of every trip that carried the order.
+func (uc *TelemetryUsecase) OrderTrack(ctx context.Context, orderID int64) ([]model.Track, error) {
+	if _, err := uc.orderRepo.GetByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	points, err := uc.telemetryRepo.ListByOrder(ctx, orderID, model.MaxTrackPoints+1)
+	if err != nil {
+		return nil, err
+	}
+
+	truncated := len(points) > model.MaxTrackPoints
+	if truncated {
+		points = points[:model.MaxTrackPoints]
+	}
+
+	tracks := model.TracksByTrip(points)
+	if truncated && len(tracks) > 0 {
+		tracks[len(tracks)-1].Truncated = true
+	}
+
+	return tracks, nil
+}
+
+// RunMaintenance expires points past retention, then thins the history that
+// has aged past the downsampling threshold since the previous run.
+func (uc *TelemetryUsecase) RunMaintenance(ctx context.Context, now time.Time) error {
+	retentionCutoff := uc.policy.RetentionCutoff(now)
+	var deleted int64
+	for {
+		n, err := uc.telemetryRepo.DeleteBefore(ctx, retentionCutoff, telemetryDeleteBatch)
+		if err != nil {
+			return err
+		}
+		deleted += n
+		if n < telemetryDeleteBatch {
+			break
+		}
+	}
+
+	downsampleCutoff := uc.policy.DownsampleCutoff(now)
+	from := uc.downsampledUntil
+	if from.Before(retentionCutoff) {
+		from = retentionCutoff
+	}
+
+	var thinned int64
+	if from.Before(downsampleCutoff) {
+		n, err := uc.telemetryRepo.Downsample(ctx, from, downsampleCutoff, uc.policy.DownsampleInterval)
+		if err != nil {
+			return err
+		}
+		thinned = n
+		uc.downsampledUntil = downsampleCutoff
+	}
+
+	if deleted > 0 || thinned > 0 {
+		log.Printf("telemetry maintenance: deleted %d expired points, downsampled %d", deleted, thinned)
+	}
+	return nil
+}
+
+// Maintain runs RunMaintenance every interval until ctx is done. It is a
+// singleton job, run by the elected leader only.
+func (uc *TelemetryUsecase) Maintain(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		if err := uc.RunMaintenance(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("telemetry maintenance failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
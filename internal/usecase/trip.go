@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Enas-Ijaabo/drone-delivery-management/internal/model"
+)
+
+type TripRepo interface {
+	FindActiveByDrone(ctx context.Context, droneID int64) (*model.Trip, error)
+	ListActiveByDrones(ctx context.Context, droneIDs []int64) (map[int64]*model.Trip, error)
+	FindActiveByDroneForUpdate(ctx context.Context, tx *sql.Tx, droneID int64) (*model.Trip, error)
+	InsertTx(ctx context.Context, tx *sql.Tx, trip *model.Trip) (*model.Trip, error)
+	UpdateTx(ctx context.Context, tx *sql.Tx, trip *model.Trip) (*model.Trip, error)
+}
+
+// advanceTrip applies a stop change to the drone's active trip and points the
+// drone at whatever stop comes next. Drones without a trip are left untouched.
+func advanceTrip(ctx context.Context, tx *sql.Tx, tripRepo TripRepo, drone *model.Drone, apply func(trip *model.Trip)) error {
+	trip, err := tripRepo.FindActiveByDroneForUpdate(ctx, tx, drone.ID)
+	if err != nil {
+		return err
+	}
+	if trip == nil {
+		return nil
+	}
+
+	apply(trip)
+
+	if _, err := tripRepo.UpdateTx(ctx, tx, trip); err != nil {
+		return err
+	}
+
+	drone.FollowTrip(trip)
+	return nil
+}
@@ -0,0 +1,5 @@
+-- Rollback return-to-sender columns
+ALTER TABLE orders
+  DROP COLUMN return_lng,
+  DROP COLUMN return_lat,
+  MODIFY COLUMN status ENUM('pending','reserved','picked_up','handoff_pending','delivered','failed','canceled') NOT NULL DEFAULT 'pending';
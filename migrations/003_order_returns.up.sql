@@ -0,0 +1,5 @@
+-- Return-to-sender flow for failed deliveries
+ALTER TABLE orders
+  MODIFY COLUMN status ENUM('pending','reserved','picked_up','handoff_pending','delivered','failed','canceled','returning','returned') NOT NULL DEFAULT 'pending',
+  ADD COLUMN return_lat DECIMAL(9,6) NULL COMMENT 'Return destination for failed deliveries' AFTER handoff_lng,
+  ADD COLUMN return_lng DECIMAL(9,6) NULL COMMENT 'Return destination for failed deliveries' AFTER return_lat;
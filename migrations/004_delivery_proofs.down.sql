@@ -0,0 +1,3 @@
+-- Rollback proof of delivery
+DROP TABLE IF EXISTS delivery_proofs;
+ALTER TABLE orders DROP COLUMN delivery_pin;
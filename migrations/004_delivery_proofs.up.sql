@@ -0,0 +1,23 @@
+-- Proof of delivery: recipient PIN on orders plus an audit row per delivery
+ALTER TABLE orders
+  ADD COLUMN delivery_pin CHAR(6) NULL COMMENT 'One-time recipient PIN shown to the enduser' AFTER return_lng;
+
+CREATE TABLE IF NOT EXISTS delivery_proofs (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  order_id BIGINT NOT NULL,
+  drone_id BIGINT NOT NULL,
+  method ENUM('pin','evidence') NOT NULL,
+  photo_hash VARCHAR(128) NULL,
+  evidence_lat DECIMAL(9,6) NULL,
+  evidence_lng DECIMAL(9,6) NULL,
+  captured_at TIMESTAMP NULL,
+  signature VARCHAR(128) NULL,
+  drone_lat DECIMAL(9,6) NOT NULL,
+  drone_lng DECIMAL(9,6) NOT NULL,
+  distance_m DOUBLE NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE KEY uq_delivery_proofs_order (order_id),
+  KEY idx_delivery_proofs_drone (drone_id),
+  CONSTRAINT fk_delivery_proofs_order FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+  CONSTRAINT fk_delivery_proofs_drone FOREIGN KEY (drone_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
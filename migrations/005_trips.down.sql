@@ -0,0 +1,6 @@
+-- Rollback multi-stop trips
+DROP TABLE IF EXISTS trip_stops;
+DROP TABLE IF EXISTS trips;
+ALTER TABLE drone_status
+  DROP COLUMN current_trip_id,
+  DROP COLUMN capacity;
@@ -0,0 +1,30 @@
+-- Multi-stop trips: drones carry up to `capacity` orders per outing
+ALTER TABLE drone_status
+  ADD COLUMN capacity TINYINT UNSIGNED NOT NULL DEFAULT 1 COMMENT 'Max orders carried per trip' AFTER current_order_id,
+  ADD COLUMN current_trip_id BIGINT NULL AFTER capacity;
+
+CREATE TABLE IF NOT EXISTS trips (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  drone_id BIGINT NOT NULL,
+  status ENUM('active','completed','aborted') NOT NULL DEFAULT 'active',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  completed_at TIMESTAMP NULL,
+  KEY idx_trips_drone_status (drone_id, status),
+  CONSTRAINT fk_trips_drone FOREIGN KEY (drone_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS trip_stops (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  trip_id BIGINT NOT NULL,
+  sequence INT NOT NULL,
+  order_id BIGINT NOT NULL,
+  kind ENUM('pickup','dropoff','return') NOT NULL,
+  lat DECIMAL(9,6) NOT NULL,
+  lng DECIMAL(9,6) NOT NULL,
+  completed_at TIMESTAMP NULL,
+  UNIQUE KEY uq_trip_stops_sequence (trip_id, sequence),
+  KEY idx_trip_stops_order (order_id),
+  CONSTRAINT fk_trip_stops_trip FOREIGN KEY (trip_id) REFERENCES trips(id) ON DELETE CASCADE,
+  CONSTRAINT fk_trip_stops_order FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,9 @@
+-- Rollback address book
+ALTER TABLE orders
+  DROP FOREIGN KEY fk_orders_dropoff_address,
+  DROP FOREIGN KEY fk_orders_pickup_address,
+  DROP COLUMN access_instructions,
+  DROP COLUMN delivery_notes,
+  DROP COLUMN dropoff_address_id,
+  DROP COLUMN pickup_address_id;
+DROP TABLE IF EXISTS addresses;
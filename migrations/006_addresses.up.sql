@@ -0,0 +1,22 @@
+-- Enduser address book; orders snapshot the address at creation time
+CREATE TABLE IF NOT EXISTS addresses (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  user_id BIGINT NOT NULL,
+  label VARCHAR(64) NOT NULL,
+  lat DECIMAL(9,6) NOT NULL,
+  lng DECIMAL(9,6) NOT NULL,
+  delivery_notes VARCHAR(500) NULL,
+  access_instructions VARCHAR(500) NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  KEY idx_addresses_user (user_id),
+  CONSTRAINT fk_addresses_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE orders
+  ADD COLUMN pickup_address_id BIGINT NULL COMMENT 'Saved address the pickup was copied from' AFTER enduser_id,
+  ADD COLUMN dropoff_address_id BIGINT NULL COMMENT 'Saved address the dropoff was copied from' AFTER pickup_address_id,
+  ADD COLUMN delivery_notes VARCHAR(500) NULL COMMENT 'Snapshot of the dropoff address notes' AFTER dropoff_lng,
+  ADD COLUMN access_instructions VARCHAR(500) NULL COMMENT 'Snapshot of the dropoff address access instructions' AFTER delivery_notes,
+  ADD CONSTRAINT fk_orders_pickup_address FOREIGN KEY (pickup_address_id) REFERENCES addresses(id) ON DELETE SET NULL,
+  ADD CONSTRAINT fk_orders_dropoff_address FOREIGN KEY (dropoff_address_id) REFERENCES addresses(id) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+-- Rollback service areas
+DROP TABLE IF EXISTS service_areas;
@@ -0,0 +1,11 @@
+-- Service areas: orders must start and end inside an active area once any exist
+CREATE TABLE IF NOT EXISTS service_areas (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  name VARCHAR(100) NOT NULL,
+  area POLYGON NOT NULL SRID 4326,
+  active TINYINT(1) NOT NULL DEFAULT 1,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  KEY idx_service_areas_active (active),
+  SPATIAL INDEX idx_service_areas_area (area)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
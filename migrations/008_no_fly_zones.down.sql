@@ -0,0 +1,2 @@
+-- Rollback no-fly zones
+DROP TABLE IF EXISTS no_fly_zones;
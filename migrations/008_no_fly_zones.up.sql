@@ -0,0 +1,12 @@
+-- No-fly zones: restricted airspace routes are planned around; NULL bounds mean always in effect
+CREATE TABLE IF NOT EXISTS no_fly_zones (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  name VARCHAR(100) NOT NULL,
+  area POLYGON NOT NULL SRID 4326,
+  starts_at TIMESTAMP NULL,
+  ends_at TIMESTAMP NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  KEY idx_no_fly_zones_window (starts_at, ends_at),
+  SPATIAL INDEX idx_no_fly_zones_area (area)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,2 @@
+-- Rollback geofence breaches
+DROP TABLE IF EXISTS geofence_breaches;
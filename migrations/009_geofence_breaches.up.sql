@@ -0,0 +1,15 @@
+-- Geofence breaches detected from drone heartbeats; kept as an audit trail
+CREATE TABLE IF NOT EXISTS geofence_breaches (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  drone_id BIGINT NOT NULL,
+  order_id BIGINT NULL COMMENT 'Order the drone was flying when the breach was detected',
+  kind ENUM('no_fly_zone','outside_service_area') NOT NULL,
+  zone_id BIGINT NULL COMMENT 'No-fly zone entered; zones may be deleted later',
+  zone_name VARCHAR(100) NULL COMMENT 'Snapshot of the zone name',
+  lat DECIMAL(9,6) NOT NULL,
+  lng DECIMAL(9,6) NOT NULL,
+  action ENUM('none','hold','land') NOT NULL DEFAULT 'none',
+  detected_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  KEY idx_geofence_breaches_drone (drone_id, detected_at),
+  CONSTRAINT fk_geofence_breaches_drone FOREIGN KEY (drone_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,2 @@
+-- Rollback drone telemetry
+DROP TABLE IF EXISTS drone_telemetry;
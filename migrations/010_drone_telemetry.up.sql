@@ -0,0 +1,17 @@
+-- Drone telemetry history: one row per heartbeat, thinned and expired by the telemetry maintenance job
+CREATE TABLE IF NOT EXISTS drone_telemetry (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  drone_id BIGINT NOT NULL,
+  trip_id BIGINT NULL COMMENT 'Trip the drone was flying when the heartbeat arrived',
+  order_id BIGINT NULL COMMENT 'Order of the next stop at the time',
+  lat DECIMAL(9,6) NOT NULL,
+  lng DECIMAL(9,6) NOT NULL,
+  battery_pct DECIMAL(5,2) NULL,
+  speed_mps DECIMAL(6,2) NULL,
+  heading_deg DECIMAL(5,2) NULL,
+  recorded_at TIMESTAMP(3) NOT NULL,
+  KEY idx_drone_telemetry_drone_time (drone_id, recorded_at),
+  KEY idx_drone_telemetry_trip (trip_id, recorded_at),
+  KEY idx_drone_telemetry_time (recorded_at),
+  CONSTRAINT fk_drone_telemetry_drone FOREIGN KEY (drone_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
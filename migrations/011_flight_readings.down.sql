@@ -0,0 +1,15 @@
+-- Rollback richer heartbeat readings
+ALTER TABLE drone_telemetry
+  DROP COLUMN device_time,
+  DROP COLUMN gps_accuracy_m,
+  DROP COLUMN gps_fix,
+  DROP COLUMN altitude_m;
+
+ALTER TABLE drone_status
+  DROP COLUMN device_time,
+  DROP COLUMN gps_accuracy_m,
+  DROP COLUMN gps_fix,
+  DROP COLUMN battery_pct,
+  DROP COLUMN speed_mps,
+  DROP COLUMN heading_deg,
+  DROP COLUMN altitude_m;
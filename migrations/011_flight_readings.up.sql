@@ -0,0 +1,15 @@
+-- Richer heartbeats: latest flight readings on drone_status, full history in drone_telemetry
+ALTER TABLE drone_status
+  ADD COLUMN altitude_m DECIMAL(7,2) NULL AFTER last_heartbeat_at,
+  ADD COLUMN heading_deg DECIMAL(5,2) NULL AFTER altitude_m,
+  ADD COLUMN speed_mps DECIMAL(6,2) NULL COMMENT 'Ground speed' AFTER heading_deg,
+  ADD COLUMN battery_pct DECIMAL(5,2) NULL AFTER speed_mps,
+  ADD COLUMN gps_fix ENUM('none','2d','3d','dgps','rtk') NULL AFTER battery_pct,
+  ADD COLUMN gps_accuracy_m DECIMAL(7,2) NULL COMMENT 'Horizontal accuracy' AFTER gps_fix,
+  ADD COLUMN device_time TIMESTAMP(3) NULL COMMENT 'Drone clock at the latest applied heartbeat' AFTER gps_accuracy_m;
+
+ALTER TABLE drone_telemetry
+  ADD COLUMN altitude_m DECIMAL(7,2) NULL AFTER lng,
+  ADD COLUMN gps_fix ENUM('none','2d','3d','dgps','rtk') NULL AFTER heading_deg,
+  ADD COLUMN gps_accuracy_m DECIMAL(7,2) NULL AFTER gps_fix,
+  ADD COLUMN device_time TIMESTAMP(3) NULL AFTER gps_accuracy_m;
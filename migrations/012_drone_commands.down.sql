@@ -0,0 +1,2 @@
+-- Rollback drone commands
+DROP TABLE IF EXISTS drone_commands;
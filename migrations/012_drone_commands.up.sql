@@ -0,0 +1,20 @@
+-- Commands pushed to drones over the websocket, with their delivery and ack history
+CREATE TABLE IF NOT EXISTS drone_commands (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  drone_id BIGINT NOT NULL,
+  type ENUM('return_to_home','hold_position','land_now','divert','cancel_assignment') NOT NULL,
+  order_id BIGINT NULL COMMENT 'Assignment a cancel_assignment refers to',
+  lat DECIMAL(9,6) NULL COMMENT 'Divert target',
+  lng DECIMAL(9,6) NULL COMMENT 'Divert target',
+  issued_by BIGINT NULL COMMENT 'Admin who issued the command; NULL for system commands',
+  status ENUM('pending','sent','undelivered','acknowledged','rejected','completed','failed') NOT NULL DEFAULT 'pending',
+  note VARCHAR(255) NULL COMMENT 'Latest note reported by the drone',
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  sent_at TIMESTAMP NULL,
+  acked_at TIMESTAMP NULL,
+  completed_at TIMESTAMP NULL,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  KEY idx_drone_commands_drone (drone_id, created_at),
+  CONSTRAINT fk_drone_commands_drone FOREIGN KEY (drone_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT fk_drone_commands_issuer FOREIGN KEY (issued_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
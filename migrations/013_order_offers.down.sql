@@ -0,0 +1,4 @@
+-- Rollback assignment offer tracking
+ALTER TABLE orders
+  DROP FOREIGN KEY fk_orders_offered_drone,
+  DROP COLUMN offered_drone_id;
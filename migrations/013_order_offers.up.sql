@@ -0,0 +1,4 @@
+-- Remember which drone a pending order was last offered to, so it can be told about cancellations and reroutes
+ALTER TABLE orders
+  ADD COLUMN offered_drone_id BIGINT NULL COMMENT 'Drone the latest assignment offer went to' AFTER assigned_drone_id,
+  ADD CONSTRAINT fk_orders_offered_drone FOREIGN KEY (offered_drone_id) REFERENCES users(id) ON DELETE SET NULL;
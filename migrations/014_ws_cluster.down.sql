@@ -0,0 +1,3 @@
+-- Rollback websocket cluster tables
+DROP TABLE IF EXISTS ws_relay;
+DROP TABLE IF EXISTS ws_presence;
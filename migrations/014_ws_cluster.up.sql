@@ -0,0 +1,19 @@
+-- Cluster-wide drone websocket presence and the MySQL relay bus between API nodes
+CREATE TABLE IF NOT EXISTS ws_presence (
+  drone_id BIGINT PRIMARY KEY,
+  node_id VARCHAR(64) NOT NULL COMMENT 'API node holding the drone websocket',
+  connected_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  seen_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT 'Refreshed by the owning node while it is alive',
+  KEY idx_ws_presence_node (node_id),
+  CONSTRAINT fk_ws_presence_drone FOREIGN KEY (drone_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS ws_relay (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  node_id VARCHAR(64) NOT NULL COMMENT 'Node that should deliver the message',
+  drone_id BIGINT NOT NULL,
+  payload MEDIUMTEXT NOT NULL COMMENT 'JSON message as pushed to the drone',
+  created_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  KEY idx_ws_relay_node (node_id, id),
+  KEY idx_ws_relay_created (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
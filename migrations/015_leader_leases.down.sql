@@ -0,0 +1,2 @@
+-- Rollback leader leases
+DROP TABLE IF EXISTS leader_leases;
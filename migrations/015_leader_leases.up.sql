@@ -0,0 +1,9 @@
+-- Leases that elect one API node to run singleton background jobs
+CREATE TABLE IF NOT EXISTS leader_leases (
+  name VARCHAR(64) PRIMARY KEY,
+  holder VARCHAR(64) NOT NULL COMMENT 'Node id of the current leader',
+  term BIGINT NOT NULL DEFAULT 1 COMMENT 'Bumped every time leadership changes hands',
+  acquired_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  renewed_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  expires_at TIMESTAMP(3) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
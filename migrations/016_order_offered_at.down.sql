@@ -0,0 +1,4 @@
+-- Rollback assignment offer timestamps
+ALTER TABLE orders
+  DROP KEY idx_orders_offered_drone,
+  DROP COLUMN offered_at;
@@ -0,0 +1,4 @@
+-- When the latest assignment offer went out, so batch dispatch can retry offers the drone let lapse
+ALTER TABLE orders
+  ADD COLUMN offered_at TIMESTAMP(3) NULL COMMENT 'When offered_drone_id was last offered the order' AFTER offered_drone_id,
+  ADD KEY idx_orders_offered_drone (offered_drone_id, status);
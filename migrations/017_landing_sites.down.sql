@@ -0,0 +1,2 @@
+-- Rollback landing sites
+DROP TABLE IF EXISTS landing_sites;
@@ -0,0 +1,11 @@
+-- Landing sites: safe rendezvous points for the parcels of a drone that broke down
+CREATE TABLE IF NOT EXISTS landing_sites (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  name VARCHAR(100) NOT NULL,
+  lat DECIMAL(9,6) NOT NULL,
+  lng DECIMAL(9,6) NOT NULL,
+  active TINYINT(1) NOT NULL DEFAULT 1,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  KEY idx_landing_sites_active (active)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
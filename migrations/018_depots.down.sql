@@ -0,0 +1,9 @@
+-- Rollback depots
+UPDATE drone_status SET status = 'idle' WHERE status = 'charging';
+ALTER TABLE drone_status
+  DROP FOREIGN KEY fk_drone_status_depot,
+  DROP FOREIGN KEY fk_drone_status_home_depot,
+  DROP COLUMN depot_id,
+  DROP COLUMN home_depot_id,
+  MODIFY COLUMN status ENUM('idle','reserved','delivering','broken') NOT NULL DEFAULT 'idle';
+DROP TABLE IF EXISTS depots;
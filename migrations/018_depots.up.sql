@@ -0,0 +1,19 @@
+-- Depots: charging stations drones return to between trips, and each drone's home depot
+CREATE TABLE IF NOT EXISTS depots (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  name VARCHAR(100) NOT NULL,
+  lat DECIMAL(9,6) NOT NULL,
+  lng DECIMAL(9,6) NOT NULL,
+  capacity SMALLINT UNSIGNED NOT NULL COMMENT 'Charging pads',
+  active TINYINT(1) NOT NULL DEFAULT 1,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  KEY idx_depots_active (active)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE drone_status
+  MODIFY COLUMN status ENUM('idle','reserved','delivering','broken','charging') NOT NULL DEFAULT 'idle',
+  ADD COLUMN home_depot_id BIGINT NULL AFTER capacity,
+  ADD COLUMN depot_id BIGINT NULL COMMENT 'Depot whose pad the drone holds: parked, charging or on its way' AFTER home_depot_id,
+  ADD CONSTRAINT fk_drone_status_home_depot FOREIGN KEY (home_depot_id) REFERENCES depots(id) ON DELETE SET NULL,
+  ADD CONSTRAINT fk_drone_status_depot FOREIGN KEY (depot_id) REFERENCES depots(id) ON DELETE SET NULL;
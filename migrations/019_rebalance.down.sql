@@ -0,0 +1,10 @@
+-- Rollback demand-based pre-positioning
+DROP TABLE IF EXISTS rebalance_forecasts;
+
+ALTER TABLE orders
+  DROP KEY idx_orders_created;
+
+DELETE FROM drone_commands WHERE type = 'reposition';
+
+ALTER TABLE drone_commands
+  MODIFY COLUMN type ENUM('return_to_home','hold_position','land_now','divert','cancel_assignment') NOT NULL;
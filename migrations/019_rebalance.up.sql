@@ -0,0 +1,24 @@
+-- Demand-based pre-positioning: reposition commands for idle drones, and the per-cell forecasts each rebalance run made
+ALTER TABLE drone_commands
+  MODIFY COLUMN type ENUM('return_to_home','hold_position','land_now','divert','cancel_assignment','reposition') NOT NULL;
+
+-- Demand history and the wait report both scan orders by creation time
+ALTER TABLE orders
+  ADD KEY idx_orders_created (created_at);
+
+CREATE TABLE IF NOT EXISTS rebalance_forecasts (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  run_at TIMESTAMP(3) NOT NULL,
+  valid_until TIMESTAMP(3) NOT NULL COMMENT 'Cut short when the next run replaces the forecast',
+  hour_of_day TINYINT UNSIGNED NOT NULL,
+  min_lat DECIMAL(9,6) NOT NULL,
+  min_lng DECIMAL(9,6) NOT NULL,
+  max_lat DECIMAL(9,6) NOT NULL,
+  max_lng DECIMAL(9,6) NOT NULL,
+  orders_per_hour DECIMAL(10,4) NOT NULL,
+  idle_drones INT NOT NULL,
+  target_drones INT NOT NULL,
+  predicted_wait_seconds INT NULL COMMENT 'Flight time of the nearest idle drone once repositioned; NULL without idle drones',
+  KEY idx_rebalance_forecasts_run (run_at),
+  KEY idx_rebalance_forecasts_valid (valid_until)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
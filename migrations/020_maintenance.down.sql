@@ -0,0 +1,10 @@
+-- Rollback maintenance
+ALTER TABLE trips
+  DROP KEY idx_trips_drone_completed;
+
+DROP TABLE IF EXISTS maintenance_schedules;
+DROP TABLE IF EXISTS maintenance_records;
+
+UPDATE drone_status SET status = 'idle' WHERE status = 'maintenance';
+ALTER TABLE drone_status
+  MODIFY COLUMN status ENUM('idle','reserved','delivering','broken','charging') NOT NULL DEFAULT 'idle';
@@ -0,0 +1,30 @@
+-- Maintenance: per-drone service records and intervals, and the maintenance status that keeps a drone out of dispatch
+ALTER TABLE drone_status
+  MODIFY COLUMN status ENUM('idle','reserved','delivering','broken','charging','maintenance') NOT NULL DEFAULT 'idle';
+
+CREATE TABLE IF NOT EXISTS maintenance_records (
+  id BIGINT PRIMARY KEY AUTO_INCREMENT,
+  drone_id BIGINT NOT NULL,
+  issue ENUM('breakdown','scheduled','inspection','battery','propulsion','sensors','airframe','other') NOT NULL,
+  notes TEXT NULL,
+  parts JSON NULL COMMENT 'Parts replaced, as a list of names',
+  technician VARCHAR(100) NULL,
+  opened_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  closed_at TIMESTAMP NULL,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  KEY idx_maintenance_records_drone (drone_id, closed_at),
+  KEY idx_maintenance_records_opened (opened_at),
+  CONSTRAINT fk_maintenance_records_drone FOREIGN KEY (drone_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS maintenance_schedules (
+  drone_id BIGINT PRIMARY KEY,
+  flight_hours DECIMAL(8,2) NULL COMMENT 'Flight hours between services; NULL follows MAINTENANCE_FLIGHT_HOURS, 0 never',
+  cycles INT UNSIGNED NULL COMMENT 'Trips between services; NULL follows MAINTENANCE_CYCLES, 0 never',
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  CONSTRAINT fk_maintenance_schedules_drone FOREIGN KEY (drone_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Usage since the last service is summed over the trips a drone finished
+ALTER TABLE trips
+  ADD KEY idx_trips_drone_completed (drone_id, completed_at);
@@ -0,0 +1,8 @@
+-- Rollback drone usage
+ALTER TABLE drone_status
+  DROP COLUMN delivering_since,
+  DROP COLUMN airborne_seconds,
+  DROP COLUMN distance_m,
+  DROP COLUMN breakdowns,
+  DROP COLUMN failures,
+  DROP COLUMN deliveries;
@@ -0,0 +1,22 @@
+-- Drone usage: lifetime counters kept alongside the drone's status
+ALTER TABLE drone_status
+  ADD COLUMN deliveries INT UNSIGNED NOT NULL DEFAULT 0 AFTER device_time,
+  ADD COLUMN failures INT UNSIGNED NOT NULL DEFAULT 0 AFTER deliveries,
+  ADD COLUMN breakdowns INT UNSIGNED NOT NULL DEFAULT 0 AFTER failures,
+  ADD COLUMN distance_m DOUBLE NOT NULL DEFAULT 0 COMMENT 'Between consecutive heartbeats' AFTER breakdowns,
+  ADD COLUMN airborne_seconds DOUBLE NOT NULL DEFAULT 0 COMMENT 'Finished stretches in delivering' AFTER distance_m,
+  ADD COLUMN delivering_since TIMESTAMP(3) NULL AFTER airborne_seconds;
+
+-- counts what the order history still shows; distance and airborne time
+-- start from here
+UPDATE drone_status ds
+SET ds.deliveries = (
+      SELECT COUNT(*) FROM orders o
+      WHERE o.assigned_drone_id = ds.drone_id AND o.status = 'delivered'),
+    ds.failures = (
+      SELECT COUNT(*) FROM orders o
+      WHERE o.assigned_drone_id = ds.drone_id AND o.status IN ('failed','returning','returned')),
+    ds.breakdowns = (
+      SELECT COUNT(*) FROM maintenance_records m
+      WHERE m.drone_id = ds.drone_id AND m.issue = 'breakdown'),
+    ds.delivering_since = IF(ds.status = 'delivering', NOW(3), NULL);
@@ -0,0 +1,5 @@
+-- Rollback websocket session handoff
+DELETE FROM ws_relay WHERE kind = 'takeover';
+ALTER TABLE ws_relay DROP COLUMN kind;
+DELETE FROM ws_presence WHERE released_at IS NOT NULL;
+ALTER TABLE ws_presence DROP COLUMN released_at;
@@ -0,0 +1,7 @@
+-- Websocket session handoff: presence outlives the connection so a drone's
+-- next node can find the previous one, and relay rows carry a kind
+ALTER TABLE ws_presence
+  ADD COLUMN released_at TIMESTAMP(3) NULL COMMENT 'Set when the drone disconnected from node_id' AFTER seen_at;
+
+ALTER TABLE ws_relay
+  ADD COLUMN kind ENUM('message','takeover') NOT NULL DEFAULT 'message' AFTER drone_id;
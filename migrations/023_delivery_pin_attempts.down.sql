@@ -0,0 +1,3 @@
+-- Rollback delivery PIN attempts
+ALTER TABLE orders
+  DROP COLUMN pin_attempts;
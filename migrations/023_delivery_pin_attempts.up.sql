@@ -0,0 +1,3 @@
+-- Delivery PIN attempts: wrong PINs counted per order so guessing can be locked out
+ALTER TABLE orders
+  ADD COLUMN pin_attempts TINYINT UNSIGNED NOT NULL DEFAULT 0 AFTER delivery_pin;
@@ -0,0 +1,6 @@
+-- Rollback drone_status depot index; the foreign key needs an index of its
+-- own again
+ALTER TABLE drone_status
+  DROP FOREIGN KEY fk_drone_status_depot,
+  DROP INDEX idx_drone_status_depot,
+  ADD CONSTRAINT fk_drone_status_depot FOREIGN KEY (depot_id) REFERENCES depots(id) ON DELETE SET NULL;
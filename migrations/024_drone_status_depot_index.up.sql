@@ -0,0 +1,4 @@
+-- Index drone_status.depot_id so counting a depot's pads reads only the
+-- drones holding one there
+ALTER TABLE drone_status
+  ADD INDEX idx_drone_status_depot (depot_id);
@@ -0,0 +1,3 @@
+-- Rollback delivery PIN backfill; the generated PINs may already have been
+-- shown to endusers, so they are kept
+DO 0;
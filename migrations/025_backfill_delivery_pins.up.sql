@@ -0,0 +1,6 @@
+-- Backfill delivery PINs for open orders created before delivery_pin existed,
+-- so they can still be delivered by PIN
+UPDATE orders
+  SET delivery_pin = LPAD(CONV(HEX(RANDOM_BYTES(4)), 16, 10) MOD 1000000, 6, '0')
+  WHERE delivery_pin IS NULL
+    AND status IN ('pending','reserved','picked_up','handoff_pending');
@@ -0,0 +1,166 @@
+import pytest
+
+pytestmark = pytest.mark.acceptance
+
+HOME = {
+    "label": "Home",
+    "lat": 31.9632,
+    "lng": 35.9106,
+    "delivery_notes": "Leave with the doorman",
+    "access_instructions": "Gate code 4512",
+}
+OFFICE = {"label": "Office", "lat": 31.9454, "lng": 35.9284}
+
+
+def _create(api_client, token, payload, expected_status=201):
+    return api_client.post("/addresses", token=token, json_body=payload, expected_status=expected_status)
+
+
+def test_address_book_requires_enduser(api_client, admin_token, drone1_token):
+    api_client.get("/addresses", expected_status=401)
+    api_client.get("/addresses", token=admin_token, expected_status=403)
+    _create(api_client, drone1_token, OFFICE, expected_status=403)
+
+
+def test_address_crud(api_client, enduser_token):
+    created = _create(api_client, enduser_token, HOME).json()
+    address_id = created["address_id"]
+    assert created["label"] == "Home"
+    assert created["delivery_notes"] == HOME["delivery_notes"]
+    assert created["access_instructions"] == HOME["access_instructions"]
+
+    fetched = api_client.get(f"/addresses/{address_id}", token=enduser_token, expected_status=200).json()
+    assert fetched["lat"] == pytest.approx(HOME["lat"])
+    assert fetched["lng"] == pytest.approx(HOME["lng"])
+
+    listed = api_client.get("/addresses?page_size=100", token=enduser_token, expected_status=200).json()
+    assert address_id in [item["address_id"] for item in listed["data"]]
+
+    updated = api_client.patch(
+        f"/addresses/{address_id}",
+        token=enduser_token,
+        json_body={"label": "Home (new)", "delivery_notes": ""},
+        expected_status=200,
+    ).json()
+    assert updated["label"] == "Home (new)"
+    assert "delivery_notes" not in updated
+    assert updated["access_instructions"] == HOME["access_instructions"]
+
+    api_client.delete(f"/addresses/{address_id}", token=enduser_token, expected_status=204)
+    api_client.get(f"/addresses/{address_id}", token=enduser_token, expected_status=404)
+
+
+@pytest.mark.parametrize(
+    "payload",
+    [
+        pytest.param({"lat": 31.9, "lng": 35.9}, id="missing-label"),
+        pytest.param({"label": "   ", "lat": 31.9, "lng": 35.9}, id="blank-label"),
+        pytest.param({"label": "x" * 65, "lat": 31.9, "lng": 35.9}, id="long-label"),
+        pytest.param({"label": "Home", "lng": 35.9}, id="missing-lat"),
+        pytest.param({"label": "Home", "lat": 91, "lng": 35.9}, id="lat>90"),
+        pytest.param({"label": "Home", "lat": 31.9, "lng": -181}, id="lng<-180"),
+        pytest.param({"label": "Home", "lat": 31.9, "lng": 35.9, "delivery_notes": "x" * 501}, id="long-notes"),
+    ],
+)
+def test_address_validation(api_client, enduser_token, payload):
+    _create(api_client, enduser_token, payload, expected_status=400)
+
+
+def test_address_update_requires_fields(api_client, enduser_token):
+    address_id = _create(api_client, enduser_token, OFFICE).json()["address_id"]
+    api_client.patch(f"/addresses/{address_id}", token=enduser_token, json_body={}, expected_status=400)
+
+
+def test_addresses_are_private(api_client, enduser_token, enduser2_token):
+    address_id = _create(api_client, enduser_token, OFFICE).json()["address_id"]
+
+    api_client.get(f"/addresses/{address_id}", token=enduser2_token, expected_status=403)
+    api_client.patch(
+        f"/addresses/{address_id}", token=enduser2_token, json_body={"label": "Mine"}, expected_status=403
+    )
+    api_client.delete(f"/addresses/{address_id}", token=enduser2_token, expected_status=403)
+
+    listed = api_client.get("/addresses?page_size=100", token=enduser2_token, expected_status=200).json()
+    assert address_id not in [item["address_id"] for item in listed["data"]]
+
+
+def test_order_from_saved_addresses(api_client, enduser_token):
+    pickup_id = _create(api_client, enduser_token, OFFICE).json()["address_id"]
+    dropoff_id = _create(api_client, enduser_token, HOME).json()["address_id"]
+
+    body = api_client.post(
+        "/orders",
+        token=enduser_token,
+        json_body={"pickup_address_id": pickup_id, "dropoff_address_id": dropoff_id},
+        expected_status=201,
+    ).json()
+    assert body["pickup_address_id"] == pickup_id
+    assert body["dropoff_address_id"] == dropoff_id
+    assert body["pickup"]["lat"] == pytest.approx(OFFICE["lat"])
+    assert body["dropoff"]["lng"] == pytest.approx(HOME["lng"])
+    assert body["delivery_notes"] == HOME["delivery_notes"]
+    assert body["access_instructions"] == HOME["access_instructions"]
+
+
+def test_order_mixes_address_and_coordinates(api_client, enduser_token):
+    dropoff_id = _create(api_client, enduser_token, HOME).json()["address_id"]
+    body = api_client.post(
+        "/orders",
+        token=enduser_token,
+        json_body={"pickup_lat": 31.95, "pickup_lng": 35.92, "dropoff_address_id": dropoff_id},
+        expected_status=201,
+    ).json()
+    assert "pickup_address_id" not in body
+    assert body["pickup"]["lat"] == pytest.approx(31.95)
+    assert body["dropoff_address_id"] == dropoff_id
+
+
+def test_order_snapshot_survives_address_changes(api_client, order_actions, enduser_token):
+    dropoff_id = _create(api_client, enduser_token, HOME).json()["address_id"]
+    order_id = api_client.post(
+        "/orders",
+        token=enduser_token,
+        json_body={"pickup_lat": 31.95, "pickup_lng": 35.92, "dropoff_address_id": dropoff_id},
+        expected_status=201,
+    ).json()["order_id"]
+
+    api_client.patch(
+        f"/addresses/{dropoff_id}",
+        token=enduser_token,
+        json_body={"lat": 32.5, "lng": 36.5, "delivery_notes": "Changed"},
+        expected_status=200,
+    )
+    body = order_actions.get(order_id, token=enduser_token).json()
+    assert body["dropoff"]["lat"] == pytest.approx(HOME["lat"])
+    assert body["delivery_notes"] == HOME["delivery_notes"]
+
+    api_client.delete(f"/addresses/{dropoff_id}", token=enduser_token, expected_status=204)
+    body = order_actions.get(order_id, token=enduser_token).json()
+    assert "dropoff_address_id" not in body
+    assert body["dropoff"]["lng"] == pytest.approx(HOME["lng"])
+
+
+@pytest.mark.parametrize(
+    "payload",
+    [
+        pytest.param(
+            {"pickup_address_id": 1, "pickup_lat": 31.9, "pickup_lng": 35.9, "dropoff_lat": 32.0, "dropoff_lng": 36.0},
+            id="address-and-coordinates",
+        ),
+        pytest.param({"pickup_lat": 31.9, "pickup_lng": 35.9}, id="missing-dropoff"),
+        pytest.param({"pickup_address_id": 0, "dropoff_lat": 32.0, "dropoff_lng": 36.0}, id="non-positive-id"),
+    ],
+)
+def test_order_rejects_ambiguous_endpoints(api_client, enduser_token, payload):
+    api_client.post("/orders", token=enduser_token, json_body=payload, expected_status=400)
+
+
+def test_order_rejects_foreign_or_missing_address(api_client, enduser_token, enduser2_token):
+    foreign_id = _create(api_client, enduser2_token, OFFICE).json()["address_id"]
+    coords = {"pickup_lat": 31.9, "pickup_lng": 35.9}
+    api_client.post(
+        "/orders", token=enduser_token, json_body={**coords, "dropoff_address_id": foreign_id}, expected_status=403
+    )
+    api_client.post(
+        "/orders", token=enduser_token, json_body={**coords, "dropoff_address_id": 999999999}, expected_status=404
+    )
@@ -0,0 +1,113 @@
+import pytest
+
+pytestmark = pytest.mark.acceptance
+
+DRY_RUN_URL = "/admin/dispatch/dry-run"
+LAT = 31.95
+BASE_LNG = 35.90
+
+
+def _lng(km_east):
+    """Points along one parallel, roughly 0.95 km per 0.01 degree here."""
+    return BASE_LNG + km_east * 0.01
+
+
+def _order(order_id, x):
+    return {
+        "id": order_id,
+        "pickup_lat": LAT,
+        "pickup_lng": _lng(x),
+        "dropoff_lat": LAT + 0.01,
+        "dropoff_lng": _lng(x),
+    }
+
+
+def _drone(drone_id, x):
+    return {"id": drone_id, "lat": LAT, "lng": _lng(x)}
+
+
+# The first order sits slightly closer to drone 1, which the second order
+# needs: greedy sends drone 2 all the way across, batch swaps them.
+CROSSING_SCENARIO = {
+    "orders": [_order(1, 1.9), _order(2, 0)],
+    "drones": [_drone(1, 1), _drone(2, 3)],
+}
+
+
+def _dry_run(api_client, admin_token, body, expected_status=200):
+    return api_client.post(DRY_RUN_URL, token=admin_token, json_body=body, expected_status=expected_status).json()
+
+
+def _pairs(plan):
+    return {a["order_id"]: a["drone_id"] for a in plan["assignments"]}
+
+
+def test_dry_run_requires_admin(api_client, enduser_token, drone1_token):
+    api_client.post(DRY_RUN_URL, json_body={}, expected_status=401)
+    api_client.post(DRY_RUN_URL, token=enduser_token, json_body={}, expected_status=403)
+    api_client.post(DRY_RUN_URL, token=drone1_token, json_body={}, expected_status=403)
+
+
+def test_batch_beats_greedy_on_crossing_scenario(api_client, admin_token):
+    greedy = _dry_run(api_client, admin_token, {"strategy": "greedy", "scenario": CROSSING_SCENARIO})
+    batch = _dry_run(api_client, admin_token, {"strategy": "batch", "scenario": CROSSING_SCENARIO})
+
+    assert greedy["source"] == batch["source"] == "scenario"
+    assert _pairs(greedy) == {1: 1, 2: 2}
+    assert _pairs(batch) == {1: 2, 2: 1}
+    assert batch["total_distance_km"] < greedy["total_distance_km"]
+    assert batch["unassigned_order_ids"] == []
+
+
+def test_proposals_carry_etas_and_distances(api_client, admin_token):
+    plan = _dry_run(api_client, admin_token, {"strategy": "batch", "cost": "route", "scenario": CROSSING_SCENARIO})
+
+    assert plan["cost"] == "route"
+    assert plan["orders_considered"] == 2
+    assert plan["drones_considered"] == 2
+    for assignment in plan["assignments"]:
+        assert assignment["distance_km"] > 0
+        assert assignment["delivery_distance_km"] > assignment["distance_km"]
+        assert 1 <= assignment["pickup_eta_minutes"] <= assignment["delivery_eta_minutes"]
+    total = sum(a["distance_km"] for a in plan["assignments"])
+    assert plan["total_distance_km"] == pytest.approx(total)
+
+
+def test_orders_beyond_fleet_capacity_are_left_unassigned(api_client, admin_token):
+    scenario = {
+        "orders": [_order(1, 0), _order(2, 1), _order(3, 2)],
+        "drones": [{**_drone(1, 1), "capacity": 2}],
+    }
+    plan = _dry_run(api_client, admin_token, {"strategy": "batch", "scenario": scenario})
+
+    assert len(plan["assignments"]) == 2
+    assert set(_pairs(plan).values()) == {1}
+    assert plan["unassigned_order_ids"] == [3]
+
+
+@pytest.mark.parametrize(
+    "body",
+    [
+        {"strategy": "fastest"},
+        {"cost": "euclidean"},
+        {"scenario": {"orders": [], "drones": [_drone(1, 0)]}},
+        {"scenario": {"orders": [_order(1, 0), _order(1, 1)], "drones": [_drone(1, 0)]}},
+        {"scenario": {"orders": [_order(1, 0)], "drones": [{"id": 1, "lat": 95, "lng": 35.9}]}},
+        {"scenario": {"orders": [_order(1, 0)], "drones": [{**_drone(1, 0), "capacity": 2, "active_orders": 3}]}},
+    ],
+)
+def test_invalid_dry_run_is_rejected(api_client, admin_token, body):
+    resp = _dry_run(api_client, admin_token, body, expected_status=400)
+    assert resp["error"] in {"invalid_dispatch_plan", "invalid_latitude"}
+
+
+def test_live_dry_run_commits_nothing(api_client, admin_token, enduser_token, order_factory):
+    order_id = order_factory()
+
+    plan = _dry_run(api_client, admin_token, {})
+    assert plan["source"] == "live"
+    assert plan["strategy"] in {"greedy", "batch"}
+    assert len(plan["assignments"]) + len(plan["unassigned_order_ids"]) == plan["orders_considered"]
+
+    order = api_client.get(f"/orders/{order_id}", token=enduser_token).json()
+    assert order["status"] == "pending"
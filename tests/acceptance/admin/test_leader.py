@@ -0,0 +1,52 @@
+import time
+
+import pytest
+
+from ..support.http import ApiClient
+
+pytestmark = pytest.mark.acceptance
+
+LEADER_URL = "/admin/leader"
+
+
+def _wait_for_leader(client, admin_token, timeout=30):
+    """A fresh stack may still be campaigning, or failing over."""
+    deadline = time.time() + timeout
+    while True:
+        body = client.get(LEADER_URL, token=admin_token).json()
+        if body["leader"] is not None or time.time() >= deadline:
+            return body
+        time.sleep(1)
+
+
+def test_leader_requires_admin(api_client, enduser_token, drone1_token):
+    api_client.get(LEADER_URL, expected_status=401)
+    api_client.get(LEADER_URL, token=enduser_token, expected_status=403)
+    api_client.get(LEADER_URL, token=drone1_token, expected_status=403)
+
+
+def test_leader_status_shape(api_client, admin_token):
+    body = _wait_for_leader(api_client, admin_token)
+
+    assert body["node_id"]
+    assert "telemetry-maintenance" in body["jobs"]
+
+    leader = body["leader"]
+    assert leader is not None
+    assert leader["term"] >= 1
+    for key in ("acquired_at", "renewed_at", "expires_at"):
+        assert leader[key]
+    assert body["is_leader"] == (leader["node_id"] == body["node_id"])
+
+
+def test_nodes_agree_on_a_single_leader(peer_base_url, api_client, admin_token):
+    peer = ApiClient(peer_base_url)
+    try:
+        mine = _wait_for_leader(api_client, admin_token)
+        theirs = _wait_for_leader(peer, admin_token)
+    finally:
+        peer.close()
+
+    assert mine["node_id"] != theirs["node_id"]
+    assert mine["leader"]["node_id"] == theirs["leader"]["node_id"]
+    assert [mine["is_leader"], theirs["is_leader"]].count(True) == 1
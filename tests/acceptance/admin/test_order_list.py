@@ -155,4 +155,4 @@ def test_admin_order_list_status_fields(
     assert entry.get("canceled_at")
 
     order_actions.fail(reserved_order, token=drone1_token)
-    order_actions.deliver(pickup_order, token=drone2_token)
+    order_actions.complete_delivery(pickup_order, token=drone2_token, enduser_token=enduser_token)
@@ -60,12 +60,12 @@ def test_admin_route_update_only_pending(
     order_actions.reserve(picked_up, token=drone2_token)
     order_actions.pickup(picked_up, token=drone2_token)
     _patch_order(api_client, admin_token, picked_up, {"pickup_lat": 30.0, "pickup_lng": 35.0}, expected_status=409)
-    order_actions.deliver(picked_up, token=drone2_token)
+    order_actions.complete_delivery(picked_up, token=drone2_token, enduser_token=enduser_token)
 
     delivered = order_actions.create(token=enduser_token)
     order_actions.reserve(delivered, token=drone1_token)
     order_actions.pickup(delivered, token=drone1_token)
-    order_actions.deliver(delivered, token=drone1_token)
+    order_actions.complete_delivery(delivered, token=drone1_token, enduser_token=enduser_token)
     _patch_order(api_client, admin_token, delivered, {"pickup_lat": 30.0, "pickup_lng": 35.0}, expected_status=409)
 
     canceled = order_actions.create(token=enduser_token)
@@ -0,0 +1,141 @@
+import json
+
+import pytest
+
+from ..support.ws import recv_of_type, websocket_connection
+
+pytestmark = pytest.mark.acceptance
+
+
+def _commands_url(drone_id):
+    return f"/admin/drones/{drone_id}/commands"
+
+
+def _connect(ws):
+    """Send a heartbeat and wait for its reply so the connection is registered."""
+    ws.send(json.dumps({"type": "heartbeat", "lat": 31.9454, "lng": 35.9284}))
+    assert recv_of_type(ws, "heartbeat")["message"] == "ok"
+
+
+def _report(ws, message_type, command_id, status, note=None):
+    payload = {"type": message_type, "command_id": command_id, "status": status}
+    if note:
+        payload["note"] = note
+    ws.send(json.dumps(payload))
+    while True:
+        data = json.loads(ws.recv())
+        if data.get("type") in (message_type, "heartbeat"):
+            return data
+
+
+def test_command_endpoints_require_admin(api_client, enduser_token, drone1_token, drone1_id):
+    payload = {"type": "hold_position"}
+    api_client.post(_commands_url(drone1_id), json_body=payload, expected_status=401)
+    api_client.post(_commands_url(drone1_id), token=enduser_token, json_body=payload, expected_status=403)
+    api_client.post(_commands_url(drone1_id), token=drone1_token, json_body=payload, expected_status=403)
+    api_client.get(_commands_url(drone1_id), token=drone1_token, expected_status=403)
+
+
+@pytest.mark.parametrize(
+    "payload",
+    [
+        {},
+        {"type": "fly_to_the_moon"},
+        {"type": "divert"},
+        {"type": "divert", "lat": 95.0, "lng": 35.0},
+        {"type": "cancel_assignment"},
+        {"type": "hold_position", "lat": 31.9, "lng": 35.9},
+        {"type": "return_to_home", "lat": 31.9},
+        {"type": "reposition"},
+    ],
+)
+def test_issue_rejects_invalid_command(api_client, admin_token, drone1_id, payload):
+    api_client.post(_commands_url(drone1_id), token=admin_token, json_body=payload, expected_status=400)
+
+
+def test_issue_to_unknown_drone(api_client, admin_token):
+    api_client.post(_commands_url(999999), token=admin_token, json_body={"type": "land_now"}, expected_status=404)
+    api_client.get(_commands_url(999999), token=admin_token, expected_status=404)
+    api_client.post(_commands_url("abc"), token=admin_token, json_body={"type": "land_now"}, expected_status=400)
+
+
+def test_command_to_offline_drone_is_undelivered(api_client, admin_token, drone2_id):
+    body = api_client.post(
+        _commands_url(drone2_id), token=admin_token, json_body={"type": "return_to_home"}, expected_status=201
+    ).json()
+    assert body["status"] == "undelivered"
+    assert "sent_at" not in body
+
+
+def test_reposition_carries_target(api_client, admin_token, drone2_id):
+    body = api_client.post(
+        _commands_url(drone2_id),
+        token=admin_token,
+        json_body={"type": "reposition", "lat": 31.95, "lng": 35.91},
+        expected_status=201,
+    ).json()
+    assert body["type"] == "reposition"
+    assert body["lat"] == pytest.approx(31.95)
+    assert body["lng"] == pytest.approx(35.91)
+
+
+def test_command_round_trip(base_url, api_client, admin_token, admin_profile, drone1_token, drone1_id):
+    with websocket_connection(base_url, drone1_token) as ws:
+        _connect(ws)
+        issued = api_client.post(
+            _commands_url(drone1_id),
+            token=admin_token,
+            json_body={"type": "divert", "lat": 31.95, "lng": 35.91},
+            expected_status=201,
+        ).json()
+        message = recv_of_type(ws, "command")
+
+        assert issued["type"] == "divert"
+        assert message["command_id"] == issued["command_id"]
+        assert message["command"] == "divert"
+        assert message["lat"] == pytest.approx(31.95)
+        assert message["lng"] == pytest.approx(35.91)
+
+        ack = _report(ws, "command_ack", issued["command_id"], "accepted")
+        assert ack["command_status"] == "acknowledged"
+        result = _report(ws, "command_result", issued["command_id"], "completed", note="arrived")
+        assert result["command_status"] == "completed"
+
+    command = api_client.get(
+        f"{_commands_url(drone1_id)}/{issued['command_id']}", token=admin_token, expected_status=200
+    ).json()
+    assert command["status"] == "completed"
+    assert command["note"] == "arrived"
+    assert command["issued_by"] == admin_profile["user"]["id"]
+    for field in ("sent_at", "acked_at", "completed_at"):
+        assert field in command
+
+    history = api_client.get(_commands_url(drone1_id), token=admin_token, expected_status=200).json()
+    assert history["data"][0]["command_id"] == issued["command_id"]
+
+
+def test_rejected_command_cannot_complete(base_url, api_client, admin_token, drone1_token, drone1_id):
+    with websocket_connection(base_url, drone1_token) as ws:
+        _connect(ws)
+        issued = api_client.post(
+            _commands_url(drone1_id), token=admin_token, json_body={"type": "land_now"}, expected_status=201
+        ).json()
+        recv_of_type(ws, "command")
+
+        reject = _report(ws, "command_ack", issued["command_id"], "rejected", note="low battery")
+        assert reject["command_status"] == "rejected"
+        late = _report(ws, "command_result", issued["command_id"], "completed")
+        assert late["message"] == "error"
+
+        bad_status = _report(ws, "command_ack", issued["command_id"], "maybe")
+        assert bad_status["message"] == "error"
+
+
+def test_drone_cannot_ack_another_drones_command(base_url, api_client, admin_token, drone1_id, drone2_token):
+    issued = api_client.post(
+        _commands_url(drone1_id), token=admin_token, json_body={"type": "hold_position"}, expected_status=201
+    ).json()
+    with websocket_connection(base_url, drone2_token) as ws:
+        resp = _report(ws, "command_ack", issued["command_id"], "accepted")
+    assert resp["message"] == "error"
+    assert "not found" in resp["error"]
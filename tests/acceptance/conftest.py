@@ -25,6 +25,24 @@ def base_url(pytestconfig: pytest.Config) -> str:
     return (cli_value or env_value or "http://localhost:8080").rstrip("/")
 
 
+@pytest.fixture(scope="session")
+def peer_base_url() -> str:
+    """Second API node sharing the database; skip when the stack runs a single node."""
+    url = os.getenv("PEER_BASE_URL")
+    if not url:
+        pytest.skip("PEER_BASE_URL not set")
+
+    deadline = time.time() + int(os.getenv("API_WAIT_TIMEOUT", "60"))
+    while time.time() < deadline:
+        try:
+            if requests.get(f"{url}/health", timeout=2).status_code == 200:
+                return url.rstrip("/")
+        except requests.RequestException:
+            pass
+        time.sleep(1)
+    pytest.skip(f"peer node at {url} is not reachable")
+
+
 @pytest.fixture(scope="session")
 def api_client(base_url: str) -> ApiClient:
     client = ApiClient(base_url)
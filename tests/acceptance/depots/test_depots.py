@@ -0,0 +1,157 @@
+import json
+
+import pytest
+
+from ..support.ws import recv_of_type, websocket_connection
+
+pytestmark = pytest.mark.acceptance
+
+DEPOT = {"name": "Shmeisani depot", "lat": 31.9700, "lng": 35.8950, "capacity": 2}
+
+
+@pytest.fixture
+def depot(api_client, admin_token):
+    body = api_client.post("/admin/depots", token=admin_token, json_body=DEPOT, expected_status=201).json()
+    yield body
+    api_client.delete(f"/admin/depots/{body['depot_id']}", token=admin_token)
+
+
+def _drone(drone_actions, drone_id):
+    drones = drone_actions.list_drones(query="page_size=100").json()["data"]
+    return next(d for d in drones if d["drone_id"] == drone_id)
+
+
+def _heartbeat(ws, battery_pct):
+    """Send a heartbeat and return what arrived up to and including its reply."""
+    ws.send(json.dumps({"type": "heartbeat", "lat": 31.9454, "lng": 35.9284, "battery_pct": battery_pct}))
+    received = []
+    while not received or received[-1].get("type") != "heartbeat":
+        received.append(json.loads(ws.recv()))
+    assert received[-1]["message"] == "ok"
+    return received
+
+
+def test_admin_endpoints_require_admin(api_client, enduser_token, drone1_token, drone1_id):
+    api_client.post("/admin/depots", json_body=DEPOT, expected_status=401)
+    api_client.post("/admin/depots", token=enduser_token, json_body=DEPOT, expected_status=403)
+    api_client.get("/admin/depots", token=drone1_token, expected_status=403)
+    api_client.put(
+        f"/admin/drones/{drone1_id}/home-depot", token=drone1_token, json_body={"depot_id": 1}, expected_status=403
+    )
+
+
+def test_create_and_list_depot(api_client, admin_token, depot):
+    assert depot["name"] == DEPOT["name"]
+    assert depot["lat"] == pytest.approx(DEPOT["lat"])
+    assert depot["lng"] == pytest.approx(DEPOT["lng"])
+    assert depot["capacity"] == 2
+    assert depot["occupied"] == 0
+    assert depot["charging"] == 0
+    assert depot["free_pads"] == 2
+    assert depot["active"] is True
+
+    body = api_client.get("/admin/depots", token=admin_token, expected_status=200).json()
+    assert depot["depot_id"] in [d["depot_id"] for d in body["data"]]
+
+
+@pytest.mark.parametrize(
+    "payload",
+    [
+        pytest.param({"lat": 31.97, "lng": 35.89, "capacity": 2}, id="no-name"),
+        pytest.param({"name": "  ", "lat": 31.97, "lng": 35.89, "capacity": 2}, id="blank-name"),
+        pytest.param({"name": "Depot", "lng": 35.89, "capacity": 2}, id="no-lat"),
+        pytest.param({"name": "Depot", "lat": 95, "lng": 35.89, "capacity": 2}, id="lat-invalid"),
+        pytest.param({"name": "Depot", "lat": 31.97, "lng": 35.89}, id="no-capacity"),
+        pytest.param({"name": "Depot", "lat": 31.97, "lng": 35.89, "capacity": 0}, id="zero-capacity"),
+        pytest.param({"name": "Depot", "lat": 31.97, "lng": 35.89, "capacity": 101}, id="capacity-too-large"),
+    ],
+)
+def test_create_rejects_invalid_depot(api_client, admin_token, payload):
+    api_client.post("/admin/depots", token=admin_token, json_body=payload, expected_status=400)
+
+
+def test_update_depot(api_client, admin_token, depot):
+    body = api_client.patch(
+        f"/admin/depots/{depot['depot_id']}",
+        token=admin_token,
+        json_body={"name": "Shmeisani yard", "capacity": 5, "active": False},
+        expected_status=200,
+    ).json()
+    assert body["name"] == "Shmeisani yard"
+    assert body["capacity"] == 5
+    assert body["free_pads"] == 5
+    assert body["active"] is False
+
+
+@pytest.mark.parametrize(
+    "payload",
+    [
+        pytest.param({}, id="empty"),
+        pytest.param({"lat": 31.97}, id="lat-without-lng"),
+        pytest.param({"capacity": -1}, id="negative-capacity"),
+    ],
+)
+def test_update_rejects_invalid_fields(api_client, admin_token, depot, payload):
+    body = api_client.patch(
+        f"/admin/depots/{depot['depot_id']}", token=admin_token, json_body=payload, expected_status=400
+    ).json()
+    assert body["error"] == "invalid_depot"
+
+
+def test_update_and_delete_unknown_depot(api_client, admin_token):
+    api_client.patch("/admin/depots/999999", token=admin_token, json_body={"active": False}, expected_status=404)
+    api_client.delete("/admin/depots/999999", token=admin_token, expected_status=404)
+    api_client.patch("/admin/depots/abc", token=admin_token, json_body={"active": False}, expected_status=400)
+
+
+def test_set_and_clear_home_depot(api_client, admin_token, depot, drone2_id):
+    url = f"/admin/drones/{drone2_id}/home-depot"
+    body = api_client.put(url, token=admin_token, json_body={"depot_id": depot["depot_id"]}, expected_status=200).json()
+    assert body["home_depot_id"] == depot["depot_id"]
+
+    body = api_client.delete(url, token=admin_token, expected_status=200).json()
+    assert "home_depot_id" not in body
+
+
+def test_home_depot_rejects_unknown_depot_or_drone(api_client, admin_token, depot, drone2_id):
+    api_client.put(
+        f"/admin/drones/{drone2_id}/home-depot", token=admin_token, json_body={"depot_id": 999999}, expected_status=404
+    )
+    api_client.put(
+        "/admin/drones/999999/home-depot", token=admin_token, json_body={"depot_id": depot["depot_id"]}, expected_status=404
+    )
+    api_client.put(f"/admin/drones/{drone2_id}/home-depot", token=admin_token, json_body={}, expected_status=400)
+
+
+def test_low_battery_drone_charges_at_home_depot(
+    base_url, api_client, admin_token, depot, drone1_token, drone1_id, drone_actions, reset_drones
+):
+    api_client.put(
+        f"/admin/drones/{drone1_id}/home-depot",
+        token=admin_token,
+        json_body={"depot_id": depot["depot_id"]},
+        expected_status=200,
+    )
+    try:
+        with websocket_connection(base_url, drone1_token) as ws:
+            received = _heartbeat(ws, 10.0)
+            command = next((m for m in received if m.get("type") == "command"), None) or recv_of_type(ws, "command")
+            assert command["command"] == "return_to_home"
+            assert command["lat"] == pytest.approx(DEPOT["lat"])
+            assert command["lng"] == pytest.approx(DEPOT["lng"])
+
+            drone = _drone(drone_actions, drone1_id)
+            assert drone["status"] == "charging"
+            assert drone["depot_id"] == depot["depot_id"]
+
+            depots = api_client.get("/admin/depots", token=admin_token, expected_status=200).json()["data"]
+            listed = next(d for d in depots if d["depot_id"] == depot["depot_id"])
+            assert listed["occupied"] == 1
+            assert listed["charging"] == 1
+
+            _heartbeat(ws, 95.0)
+            drone = _drone(drone_actions, drone1_id)
+            assert drone["status"] == "idle"
+            assert drone["depot_id"] == depot["depot_id"]
+    finally:
+        api_client.delete(f"/admin/drones/{drone1_id}/home-depot", token=admin_token)
@@ -48,6 +48,29 @@ def test_broken_drone_triggers_handoff(drone_actions, order_actions, enduser_tok
     drone_actions.ensure_idle(drone1_id, lat=30.0, lng=35.0)
 
 
+def test_parcel_stays_at_first_handoff_point_when_rescuer_breaks(
+    drone_actions, order_actions, enduser_token, drone1_token, drone2_token, drone1_id, drone2_id
+):
+    drone_actions.ensure_idle(drone1_id, lat=30.0, lng=35.0)
+    drone_actions.ensure_idle(drone2_id, lat=29.0, lng=34.0)
+    order_id = order_actions.create(token=enduser_token)
+    order_actions.reserve(order_id, token=drone1_token)
+    order_actions.pickup(order_id, token=drone1_token)
+    drone_actions.mark_broken(drone1_id, lat=30.05, lng=35.05, token=drone1_token)
+
+    # the rescuer breaks down before collecting the parcel, which is still
+    # where the first drone left it
+    order_actions.reserve(order_id, token=drone2_token)
+    drone_actions.mark_broken(drone2_id, lat=29.55, lng=34.55, token=drone2_token)
+    order = order_actions.get(order_id, token=enduser_token).json()
+    assert order["status"] == "handoff_pending"
+    assert order["handoff_lat"] == pytest.approx(30.05)
+    assert order["handoff_lng"] == pytest.approx(35.05)
+
+    drone_actions.ensure_idle(drone1_id, lat=30.0, lng=35.0)
+    drone_actions.ensure_idle(drone2_id, lat=29.0, lng=34.0)
+
+
 def test_admin_marks_busy_drone_broken_releases_order(
     drone_actions, order_actions, enduser_token, drone2_token, drone2_id, admin_token
 ):
@@ -0,0 +1,138 @@
+import math
+import time
+
+import pytest
+
+from ..support.ws import send_multiple_heartbeats
+
+pytestmark = pytest.mark.acceptance
+
+
+@pytest.fixture
+def idle_drone1(drone_actions, drone1_id):
+    drone_actions.ensure_idle(drone1_id, lat=30.0, lng=35.0)
+    yield
+    drone_actions.ensure_idle(drone1_id, lat=30.0, lng=35.0)
+
+
+def _usage(api_client, admin_token, drone_id):
+    return api_client.get(f"/admin/drones/{drone_id}", token=admin_token, expected_status=200).json()["usage"]
+
+
+def _distance_m(a, b):
+    lat1, lat2 = math.radians(a["lat"]), math.radians(b["lat"])
+    dlat = lat2 - lat1
+    dlng = math.radians(b["lng"] - a["lng"])
+    h = math.sin(dlat / 2) ** 2 + math.cos(lat1) * math.cos(lat2) * math.sin(dlng / 2) ** 2
+    return 2 * 6371000 * math.atan2(math.sqrt(h), math.sqrt(1 - h))
+
+
+def test_drone_detail_requires_admin(api_client, admin_token, enduser_token, drone1_token, drone1_id):
+    api_client.get(f"/admin/drones/{drone1_id}", expected_status=401)
+    api_client.get(f"/admin/drones/{drone1_id}", token=enduser_token, expected_status=403)
+    api_client.get(f"/admin/drones/{drone1_id}", token=drone1_token, expected_status=403)
+    api_client.get("/admin/drones/abc", token=admin_token, expected_status=400)
+    api_client.get("/admin/drones/999999999", token=admin_token, expected_status=404)
+
+
+def test_drone_detail_matches_list(api_client, admin_token, drone_actions, drone1_id):
+    detail = api_client.get(f"/admin/drones/{drone1_id}", token=admin_token, expected_status=200).json()
+    assert detail["drone_id"] == drone1_id
+    assert set(detail["usage"]) == {"deliveries", "failures", "breakdowns", "distance_m", "airborne_seconds"}
+
+    listed = drone_actions.list_drones(query="page_size=100").json()["data"]
+    entry = next(d for d in listed if d["drone_id"] == drone1_id)
+    assert entry["usage"]["deliveries"] == detail["usage"]["deliveries"]
+    assert entry["usage"]["distance_m"] == pytest.approx(detail["usage"]["distance_m"])
+
+
+def test_heartbeats_add_distance_flown(
+    api_client, base_url, admin_token, enduser_token, drone1_token, drone1_id, order_actions, idle_drone1
+):
+    start = {"lat": 31.9454, "lng": 35.9284}
+    end = {"lat": 31.9632, "lng": 35.9106}
+    send_multiple_heartbeats(base_url, drone1_token, [start])
+    order_id = order_actions.create(token=enduser_token)
+    order_actions.reserve(order_id, token=drone1_token)
+    before = _usage(api_client, admin_token, drone1_id)
+
+    send_multiple_heartbeats(base_url, drone1_token, [end, start])
+    after = _usage(api_client, admin_token, drone1_id)
+
+    assert after["distance_m"] - before["distance_m"] == pytest.approx(2 * _distance_m(start, end), abs=1)
+    order_actions.fail(order_id, token=drone1_token)
+
+
+def test_idle_heartbeats_add_no_distance(api_client, base_url, admin_token, drone1_token, drone1_id, idle_drone1):
+    start = {"lat": 31.9454, "lng": 35.9284}
+    jitter = {"lat": 31.9455, "lng": 35.9285}
+    send_multiple_heartbeats(base_url, drone1_token, [start])
+    before = _usage(api_client, admin_token, drone1_id)
+
+    send_multiple_heartbeats(base_url, drone1_token, [jitter, start, jitter])
+    after = _usage(api_client, admin_token, drone1_id)
+
+    assert after["distance_m"] == pytest.approx(before["distance_m"])
+
+
+def test_delivery_counts_and_airborne_time(
+    api_client, admin_token, enduser_token, drone1_token, drone1_id, order_actions, idle_drone1
+):
+    before = _usage(api_client, admin_token, drone1_id)
+
+    order_id = order_actions.create(token=enduser_token)
+    order_actions.reserve(order_id, token=drone1_token)
+    order_actions.pickup(order_id, token=drone1_token)
+    time.sleep(1)
+    delivering = _usage(api_client, admin_token, drone1_id)
+    assert delivering["airborne_seconds"] >= before["airborne_seconds"] + 0.9
+    order_actions.complete_delivery(order_id, token=drone1_token, enduser_token=enduser_token)
+
+    after = _usage(api_client, admin_token, drone1_id)
+    assert after["deliveries"] == before["deliveries"] + 1
+    assert after["failures"] == before["failures"]
+    assert after["airborne_seconds"] >= delivering["airborne_seconds"]
+
+    time.sleep(0.5)
+    idle = _usage(api_client, admin_token, drone1_id)
+    assert idle["airborne_seconds"] == pytest.approx(after["airborne_seconds"])
+
+
+def test_failed_delivery_is_counted(
+    api_client, admin_token, enduser_token, drone1_token, drone1_id, order_actions, idle_drone1
+):
+    before = _usage(api_client, admin_token, drone1_id)
+
+    order_id = order_actions.create(token=enduser_token)
+    order_actions.reserve(order_id, token=drone1_token)
+    order_actions.pickup(order_id, token=drone1_token)
+    order_actions.fail(order_id, token=drone1_token)
+
+    after = _usage(api_client, admin_token, drone1_id)
+    assert after["failures"] == before["failures"] + 1
+    assert after["deliveries"] == before["deliveries"]
+
+
+def test_breakdowns_are_counted_once(api_client, admin_token, drone1_token, drone1_id, drone_actions, idle_drone1):
+    before = _usage(api_client, admin_token, drone1_id)
+
+    drone_actions.mark_broken(drone1_id, lat=30.05, lng=35.05, token=drone1_token)
+    drone_actions.mark_broken(drone1_id, lat=30.06, lng=35.06, token=drone1_token)
+
+    after = _usage(api_client, admin_token, drone1_id)
+    assert after["breakdowns"] == before["breakdowns"] + 1
+
+
+def test_failing_returning_order_is_not_counted_again(
+    api_client, admin_token, enduser_token, drone1_token, drone1_id, order_actions, idle_drone1
+):
+    before = _usage(api_client, admin_token, drone1_id)
+
+    order_id = order_actions.create(token=enduser_token)
+    order_actions.reserve(order_id, token=drone1_token)
+    order_actions.pickup(order_id, token=drone1_token)
+    assert order_actions.fail(order_id, token=drone1_token).json()["status"] == "returning"
+    assert order_actions.fail(order_id, token=drone1_token).json()["status"] == "failed"
+
+    after = _usage(api_client, admin_token, drone1_id)
+    assert after["failures"] == before["failures"] + 1
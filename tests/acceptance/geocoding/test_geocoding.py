@@ -0,0 +1,118 @@
+from urllib.parse import urlencode
+
+import pytest
+
+pytestmark = pytest.mark.acceptance
+
+# Entries from data/gazetteer.csv shipped with the API image
+DOWNTOWN = {"address": "Downtown Amman", "lat": 31.9454, "lng": 35.9284}
+ABDALI = {"address": "Abdali Boulevard", "lat": 31.9632, "lng": 35.9106}
+
+
+def _geocode(api_client, token, query, expected_status=200):
+    return api_client.get(f"/geocode?{urlencode({'q': query})}", token=token, expected_status=expected_status)
+
+
+def test_geocode_requires_auth(api_client):
+    api_client.get("/geocode?q=abdali", expected_status=401)
+    api_client.get("/geocode/reverse?lat=31.9&lng=35.9", expected_status=401)
+
+
+@pytest.mark.parametrize("query", ["Abdali Boulevard", "  abdali   BOULEVARD, ", "The Boulevard", "abdali"])
+def test_geocode_resolves_known_places(api_client, enduser_token, query):
+    body = _geocode(api_client, enduser_token, query).json()
+    assert body["address"] == ABDALI["address"]
+    assert body["lat"] == pytest.approx(ABDALI["lat"])
+    assert body["lng"] == pytest.approx(ABDALI["lng"])
+
+
+def test_geocode_available_to_all_roles(api_client, admin_token, drone1_token):
+    for token in (admin_token, drone1_token):
+        assert _geocode(api_client, token, "Downtown Amman").json()["address"] == DOWNTOWN["address"]
+
+
+def test_geocode_rejects_unknown_or_empty(api_client, enduser_token):
+    body = _geocode(api_client, enduser_token, "221B Baker Street", expected_status=422).json()
+    assert body["error"] == "address_not_geocoded"
+    assert body["details"]["query"] == "221B Baker Street"
+    api_client.get("/geocode", token=enduser_token, expected_status=400)
+    _geocode(api_client, enduser_token, "   ", expected_status=400)
+
+
+def test_reverse_geocode(api_client, enduser_token):
+    body = api_client.get(
+        f"/geocode/reverse?lat={DOWNTOWN['lat'] + 0.001}&lng={DOWNTOWN['lng']}", token=enduser_token, expected_status=200
+    ).json()
+    assert body["address"] == DOWNTOWN["address"]
+
+    api_client.get("/geocode/reverse?lat=0&lng=0", token=enduser_token, expected_status=404)
+    api_client.get("/geocode/reverse?lat=abc&lng=0", token=enduser_token, expected_status=400)
+    api_client.get("/geocode/reverse?lat=91&lng=0", token=enduser_token, expected_status=400)
+
+
+def test_create_order_by_address(api_client, order_actions, enduser_token):
+    body = api_client.post(
+        "/orders",
+        token=enduser_token,
+        json_body={"pickup_address": DOWNTOWN["address"], "dropoff_address": "abdali"},
+        expected_status=201,
+    ).json()
+    assert body["pickup"]["lat"] == pytest.approx(DOWNTOWN["lat"])
+    assert body["dropoff"]["lng"] == pytest.approx(ABDALI["lng"])
+
+    details = order_actions.get(body["order_id"], token=enduser_token).json()
+    assert details["pickup"]["address"] == DOWNTOWN["address"]
+    assert details["dropoff"]["address"] == ABDALI["address"]
+
+
+def test_order_details_omit_unknown_place_names(order_actions, enduser_token):
+    order_id = order_actions.create(token=enduser_token, pickup_lat=0, pickup_lng=0, dropoff_lat=1, dropoff_lng=1)
+    details = order_actions.get(order_id, token=enduser_token).json()
+    assert "address" not in details["pickup"]
+    assert "address" not in details["dropoff"]
+
+
+def test_create_order_with_unknown_address(api_client, enduser_token):
+    body = api_client.post(
+        "/orders",
+        token=enduser_token,
+        json_body={"pickup_address": DOWNTOWN["address"], "dropoff_address": "Atlantis"},
+        expected_status=422,
+    ).json()
+    assert body["error"] == "address_not_geocoded"
+
+
+@pytest.mark.parametrize(
+    "payload",
+    [
+        pytest.param(
+            {"pickup_address": "abdali", "pickup_lat": 31.9, "pickup_lng": 35.9, "dropoff_address": "abdali"},
+            id="address-and-coordinates",
+        ),
+        pytest.param({"pickup_address": "", "dropoff_address": "abdali"}, id="empty-address"),
+        pytest.param({"pickup_address": "abdali"}, id="missing-dropoff"),
+    ],
+)
+def test_create_order_rejects_ambiguous_addresses(api_client, enduser_token, payload):
+    api_client.post("/orders", token=enduser_token, json_body=payload, expected_status=400)
+
+
+def test_admin_reroutes_by_address(api_client, admin_token, order_actions, enduser_token):
+    order_id = order_actions.create(token=enduser_token)
+    body = api_client.patch(
+        f"/admin/orders/{order_id}",
+        token=admin_token,
+        json_body={"dropoff_address": "University of Jordan"},
+        expected_status=200,
+    ).json()
+    assert body["dropoff"]["lat"] == pytest.approx(32.0136)
+
+    api_client.patch(
+        f"/admin/orders/{order_id}",
+        token=admin_token,
+        json_body={"dropoff_address": "abdali", "dropoff_lat": 31.9, "dropoff_lng": 35.9},
+        expected_status=400,
+    )
+    api_client.patch(
+        f"/admin/orders/{order_id}", token=admin_token, json_body={"pickup_address": "Atlantis"}, expected_status=422
+    )
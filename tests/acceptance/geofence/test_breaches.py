@@ -0,0 +1,117 @@
+import pytest
+
+from ..support.ws import recv_of_type, send_heartbeat_collect, websocket_connection
+
+pytestmark = pytest.mark.acceptance
+
+ZONE_BOX = [
+    {"lat": 30.04, "lng": 34.98},
+    {"lat": 30.04, "lng": 35.02},
+    {"lat": 30.06, "lng": 35.02},
+    {"lat": 30.06, "lng": 34.98},
+]
+OUTSIDE = {"lat": 30.0, "lng": 35.0}
+INSIDE = {"lat": 30.05, "lng": 35.0}
+
+
+@pytest.fixture
+def no_fly_zone(api_client, admin_token):
+    body = api_client.post(
+        "/admin/no-fly-zones",
+        token=admin_token,
+        json_body={"name": "Breach Test Zone", "boundary": ZONE_BOX},
+        expected_status=201,
+    ).json()
+    yield body
+    api_client.delete(f"/admin/no-fly-zones/{body['no_fly_zone_id']}", token=admin_token)
+
+
+def _breaches(api_client, admin_token, drone_id):
+    return api_client.get(f"/admin/drones/{drone_id}/breaches", token=admin_token, expected_status=200).json()
+
+
+def _heartbeat(base_url, token, point):
+    messages = send_heartbeat_collect(base_url, token, point["lat"], point["lng"])
+    assert messages[-1].get("message") == "ok"
+    return messages[:-1]
+
+
+def test_breach_history_requires_admin(api_client, enduser_token, drone1_token, drone1_id):
+    api_client.get(f"/admin/drones/{drone1_id}/breaches", expected_status=401)
+    api_client.get(f"/admin/drones/{drone1_id}/breaches", token=enduser_token, expected_status=403)
+    api_client.get(f"/admin/drones/{drone1_id}/breaches", token=drone1_token, expected_status=403)
+
+
+def test_breach_history_unknown_drone(api_client, admin_token):
+    api_client.get("/admin/drones/999999/breaches", token=admin_token, expected_status=404)
+    api_client.get("/admin/drones/abc/breaches", token=admin_token, expected_status=400)
+
+
+def test_admin_event_stream_requires_admin(base_url, drone1_token):
+    with pytest.raises(Exception):
+        with websocket_connection(base_url, drone1_token, path="/ws/admin"):
+            pass
+
+
+def test_entering_no_fly_zone_raises_breach(
+    base_url, api_client, admin_token, drone1_token, drone1_id, drone_actions, no_fly_zone
+):
+    drone_actions.ensure_idle(drone1_id, lat=OUTSIDE["lat"], lng=OUTSIDE["lng"])
+    assert _heartbeat(base_url, drone1_token, OUTSIDE) == []
+    before = _breaches(api_client, admin_token, drone1_id)["data"]
+
+    with websocket_connection(base_url, admin_token, path="/ws/admin") as admin_ws:
+        drone_messages = _heartbeat(base_url, drone1_token, INSIDE)
+        event = recv_of_type(admin_ws, "geofence_breach")
+
+    assert event["drone_id"] == drone1_id
+    assert event["kind"] == "no_fly_zone"
+    assert event["zone_id"] == no_fly_zone["no_fly_zone_id"]
+    assert event["zone_name"] == "Breach Test Zone"
+    assert event["lat"] == pytest.approx(INSIDE["lat"])
+
+    # the configured action (hold by default) is relayed to the drone
+    assert [m["type"] for m in drone_messages] == ["geofence_breach"]
+    assert drone_messages[0]["breach_id"] == event["breach_id"]
+    assert drone_messages[0]["action"] == event["action"]
+
+    history = _breaches(api_client, admin_token, drone1_id)["data"]
+    assert len(history) == len(before) + 1
+    assert history[0]["breach_id"] == event["breach_id"]
+
+
+def test_staying_inside_zone_does_not_repeat_breach(
+    base_url, api_client, admin_token, drone1_token, drone1_id, drone_actions, no_fly_zone
+):
+    drone_actions.ensure_idle(drone1_id, lat=OUTSIDE["lat"], lng=OUTSIDE["lng"])
+    _heartbeat(base_url, drone1_token, OUTSIDE)
+    _heartbeat(base_url, drone1_token, INSIDE)
+    count = len(_breaches(api_client, admin_token, drone1_id)["data"])
+
+    assert _heartbeat(base_url, drone1_token, {"lat": 30.051, "lng": 35.001}) == []
+    assert len(_breaches(api_client, admin_token, drone1_id)["data"]) == count
+
+    # leaving and re-entering is a new breach
+    _heartbeat(base_url, drone1_token, OUTSIDE)
+    _heartbeat(base_url, drone1_token, INSIDE)
+    assert len(_breaches(api_client, admin_token, drone1_id)["data"]) == count + 1
+
+
+def test_leaving_service_area_raises_breach(base_url, api_client, admin_token, drone1_token, drone1_id, drone_actions):
+    area = api_client.post(
+        "/admin/service-areas",
+        token=admin_token,
+        json_body={"name": "Breach Test Area", "boundary": ZONE_BOX},
+        expected_status=201,
+    ).json()
+    try:
+        drone_actions.ensure_idle(drone1_id, lat=INSIDE["lat"], lng=INSIDE["lng"])
+        _heartbeat(base_url, drone1_token, INSIDE)
+        messages = _heartbeat(base_url, drone1_token, OUTSIDE)
+    finally:
+        api_client.delete(f"/admin/service-areas/{area['service_area_id']}", token=admin_token)
+
+    assert messages and messages[0]["kind"] == "outside_service_area"
+    history = _breaches(api_client, admin_token, drone1_id)["data"]
+    assert history[0]["kind"] == "outside_service_area"
+    assert "zone_id" not in history[0]
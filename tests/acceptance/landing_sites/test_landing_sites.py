@@ -0,0 +1,81 @@
+import pytest
+
+pytestmark = pytest.mark.acceptance
+
+ROOFTOP = {"name": "Abdali rooftop pad", "lat": 31.9630, "lng": 35.9100}
+
+
+@pytest.fixture
+def landing_site(api_client, admin_token):
+    body = api_client.post("/admin/landing-sites", token=admin_token, json_body=ROOFTOP, expected_status=201).json()
+    yield body
+    api_client.delete(f"/admin/landing-sites/{body['landing_site_id']}", token=admin_token)
+
+
+def test_admin_endpoints_require_admin(api_client, enduser_token, drone1_token):
+    api_client.post("/admin/landing-sites", json_body=ROOFTOP, expected_status=401)
+    api_client.post("/admin/landing-sites", token=enduser_token, json_body=ROOFTOP, expected_status=403)
+    api_client.get("/admin/landing-sites", token=drone1_token, expected_status=403)
+
+
+def test_create_and_list_landing_site(api_client, admin_token, landing_site):
+    assert landing_site["name"] == ROOFTOP["name"]
+    assert landing_site["lat"] == pytest.approx(ROOFTOP["lat"])
+    assert landing_site["lng"] == pytest.approx(ROOFTOP["lng"])
+    assert landing_site["active"] is True
+
+    body = api_client.get("/admin/landing-sites", token=admin_token, expected_status=200).json()
+    assert landing_site["landing_site_id"] in [s["landing_site_id"] for s in body["data"]]
+
+
+@pytest.mark.parametrize(
+    "payload",
+    [
+        pytest.param({"lat": 31.96, "lng": 35.91}, id="no-name"),
+        pytest.param({"name": "  ", "lat": 31.96, "lng": 35.91}, id="blank-name"),
+        pytest.param({"name": "x" * 101, "lat": 31.96, "lng": 35.91}, id="long-name"),
+        pytest.param({"name": "Pad", "lng": 35.91}, id="no-lat"),
+        pytest.param({"name": "Pad", "lat": 95, "lng": 35.91}, id="lat-invalid"),
+        pytest.param({"name": "Pad", "lat": 31.96, "lng": 190}, id="lng-invalid"),
+    ],
+)
+def test_create_rejects_invalid_site(api_client, admin_token, payload):
+    api_client.post("/admin/landing-sites", token=admin_token, json_body=payload, expected_status=400)
+
+
+def test_update_landing_site(api_client, admin_token, landing_site):
+    site_id = landing_site["landing_site_id"]
+    body = api_client.patch(
+        f"/admin/landing-sites/{site_id}",
+        token=admin_token,
+        json_body={"name": "Abdali yard", "lat": 31.9640, "lng": 35.9110, "active": False},
+        expected_status=200,
+    ).json()
+    assert body["name"] == "Abdali yard"
+    assert body["lat"] == pytest.approx(31.9640)
+    assert body["lng"] == pytest.approx(35.9110)
+    assert body["active"] is False
+
+
+@pytest.mark.parametrize(
+    "payload",
+    [
+        pytest.param({}, id="empty"),
+        pytest.param({"lat": 31.97}, id="lat-without-lng"),
+        pytest.param({"name": ""}, id="blank-name"),
+    ],
+)
+def test_update_rejects_invalid_fields(api_client, admin_token, landing_site, payload):
+    body = api_client.patch(
+        f"/admin/landing-sites/{landing_site['landing_site_id']}",
+        token=admin_token,
+        json_body=payload,
+        expected_status=400,
+    ).json()
+    assert body["error"] == "invalid_landing_site"
+
+
+def test_update_and_delete_unknown_site(api_client, admin_token):
+    api_client.patch("/admin/landing-sites/999999", token=admin_token, json_body={"active": False}, expected_status=404)
+    api_client.delete("/admin/landing-sites/999999", token=admin_token, expected_status=404)
+    api_client.patch("/admin/landing-sites/abc", token=admin_token, json_body={"active": False}, expected_status=400)
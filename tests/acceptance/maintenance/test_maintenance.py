@@ -0,0 +1,247 @@
+import pytest
+
+pytestmark = pytest.mark.acceptance
+
+LOCATION = {"lat": 31.9454, "lng": 35.9284}
+
+
+@pytest.fixture
+def idle_drone1(drone_actions, drone1_id):
+    drone_actions.ensure_idle(drone1_id)
+    yield
+    drone_actions.ensure_idle(drone1_id)
+
+
+@pytest.fixture
+def default_schedule(api_client, admin_token, drone1_id):
+    yield
+    api_client.put(
+        f"/admin/drones/{drone1_id}/maintenance-schedule", token=admin_token, json_body={}, expected_status=200
+    )
+
+
+def _open(api_client, admin_token, drone_id, expected_status=201, **body):
+    body.setdefault("issue", "inspection")
+    return api_client.post(
+        f"/admin/drones/{drone_id}/maintenance", token=admin_token, json_body=body, expected_status=expected_status
+    ).json()
+
+
+def _close(api_client, admin_token, record_id, expected_status=200, **body):
+    return api_client.post(
+        f"/admin/maintenance/{record_id}/close",
+        token=admin_token,
+        json_body=body or None,
+        expected_status=expected_status,
+    ).json()
+
+
+def _health(api_client, admin_token, drone_id):
+    return api_client.get(f"/admin/drones/{drone_id}/maintenance", token=admin_token, expected_status=200).json()
+
+
+def test_maintenance_endpoints_require_admin(api_client, enduser_token, drone1_token, drone1_id):
+    api_client.get("/admin/maintenance", expected_status=401)
+    api_client.get("/admin/maintenance", token=enduser_token, expected_status=403)
+    api_client.post(
+        f"/admin/drones/{drone1_id}/maintenance",
+        token=drone1_token,
+        json_body={"issue": "inspection"},
+        expected_status=403,
+    )
+    api_client.get(f"/admin/drones/{drone1_id}/maintenance", token=drone1_token, expected_status=403)
+
+
+def test_open_takes_drone_out_of_service_until_closed(api_client, admin_token, drone1_id, idle_drone1):
+    record = _open(api_client, admin_token, drone1_id, issue="battery", notes="cell imbalance", parts=["battery pack"])
+    assert record["drone_id"] == drone1_id
+    assert record["issue"] == "battery"
+    assert record["notes"] == "cell imbalance"
+    assert record["parts"] == ["battery pack"]
+    assert record["open"] is True
+    assert "closed_at" not in record
+
+    health = _health(api_client, admin_token, drone1_id)
+    assert health["status"] == "maintenance"
+    assert health["open_record"]["record_id"] == record["record_id"]
+
+    conflict = _open(api_client, admin_token, drone1_id, expected_status=409)
+    assert conflict["error"] == "maintenance_already_open"
+
+    updated = api_client.patch(
+        f"/admin/maintenance/{record['record_id']}",
+        token=admin_token,
+        json_body={"parts": ["battery pack", "balance lead"], "technician": "Sami"},
+        expected_status=200,
+    ).json()
+    assert updated["parts"] == ["battery pack", "balance lead"]
+    assert updated["technician"] == "Sami"
+    assert updated["notes"] == "cell imbalance"
+
+    closed = _close(api_client, admin_token, record["record_id"], notes="pack replaced")
+    assert closed["open"] is False
+    assert closed["closed_at"]
+    assert closed["notes"] == "pack replaced"
+
+    health = _health(api_client, admin_token, drone1_id)
+    assert health["status"] == "idle"
+    assert health["last_service_at"]
+    assert health["cycles"] == 0
+    assert "open_record" not in health
+
+    again = _close(api_client, admin_token, record["record_id"], expected_status=409)
+    assert again["error"] == "maintenance_closed"
+
+
+@pytest.mark.parametrize(
+    "body",
+    [
+        {},
+        {"issue": "corrosion"},
+        {"issue": "other", "parts": [""]},
+        {"issue": "other", "technician": "x" * 101},
+    ],
+)
+def test_open_rejects_invalid_record(api_client, admin_token, drone1_id, idle_drone1, body):
+    response = api_client.post(
+        f"/admin/drones/{drone1_id}/maintenance", token=admin_token, json_body=body, expected_status=400
+    ).json()
+    assert response["error"] in ("invalid_request", "invalid_maintenance")
+
+
+def test_update_requires_fields(api_client, admin_token, drone1_id, idle_drone1):
+    record = _open(api_client, admin_token, drone1_id)
+    response = api_client.patch(
+        f"/admin/maintenance/{record['record_id']}", token=admin_token, json_body={}, expected_status=400
+    ).json()
+    assert response["error"] == "invalid_maintenance"
+    _close(api_client, admin_token, record["record_id"])
+
+
+def test_unknown_record_is_not_found(api_client, admin_token):
+    api_client.get("/admin/maintenance/999999999", token=admin_token, expected_status=404)
+    api_client.post("/admin/maintenance/999999999/close", token=admin_token, expected_status=404)
+
+
+def test_list_filters_by_drone_and_open(api_client, admin_token, drone1_id, drone2_id, idle_drone1):
+    record = _open(api_client, admin_token, drone1_id, issue="sensors")
+
+    listed = api_client.get(
+        f"/admin/maintenance?drone_id={drone1_id}&open=true", token=admin_token, expected_status=200
+    ).json()
+    assert [r["record_id"] for r in listed["data"]] == [record["record_id"]]
+    assert listed["meta"]["page"] == 1
+
+    other = api_client.get(f"/admin/maintenance?drone_id={drone2_id}", token=admin_token, expected_status=200).json()
+    assert all(r["drone_id"] == drone2_id for r in other["data"])
+
+    _close(api_client, admin_token, record["record_id"])
+
+    closed = api_client.get(
+        f"/admin/maintenance?drone_id={drone1_id}&open=false", token=admin_token, expected_status=200
+    ).json()
+    assert record["record_id"] in [r["record_id"] for r in closed["data"]]
+    assert all(r["open"] is False for r in closed["data"])
+
+    api_client.get("/admin/maintenance?open=maybe", token=admin_token, expected_status=400)
+
+
+def test_drone_in_maintenance_cannot_report_itself_fixed(
+    api_client, admin_token, drone1_token, drone1_id, drone_actions, idle_drone1
+):
+    record = _open(api_client, admin_token, drone1_id)
+
+    body = drone_actions.mark_fixed(
+        drone1_id, token=drone1_token, via_admin=False, expected_status=409, **LOCATION
+    ).json()
+    assert body["error"] == "drone_status_transition_not_allowed"
+
+    health = _health(api_client, admin_token, drone1_id)
+    assert health["status"] == "maintenance"
+    assert health["open_record"]["record_id"] == record["record_id"]
+
+    _close(api_client, admin_token, record["record_id"])
+
+
+def test_breakdown_is_recorded_and_closed_by_fix(
+    api_client, admin_token, drone1_token, drone1_id, drone_actions, idle_drone1
+):
+    drone_actions.mark_broken(drone1_id, token=drone1_token, **LOCATION)
+
+    health = _health(api_client, admin_token, drone1_id)
+    assert health["status"] == "broken"
+    record = health["open_record"]
+    assert record["issue"] == "breakdown"
+
+    conflict = _close(api_client, admin_token, record["record_id"], expected_status=409)
+    assert conflict["error"] == "maintenance_drone_broken"
+
+    api_client.post(
+        f"/admin/drones/{drone1_id}/fixed",
+        token=admin_token,
+        json_body={**LOCATION, "notes": "motor replaced", "parts": ["motor"], "technician": "Sami"},
+        expected_status=200,
+    )
+
+    closed = api_client.get(
+        f"/admin/maintenance/{record['record_id']}", token=admin_token, expected_status=200
+    ).json()
+    assert closed["open"] is False
+    assert closed["notes"] == "motor replaced"
+    assert closed["parts"] == ["motor"]
+    assert closed["technician"] == "Sami"
+    assert _health(api_client, admin_token, drone1_id)["status"] == "idle"
+
+
+@pytest.mark.parametrize(
+    "body",
+    [
+        {"flight_hours": -1},
+        {"cycles": -1},
+        {"flight_hours": 10001},
+    ],
+)
+def test_schedule_rejects_invalid_interval(api_client, admin_token, drone1_id, body):
+    response = api_client.put(
+        f"/admin/drones/{drone1_id}/maintenance-schedule", token=admin_token, json_body=body, expected_status=400
+    ).json()
+    assert response["error"] == "invalid_maintenance"
+
+
+def test_schedule_takes_drone_out_of_service_after_trips(
+    api_client,
+    admin_token,
+    enduser_token,
+    drone1_token,
+    drone1_id,
+    order_actions,
+    idle_drone1,
+    default_schedule,
+):
+    # a closed record counts as the last service, so usage starts from zero
+    _close(api_client, admin_token, _open(api_client, admin_token, drone1_id)["record_id"])
+
+    health = api_client.put(
+        f"/admin/drones/{drone1_id}/maintenance-schedule",
+        token=admin_token,
+        json_body={"cycles": 1},
+        expected_status=200,
+    ).json()
+    assert health["custom_schedule"] is True
+    assert health["service_cycles"] == 1
+    assert health["cycles"] == 0
+    assert health["due"] is False
+
+    order_id = order_actions.create(token=enduser_token)
+    order_actions.reserve(order_id, token=drone1_token)
+    order_actions.pickup(order_id, token=drone1_token)
+    order_actions.complete_delivery(order_id, token=drone1_token, enduser_token=enduser_token)
+
+    health = _health(api_client, admin_token, drone1_id)
+    assert health["status"] == "maintenance"
+    assert health["cycles"] == 1
+    assert health["open_record"]["issue"] == "scheduled"
+    assert health["due"] is True
+
+    _close(api_client, admin_token, health["open_record"]["record_id"])
+    assert _health(api_client, admin_token, drone1_id)["status"] == "idle"
@@ -0,0 +1,162 @@
+import concurrent.futures
+import time
+from datetime import datetime, timedelta, timezone
+
+import pytest
+
+from ..support.ws import send_heartbeat, wait_for_assignment
+
+pytestmark = pytest.mark.acceptance
+
+# Box between the drone's parking spot and the pickup used below
+ZONE_BOX = [
+    {"lat": 30.04, "lng": 34.98},
+    {"lat": 30.04, "lng": 35.02},
+    {"lat": 30.06, "lng": 35.02},
+    {"lat": 30.06, "lng": 34.98},
+]
+INSIDE = {"lat": 30.05, "lng": 35.0}
+DRONE_SPOT = {"lat": 30.0, "lng": 35.0}
+PICKUP = {"lat": 30.1, "lng": 35.0}
+
+
+def _iso(delta):
+    return (datetime.now(timezone.utc) + delta).strftime("%Y-%m-%dT%H:%M:%SZ")
+
+
+@pytest.fixture
+def zone_factory(api_client, admin_token):
+    created = []
+
+    def _create(**extra):
+        payload = {"name": "Test Stadium", "boundary": ZONE_BOX, **extra}
+        body = api_client.post("/admin/no-fly-zones", token=admin_token, json_body=payload, expected_status=201).json()
+        created.append(body["no_fly_zone_id"])
+        return body
+
+    yield _create
+    for zone_id in created:
+        api_client.delete(f"/admin/no-fly-zones/{zone_id}", token=admin_token)
+
+
+def _inside(point):
+    lats = [v["lat"] for v in ZONE_BOX]
+    lngs = [v["lng"] for v in ZONE_BOX]
+    return min(lats) < point["lat"] < max(lats) and min(lngs) < point["lng"] < max(lngs)
+
+
+def test_admin_endpoints_require_admin(api_client, enduser_token, drone1_token):
+    payload = {"name": "Nope", "boundary": ZONE_BOX}
+    api_client.post("/admin/no-fly-zones", json_body=payload, expected_status=401)
+    api_client.post("/admin/no-fly-zones", token=enduser_token, json_body=payload, expected_status=403)
+    api_client.get("/admin/no-fly-zones", token=drone1_token, expected_status=403)
+
+
+def test_create_list_and_update_zone(api_client, admin_token, zone_factory):
+    zone = zone_factory()
+    assert zone["name"] == "Test Stadium"
+    assert zone["active"] is True
+    assert "starts_at" not in zone and "ends_at" not in zone
+
+    listed = api_client.get("/admin/no-fly-zones", token=admin_token, expected_status=200).json()
+    assert zone["no_fly_zone_id"] in [z["no_fly_zone_id"] for z in listed["data"]]
+
+    updated = api_client.patch(
+        f"/admin/no-fly-zones/{zone['no_fly_zone_id']}",
+        token=admin_token,
+        json_body={"starts_at": _iso(timedelta(hours=1)), "ends_at": _iso(timedelta(hours=3))},
+        expected_status=200,
+    ).json()
+    assert updated["active"] is False
+    assert updated["starts_at"] and updated["ends_at"]
+
+    cleared = api_client.patch(
+        f"/admin/no-fly-zones/{zone['no_fly_zone_id']}",
+        token=admin_token,
+        json_body={"starts_at": "", "ends_at": ""},
+        expected_status=200,
+    ).json()
+    assert cleared["active"] is True
+    assert "starts_at" not in cleared
+
+
+@pytest.mark.parametrize(
+    "payload",
+    [
+        {"name": "Bad", "boundary": ZONE_BOX[:2]},
+        {"name": " ", "boundary": ZONE_BOX},
+        {"boundary": ZONE_BOX},
+        {"name": "Bad", "boundary": ZONE_BOX, "starts_at": "tomorrow"},
+        {"name": "Bad", "boundary": ZONE_BOX, "starts_at": "2030-01-02T00:00:00Z", "ends_at": "2030-01-01T00:00:00Z"},
+    ],
+)
+def test_create_rejects_invalid_zone(api_client, admin_token, payload):
+    api_client.post("/admin/no-fly-zones", token=admin_token, json_body=payload, expected_status=400)
+
+
+def test_update_and_delete_unknown_zone(api_client, admin_token):
+    api_client.patch("/admin/no-fly-zones/999999", token=admin_token, json_body={"name": "x"}, expected_status=404)
+    api_client.delete("/admin/no-fly-zones/999999", token=admin_token, expected_status=404)
+
+
+@pytest.mark.parametrize("point", ["pickup", "dropoff"])
+def test_order_endpoint_inside_zone_is_rejected(order_actions, api_client, enduser_token, zone_factory, point):
+    zone_factory()
+    payload = {"pickup_lat": PICKUP["lat"], "pickup_lng": PICKUP["lng"], "dropoff_lat": 30.2, "dropoff_lng": 35.0}
+    payload[f"{point}_lat"] = INSIDE["lat"]
+    payload[f"{point}_lng"] = INSIDE["lng"]
+
+    body = api_client.post("/orders", token=enduser_token, json_body=payload, expected_status=422).json()
+    assert body["error"] == "inside_no_fly_zone"
+    assert body["details"]["point"] == point
+    assert body["details"]["zone"] == "Test Stadium"
+
+
+def test_zone_outside_its_window_does_not_block(api_client, enduser_token, zone_factory):
+    zone_factory(starts_at=_iso(timedelta(hours=1)))
+    zone_factory(starts_at=_iso(timedelta(hours=-3)), ends_at=_iso(timedelta(hours=-1)))
+    payload = {"pickup_lat": INSIDE["lat"], "pickup_lng": INSIDE["lng"], "dropoff_lat": 30.2, "dropoff_lng": 35.0}
+    api_client.post("/orders", token=enduser_token, json_body=payload, expected_status=201)
+
+
+def test_admin_reroute_into_zone_is_rejected(api_client, order_actions, admin_token, enduser_token, zone_factory):
+    order_id = order_actions.create(token=enduser_token, pickup_lat=60.0, pickup_lng=60.0, dropoff_lat=60.1, dropoff_lng=60.1)
+    zone_factory()
+    body = api_client.patch(
+        f"/admin/orders/{order_id}",
+        token=admin_token,
+        json_body={"dropoff_lat": INSIDE["lat"], "dropoff_lng": INSIDE["lng"]},
+        expected_status=422,
+    ).json()
+    assert body["error"] == "inside_no_fly_zone"
+
+
+def test_assignment_waypoints_avoid_zone(
+    base_url, order_actions, enduser_token, drone1_token, drone1_id, drone_actions, zone_factory
+):
+    zone_factory()
+    drone_actions.ensure_idle(drone1_id, lat=DRONE_SPOT["lat"], lng=DRONE_SPOT["lng"])
+    assert send_heartbeat(base_url, drone1_token, DRONE_SPOT["lat"], DRONE_SPOT["lng"]).get("message") == "ok"
+
+    with concurrent.futures.ThreadPoolExecutor() as executor:
+        future = executor.submit(wait_for_assignment, base_url, drone1_token, 15)
+        time.sleep(1)
+        order_id = order_actions.create(
+            token=enduser_token,
+            pickup_lat=PICKUP["lat"],
+            pickup_lng=PICKUP["lng"],
+            dropoff_lat=30.2,
+            dropoff_lng=35.0,
+        )
+        assignment = future.result(timeout=20)
+
+    assert assignment["order_id"] == order_id
+    waypoints = assignment["waypoints"]
+    assert waypoints[0] == pytest.approx(DRONE_SPOT)
+    assert waypoints[-1] == pytest.approx({"lat": 30.2, "lng": 35.0})
+    # the straight drone -> pickup -> dropoff line would be 3 points
+    assert len(waypoints) > 3
+    assert not any(_inside(p) for p in waypoints)
+
+    order_actions.reserve(order_id, token=drone1_token)
+    order_actions.fail(order_id, token=drone1_token)
@@ -43,7 +43,7 @@ def test_deliver_requires_assigned_drone(order_actions, enduser_token, drone1_to
 def test_deliver_happy_path(order_actions, enduser_token, drone1_token, drone_actions, drone1_id):
     drone_actions.ensure_idle(drone1_id)
     order_id = _picked_up_order(order_actions, enduser_token, drone1_token)
-    result = order_actions.deliver(order_id, token=drone1_token, expected_status=200).json()
+    result = order_actions.complete_delivery(order_id, token=drone1_token, enduser_token=enduser_token).json()
     assert result["status"] == "delivered"
     order_actions.deliver(order_id, token=drone1_token, expected_status=409)
 
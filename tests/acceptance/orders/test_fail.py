@@ -49,6 +49,8 @@ def test_fail_from_picked_up(order_actions, enduser_token, drone1_token, drone_a
     drone_actions.ensure_idle(drone1_id)
     order_id = _picked_up_order(order_actions, enduser_token, drone1_token)
     body = order_actions.fail(order_id, token=drone1_token, expected_status=200).json()
+    assert body["status"] == "returning"
+    body = order_actions.fail(order_id, token=drone1_token, expected_status=200).json()
     assert body["status"] == "failed"
     order_actions.fail(order_id, token=drone1_token, expected_status=409)
 
@@ -64,7 +66,7 @@ def test_fail_status_transitions(order_actions, enduser_token, drone1_token):
     order_actions.fail(pending_order, token=drone1_token, expected_status=404)
 
     delivered_order = _picked_up_order(order_actions, enduser_token, drone1_token)
-    order_actions.deliver(delivered_order, token=drone1_token)
+    order_actions.complete_delivery(delivered_order, token=drone1_token, enduser_token=enduser_token)
     order_actions.fail(delivered_order, token=drone1_token, expected_status=409)
 
     canceled_order = order_actions.create(token=enduser_token)
@@ -33,9 +33,10 @@ def test_get_pending_order_fields(api_client, order_factory, enduser_token):
     assert not body.get("assigned_drone_id")
     assert body.get("drone_location") in (None, {})
     assert body.get("eta_minutes") is None
+    assert len(body["delivery_pin"]) == 6
 
 
-def test_get_order_lifecycle_updates_fields(api_client, order_factory, enduser_token, drone_token):
+def test_get_order_lifecycle_updates_fields(api_client, order_factory, order_actions, enduser_token, drone_token):
     order_id = order_factory()
 
     # Reserve the order
@@ -54,6 +55,7 @@ def test_get_order_lifecycle_updates_fields(api_client, order_factory, enduser_t
     assert picked_up.get("eta_minutes") is not None
 
     # Deliver the order
-    api_client.post(f"/orders/{order_id}/deliver", token=drone_token, expected_status=200)
+    order_actions.complete_delivery(order_id, token=drone_token, enduser_token=enduser_token)
     delivered = api_client.get(f"/orders/{order_id}", token=enduser_token, expected_status=200).json()
     assert delivered["status"] == "delivered"
+    assert "delivery_pin" not in delivered
@@ -0,0 +1,125 @@
+from datetime import datetime, timedelta, timezone
+from urllib.parse import urlencode
+
+import pytest
+
+pytestmark = pytest.mark.acceptance
+
+
+@pytest.fixture
+def idle_drone1(drone_actions, drone1_id):
+    drone_actions.ensure_idle(drone1_id)
+    yield
+    drone_actions.ensure_idle(drone1_id)
+
+
+def _list(order_actions, token, **params):
+    return order_actions.list_mine(token=token, query=urlencode(params)).json()
+
+
+def _order_ids(response):
+    return [item["order_id"] for item in response["data"]]
+
+
+def test_order_history_requires_enduser(api_client, admin_token, drone1_token):
+    api_client.get("/orders", expected_status=401)
+    api_client.get("/orders", token=drone1_token, expected_status=403)
+    api_client.get("/orders", token=admin_token, expected_status=403)
+
+
+def test_order_history_scoped_to_caller(order_actions, enduser_token, enduser2_token):
+    mine = order_actions.create(token=enduser_token)
+    theirs = order_actions.create(token=enduser2_token)
+
+    response = _list(order_actions, enduser_token, page_size=100)
+    ids = _order_ids(response)
+    assert mine in ids
+    assert theirs not in ids
+
+
+def test_order_history_ignores_enduser_override(order_actions, enduser_token, enduser2_token, enduser2_id):
+    theirs = order_actions.create(token=enduser2_token)
+    response = _list(order_actions, enduser_token, enduser_id=enduser2_id, page_size=100)
+    assert theirs not in _order_ids(response)
+
+
+def test_order_history_newest_first(order_actions, enduser_token):
+    first = order_actions.create(token=enduser_token)
+    second = order_actions.create(token=enduser_token)
+    ids = _order_ids(_list(order_actions, enduser_token, page_size=2))
+    assert ids == [second, first]
+
+
+def test_order_history_pagination(order_actions, enduser_token):
+    for _ in range(2):
+        order_actions.create(token=enduser_token)
+
+    page_one = _list(order_actions, enduser_token, page_size=1)
+    assert len(page_one["data"]) == 1
+    assert page_one["meta"]["has_next"] is True
+
+    page_two = _list(order_actions, enduser_token, page=2, page_size=1)
+    assert page_two["meta"]["page"] == 2
+    assert _order_ids(page_two) != _order_ids(page_one)
+
+    order_actions.list_mine(token=enduser_token, query="page=abc", expected_status=400)
+
+
+def test_order_history_filter_by_status(order_actions, enduser_token):
+    pending = order_actions.create(token=enduser_token)
+    canceled = order_actions.create(token=enduser_token)
+    order_actions.cancel(canceled, token=enduser_token)
+
+    response = _list(order_actions, enduser_token, status="canceled", page_size=100)
+    ids = _order_ids(response)
+    assert canceled in ids
+    assert pending not in ids
+    assert all(item["status"] == "canceled" for item in response["data"])
+
+    order_actions.list_mine(token=enduser_token, query="status=invalid", expected_status=400)
+
+
+def test_order_history_filter_by_date_range(order_actions, enduser_token):
+    order_id = order_actions.create(token=enduser_token)
+    now = datetime.now(timezone.utc)
+
+    today = _list(order_actions, enduser_token, to=now.strftime("%Y-%m-%d"), page_size=100)
+    assert order_id in _order_ids(today)
+
+    window = _list(
+        order_actions,
+        enduser_token,
+        **{
+            "from": (now - timedelta(hours=1)).isoformat(timespec="seconds"),
+            "to": (now + timedelta(hours=1)).isoformat(timespec="seconds"),
+        },
+        page_size=100,
+    )
+    assert order_id in _order_ids(window)
+
+    future = _list(order_actions, enduser_token, **{"from": (now + timedelta(days=1)).strftime("%Y-%m-%d")})
+    assert future["data"] == []
+
+    past = _list(order_actions, enduser_token, to=(now - timedelta(days=1)).strftime("%Y-%m-%d"), page_size=100)
+    assert order_id not in _order_ids(past)
+
+
+def test_order_history_invalid_date_range(order_actions, enduser_token):
+    response = order_actions.list_mine(token=enduser_token, query="from=2025-02-01&to=2025-01-01", expected_status=400)
+    assert response.json()["error"] == "invalid_date_range"
+    order_actions.list_mine(token=enduser_token, query="from=yesterday", expected_status=400)
+    order_actions.list_mine(token=enduser_token, query="to=2025-13-01", expected_status=400)
+
+
+def test_order_history_tracks_active_orders(order_actions, enduser_token, drone1_token, idle_drone1):
+    active = order_actions.create(token=enduser_token)
+    canceled = order_actions.create(token=enduser_token)
+    order_actions.cancel(canceled, token=enduser_token)
+    order_actions.reserve(active, token=drone1_token)
+
+    items = {item["order_id"]: item for item in _list(order_actions, enduser_token, page_size=100)["data"]}
+    assert isinstance(items[active].get("drone_location"), dict)
+    assert items[active].get("eta_minutes") is not None
+    assert items[canceled].get("drone_location") is None
+
+    order_actions.fail(active, token=drone1_token)
@@ -0,0 +1,155 @@
+import pytest
+
+pytestmark = pytest.mark.acceptance
+
+ORDER_A = {"pickup_lat": 31.9454, "pickup_lng": 35.9284, "dropoff_lat": 31.9632, "dropoff_lng": 35.9106}
+ORDER_B = {"pickup_lat": 31.9460, "pickup_lng": 35.9290, "dropoff_lat": 31.9700, "dropoff_lng": 35.9000}
+
+
+@pytest.fixture
+def two_seat_drone(drone_actions, drone1_id):
+    drone_actions.ensure_idle(drone1_id)
+    drone_actions.set_capacity(drone1_id, 2)
+    yield drone1_id
+    drone_actions.ensure_idle(drone1_id)
+    drone_actions.set_capacity(drone1_id, 1)
+
+
+def _drone_entry(drone_actions, drone_id):
+    body = drone_actions.list_drones(query="page_size=100").json()
+    return next(item for item in body["data"] if item["drone_id"] == drone_id)
+
+
+def _stop_index(stops, order_id, kind):
+    return next(i for i, stop in enumerate(stops) if stop["order_id"] == order_id and stop["kind"] == kind)
+
+
+@pytest.mark.parametrize("capacity", [0, -1, 9])
+def test_capacity_bounds(drone_actions, drone1_id, capacity):
+    drone_actions.set_capacity(drone1_id, capacity, expected_status=400)
+
+
+def test_capacity_requires_admin(api_client, drone1_id, drone1_token, enduser_token):
+    payload = {"capacity": 2}
+    api_client.patch(f"/admin/drones/{drone1_id}", json_body=payload, expected_status=401)
+    api_client.patch(f"/admin/drones/{drone1_id}", token=drone1_token, json_body=payload, expected_status=403)
+    api_client.patch(f"/admin/drones/{drone1_id}", token=enduser_token, json_body=payload, expected_status=403)
+
+
+def test_single_capacity_drone_rejects_second_order(
+    drone_actions, order_actions, enduser_token, drone1_token, drone1_id
+):
+    drone_actions.ensure_idle(drone1_id)
+    first = order_actions.create(token=enduser_token)
+    second = order_actions.create(token=enduser_token)
+    order_actions.reserve(first, token=drone1_token)
+    response = order_actions.reserve(second, token=drone1_token, expected_status=409)
+    assert response.json()["error"] == "drone_at_capacity"
+    order_actions.fail(first, token=drone1_token)
+
+
+def test_drone_batches_orders_into_one_trip(
+    drone_actions, order_actions, enduser_token, drone1_token, two_seat_drone
+):
+    first = order_actions.create(token=enduser_token, **ORDER_A)
+    second = order_actions.create(token=enduser_token, **ORDER_B)
+    order_actions.reserve(first, token=drone1_token)
+    order_actions.reserve(second, token=drone1_token)
+
+    entry = _drone_entry(drone_actions, two_seat_drone)
+    assert entry["capacity"] == 2
+    assert entry["active_orders"] == 2
+    assert entry["current_trip_id"] is not None
+
+    trip = drone_actions.get_trip(two_seat_drone, token=drone1_token).json()
+    assert trip["trip_id"] == entry["current_trip_id"]
+    assert len(trip["stops"]) == 4
+    for order_id in (first, second):
+        assert _stop_index(trip["stops"], order_id, "pickup") < _stop_index(trip["stops"], order_id, "dropoff")
+    assert trip["next_stop"]["kind"] == "pickup"
+    assert entry["current_order_id"] == trip["next_stop"]["order_id"]
+
+    order_actions.reserve(order_actions.create(token=enduser_token), token=drone1_token, expected_status=409)
+
+
+def test_trip_visible_to_owner_and_admin_only(api_client, drone_actions, drone2_token, enduser_token, two_seat_drone):
+    drone_actions.get_trip(two_seat_drone, token=drone2_token, expected_status=403)
+    api_client.get(f"/drones/{two_seat_drone}/trip", token=enduser_token, expected_status=403)
+    drone_actions.get_trip(two_seat_drone, expected_status=404)
+
+
+def test_order_details_report_per_leg_etas(
+    order_actions, enduser_token, drone1_token, two_seat_drone
+):
+    first = order_actions.create(token=enduser_token, **ORDER_A)
+    second = order_actions.create(token=enduser_token, **ORDER_B)
+    order_actions.reserve(first, token=drone1_token)
+    order_actions.reserve(second, token=drone1_token)
+
+    for order_id in (first, second):
+        body = order_actions.get(order_id, token=enduser_token).json()
+        kinds = [leg["kind"] for leg in body["legs"]]
+        assert kinds == ["pickup", "dropoff"]
+        assert body["legs"][0]["eta_minutes"] <= body["legs"][1]["eta_minutes"]
+        assert body["eta_minutes"] == body["legs"][-1]["eta_minutes"]
+
+    stops_before = [
+        order_actions.get(order_id, token=enduser_token).json()["legs"][-1]["stops_before"]
+        for order_id in (first, second)
+    ]
+    assert max(stops_before) >= 1
+
+
+def test_drone_stays_on_trip_until_last_dropoff(
+    drone_actions, order_actions, enduser_token, drone1_token, two_seat_drone
+):
+    first = order_actions.create(token=enduser_token, **ORDER_A)
+    second = order_actions.create(token=enduser_token, **ORDER_B)
+    for order_id in (first, second):
+        order_actions.reserve(order_id, token=drone1_token)
+    for order_id in (first, second):
+        assert order_actions.pickup(order_id, token=drone1_token).json()["status"] == "picked_up"
+
+    order_actions.complete_delivery(first, token=drone1_token, enduser_token=enduser_token)
+    entry = _drone_entry(drone_actions, two_seat_drone)
+    assert entry["status"] == "delivering"
+    assert entry["active_orders"] == 1
+    assert entry["current_order_id"] == second
+
+    order_actions.complete_delivery(second, token=drone1_token, enduser_token=enduser_token)
+    entry = _drone_entry(drone_actions, two_seat_drone)
+    assert entry["status"] == "idle"
+    assert entry["active_orders"] == 0
+    assert entry.get("current_trip_id") is None
+    drone_actions.get_trip(two_seat_drone, expected_status=404)
+
+
+def test_failed_order_leaves_rest_of_trip(
+    drone_actions, order_actions, enduser_token, drone1_token, two_seat_drone
+):
+    first = order_actions.create(token=enduser_token, **ORDER_A)
+    second = order_actions.create(token=enduser_token, **ORDER_B)
+    for order_id in (first, second):
+        order_actions.reserve(order_id, token=drone1_token)
+
+    order_actions.fail(first, token=drone1_token)
+    trip = drone_actions.get_trip(two_seat_drone).json()
+    assert {stop["order_id"] for stop in trip["stops"]} == {second}
+    assert _drone_entry(drone_actions, two_seat_drone)["status"] == "reserved"
+
+
+def test_broken_drone_hands_off_whole_trip(
+    drone_actions, order_actions, enduser_token, drone1_token, two_seat_drone
+):
+    first = order_actions.create(token=enduser_token, **ORDER_A)
+    second = order_actions.create(token=enduser_token, **ORDER_B)
+    for order_id in (first, second):
+        order_actions.reserve(order_id, token=drone1_token)
+    order_actions.pickup(first, token=drone1_token)
+
+    body = drone_actions.mark_broken(two_seat_drone, lat=31.95, lng=35.92, token=drone1_token).json()
+    assert sorted(body["handoff_order_ids"]) == sorted([first, second])
+    assert body["assignment_pending"] is True
+    assert order_actions.get(first, token=enduser_token).json()["status"] == "handoff_pending"
+    assert order_actions.get(second, token=enduser_token).json()["status"] == "pending"
+    drone_actions.get_trip(two_seat_drone, expected_status=404)
@@ -53,7 +53,7 @@ def test_pickup_status_transitions(order_actions, enduser_token, drone1_token):
 
     delivered_order = _reserve_order(order_actions, enduser_token, drone1_token)
     order_actions.pickup(delivered_order, token=drone1_token)
-    order_actions.deliver(delivered_order, token=drone1_token)
+    order_actions.complete_delivery(delivered_order, token=drone1_token, enduser_token=enduser_token)
     order_actions.pickup(delivered_order, token=drone1_token, expected_status=409)
 
     canceled_order = order_actions.create(token=enduser_token)
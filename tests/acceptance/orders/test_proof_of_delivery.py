@@ -0,0 +1,139 @@
+import hashlib
+import hmac
+from datetime import datetime, timedelta, timezone
+
+import pytest
+
+from ..support.ws import send_heartbeat
+
+pytestmark = pytest.mark.acceptance
+
+EVIDENCE_SECRET = b"dev-evidence-secret"
+DROPOFF_LAT = 31.9632
+DROPOFF_LNG = 35.9106
+MAX_PIN_ATTEMPTS = 5
+
+
+@pytest.fixture(autouse=True)
+def _reset_drones(reset_drones):
+    return
+
+
+def _picked_up_order(order_actions, enduser_token, drone_token):
+    order_id = order_actions.create(token=enduser_token, dropoff_lat=DROPOFF_LAT, dropoff_lng=DROPOFF_LNG)
+    order_actions.reserve(order_id, token=drone_token)
+    order_actions.pickup(order_id, token=drone_token)
+    return order_id
+
+
+def _drone_key(drone_id, secret=EVIDENCE_SECRET):
+    return hmac.new(secret, str(drone_id).encode(), hashlib.sha256).digest()
+
+
+def _signed_evidence(order_id, drone_id, *, lat=DROPOFF_LAT, lng=DROPOFF_LNG, captured_at=None, secret=EVIDENCE_SECRET):
+    captured = (captured_at or datetime.now(timezone.utc)).replace(microsecond=0)
+    stamp = captured.strftime("%Y-%m-%dT%H:%M:%SZ")
+    photo_hash = hashlib.sha256(f"photo-{order_id}".encode()).hexdigest()
+    payload = f"{order_id}|{photo_hash}|{lat:.6f}|{lng:.6f}|{stamp}"
+    signature = hmac.new(_drone_key(drone_id, secret), payload.encode(), hashlib.sha256).hexdigest()
+    return {"photo_hash": photo_hash, "lat": lat, "lng": lng, "captured_at": stamp, "signature": signature}
+
+
+def test_pin_visible_to_enduser_only_while_active(api_client, order_actions, enduser_token, admin_token):
+    created = api_client.post(
+        "/orders",
+        token=enduser_token,
+        json_body={"pickup_lat": 31.9454, "pickup_lng": 35.9284, "dropoff_lat": DROPOFF_LAT, "dropoff_lng": DROPOFF_LNG},
+        expected_status=201,
+    ).json()
+    assert len(created["delivery_pin"]) == 6 and created["delivery_pin"].isdigit()
+    fetched = order_actions.get(created["order_id"], token=enduser_token).json()
+    assert fetched["delivery_pin"] == created["delivery_pin"]
+    order_actions.cancel(created["order_id"], token=enduser_token)
+    canceled = order_actions.get(created["order_id"], token=enduser_token).json()
+    assert "delivery_pin" not in canceled
+
+
+def test_deliver_requires_proof(order_actions, enduser_token, drone1_token, base_url):
+    order_id = _picked_up_order(order_actions, enduser_token, drone1_token)
+    send_heartbeat(base_url, drone1_token, DROPOFF_LAT, DROPOFF_LNG)
+    response = order_actions.deliver(order_id, token=drone1_token, expected_status=400)
+    assert response.json()["error"] == "delivery_proof_required"
+    order_actions.fail(order_id, token=drone1_token)
+
+
+def test_deliver_rejects_wrong_pin(order_actions, enduser_token, drone1_token, base_url):
+    order_id = _picked_up_order(order_actions, enduser_token, drone1_token)
+    pin = order_actions.get(order_id, token=enduser_token).json()["delivery_pin"]
+    wrong = "000000" if pin != "000000" else "111111"
+    send_heartbeat(base_url, drone1_token, DROPOFF_LAT, DROPOFF_LNG)
+    response = order_actions.deliver(order_id, token=drone1_token, pin=wrong, expected_status=403)
+    assert response.json()["error"] == "invalid_delivery_pin"
+    order = order_actions.get(order_id, token=enduser_token).json()
+    assert order["status"] == "picked_up"
+    order_actions.deliver(order_id, token=drone1_token, pin=pin, expected_status=200)
+
+
+def test_deliver_rejects_drone_away_from_dropoff(order_actions, enduser_token, drone1_token, base_url):
+    order_id = _picked_up_order(order_actions, enduser_token, drone1_token)
+    pin = order_actions.get(order_id, token=enduser_token).json()["delivery_pin"]
+    send_heartbeat(base_url, drone1_token, 31.9454, 35.9284)
+    response = order_actions.deliver(order_id, token=drone1_token, pin=pin, expected_status=409)
+    assert response.json()["error"] == "drone_not_at_dropoff"
+    send_heartbeat(base_url, drone1_token, DROPOFF_LAT + 0.0003, DROPOFF_LNG)
+    body = order_actions.deliver(order_id, token=drone1_token, pin=pin, expected_status=200).json()
+    assert body["status"] == "delivered"
+
+
+def test_deliver_with_signed_evidence(order_actions, enduser_token, drone1_token, drone1_id, base_url):
+    order_id = _picked_up_order(order_actions, enduser_token, drone1_token)
+    send_heartbeat(base_url, drone1_token, DROPOFF_LAT, DROPOFF_LNG)
+    evidence = _signed_evidence(order_id, drone1_id)
+    body = order_actions.deliver(order_id, token=drone1_token, evidence=evidence, expected_status=200).json()
+    assert body["status"] == "delivered"
+
+
+@pytest.mark.parametrize(
+    "tamper",
+    [
+        pytest.param(lambda oid, did, _: _signed_evidence(oid, did, secret=b"wrong-secret"), id="bad-signature"),
+        pytest.param(lambda oid, did, _: _signed_evidence(oid + 1, did), id="other-order"),
+        pytest.param(lambda oid, _, other: _signed_evidence(oid, other), id="other-drone-key"),
+        pytest.param(
+            lambda oid, did, _: _signed_evidence(oid, did, captured_at=datetime.now(timezone.utc) - timedelta(hours=1)),
+            id="stale",
+        ),
+        pytest.param(lambda oid, did, _: _signed_evidence(oid, did, lat=DROPOFF_LAT + 0.05), id="outside-radius"),
+    ],
+)
+def test_deliver_rejects_invalid_evidence(
+    order_actions, enduser_token, drone1_token, drone1_id, drone2_id, base_url, tamper
+):
+    order_id = _picked_up_order(order_actions, enduser_token, drone1_token)
+    send_heartbeat(base_url, drone1_token, DROPOFF_LAT, DROPOFF_LNG)
+    response = order_actions.deliver(
+        order_id,
+        token=drone1_token,
+        evidence=tamper(order_id, drone1_id, drone2_id),
+        expected_status=403,
+    )
+    assert response.json()["error"] == "invalid_delivery_evidence"
+    order_actions.fail(order_id, token=drone1_token)
+
+
+def test_repeated_wrong_pins_lock_pin_proof_and_return_order(order_actions, enduser_token, drone1_token, base_url):
+    order_id = _picked_up_order(order_actions, enduser_token, drone1_token)
+    pin = order_actions.get(order_id, token=enduser_token).json()["delivery_pin"]
+    wrong = "000000" if pin != "000000" else "111111"
+    send_heartbeat(base_url, drone1_token, DROPOFF_LAT, DROPOFF_LNG)
+    for _ in range(MAX_PIN_ATTEMPTS - 1):
+        response = order_actions.deliver(order_id, token=drone1_token, pin=wrong, expected_status=403)
+        assert response.json()["error"] == "invalid_delivery_pin"
+
+    response = order_actions.deliver(order_id, token=drone1_token, pin=wrong, expected_status=403)
+    assert response.json()["error"] == "delivery_pin_locked"
+    assert order_actions.get(order_id, token=enduser_token).json()["status"] == "returning"
+
+    response = order_actions.deliver(order_id, token=drone1_token, pin=pin, expected_status=403)
+    assert response.json()["error"] == "delivery_pin_locked"
+    order_actions.confirm_return(order_id, token=drone1_token)
@@ -51,7 +51,7 @@ def test_reserve_prevents_other_statuses(
     delivered_order = order_actions.create(token=enduser_token)
     order_actions.reserve(delivered_order, token=drone1_token)
     order_actions.pickup(delivered_order, token=drone1_token)
-    order_actions.deliver(delivered_order, token=drone1_token)
+    order_actions.complete_delivery(delivered_order, token=drone1_token, enduser_token=enduser_token)
     order_actions.reserve(delivered_order, token=drone1_token, expected_status=409)
 
     failed_order = order_actions.create(token=enduser_token)
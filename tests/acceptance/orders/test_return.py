@@ -0,0 +1,96 @@
+import pytest
+
+from ..support.ws import send_heartbeat
+
+pytestmark = pytest.mark.acceptance
+
+
+@pytest.fixture(autouse=True)
+def _reset_drones(reset_drones):
+    return
+
+
+def _returning_order(order_actions, enduser_token, drone_token):
+    order_id = order_actions.create(token=enduser_token)
+    order_actions.reserve(order_id, token=drone_token)
+    order_actions.pickup(order_id, token=drone_token)
+    order_actions.fail(order_id, token=drone_token)
+    return order_id
+
+
+def test_return_requires_drone_token(api_client, order_actions, enduser_token, admin_token, drone1_token):
+    order_id = _returning_order(order_actions, enduser_token, drone1_token)
+    api_client.post(f"/orders/{order_id}/return", expected_status=401)
+    api_client.post(f"/orders/{order_id}/return", token=enduser_token, expected_status=403)
+    api_client.post(f"/orders/{order_id}/return", token=admin_token, expected_status=403)
+
+
+@pytest.mark.parametrize("order_id", ["abc", "0", "-1"])
+def test_return_invalid_identifiers(api_client, drone1_token, order_id):
+    api_client.post(f"/orders/{order_id}/return", token=drone1_token, expected_status=400)
+
+
+def test_return_missing_order(api_client, drone1_token):
+    api_client.post("/orders/99999/return", token=drone1_token, expected_status=404)
+
+
+def test_failed_pickup_routes_back_to_origin(order_actions, enduser_token, drone1_token):
+    order_id = order_actions.create(token=enduser_token, pickup_lat=31.9454, pickup_lng=35.9284)
+    order_actions.reserve(order_id, token=drone1_token)
+    order_actions.pickup(order_id, token=drone1_token)
+    body = order_actions.fail(order_id, token=drone1_token).json()
+    assert body["status"] == "returning"
+    assert body["return_lat"] == pytest.approx(31.9454, rel=1e-6)
+    assert body["return_lng"] == pytest.approx(35.9284, rel=1e-6)
+
+
+def test_returning_order_eta_targets_origin(base_url, order_actions, enduser_token, drone1_token):
+    order_id = _returning_order(order_actions, enduser_token, drone1_token)
+    response = send_heartbeat(base_url, drone1_token, lat=31.9454, lng=35.9284)
+    assert response.get("message") == "ok"
+    order = order_actions.get(order_id, token=enduser_token).json()
+    assert order["status"] == "returning"
+    assert order["eta_minutes"] == 1
+
+
+def test_return_confirm_releases_drone(order_actions, enduser_token, drone1_token, drone_actions):
+    order_id = _returning_order(order_actions, enduser_token, drone1_token)
+    body = order_actions.confirm_return(order_id, token=drone1_token).json()
+    assert body["status"] == "returned"
+    order_actions.confirm_return(order_id, token=drone1_token, expected_status=409)
+
+    drones = drone_actions.list_drones().json()["data"]
+    drone = next(item for item in drones if item["drone_id"] == body["assigned_drone_id"])
+    assert drone["status"] == "idle"
+    assert drone.get("current_order_id") is None
+
+
+def test_return_requires_assigned_drone(order_actions, enduser_token, drone1_token, drone2_token):
+    order_id = _returning_order(order_actions, enduser_token, drone1_token)
+    order_actions.confirm_return(order_id, token=drone2_token, expected_status=404)
+
+
+def test_return_requires_returning_status(order_actions, enduser_token, drone1_token):
+    order_id = order_actions.create(token=enduser_token)
+    order_actions.reserve(order_id, token=drone1_token)
+    order_actions.confirm_return(order_id, token=drone1_token, expected_status=409)
+    order_actions.pickup(order_id, token=drone1_token)
+    order_actions.confirm_return(order_id, token=drone1_token, expected_status=409)
+    order_actions.complete_delivery(order_id, token=drone1_token, enduser_token=enduser_token)
+
+
+def test_broken_drone_hands_off_returning_order(
+    order_actions, enduser_token, drone1_token, drone2_token, drone1_id, drone_actions
+):
+    order_id = _returning_order(order_actions, enduser_token, drone1_token)
+    drone_actions.mark_broken(drone1_id, lat=31.95, lng=35.92, token=drone1_token)
+
+    order = order_actions.get(order_id, token=enduser_token).json()
+    assert order["status"] == "handoff_pending"
+    assert order["return_lat"] is not None
+
+    order_actions.reserve(order_id, token=drone2_token)
+    body = order_actions.pickup(order_id, token=drone2_token).json()
+    assert body["status"] == "returning"
+    body = order_actions.confirm_return(order_id, token=drone2_token).json()
+    assert body["status"] == "returned"
@@ -13,7 +13,7 @@ def test_happy_path(order_actions, enduser_token, drone1_token, drone_actions, d
     order_id = order_actions.create(token=enduser_token)
     order_actions.reserve(order_id, token=drone1_token, expected_status=200)
     order_actions.pickup(order_id, token=drone1_token, expected_status=200)
-    body = order_actions.deliver(order_id, token=drone1_token, expected_status=200).json()
+    body = order_actions.complete_delivery(order_id, token=drone1_token, enduser_token=enduser_token).json()
     assert body["status"] == "delivered"
 
 
@@ -22,7 +22,9 @@ def test_failure_after_pickup(order_actions, enduser_token, drone1_token):
     order_actions.reserve(order_id, token=drone1_token)
     order_actions.pickup(order_id, token=drone1_token)
     body = order_actions.fail(order_id, token=drone1_token, expected_status=200).json()
-    assert body["status"] == "failed"
+    assert body["status"] == "returning"
+    body = order_actions.confirm_return(order_id, token=drone1_token, expected_status=200).json()
+    assert body["status"] == "returned"
 
 
 def test_failure_after_reserve(order_actions, enduser_token, drone1_token):
@@ -44,6 +46,6 @@ def test_sequential_orders(order_actions, enduser_token, drone1_token):
 
     order_actions.reserve(first_order, token=drone1_token)
     order_actions.pickup(first_order, token=drone1_token)
-    order_actions.deliver(first_order, token=drone1_token)
+    order_actions.complete_delivery(first_order, token=drone1_token, enduser_token=enduser_token)
 
     order_actions.reserve(second_order, token=drone1_token, expected_status=200)
@@ -0,0 +1,74 @@
+import pytest
+
+pytestmark = pytest.mark.acceptance
+
+PICKUP = {"pickup_lat": 31.9454, "pickup_lng": 35.9284}
+
+
+def _cell_containing(cells, lat, lng):
+    return next(
+        (c for c in cells if c["min_lat"] <= lat < c["max_lat"] and c["min_lng"] <= lng < c["max_lng"]),
+        None,
+    )
+
+
+def test_rebalance_endpoints_require_admin(api_client, enduser_token, drone1_token):
+    api_client.post("/admin/rebalance/run", json_body={"dry_run": True}, expected_status=401)
+    api_client.post("/admin/rebalance/run", token=enduser_token, json_body={"dry_run": True}, expected_status=403)
+    api_client.get("/admin/rebalance/report", token=drone1_token, expected_status=403)
+
+
+def test_dry_run_forecasts_recent_pickups(api_client, admin_token, enduser_token, order_factory, order_actions):
+    order_id = order_factory(**PICKUP)
+    order_actions.cancel(order_id, token=enduser_token)
+
+    plan = api_client.post(
+        "/admin/rebalance/run", token=admin_token, json_body={"dry_run": True, "aggressiveness": 1}, expected_status=200
+    ).json()
+    assert plan["dry_run"] is True
+    assert plan["aggressiveness"] == 1
+    assert 0 <= plan["hour_of_day"] <= 23
+    assert all("command_id" not in m for m in plan["moves"])
+
+    cell = _cell_containing(plan["cells"], PICKUP["pickup_lat"], PICKUP["pickup_lng"])
+    assert cell is not None
+    assert cell["orders_per_hour"] > 0
+    assert cell["target_drones"] >= 0
+    assert sum(c["target_drones"] for c in plan["cells"]) == plan["idle_drones"]
+
+
+@pytest.mark.parametrize("aggressiveness", [-0.1, 1.5])
+def test_run_rejects_invalid_aggressiveness(api_client, admin_token, aggressiveness):
+    body = api_client.post(
+        "/admin/rebalance/run",
+        token=admin_token,
+        json_body={"dry_run": True, "aggressiveness": aggressiveness},
+        expected_status=400,
+    ).json()
+    assert body["error"] == "invalid_rebalance"
+
+
+def test_run_is_recorded_in_report(api_client, admin_token):
+    plan = api_client.post(
+        "/admin/rebalance/run", token=admin_token, json_body={"aggressiveness": 0}, expected_status=200
+    ).json()
+    assert plan["dry_run"] is False
+    assert plan["moves"] == []
+
+    report = api_client.get("/admin/rebalance/report", token=admin_token, expected_status=200).json()
+    assert report["overall"]["forecasts"] >= len(plan["cells"])
+    assert len(report["cells"]) >= (1 if plan["cells"] else 0)
+    for cell in report["cells"]:
+        assert cell["picked_up"] <= cell["orders"]
+
+
+@pytest.mark.parametrize(
+    "query",
+    [
+        "from=not-a-date",
+        "from=2025-02-01T00:00:00Z&to=2025-01-01T00:00:00Z",
+        "from=2025-01-01&to=2025-03-01",
+    ],
+)
+def test_report_rejects_invalid_window(api_client, admin_token, query):
+    api_client.get(f"/admin/rebalance/report?{query}", token=admin_token, expected_status=400)
@@ -0,0 +1,141 @@
+import pytest
+
+pytestmark = pytest.mark.acceptance
+
+# Rough box around central Amman; covers the default order coordinates
+AMMAN_BOX = [
+    {"lat": 31.90, "lng": 35.85},
+    {"lat": 31.90, "lng": 35.96},
+    {"lat": 32.00, "lng": 35.96},
+    {"lat": 32.00, "lng": 35.85},
+]
+INSIDE = {"lat": 31.9454, "lng": 35.9284}
+OUTSIDE = {"lat": 29.5320, "lng": 35.0063}  # Aqaba
+
+
+@pytest.fixture
+def service_area(api_client, admin_token):
+    body = api_client.post(
+        "/admin/service-areas",
+        token=admin_token,
+        json_body={"name": "Central Amman", "boundary": AMMAN_BOX},
+        expected_status=201,
+    ).json()
+    yield body
+    api_client.delete(f"/admin/service-areas/{body['service_area_id']}", token=admin_token)
+
+
+def _order_payload(pickup, dropoff):
+    return {
+        "pickup_lat": pickup["lat"],
+        "pickup_lng": pickup["lng"],
+        "dropoff_lat": dropoff["lat"],
+        "dropoff_lng": dropoff["lng"],
+    }
+
+
+def test_admin_endpoints_require_admin(api_client, enduser_token, drone1_token):
+    payload = {"name": "Nope", "boundary": AMMAN_BOX}
+    api_client.post("/admin/service-areas", json_body=payload, expected_status=401)
+    api_client.post("/admin/service-areas", token=enduser_token, json_body=payload, expected_status=403)
+    api_client.get("/admin/service-areas", token=drone1_token, expected_status=403)
+    api_client.get("/service-areas", expected_status=401)
+
+
+def test_create_and_list_service_area(api_client, admin_token, enduser_token, service_area):
+    assert service_area["name"] == "Central Amman"
+    assert service_area["active"] is True
+    assert len(service_area["boundary"]) == len(AMMAN_BOX)
+    assert service_area["boundary"][0]["lat"] == pytest.approx(AMMAN_BOX[0]["lat"])
+    assert service_area["boundary"][0]["lng"] == pytest.approx(AMMAN_BOX[0]["lng"])
+
+    public = api_client.get("/service-areas", token=enduser_token, expected_status=200).json()
+    assert service_area["service_area_id"] in [a["service_area_id"] for a in public["data"]]
+
+    admin = api_client.get("/admin/service-areas", token=admin_token, expected_status=200).json()
+    assert service_area["service_area_id"] in [a["service_area_id"] for a in admin["data"]]
+
+
+@pytest.mark.parametrize(
+    "boundary",
+    [
+        [],
+        AMMAN_BOX[:2],
+        [{"lat": 31.9, "lng": 35.9}, {"lat": 31.9, "lng": 35.9}, {"lat": 31.9, "lng": 35.9}],
+        [{"lat": 95, "lng": 35.9}, {"lat": 31.9, "lng": 36.0}, {"lat": 32.0, "lng": 36.0}],
+        [{"lat": 31.9}, {"lat": 31.9, "lng": 36.0}, {"lat": 32.0, "lng": 36.0}],
+    ],
+)
+def test_create_rejects_invalid_polygon(api_client, admin_token, boundary):
+    api_client.post(
+        "/admin/service-areas",
+        token=admin_token,
+        json_body={"name": "Broken", "boundary": boundary},
+        expected_status=400,
+    )
+
+
+def test_create_rejects_missing_name(api_client, admin_token):
+    api_client.post("/admin/service-areas", token=admin_token, json_body={"boundary": AMMAN_BOX}, expected_status=400)
+    api_client.post(
+        "/admin/service-areas", token=admin_token, json_body={"name": "  ", "boundary": AMMAN_BOX}, expected_status=400
+    )
+
+
+def test_order_inside_area_is_accepted(api_client, enduser_token, service_area):
+    api_client.post("/orders", token=enduser_token, json_body=_order_payload(INSIDE, INSIDE), expected_status=201)
+
+
+@pytest.mark.parametrize(
+    "pickup,dropoff,point",
+    [(OUTSIDE, INSIDE, "pickup"), (INSIDE, OUTSIDE, "dropoff")],
+)
+def test_order_outside_area_is_rejected(api_client, enduser_token, service_area, pickup, dropoff, point):
+    body = api_client.post(
+        "/orders", token=enduser_token, json_body=_order_payload(pickup, dropoff), expected_status=422
+    ).json()
+    assert body["error"] == "outside_service_area"
+    assert body["details"]["point"] == point
+
+
+def test_admin_reroute_outside_area_is_rejected(api_client, admin_token, enduser_token, service_area):
+    order_id = api_client.post(
+        "/orders", token=enduser_token, json_body=_order_payload(INSIDE, INSIDE), expected_status=201
+    ).json()["order_id"]
+
+    body = api_client.patch(
+        f"/admin/orders/{order_id}",
+        token=admin_token,
+        json_body={"dropoff_lat": OUTSIDE["lat"], "dropoff_lng": OUTSIDE["lng"]},
+        expected_status=422,
+    ).json()
+    assert body["error"] == "outside_service_area"
+
+
+def test_deactivating_last_area_rejects_all_orders(api_client, admin_token, enduser_token, service_area):
+    area_id = service_area["service_area_id"]
+    body = api_client.patch(
+        f"/admin/service-areas/{area_id}", token=admin_token, json_body={"active": False}, expected_status=200
+    ).json()
+    assert body["active"] is False
+
+    public = api_client.get("/service-areas", token=enduser_token, expected_status=200).json()
+    assert area_id not in [a["service_area_id"] for a in public["data"]]
+
+    for pickup, dropoff, point in [(INSIDE, INSIDE, "pickup"), (OUTSIDE, INSIDE, "pickup"), (INSIDE, OUTSIDE, "pickup")]:
+        body = api_client.post(
+            "/orders", token=enduser_token, json_body=_order_payload(pickup, dropoff), expected_status=422
+        ).json()
+        assert body["error"] == "outside_service_area"
+        assert body["details"]["point"] == point
+
+
+def test_deleting_last_area_lifts_coverage(api_client, admin_token, enduser_token, service_area):
+    api_client.delete(f"/admin/service-areas/{service_area['service_area_id']}", token=admin_token, expected_status=204)
+    api_client.post("/orders", token=enduser_token, json_body=_order_payload(OUTSIDE, INSIDE), expected_status=201)
+
+
+def test_update_and_delete_unknown_area(api_client, admin_token):
+    api_client.patch("/admin/service-areas/999999", token=admin_token, json_body={"active": False}, expected_status=404)
+    api_client.delete("/admin/service-areas/999999", token=admin_token, expected_status=404)
+    api_client.patch("/admin/service-areas/abc", token=admin_token, json_body={"active": False}, expected_status=400)
@@ -2,6 +2,7 @@ from dataclasses import dataclass
 from typing import Any, Dict, Optional
 
 from .http import ApiClient, ApiResult
+from .ws import send_heartbeat
 
 
 @dataclass
@@ -33,6 +34,10 @@ class OrderActions:
     def get(self, order_id: int, *, token: str, expected_status: int = 200) -> ApiResult:
         return self.api_client.get(f"/orders/{order_id}", token=token, expected_status=expected_status)
 
+    def list_mine(self, *, token: str, query: str = "", expected_status: int = 200) -> ApiResult:
+        path = f"/orders?{query}" if query else "/orders"
+        return self.api_client.get(path, token=token, expected_status=expected_status)
+
     def cancel(self, order_id: int, *, token: str, expected_status: int = 200) -> ApiResult:
         return self.api_client.post(f"/orders/{order_id}/cancel", token=token, expected_status=expected_status)
 
@@ -42,12 +47,39 @@ class OrderActions:
     def pickup(self, order_id: int, *, token: str, expected_status: int = 200) -> ApiResult:
         return self.api_client.post(f"/orders/{order_id}/pickup", token=token, expected_status=expected_status)
 
-    def deliver(self, order_id: int, *, token: str, expected_status: int = 200) -> ApiResult:
-        return self.api_client.post(f"/orders/{order_id}/deliver", token=token, expected_status=expected_status)
+    def deliver(
+        self,
+        order_id: int,
+        *,
+        token: str,
+        pin: Optional[str] = None,
+        evidence: Optional[Dict[str, Any]] = None,
+        expected_status: int = 200,
+    ) -> ApiResult:
+        payload: Dict[str, Any] = {}
+        if pin is not None:
+            payload["pin"] = pin
+        if evidence is not None:
+            payload["evidence"] = evidence
+        return self.api_client.post(
+            f"/orders/{order_id}/deliver", token=token, json_body=payload or None, expected_status=expected_status
+        )
+
+    def complete_delivery(
+        self, order_id: int, *, token: str, enduser_token: str, expected_status: int = 200
+    ) -> ApiResult:
+        """Fly the drone to the dropoff via heartbeat and deliver with the recipient PIN."""
+        order = self.get(order_id, token=enduser_token).json()
+        dropoff = order["dropoff"]
+        send_heartbeat(self.api_client.base_url, token, dropoff["lat"], dropoff["lng"])
+        return self.deliver(order_id, token=token, pin=order["delivery_pin"], expected_status=expected_status)
 
     def fail(self, order_id: int, *, token: str, expected_status: int = 200) -> ApiResult:
         return self.api_client.post(f"/orders/{order_id}/fail", token=token, expected_status=expected_status)
 
+    def confirm_return(self, order_id: int, *, token: str, expected_status: int = 200) -> ApiResult:
+        return self.api_client.post(f"/orders/{order_id}/return", token=token, expected_status=expected_status)
+
 
 @dataclass
 class DroneActions:
@@ -105,6 +137,22 @@ class DroneActions:
             path = f"{path}{query}"
         return self.api_client.get(path, token=actor_token, expected_status=200)
 
+    def set_capacity(self, drone_id: int, capacity: int, *, expected_status: int = 200) -> ApiResult:
+        return self.api_client.patch(
+            f"/admin/drones/{drone_id}",
+            token=self.admin_token,
+            json_body={"capacity": capacity},
+            expected_status=expected_status,
+        )
+
+    def get_trip(self, drone_id: int, *, token: Optional[str] = None, expected_status: int = 200) -> ApiResult:
+        if token:
+            return self.api_client.get(f"/drones/{drone_id}/trip", token=token, expected_status=expected_status)
+        return self.api_client.get(
+            f"/admin/drones/{drone_id}/trip", token=self.admin_token, expected_status=expected_status
+        )
+
     def ensure_idle(self, drone_id: int, *, lat: float = 0.0, lng: float = 0.0) -> None:
-        """Reset drone to idle via admin fix endpoint."""
+        """Reset drone to idle: only a broken drone can be fixed, so break it first."""
+        self.mark_broken(drone_id, lat=lat, lng=lng, via_admin=True)
         self.mark_fixed(drone_id, lat=lat, lng=lng)
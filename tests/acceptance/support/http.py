@@ -126,3 +126,33 @@ class ApiClient:
             headers=headers,
             expected_status=expected_status,
         )
+
+    def put(
+        self,
+        path: str,
+        *,
+        token: Optional[str] = None,
+        json_body: Optional[Dict[str, Any]] = None,
+        raw_body: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+        expected_status: Optional[int] = None,
+    ) -> ApiResult:
+        return self.request(
+            "PUT",
+            path,
+            token=token,
+            json_body=json_body,
+            raw_body=raw_body,
+            headers=headers,
+            expected_status=expected_status,
+        )
+
+    def delete(
+        self,
+        path: str,
+        *,
+        token: Optional[str] = None,
+        headers: Optional[Dict[str, str]] = None,
+        expected_status: Optional[int] = None,
+    ) -> ApiResult:
+        return self.request("DELETE", path, token=token, headers=headers, expected_status=expected_status)
@@ -61,3 +61,26 @@ def send_assignment_ack(base_url: str, token: str, order_id: int, status: str) -
     with websocket_connection(base_url, token) as ws:
         ws.send(json.dumps(payload))
         return json.loads(ws.recv())
+
+
+def send_heartbeat_collect(base_url: str, token: str, lat: float, lng: float, *, timeout: int = 5) -> List[Dict]:
+    """Send one heartbeat and return every message up to and including its response."""
+    payload = json.dumps({"type": "heartbeat", "lat": lat, "lng": lng})
+    messages: List[Dict] = []
+    with websocket_connection(base_url, token, timeout=timeout) as ws:
+        ws.send(payload)
+        while True:
+            data = json.loads(ws.recv())
+            messages.append(data)
+            if data.get("type") == "heartbeat":
+                return messages
+
+
+def recv_of_type(ws, message_type: str, timeout: int = 5) -> Dict:
+    deadline = time.time() + timeout
+    while time.time() < deadline:
+        ws.settimeout(max(0.1, deadline - time.time()))
+        data = json.loads(ws.recv())
+        if data.get("type") == message_type:
+            return data
+    raise TimeoutError(f"No {message_type} message within {timeout}s")
@@ -0,0 +1,112 @@
+from datetime import datetime, timedelta, timezone
+
+import pytest
+
+from ..support.ws import send_multiple_heartbeats
+
+pytestmark = pytest.mark.acceptance
+
+PATH = [
+    {"lat": 31.9454, "lng": 35.9284, "battery_pct": 90.0, "speed_mps": 10.0, "heading_deg": 0.0},
+    {"lat": 31.9500, "lng": 35.9250, "battery_pct": 89.5, "speed_mps": 12.5, "heading_deg": 315.0},
+    {"lat": 31.9550, "lng": 35.9200},
+]
+
+
+def _iso(dt):
+    return dt.strftime("%Y-%m-%dT%H:%M:%SZ")
+
+
+def _fly(base_url, token, path):
+    responses = send_multiple_heartbeats(base_url, token, path)
+    assert all(r.get("message") == "ok" for r in responses)
+
+
+def test_track_endpoints_require_admin(api_client, enduser_token, drone1_token, drone1_id):
+    api_client.get(f"/admin/drones/{drone1_id}/track", expected_status=401)
+    api_client.get(f"/admin/drones/{drone1_id}/track", token=enduser_token, expected_status=403)
+    api_client.get(f"/admin/drones/{drone1_id}/track", token=drone1_token, expected_status=403)
+    api_client.get("/admin/orders/1/track", token=drone1_token, expected_status=403)
+
+
+def test_track_unknown_ids(api_client, admin_token):
+    api_client.get("/admin/drones/999999/track", token=admin_token, expected_status=404)
+    api_client.get("/admin/orders/999999/track", token=admin_token, expected_status=404)
+    api_client.get("/admin/drones/abc/track", token=admin_token, expected_status=400)
+    api_client.get("/admin/orders/abc/track", token=admin_token, expected_status=400)
+
+
+def test_track_rejects_bad_window(api_client, admin_token, drone1_id):
+    now = datetime.now(timezone.utc)
+    url = f"/admin/drones/{drone1_id}/track"
+    api_client.get(f"{url}?from={_iso(now)}&to={_iso(now - timedelta(hours=1))}", token=admin_token, expected_status=400)
+    body = api_client.get(
+        f"{url}?from={_iso(now - timedelta(days=8))}&to={_iso(now)}", token=admin_token, expected_status=400
+    ).json()
+    assert body["error"] == "track_window_too_large"
+    api_client.get(f"{url}?from=yesterday", token=admin_token, expected_status=400)
+
+
+def test_drone_track_replays_heartbeats(base_url, api_client, admin_token, drone1_token, drone1_id, drone_actions):
+    drone_actions.ensure_idle(drone1_id, lat=PATH[0]["lat"], lng=PATH[0]["lng"])
+    start = datetime.now(timezone.utc) - timedelta(seconds=1)
+    _fly(base_url, drone1_token, PATH)
+
+    body = api_client.get(
+        f"/admin/drones/{drone1_id}/track?from={_iso(start)}", token=admin_token, expected_status=200
+    ).json()
+
+    assert body["type"] == "Feature"
+    assert body["geometry"]["type"] == "LineString"
+    coords = body["geometry"]["coordinates"]
+    assert len(coords) >= len(PATH)
+    # GeoJSON positions are [lng, lat]
+    for expected, actual in zip(PATH, coords[-len(PATH):]):
+        assert actual[0] == pytest.approx(expected["lng"])
+        assert actual[1] == pytest.approx(expected["lat"])
+
+    props = body["properties"]
+    assert props["drone_id"] == drone1_id
+    assert props["truncated"] is False
+    assert props["point_count"] == len(coords) == len(props["timestamps"])
+    assert props["battery_pct"][-3:] == [90.0, 89.5, None]
+    assert props["speed_mps"][-2] == pytest.approx(12.5)
+    assert props["heading_deg"][-1] is None
+
+
+def test_drone_track_empty_window_has_no_geometry(api_client, admin_token, drone1_id):
+    long_ago = datetime(2020, 1, 1, tzinfo=timezone.utc)
+    body = api_client.get(
+        f"/admin/drones/{drone1_id}/track?from={_iso(long_ago)}&to={_iso(long_ago + timedelta(hours=1))}",
+        token=admin_token,
+        expected_status=200,
+    ).json()
+    assert body["geometry"] is None
+    assert body["properties"]["point_count"] == 0
+
+
+def test_order_track_follows_the_trip(
+    base_url, api_client, admin_token, drone1_token, drone1_id, drone_actions, order_factory, order_actions
+):
+    drone_actions.ensure_idle(drone1_id, lat=PATH[0]["lat"], lng=PATH[0]["lng"])
+    order_id = order_factory()
+    order_actions.reserve(order_id, token=drone1_token)
+
+    _fly(base_url, drone1_token, PATH)
+
+    body = api_client.get(f"/admin/orders/{order_id}/track", token=admin_token, expected_status=200).json()
+    assert body["type"] == "FeatureCollection"
+    assert len(body["features"]) == 1
+
+    feature = body["features"][0]
+    assert feature["geometry"]["type"] == "LineString"
+    assert len(feature["geometry"]["coordinates"]) == len(PATH)
+    assert feature["properties"]["order_id"] == order_id
+    assert feature["properties"]["drone_id"] == drone1_id
+    assert "trip_id" in feature["properties"]
+
+
+def test_order_track_before_flight_is_empty(api_client, admin_token, order_factory):
+    order_id = order_factory()
+    body = api_client.get(f"/admin/orders/{order_id}/track", token=admin_token, expected_status=200).json()
+    assert body == {"type": "FeatureCollection", "features": []}
@@ -0,0 +1,89 @@
+import json
+import time
+
+import pytest
+
+from ..support.ws import recv_of_type, websocket_connection
+
+pytestmark = pytest.mark.acceptance
+
+
+def _connect(ws):
+    ws.send(json.dumps({"type": "heartbeat", "lat": 31.9454, "lng": 35.9284}))
+    assert recv_of_type(ws, "heartbeat")["message"] == "ok"
+
+
+def _issue(api_client, admin_token, drone_id):
+    return api_client.post(
+        f"/admin/drones/{drone_id}/commands",
+        token=admin_token,
+        json_body={"type": "hold_position"},
+        expected_status=201,
+    ).json()
+
+
+def _recv_command(ws, command_id):
+    while True:
+        message = recv_of_type(ws, "command")
+        if message["command_id"] == command_id:
+            return message
+
+
+def test_command_reaches_drone_connected_to_other_node(
+    peer_base_url, api_client, admin_token, drone1_token, drone1_id
+):
+    with websocket_connection(peer_base_url, drone1_token) as ws:
+        _connect(ws)
+
+        issued = _issue(api_client, admin_token, drone1_id)
+        assert issued["status"] == "sent"
+
+        message = _recv_command(ws, issued["command_id"])
+        assert message["seq"] > 0
+
+        ws.send(json.dumps({"type": "ack", "seq": message["seq"]}))
+        ws.send(json.dumps({"type": "command_ack", "command_id": issued["command_id"], "status": "accepted"}))
+        assert recv_of_type(ws, "command_ack")["command_status"] == "acknowledged"
+
+    command = api_client.get(
+        f"/admin/drones/{drone1_id}/commands/{issued['command_id']}", token=admin_token
+    ).json()
+    assert command["status"] == "acknowledged"
+
+
+def test_presence_is_withdrawn_when_drone_leaves_other_node(
+    peer_base_url, api_client, admin_token, drone1_token, drone1_id
+):
+    with websocket_connection(peer_base_url, drone1_token) as ws:
+        _connect(ws)
+    # let the peer notice the close
+    time.sleep(0.5)
+
+    assert _issue(api_client, admin_token, drone1_id)["status"] == "undelivered"
+
+
+def test_resume_on_other_node_hands_over_unacked_messages(
+    base_url, peer_base_url, api_client, admin_token, drone1_token, drone1_id
+):
+    with websocket_connection(base_url, drone1_token) as ws:
+        _connect(ws)
+        seen = _recv_command(ws, _issue(api_client, admin_token, drone1_id)["command_id"])
+        last_seq = seen["seq"]
+        ws.send(json.dumps({"type": "ack", "seq": last_seq}))
+    # let the node notice the close; the next command waits in its buffer
+    time.sleep(0.5)
+
+    missed = _issue(api_client, admin_token, drone1_id)
+    assert missed["status"] == "undelivered"
+
+    with websocket_connection(peer_base_url, drone1_token) as ws:
+        _connect(ws)
+        ws.send(json.dumps({"type": "resume", "last_seq": last_seq}))
+        assert recv_of_type(ws, "resume")["last_seq"] >= last_seq
+
+        message = _recv_command(ws, missed["command_id"])
+        assert message["seq"] > last_seq
+
+        ws.send(json.dumps({"type": "ack", "seq": message["seq"]}))
+        ws.send(json.dumps({"type": "command_ack", "command_id": missed["command_id"], "status": "accepted"}))
+        assert recv_of_type(ws, "command_ack")["command_status"] == "acknowledged"
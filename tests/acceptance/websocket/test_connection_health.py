@@ -0,0 +1,67 @@
+import json
+import time
+
+import pytest
+
+from ..support.ws import recv_of_type, websocket_connection
+
+pytestmark = pytest.mark.acceptance
+
+METRICS_URL = "/admin/ws/metrics"
+
+
+def _metrics(api_client, admin_token):
+    return api_client.get(METRICS_URL, token=admin_token).json()
+
+
+def test_metrics_require_admin(api_client, enduser_token, drone1_token):
+    api_client.get(METRICS_URL, expected_status=401)
+    api_client.get(METRICS_URL, token=enduser_token, expected_status=403)
+    api_client.get(METRICS_URL, token=drone1_token, expected_status=403)
+
+
+def test_metrics_shape(api_client, admin_token):
+    body = _metrics(api_client, admin_token)
+
+    assert set(body["active_connections"]) == {"drone", "admin"}
+    assert set(body["disconnects"]) == {
+        "peer_closed",
+        "read_timeout",
+        "read_error",
+        "write_error",
+        "slow_consumer",
+        "replaced",
+        "server_closed",
+    }
+    for key in ("connections_opened", "messages_sent", "messages_dropped", "pings_sent", "pongs_received"):
+        assert body[key] >= 0
+
+
+def test_metrics_track_drone_connections(base_url, api_client, admin_token, drone1_token):
+    before = _metrics(api_client, admin_token)
+
+    with websocket_connection(base_url, drone1_token) as ws:
+        ws.send(json.dumps({"type": "heartbeat", "lat": 31.9454, "lng": 35.9284}))
+        assert recv_of_type(ws, "heartbeat")["message"] == "ok"
+        during = _metrics(api_client, admin_token)
+
+    time.sleep(0.5)
+    after = _metrics(api_client, admin_token)
+
+    assert during["connections_opened"] > before["connections_opened"]
+    assert during["active_connections"]["drone"] >= 1
+    closed_before = sum(before["disconnects"].values())
+    assert sum(after["disconnects"].values()) > closed_before
+
+
+def test_reconnect_replaces_previous_connection(base_url, api_client, admin_token, drone1_token):
+    before = _metrics(api_client, admin_token)["disconnects"]["replaced"]
+
+    with websocket_connection(base_url, drone1_token) as first:
+        first.send(json.dumps({"type": "heartbeat", "lat": 31.9454, "lng": 35.9284}))
+        assert recv_of_type(first, "heartbeat")["message"] == "ok"
+        with websocket_connection(base_url, drone1_token) as second:
+            second.send(json.dumps({"type": "heartbeat", "lat": 31.9454, "lng": 35.9284}))
+            assert recv_of_type(second, "heartbeat")["message"] == "ok"
+
+    assert _metrics(api_client, admin_token)["disconnects"]["replaced"] == before + 1
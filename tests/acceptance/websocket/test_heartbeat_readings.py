@@ -0,0 +1,134 @@
+import json
+from datetime import datetime, timedelta, timezone
+
+import pytest
+
+from ..support.ws import send_multiple_heartbeats, websocket_connection
+
+pytestmark = pytest.mark.acceptance
+
+FAR_FROM_PICKUP = {"lat": 31.80, "lng": 35.90}
+
+
+def _device_time(offset_seconds=0.0):
+    t = datetime.now(timezone.utc) + timedelta(seconds=offset_seconds)
+    return t.isoformat(timespec="milliseconds").replace("+00:00", "Z")
+
+
+def _send(base_url, token, payloads):
+    return send_multiple_heartbeats(base_url, token, payloads)
+
+
+def _drone(drone_actions, drone_id):
+    drones = drone_actions.list_drones(query="page_size=100").json()["data"]
+    return next(d for d in drones if d["drone_id"] == drone_id)
+
+
+def test_heartbeat_stores_flight_readings(base_url, drone1_token, drone1_id, drone_actions):
+    device_time = _device_time()
+    (resp,) = _send(
+        base_url,
+        drone1_token,
+        [
+            {
+                "lat": 31.9454,
+                "lng": 35.9284,
+                "altitude_m": 120.5,
+                "heading_deg": 270.0,
+                "speed_mps": 12.5,
+                "battery_pct": 87.0,
+                "gps_fix": "3d",
+                "gps_accuracy_m": 2.5,
+                "device_time": device_time,
+            }
+        ],
+    )
+    assert resp.get("message") == "ok"
+
+    drone = _drone(drone_actions, drone1_id)
+    assert drone["altitude_m"] == pytest.approx(120.5)
+    assert drone["heading_deg"] == pytest.approx(270.0)
+    assert drone["speed_mps"] == pytest.approx(12.5)
+    assert drone["battery_pct"] == pytest.approx(87.0)
+    assert drone["gps_fix"] == "3d"
+    assert drone["gps_accuracy_m"] == pytest.approx(2.5)
+    assert "device_time" in drone
+
+
+@pytest.mark.parametrize(
+    "field,value",
+    [
+        ("altitude_m", 20000.0),
+        ("altitude_m", -1000.0),
+        ("heading_deg", 360.0),
+        ("heading_deg", -1.0),
+        ("speed_mps", -0.1),
+        ("speed_mps", 150.0),
+        ("battery_pct", 100.5),
+        ("gps_fix", "4d"),
+        ("gps_fix", "none"),
+        ("gps_accuracy_m", -1.0),
+        ("device_time", _device_time(3600)),
+    ],
+)
+def test_heartbeat_rejects_invalid_readings(base_url, drone1_token, field, value):
+    with websocket_connection(base_url, drone1_token) as ws:
+        ws.send(json.dumps({"type": "heartbeat", "lat": 31.0, "lng": 35.0, field: value}))
+        resp = json.loads(ws.recv())
+    assert resp.get("message") == "error"
+    assert field in resp.get("error", "")
+
+
+def test_heartbeat_rejects_malformed_device_time(base_url, drone1_token):
+    with websocket_connection(base_url, drone1_token) as ws:
+        ws.send(json.dumps({"type": "heartbeat", "lat": 31.0, "lng": 35.0, "device_time": "yesterday"}))
+        resp = json.loads(ws.recv())
+    assert resp.get("message") == "error"
+
+
+def test_out_of_order_heartbeat_is_rejected(base_url, drone2_token, drone2_id, drone_actions):
+    drone_actions.ensure_idle(drone2_id)
+    newer, older = _device_time(1), _device_time(0)
+    responses = _send(
+        base_url,
+        drone2_token,
+        [
+            {"lat": 31.10, "lng": 35.10, "device_time": newer},
+            {"lat": 31.20, "lng": 35.20, "device_time": older},
+            {"lat": 31.30, "lng": 35.30, "device_time": newer},
+        ],
+    )
+    assert responses[0].get("message") == "ok"
+    assert responses[1].get("message") == "error"
+    assert "older" in responses[1].get("error", "")
+    assert responses[2].get("message") == "error"
+
+    # the stale reports did not move the drone
+    drone = _drone(drone_actions, drone2_id)
+    assert drone["lat"] == pytest.approx(31.10)
+
+    (resp,) = _send(base_url, drone2_token, [{"lat": 31.40, "lng": 35.40, "device_time": _device_time(2)}])
+    assert resp.get("message") == "ok"
+
+
+def test_eta_uses_reported_ground_speed(
+    base_url, order_actions, enduser_token, drone2_token, drone2_id, drone_actions
+):
+    drone_actions.ensure_idle(drone2_id)
+    order_id = order_actions.create(token=enduser_token)
+    order_actions.reserve(order_id, token=drone2_token)
+
+    def eta_after(extra):
+        (resp,) = _send(base_url, drone2_token, [{**FAR_FROM_PICKUP, **extra}])
+        assert resp.get("message") == "ok"
+        return order_actions.get(order_id, token=enduser_token).json()["eta_minutes"]
+
+    try:
+        nominal = eta_after({})
+        hovering = eta_after({"speed_mps": 0.5})
+        fast = eta_after({"speed_mps": 40.0})
+    finally:
+        order_actions.fail(order_id, token=drone2_token)
+
+    assert hovering == nominal
+    assert fast < nominal
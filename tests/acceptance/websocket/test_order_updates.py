@@ -0,0 +1,116 @@
+import json
+import time
+
+import pytest
+
+from ..support.ws import recv_of_type, websocket_connection
+
+pytestmark = pytest.mark.acceptance
+
+
+def _connect(ws, lat, lng):
+    """Send a heartbeat and wait for its reply so the connection is registered."""
+    ws.send(json.dumps({"type": "heartbeat", "lat": lat, "lng": lng}))
+    assert recv_of_type(ws, "heartbeat")["message"] == "ok"
+
+
+def _ack(ws, order_id, event, status="accepted"):
+    ws.send(json.dumps({"type": "order_update_ack", "order_id": order_id, "event": event, "status": status}))
+    while True:
+        data = json.loads(ws.recv())
+        if data.get("type") in ("order_update_ack", "heartbeat"):
+            return data
+
+
+def _offer(ws, order_actions, enduser_token, **kwargs):
+    order_id = order_actions.create(token=enduser_token, **kwargs)
+    assignment = recv_of_type(ws, "assignment", timeout=10)
+    assert assignment["order_id"] == order_id
+    # the offer is recorded right after the assignment is pushed
+    time.sleep(0.5)
+    return order_id
+
+
+@pytest.fixture
+def idle_drones(base_url, drone_actions, drone1_id, drone2_id):
+    drone_actions.ensure_idle(drone1_id, lat=30.0, lng=35.0)
+    drone_actions.ensure_idle(drone2_id, lat=32.0, lng=37.0)
+
+
+def test_cancel_notifies_offered_drone(base_url, idle_drones, order_actions, enduser_token, drone1_token):
+    with websocket_connection(base_url, drone1_token) as ws:
+        _connect(ws, 30.0, 35.0)
+        order_id = _offer(ws, order_actions, enduser_token, pickup_lat=30.1, pickup_lng=35.1)
+
+        order_actions.cancel(order_id, token=enduser_token)
+        update = recv_of_type(ws, "order_update")
+
+        assert update["event"] == "order_canceled"
+        assert update["order_id"] == order_id
+        assert update["order_status"] == "canceled"
+
+        ack = _ack(ws, order_id, "order_canceled")
+        assert ack["type"] == "order_update_ack"
+        assert ack["message"] == "acknowledged"
+
+
+def test_route_update_sends_new_waypoints(
+    base_url, idle_drones, api_client, admin_token, order_actions, enduser_token, drone1_token
+):
+    with websocket_connection(base_url, drone1_token) as ws:
+        _connect(ws, 30.0, 35.0)
+        order_id = _offer(ws, order_actions, enduser_token, pickup_lat=30.1, pickup_lng=35.1)
+
+        api_client.patch(
+            f"/admin/orders/{order_id}",
+            token=admin_token,
+            json_body={"dropoff_lat": 30.25, "dropoff_lng": 35.25},
+        )
+        update = recv_of_type(ws, "order_update")
+
+        assert update["event"] == "route_updated"
+        assert update["order_id"] == order_id
+        assert (update["dropoff_lat"], update["dropoff_lng"]) == (30.25, 35.25)
+        assert update["waypoints"][0] == {"lat": 30.0, "lng": 35.0}
+        assert update["waypoints"][-1] == {"lat": 30.25, "lng": 35.25}
+
+        assert _ack(ws, order_id, "route_updated")["message"] == "acknowledged"
+
+    order_actions.cancel(order_id, token=enduser_token)
+
+
+def test_broken_drone_is_told_to_hand_off(
+    base_url, idle_drones, order_actions, drone_actions, enduser_token, drone1_token, drone1_id
+):
+    with websocket_connection(base_url, drone1_token) as ws:
+        _connect(ws, 30.0, 35.0)
+        order_id = _offer(ws, order_actions, enduser_token, pickup_lat=30.1, pickup_lng=35.1)
+        order_actions.reserve(order_id, token=drone1_token)
+        order_actions.pickup(order_id, token=drone1_token)
+
+        drone_actions.mark_broken(drone1_id, lat=30.05, lng=35.05, token=drone1_token)
+        update = recv_of_type(ws, "order_update")
+
+        assert update["event"] == "handoff_required"
+        assert update["order_id"] == order_id
+        assert update["order_status"] == "handoff_pending"
+        assert (update["handoff_lat"], update["handoff_lng"]) == (30.05, 35.05)
+
+        assert _ack(ws, order_id, "handoff_required")["message"] == "acknowledged"
+
+    drone_actions.mark_fixed(drone1_id, lat=30.0, lng=35.0)
+
+
+@pytest.mark.parametrize(
+    "payload",
+    [
+        {"type": "order_update_ack", "event": "order_canceled", "status": "accepted"},
+        {"type": "order_update_ack", "order_id": 1, "event": "exploded", "status": "accepted"},
+        {"type": "order_update_ack", "order_id": 1, "event": "order_canceled", "status": "maybe"},
+    ],
+)
+def test_invalid_order_update_ack(base_url, drone1_token, payload):
+    with websocket_connection(base_url, drone1_token) as ws:
+        ws.send(json.dumps(payload))
+        response = recv_of_type(ws, "heartbeat")
+        assert response["message"] == "error"
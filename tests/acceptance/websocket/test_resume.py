@@ -0,0 +1,96 @@
+import json
+import time
+
+import pytest
+
+from ..support.ws import recv_of_type, websocket_connection
+
+pytestmark = pytest.mark.acceptance
+
+
+def _commands_url(drone_id):
+    return f"/admin/drones/{drone_id}/commands"
+
+
+def _connect(ws):
+    """Send a heartbeat and wait for its reply so the connection is registered."""
+    ws.send(json.dumps({"type": "heartbeat", "lat": 31.9454, "lng": 35.9284}))
+    assert recv_of_type(ws, "heartbeat")["message"] == "ok"
+
+
+def _issue(api_client, admin_token, drone_id):
+    return api_client.post(
+        _commands_url(drone_id), token=admin_token, json_body={"type": "hold_position"}, expected_status=201
+    ).json()
+
+
+def _recv_command(ws, command_id):
+    while True:
+        message = recv_of_type(ws, "command")
+        if message["command_id"] == command_id:
+            return message
+
+
+def test_pushed_messages_carry_increasing_seq(base_url, api_client, admin_token, drone1_token, drone1_id):
+    with websocket_connection(base_url, drone1_token) as ws:
+        _connect(ws)
+        first = _recv_command(ws, _issue(api_client, admin_token, drone1_id)["command_id"])
+        second = _recv_command(ws, _issue(api_client, admin_token, drone1_id)["command_id"])
+        ws.send(json.dumps({"type": "ack", "seq": second["seq"]}))
+
+    assert second["seq"] > first["seq"] > 0
+
+
+def test_message_sent_while_disconnected_is_delivered_on_reconnect(
+    base_url, api_client, admin_token, drone1_token, drone1_id
+):
+    with websocket_connection(base_url, drone1_token) as ws:
+        _connect(ws)
+    # let the server notice the close
+    time.sleep(0.5)
+
+    missed = _issue(api_client, admin_token, drone1_id)
+    assert missed["status"] == "undelivered"
+
+    with websocket_connection(base_url, drone1_token) as ws:
+        message = _recv_command(ws, missed["command_id"])
+        ws.send(json.dumps({"type": "ack", "seq": message["seq"]}))
+        ws.send(json.dumps({"type": "command_ack", "command_id": missed["command_id"], "status": "accepted"}))
+        assert recv_of_type(ws, "command_ack")["command_status"] == "acknowledged"
+
+
+def test_resume_replays_unacked_messages(base_url, api_client, admin_token, drone1_token, drone1_id):
+    with websocket_connection(base_url, drone1_token) as ws:
+        _connect(ws)
+        issued = _issue(api_client, admin_token, drone1_id)
+        seen = _recv_command(ws, issued["command_id"])
+        # connection drops before the drone acks
+
+    with websocket_connection(base_url, drone1_token) as ws:
+        ws.send(json.dumps({"type": "resume", "last_seq": seen["seq"] - 1}))
+        replayed = _recv_command(ws, issued["command_id"])
+        resumed = recv_of_type(ws, "resume")
+
+        assert replayed["seq"] == seen["seq"]
+        assert resumed["message"] == "ok"
+        assert resumed["replayed"] >= 1
+        assert resumed["last_seq"] >= seen["seq"]
+
+        # once acked, a second resume has nothing left to send
+        ws.send(json.dumps({"type": "resume", "last_seq": resumed["last_seq"]}))
+        assert recv_of_type(ws, "resume")["replayed"] == 0
+
+
+@pytest.mark.parametrize(
+    "payload",
+    [
+        {"type": "resume"},
+        {"type": "resume", "last_seq": -1},
+        {"type": "ack"},
+        {"type": "ack", "seq": "x"},
+    ],
+)
+def test_invalid_resume_and_ack(base_url, drone1_token, payload):
+    with websocket_connection(base_url, drone1_token) as ws:
+        ws.send(json.dumps(payload))
+        assert recv_of_type(ws, "heartbeat")["message"] == "error"